@@ -0,0 +1,305 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	copilot "github.com/github/copilot-sdk/go"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+)
+
+// --- get_admission_context tool ---
+
+type getAdmissionContextParams struct {
+	GVR       string `json:"gvr" jsonschema:"Group/Version/Resource of the rejected object, e.g. apps/v1/deployments, v1/pods"`
+	Operation string `json:"operation,omitempty" jsonschema:"Admission operation: CREATE, UPDATE, DELETE or CONNECT. Empty matches any operation"`
+	Namespace string `json:"namespace,omitempty" jsonschema:"Namespace of the object, used to evaluate webhook namespace selectors"`
+}
+
+// webhook is the part of a validating or mutating webhook used to match it.
+type webhook struct {
+	config, kind      string
+	name              string
+	clientConfig      admissionv1.WebhookClientConfig
+	rules             []admissionv1.RuleWithOperations
+	failurePolicy     *admissionv1.FailurePolicyType
+	namespaceSelector *metav1.LabelSelector
+	objectSelector    *metav1.LabelSelector
+	timeoutSeconds    *int32
+}
+
+// webhookStatus reports a webhook matching an admission request.
+type webhookStatus struct {
+	Configuration     string   `json:"configuration"`
+	Type              string   `json:"type"`
+	Name              string   `json:"name"`
+	FailurePolicy     string   `json:"failurePolicy"`
+	TimeoutSeconds    int32    `json:"timeoutSeconds,omitempty"`
+	Rules             []string `json:"rules"`
+	NamespaceSelector string   `json:"namespaceSelector,omitempty"`
+	ObjectSelector    string   `json:"objectSelector,omitempty"`
+	Endpoint          string   `json:"endpoint"`
+	Reachability      string   `json:"reachability"`
+	ReadyEndpoints    int      `json:"readyEndpoints,omitempty"`
+}
+
+func (tf *ToolFactory) getAdmissionContextTool() copilot.Tool {
+	return copilot.DefineTool(
+		"get_admission_context",
+		"List the validating and mutating admission webhooks whose rules match a GVR and operation, with their failurePolicy, "+
+			"selectors and the reachability of the webhook service. "+
+			"Use when a change fails with 'admission webhook ... denied the request' or a webhook call error, to explain which webhook rejected it and why.",
+		func(params getAdmissionContextParams, inv copilot.ToolInvocation) (any, error) {
+			gvr, err := parseGVR(params.GVR)
+			if err != nil {
+				return nil, err
+			}
+			op := admissionv1.OperationType(strings.ToUpper(params.Operation))
+			dial, err := tf.conn.Dial()
+			if err != nil {
+				return nil, fmt.Errorf("failed to connect to cluster: %w", err)
+			}
+			ctx := context.Background()
+
+			var nsLabels labels.Set
+			if params.Namespace != "" {
+				ns, err := dial.CoreV1().Namespaces().Get(ctx, params.Namespace, metav1.GetOptions{})
+				if err != nil {
+					return nil, fmt.Errorf("failed to get namespace %s: %w", params.Namespace, err)
+				}
+				nsLabels = ns.Labels
+			}
+
+			vv, err := dial.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list validating webhook configurations: %w", err)
+			}
+			mm, err := dial.AdmissionregistrationV1().MutatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list mutating webhook configurations: %w", err)
+			}
+
+			ss := webhookStatuses(admissionWebhooks(vv.Items, mm.Items), gvr, op, nsLabels)
+			for i := range ss {
+				tf.probeWebhook(ctx, dial, &ss[i])
+			}
+			result := map[string]any{
+				"gvr":      params.GVR,
+				"count":    len(ss),
+				"webhooks": ss,
+			}
+			if risks := webhookRisks(ss); len(risks) > 0 {
+				result["risks"] = risks
+			}
+
+			return result, nil
+		},
+	)
+}
+
+// admissionWebhooks flattens webhook configurations into their webhooks.
+func admissionWebhooks(vv []admissionv1.ValidatingWebhookConfiguration, mm []admissionv1.MutatingWebhookConfiguration) []webhook {
+	var ww []webhook
+	for _, v := range vv {
+		for _, w := range v.Webhooks {
+			ww = append(ww, webhook{
+				config:            v.Name,
+				kind:              "validating",
+				name:              w.Name,
+				clientConfig:      w.ClientConfig,
+				rules:             w.Rules,
+				failurePolicy:     w.FailurePolicy,
+				namespaceSelector: w.NamespaceSelector,
+				objectSelector:    w.ObjectSelector,
+				timeoutSeconds:    w.TimeoutSeconds,
+			})
+		}
+	}
+	for _, m := range mm {
+		for _, w := range m.Webhooks {
+			ww = append(ww, webhook{
+				config:            m.Name,
+				kind:              "mutating",
+				name:              w.Name,
+				clientConfig:      w.ClientConfig,
+				rules:             w.Rules,
+				failurePolicy:     w.FailurePolicy,
+				namespaceSelector: w.NamespaceSelector,
+				objectSelector:    w.ObjectSelector,
+				timeoutSeconds:    w.TimeoutSeconds,
+			})
+		}
+	}
+
+	return ww
+}
+
+// webhookStatuses keeps the webhooks whose rules match the request. When
+// namespace labels are given, webhooks whose namespace selector excludes
+// them are dropped too.
+func webhookStatuses(ww []webhook, gvr schema.GroupVersionResource, op admissionv1.OperationType, nsLabels labels.Set) []webhookStatus {
+	ss := make([]webhookStatus, 0, len(ww))
+	for _, w := range ww {
+		rules := matchingRules(w.rules, gvr, op)
+		if len(rules) == 0 {
+			continue
+		}
+		if nsLabels != nil && w.namespaceSelector != nil {
+			sel, err := metav1.LabelSelectorAsSelector(w.namespaceSelector)
+			if err != nil || !sel.Matches(nsLabels) {
+				continue
+			}
+		}
+		s := webhookStatus{
+			Configuration: w.config,
+			Type:          w.kind,
+			Name:          w.name,
+			// Fail is the API server default.
+			FailurePolicy: string(admissionv1.Fail),
+			Rules:         rules,
+			Endpoint:      webhookEndpoint(w.clientConfig),
+		}
+		if w.failurePolicy != nil {
+			s.FailurePolicy = string(*w.failurePolicy)
+		}
+		if w.timeoutSeconds != nil {
+			s.TimeoutSeconds = *w.timeoutSeconds
+		}
+		if sel := w.namespaceSelector; sel != nil && (len(sel.MatchLabels) > 0 || len(sel.MatchExpressions) > 0) {
+			s.NamespaceSelector = metav1.FormatLabelSelector(sel)
+		}
+		if sel := w.objectSelector; sel != nil && (len(sel.MatchLabels) > 0 || len(sel.MatchExpressions) > 0) {
+			s.ObjectSelector = metav1.FormatLabelSelector(sel)
+		}
+		ss = append(ss, s)
+	}
+
+	return ss
+}
+
+// matchingRules returns the rules matching the GVR and operation, formatted
+// as operations:group/version/resource. An empty operation matches any.
+func matchingRules(rr []admissionv1.RuleWithOperations, gvr schema.GroupVersionResource, op admissionv1.OperationType) []string {
+	var mm []string
+	for _, r := range rr {
+		if op != "" && !slices.Contains(r.Operations, op) && !slices.Contains(r.Operations, admissionv1.OperationAll) {
+			continue
+		}
+		if !matchesAll(r.APIGroups, gvr.Group) || !matchesAll(r.APIVersions, gvr.Version) {
+			continue
+		}
+		if !slices.Contains(r.Resources, gvr.Resource) && !matchesAll(r.Resources, "*/*") {
+			continue
+		}
+		ops := make([]string, 0, len(r.Operations))
+		for _, o := range r.Operations {
+			ops = append(ops, string(o))
+		}
+		mm = append(mm, fmt.Sprintf("%s:%s/%s/%s",
+			strings.Join(ops, ","), strings.Join(r.APIGroups, ","), strings.Join(r.APIVersions, ","), strings.Join(r.Resources, ",")))
+	}
+
+	return mm
+}
+
+// matchesAll checks whether a rule field lists the value or a wildcard.
+func matchesAll(ss []string, v string) bool {
+	return slices.Contains(ss, "*") || slices.Contains(ss, v)
+}
+
+// webhookEndpoint describes where the API server sends admission reviews.
+func webhookEndpoint(cc admissionv1.WebhookClientConfig) string {
+	if cc.URL != nil {
+		return *cc.URL
+	}
+	if svc := cc.Service; svc != nil {
+		port := int32(443)
+		if svc.Port != nil {
+			port = *svc.Port
+		}
+		path := ""
+		if svc.Path != nil {
+			path = *svc.Path
+		}
+		return fmt.Sprintf("service %s/%s:%d%s", svc.Namespace, svc.Name, port, path)
+	}
+
+	return "unknown"
+}
+
+// probeWebhook reports whether the webhook service has ready endpoints.
+// URL webhooks live outside the cluster and are not probed.
+func (tf *ToolFactory) probeWebhook(ctx context.Context, dial kubernetes.Interface, s *webhookStatus) {
+	ns, name, ok := strings.Cut(strings.TrimPrefix(s.Endpoint, "service "), "/")
+	if !ok || !strings.HasPrefix(s.Endpoint, "service ") {
+		s.Reachability = "external URL, not checked"
+		return
+	}
+	name, _, _ = strings.Cut(name, ":")
+
+	if _, err := dial.CoreV1().Services(ns).Get(ctx, name, metav1.GetOptions{}); err != nil {
+		if kerrors.IsNotFound(err) {
+			s.Reachability = "service not found"
+			return
+		}
+		tf.log.Warn("Webhook service lookup failed", "service", ns+"/"+name, "error", err)
+		s.Reachability = "unknown: " + err.Error()
+		return
+	}
+	list, err := dial.DiscoveryV1().EndpointSlices(ns).List(ctx, metav1.ListOptions{
+		LabelSelector: discoveryv1.LabelServiceName + "=" + name,
+	})
+	if err != nil {
+		s.Reachability = "unknown: " + err.Error()
+		return
+	}
+	s.ReadyEndpoints = readyEndpoints(list.Items)
+	if s.ReadyEndpoints == 0 {
+		s.Reachability = "no ready endpoints"
+		return
+	}
+	s.Reachability = "ready"
+}
+
+// readyEndpoints counts the ready endpoints across endpoint slices.
+func readyEndpoints(ss []discoveryv1.EndpointSlice) int {
+	var n int
+	for _, s := range ss {
+		for _, e := range s.Endpoints {
+			if e.Conditions.Ready == nil || *e.Conditions.Ready {
+				n++
+			}
+		}
+	}
+
+	return n
+}
+
+// webhookRisks flags webhooks likely to reject matching requests.
+func webhookRisks(ss []webhookStatus) []string {
+	var rr []string
+	for _, s := range ss {
+		down := s.Reachability == "service not found" || s.Reachability == "no ready endpoints"
+		switch {
+		case down && s.FailurePolicy == string(admissionv1.Fail):
+			rr = append(rr, fmt.Sprintf("%s webhook %s (%s) is unreachable (%s) with failurePolicy Fail: every matching request is rejected",
+				s.Type, s.Name, s.Configuration, s.Reachability))
+		case down:
+			rr = append(rr, fmt.Sprintf("%s webhook %s (%s) is unreachable (%s) but ignored on failure: requests are admitted without it",
+				s.Type, s.Name, s.Configuration, s.Reachability))
+		}
+	}
+
+	return rr
+}
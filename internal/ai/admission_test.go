@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestWebhookStatuses(t *testing.T) {
+	ignore, path, url := admissionv1.Ignore, "/v1/admit", "https://istiod:15017/inject"
+	vv := []admissionv1.ValidatingWebhookConfiguration{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "gatekeeper"},
+			Webhooks: []admissionv1.ValidatingWebhook{
+				{
+					Name:              "validation.gatekeeper.sh",
+					Rules:             []admissionv1.RuleWithOperations{makeRule([]string{"*"}, []string{"*"}, admissionv1.Create, admissionv1.Update)},
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"policy": "on"}},
+					ClientConfig: admissionv1.WebhookClientConfig{
+						Service: &admissionv1.ServiceReference{Namespace: "gk", Name: "gk-webhook", Path: &path},
+					},
+				},
+			},
+		},
+	}
+	mm := []admissionv1.MutatingWebhookConfiguration{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "istio"},
+			Webhooks: []admissionv1.MutatingWebhook{
+				{
+					Name:          "sidecar.istio.io",
+					Rules:         []admissionv1.RuleWithOperations{makeRule([]string{""}, []string{"pods"}, admissionv1.Create)},
+					FailurePolicy: &ignore,
+					ClientConfig:  admissionv1.WebhookClientConfig{URL: &url},
+				},
+			},
+		},
+	}
+	ww := admissionWebhooks(vv, mm)
+
+	uu := map[string]struct {
+		gvr      schema.GroupVersionResource
+		op       admissionv1.OperationType
+		nsLabels labels.Set
+		e        []string
+	}{
+		"deploy-update": {
+			gvr: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+			op:  admissionv1.Update,
+			e:   []string{"validation.gatekeeper.sh"},
+		},
+		"pod-create": {
+			gvr: schema.GroupVersionResource{Version: "v1", Resource: "pods"},
+			op:  admissionv1.Create,
+			e:   []string{"validation.gatekeeper.sh", "sidecar.istio.io"},
+		},
+		"pod-delete": {
+			gvr: schema.GroupVersionResource{Version: "v1", Resource: "pods"},
+			op:  admissionv1.Delete,
+			e:   []string{},
+		},
+		"pod-any-op": {
+			gvr: schema.GroupVersionResource{Version: "v1", Resource: "pods"},
+			e:   []string{"validation.gatekeeper.sh", "sidecar.istio.io"},
+		},
+		"ns-excluded": {
+			gvr:      schema.GroupVersionResource{Version: "v1", Resource: "pods"},
+			op:       admissionv1.Create,
+			nsLabels: labels.Set{"policy": "off"},
+			e:        []string{"sidecar.istio.io"},
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			ss := webhookStatuses(ww, u.gvr, u.op, u.nsLabels)
+			names := make([]string, 0, len(ss))
+			for _, s := range ss {
+				names = append(names, s.Name)
+			}
+			assert.Equal(t, u.e, names)
+		})
+	}
+
+	ss := webhookStatuses(ww, schema.GroupVersionResource{Version: "v1", Resource: "pods"}, admissionv1.Create, nil)
+	assert.Equal(t, webhookStatus{
+		Configuration:     "gatekeeper",
+		Type:              "validating",
+		Name:              "validation.gatekeeper.sh",
+		FailurePolicy:     "Fail",
+		Rules:             []string{"CREATE,UPDATE:*/v1/*"},
+		NamespaceSelector: "policy=on",
+		Endpoint:          "service gk/gk-webhook:443/v1/admit",
+	}, ss[0])
+	assert.Equal(t, "Ignore", ss[1].FailurePolicy)
+	assert.Equal(t, "https://istiod:15017/inject", ss[1].Endpoint)
+}
+
+func TestReadyEndpoints(t *testing.T) {
+	yes, no := true, false
+	ss := []discoveryv1.EndpointSlice{
+		{Endpoints: []discoveryv1.Endpoint{
+			{Conditions: discoveryv1.EndpointConditions{Ready: &yes}},
+			{Conditions: discoveryv1.EndpointConditions{Ready: &no}},
+		}},
+		{Endpoints: []discoveryv1.Endpoint{{}}},
+	}
+
+	assert.Equal(t, 2, readyEndpoints(ss))
+	assert.Zero(t, readyEndpoints(nil))
+}
+
+func TestWebhookRisks(t *testing.T) {
+	ss := []webhookStatus{
+		{Type: "validating", Name: "a", Configuration: "ca", FailurePolicy: "Fail", Reachability: "no ready endpoints"},
+		{Type: "mutating", Name: "b", Configuration: "cb", FailurePolicy: "Ignore", Reachability: "service not found"},
+		{Type: "validating", Name: "c", Configuration: "cc", FailurePolicy: "Fail", Reachability: "ready"},
+	}
+
+	rr := webhookRisks(ss)
+	assert.Len(t, rr, 2)
+	assert.Contains(t, rr[0], "every matching request is rejected")
+	assert.Contains(t, rr[1], "admitted without it")
+}
+
+// Helpers...
+
+func makeRule(groups, resources []string, ops ...admissionv1.OperationType) admissionv1.RuleWithOperations {
+	return admissionv1.RuleWithOperations{
+		Operations: ops,
+		Rule: admissionv1.Rule{
+			APIGroups:   groups,
+			APIVersions: []string{"v1"},
+			Resources:   resources,
+		},
+	}
+}
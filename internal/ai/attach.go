@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// AttachHeader marks local manifests attached to a chat question.
+	AttachHeader = "[ATTACHED MANIFEST]"
+
+	// maxAttachBytes caps the size of an attached file.
+	maxAttachBytes = 128 * 1024
+)
+
+// Attachment is a local manifest to review along with the next question.
+type Attachment struct {
+	Path  string
+	Docs  int
+	Bytes int
+	body  string
+}
+
+// LoadAttachment reads a local YAML or JSON manifest, masking credentials
+// with the redactor, if any. Huge, binary or unparsable files are rejected.
+func LoadAttachment(path string, r *Redactor) (*Attachment, error) {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[2:])
+		}
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.Mode().IsRegular() {
+		return nil, fmt.Errorf("%s is not a regular file", path)
+	}
+	if fi.Size() > maxAttachBytes {
+		return nil, fmt.Errorf("%s is too large (%d bytes, max %d)", path, fi.Size(), maxAttachBytes)
+	}
+	bb, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if isBinary(bb) {
+		return nil, fmt.Errorf("%s does not look like a text file", path)
+	}
+	docs, err := countManifestDocs(bb)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not valid YAML or JSON: %w", path, err)
+	}
+	if docs == 0 {
+		return nil, fmt.Errorf("%s holds no manifest", path)
+	}
+
+	return &Attachment{
+		Path:  path,
+		Docs:  docs,
+		Bytes: len(bb),
+		body:  r.Redact(sanitizeText(bb)),
+	}, nil
+}
+
+// Prompt returns the labeled attachment to send ahead of the question.
+func (a *Attachment) Prompt() string {
+	return fmt.Sprintf("%s\nFile: %s (not applied to the cluster; use validate_manifest to check it against the API server)\n```yaml\n%s\n```\n",
+		AttachHeader, filepath.Base(a.Path), strings.TrimRight(a.body, "\n"))
+}
+
+// countManifestDocs returns the number of non empty documents in a YAML
+// stream. JSON parses as YAML.
+func countManifestDocs(bb []byte) (int, error) {
+	if json.Valid(bb) {
+		return 1, nil
+	}
+	var n int
+	dec := yaml.NewDecoder(bytes.NewReader(bb))
+	for {
+		var doc any
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return n, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		if doc != nil {
+			n++
+		}
+	}
+}
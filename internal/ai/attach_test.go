@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadAttachment(t *testing.T) {
+	dir := t.TempDir()
+	write := func(n string, bb []byte) string {
+		p := filepath.Join(dir, n)
+		require.NoError(t, os.WriteFile(p, bb, 0o600))
+		return p
+	}
+
+	uu := map[string]struct {
+		path string
+		docs int
+		err  string
+	}{
+		"yaml": {
+			path: write("dp.yaml", []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: b\n")),
+			docs: 2,
+		},
+		"json": {
+			path: write("cm.json", []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"a"}}`)),
+			docs: 1,
+		},
+		"huge": {
+			path: write("huge.yaml", []byte(strings.Repeat("a: b\n", maxAttachBytes))),
+			err:  "too large",
+		},
+		"binary": {
+			path: write("bin.yaml", []byte{0x7f, 'E', 'L', 'F', 0, 0, 1}),
+			err:  "not look like a text file",
+		},
+		"invalid": {
+			path: write("bad.yaml", []byte("a: [b\n")),
+			err:  "not valid YAML or JSON",
+		},
+		"empty": {
+			path: write("empty.yaml", []byte("---\n")),
+			err:  "holds no manifest",
+		},
+		"dir": {
+			path: dir,
+			err:  "not a regular file",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			a, err := LoadAttachment(u.path, nil)
+			if u.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), u.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, u.docs, a.Docs)
+			assert.True(t, strings.HasPrefix(a.Prompt(), AttachHeader))
+		})
+	}
+}
+
+func TestLoadAttachmentRedacts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secret.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("apiVersion: v1\nkind: Secret\nmetadata:\n  name: db\nstringData:\n  password: hunter2\n"), 0o600))
+
+	a, err := LoadAttachment(path, NewRedactor(nil))
+	require.NoError(t, err)
+	assert.Contains(t, a.Prompt(), "  password: [REDACTED]\n")
+	assert.NotContains(t, a.Prompt(), "hunter2")
+
+	a, err = LoadAttachment(path, nil)
+	require.NoError(t, err)
+	assert.Contains(t, a.Prompt(), "hunter2")
+}
+
+func TestDecodeManifest(t *testing.T) {
+	oo, err := decodeManifest("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n---\n---\napiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: b\n")
+	require.NoError(t, err)
+	require.Len(t, oo, 2)
+	assert.Equal(t, "ConfigMap", oo[0].GetKind())
+	assert.Equal(t, "b", oo[1].GetName())
+
+	_, err = decodeManifest("kind: ConfigMap\nmetadata:\n  name: a\n")
+	assert.ErrorContains(t, err, "missing apiVersion or kind")
+
+	_, err = decodeManifest("apiVersion: v1\nkind: ConfigMap\n")
+	assert.ErrorContains(t, err, "missing metadata.name")
+}
@@ -0,0 +1,238 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/derailed/k9s/internal/slogs"
+)
+
+const (
+	// auditQueueSize caps the records waiting for a slow sink. Records past
+	// it are dropped so a sink never holds up the chat.
+	auditQueueSize = 100
+
+	// auditPostTimeout caps the time spent posting a record to a webhook.
+	auditPostTimeout = 10 * time.Second
+)
+
+// auditRecord is a chat turn mirrored to the audit sink.
+type auditRecord struct {
+	Start     time.Time       `json:"start"`
+	End       time.Time       `json:"end"`
+	Model     string          `json:"model,omitempty"`
+	Prompt    string          `json:"prompt"`
+	ToolCalls []auditToolCall `json:"toolCalls,omitempty"`
+	Response  string          `json:"response,omitempty"`
+	Error     string          `json:"error,omitempty"`
+	Usage     auditUsage      `json:"usage"`
+
+	mx sync.Mutex
+}
+
+// auditToolCall is a tool the model called during a turn.
+type auditToolCall struct {
+	Time time.Time      `json:"time"`
+	Name string         `json:"name"`
+	Args map[string]any `json:"args,omitempty"`
+}
+
+// auditUsage counts the tokens spent on a turn.
+type auditUsage struct {
+	InputTokens  int64 `json:"inputTokens"`
+	OutputTokens int64 `json:"outputTokens"`
+}
+
+func (r *auditRecord) addToolCall(name string, args map[string]any) {
+	if r == nil {
+		return
+	}
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	r.ToolCalls = append(r.ToolCalls, auditToolCall{Time: time.Now(), Name: name, Args: args})
+}
+
+// redacted returns a copy of the record with credentials masked in all its
+// fields. The caller must hold the record lock.
+func (r *auditRecord) redacted(rd *Redactor) *auditRecord {
+	cp := auditRecord{
+		Start:    r.Start,
+		End:      r.End,
+		Model:    rd.Redact(r.Model),
+		Prompt:   rd.Redact(r.Prompt),
+		Response: rd.Redact(r.Response),
+		Error:    rd.Redact(r.Error),
+		Usage:    r.Usage,
+	}
+	for _, tc := range r.ToolCalls {
+		cp.ToolCalls = append(cp.ToolCalls, auditToolCall{Time: tc.Time, Name: tc.Name, Args: rd.redactArgs(tc.Args)})
+	}
+
+	return &cp
+}
+
+func (r *auditRecord) addUsage(in, out *float64) {
+	if r == nil {
+		return
+	}
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	if in != nil {
+		r.Usage.InputTokens += int64(*in)
+	}
+	if out != nil {
+		r.Usage.OutputTokens += int64(*out)
+	}
+}
+
+func (r *auditRecord) setResponse(s string) {
+	if r == nil {
+		return
+	}
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	r.Response = s
+}
+
+// auditSink mirrors chat turns to a JSONL file or a webhook. Records are
+// written in the background and sink errors are logged, never returned:
+// auditing must not break the chat.
+type auditSink struct {
+	target  string
+	redact  *Redactor
+	write   func([]byte) error
+	queue   chan []byte
+	pending sync.WaitGroup
+	log     *slog.Logger
+}
+
+// newAuditSink returns a sink posting records to an http(s) URL or
+// appending them to a file, or nil if no target is set. Records go through
+// the redactor, if any, like tool results do.
+func newAuditSink(target string, r *Redactor, log *slog.Logger) *auditSink {
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return nil
+	}
+	s := auditSink{
+		target: target,
+		redact: r,
+		queue:  make(chan []byte, auditQueueSize),
+		log:    log,
+	}
+	if strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://") {
+		s.write = postAuditRecord(target)
+	} else {
+		s.write = appendAuditRecord(target)
+	}
+	go s.run()
+
+	return &s
+}
+
+func (s *auditSink) run() {
+	for rec := range s.queue {
+		if err := s.write(rec); err != nil {
+			s.log.Warn("AI audit sink write failed", slogs.Path, s.target, slogs.Error, err)
+		}
+		s.pending.Done()
+	}
+}
+
+// record queues a turn for the sink, dropping it if the sink can't keep up.
+func (s *auditSink) record(r *auditRecord) {
+	if s == nil || r == nil {
+		return
+	}
+	r.mx.Lock()
+	raw, err := json.Marshal(r.redacted(s.redact))
+	r.mx.Unlock()
+	if err != nil {
+		s.log.Warn("AI audit record encoding failed", slogs.Error, err)
+		return
+	}
+	s.pending.Add(1)
+	select {
+	case s.queue <- raw:
+	default:
+		s.pending.Done()
+		s.log.Warn("AI audit sink is falling behind, record dropped", slogs.Path, s.target)
+	}
+}
+
+// flush waits up to grace for the queued records to be written.
+func (s *auditSink) flush(grace time.Duration) {
+	if s == nil {
+		return
+	}
+	done := make(chan struct{})
+	go func() {
+		s.pending.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(grace):
+		s.log.Warn("AI audit records still queued after grace period", slogs.Path, s.target)
+	}
+}
+
+// appendAuditRecord writes records as JSON lines to a file, only readable by
+// its owner since prompts may hold sensitive data.
+func appendAuditRecord(path string) func([]byte) error {
+	return func(rec []byte) error {
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(append(rec, '\n')); err != nil {
+			_ = f.Close()
+			return err
+		}
+
+		return f.Close()
+	}
+}
+
+// postAuditRecord posts records as JSON to a webhook.
+func postAuditRecord(url string) func([]byte) error {
+	cl := http.Client{Timeout: auditPostTimeout}
+
+	return func(rec []byte) error {
+		ctx, cancel := context.WithTimeout(context.Background(), auditPostTimeout)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(rec))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := cl.Do(req)
+		if err != nil {
+			return err
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode >= http.StatusMultipleChoices {
+			return fmt.Errorf("webhook returned %s", resp.Status)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/derailed/k9s/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAuditSink(t *testing.T) {
+	assert.Nil(t, newAuditSink(" ", nil, slog.Default()))
+	assert.Nil(t, NewAIClient(config.AI{}, nil).audit)
+
+	var s *auditSink
+	s.record(&auditRecord{Prompt: "hi"})
+	s.flush(time.Millisecond)
+}
+
+func TestAuditSinkFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "ai.jsonl")
+	s := newAuditSink(path, nil, slog.Default())
+
+	r := auditRecord{Prompt: "why is api down?"}
+	in, out := 120.0, 30.0
+	r.addToolCall("get_pod_diagnostics", map[string]any{"podName": "api"})
+	r.addUsage(&in, &out)
+	r.setResponse("It is OOMKilled.")
+	s.record(&r)
+	s.record(&auditRecord{Prompt: "thanks"})
+	s.flush(time.Second)
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	info, err := f.Stat()
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	var rr []map[string]any
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var m map[string]any
+		require.NoError(t, json.Unmarshal(sc.Bytes(), &m))
+		rr = append(rr, m)
+	}
+	require.Len(t, rr, 2)
+	assert.Equal(t, "why is api down?", rr[0]["prompt"])
+	assert.Equal(t, "It is OOMKilled.", rr[0]["response"])
+	assert.Equal(t, "get_pod_diagnostics", rr[0]["toolCalls"].([]any)[0].(map[string]any)["name"])
+	assert.Equal(t, map[string]any{"inputTokens": float64(120), "outputTokens": float64(30)}, rr[0]["usage"])
+	assert.Equal(t, "thanks", rr[1]["prompt"])
+}
+
+func TestAuditSinkWebhook(t *testing.T) {
+	got := make(chan []byte, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		raw, _ := io.ReadAll(r.Body)
+		got <- raw
+	}))
+	defer srv.Close()
+
+	s := newAuditSink(srv.URL, nil, slog.Default())
+	s.record(&auditRecord{Prompt: "hello"})
+	s.flush(time.Second)
+
+	var m map[string]any
+	require.NoError(t, json.Unmarshal(<-got, &m))
+	assert.Equal(t, "hello", m["prompt"])
+}
+
+func TestAuditSinkFailOpen(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	c := NewAIClient(config.AI{AuditSink: srv.URL}, nil)
+	s := newStreamingSession()
+	s.limit = 2
+	var l countingListener
+	require.NoError(t, c.sendTurn(context.Background(), s, "hello", &l))
+	assert.Equal(t, "xx", l.answer.Load())
+	c.audit.flush(time.Second)
+}
+
+func TestAuditSinkRedacts(t *testing.T) {
+	got := make(chan []byte, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		got <- raw
+	}))
+	defer srv.Close()
+
+	s := newAuditSink(srv.URL, NewRedactor(nil), slog.Default())
+	r := auditRecord{Prompt: "why does password=hunter2 fail?", Error: "auth failed: Bearer abcdef0123456789abcdef"}
+	args := map[string]any{
+		"name":    "db",
+		"patch":   `{"stringData":{"password":"hunter2"}}`,
+		"env":     []any{map[string]any{"API_TOKEN": "s3cr3t", "secretName": "db-creds"}},
+		"replica": float64(2),
+	}
+	r.addToolCall("patch_resource", args)
+	r.setResponse("The DSN is postgres://app:s3cr3t@db:5432/app.")
+	s.record(&r)
+	s.flush(time.Second)
+
+	var m map[string]any
+	require.NoError(t, json.Unmarshal(<-got, &m))
+	assert.Equal(t, "why does password=[REDACTED] fail?", m["prompt"])
+	assert.Equal(t, "auth failed: Bearer [REDACTED]", m["error"])
+	assert.Equal(t, "The DSN is postgres://app:[REDACTED]@db:5432/app.", m["response"])
+	assert.Equal(t, map[string]any{
+		"name":    "db",
+		"patch":   `{"stringData":{"password":[REDACTED]}}`,
+		"env":     []any{map[string]any{"API_TOKEN": "[REDACTED]", "secretName": "db-creds"}},
+		"replica": float64(2),
+	}, m["toolCalls"].([]any)[0].(map[string]any)["args"])
+	assert.Equal(t, "s3cr3t", args["env"].([]any)[0].(map[string]any)["API_TOKEN"])
+}
@@ -0,0 +1,249 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/derailed/k9s/internal/client"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// podPageSize is the number of pods fetched per page on live pod scans.
+const podPageSize = 500
+
+// coreEvGVR tracks core v1 events, which carry the involved object inline.
+var coreEvGVR = client.NewGVR("v1/events")
+
+// informerWatcher tells whether an informer already watches a resource,
+// without starting one.
+type informerWatcher interface {
+	IsWatching(gvr *client.GVR, ns string) bool
+}
+
+// cached reports whether reads of a resource can be served from the K9s
+// informer caches, so repeated queries during an incident don't add load on
+// the API server. Only informers K9s already runs are used: starting one for
+// a tool call would keep a cluster-wide watch open for the session. Tools
+// also expose a live flag to bypass the cache when freshness matters.
+func (tf *ToolFactory) cached(gvr *client.GVR, ns string) bool {
+	w, ok := tf.factory.(informerWatcher)
+
+	return ok && w.IsWatching(gvr, ns)
+}
+
+// getObject fetches a resource from the informer cache or, when live is set
+// or no informer watches it, straight from the API server.
+func (tf *ToolFactory) getObject(gvr *client.GVR, ns, name string, live bool) (runtime.Object, error) {
+	if !live && tf.cached(gvr, ns) {
+		return tf.factory.Get(gvr, client.FQN(ns, name), true, labels.Everything())
+	}
+	dial, err := tf.conn.DynDial()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to cluster: %w", err)
+	}
+	res := dial.Resource(gvr.GVR())
+	if ns != "" {
+		return res.Namespace(ns).Get(context.Background(), name, metav1.GetOptions{})
+	}
+
+	return res.Get(context.Background(), name, metav1.GetOptions{})
+}
+
+// listObjects lists resources from the informer cache or, when live is set
+// or no informer watches them, straight from the API server.
+func (tf *ToolFactory) listObjects(gvr *client.GVR, ns string, sel labels.Selector, live bool) ([]runtime.Object, error) {
+	if !live && tf.cached(gvr, ns) {
+		return tf.factory.List(gvr, ns, true, sel)
+	}
+	dial, err := tf.conn.DynDial()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to cluster: %w", err)
+	}
+	opts := metav1.ListOptions{LabelSelector: sel.String()}
+	var ll *unstructured.UnstructuredList
+	if ns == "" || client.IsAllNamespace(ns) {
+		ll, err = dial.Resource(gvr.GVR()).List(context.Background(), opts)
+	} else {
+		ll, err = dial.Resource(gvr.GVR()).Namespace(ns).List(context.Background(), opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	oo := make([]runtime.Object, 0, len(ll.Items))
+	for i := range ll.Items {
+		oo = append(oo, &ll.Items[i])
+	}
+
+	return oo, nil
+}
+
+// getPod returns a typed pod from the cache or the API server.
+func (tf *ToolFactory) getPod(ns, name string, live bool) (*corev1.Pod, error) {
+	if live || !tf.cached(client.PodGVR, ns) {
+		dial, err := tf.conn.Dial()
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to cluster: %w", err)
+		}
+		return dial.CoreV1().Pods(ns).Get(context.Background(), name, metav1.GetOptions{})
+	}
+	o, err := tf.factory.Get(client.PodGVR, client.FQN(ns, name), true, labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	var pod corev1.Pod
+	if err := fromUnstructured(o, &pod); err != nil {
+		return nil, err
+	}
+
+	return &pod, nil
+}
+
+// scanPods hands the pods of a namespace to fn one at a time, from the cache
+// or, when live is set, from the API server a page at a time so large
+// clusters are never listed at once. It stops after limit pods and returns
+// the number of pods scanned and whether some were left out.
+func (tf *ToolFactory) scanPods(ns string, live bool, limit int, fn func(*corev1.Pod)) (int, bool, error) {
+	if !live && tf.cached(client.PodGVR, ns) {
+		oo, err := tf.factory.List(client.PodGVR, ns, true, labels.Everything())
+		if err != nil {
+			return 0, false, err
+		}
+		for i, o := range oo[:min(len(oo), limit)] {
+			var pod corev1.Pod
+			if err := fromUnstructured(o, &pod); err != nil {
+				return i, false, err
+			}
+			fn(&pod)
+		}
+		return min(len(oo), limit), len(oo) > limit, nil
+	}
+
+	dial, err := tf.conn.Dial()
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to connect to cluster: %w", err)
+	}
+	var n int
+	opts := metav1.ListOptions{Limit: podPageSize}
+	for {
+		ll, err := dial.CoreV1().Pods(ns).List(context.Background(), opts)
+		if err != nil {
+			return n, false, err
+		}
+		for i := range ll.Items {
+			if n == limit {
+				return n, true, nil
+			}
+			fn(&ll.Items[i])
+			n++
+		}
+		if ll.Continue == "" {
+			return n, false, nil
+		}
+		opts.Continue = ll.Continue
+	}
+}
+
+// getNode returns a typed node from the cache or the API server.
+func (tf *ToolFactory) getNode(name string, live bool) (*corev1.Node, error) {
+	if live || !tf.cached(client.NodeGVR, client.ClusterScope) {
+		dial, err := tf.conn.Dial()
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to cluster: %w", err)
+		}
+		return dial.CoreV1().Nodes().Get(context.Background(), name, metav1.GetOptions{})
+	}
+	o, err := tf.factory.Get(client.NodeGVR, name, true, labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	var node corev1.Node
+	if err := fromUnstructured(o, &node); err != nil {
+		return nil, err
+	}
+
+	return &node, nil
+}
+
+// listNodes returns typed nodes from the cache or the API server.
+func (tf *ToolFactory) listNodes(live bool) ([]corev1.Node, error) {
+	if live || !tf.cached(client.NodeGVR, client.ClusterScope) {
+		dial, err := tf.conn.Dial()
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to cluster: %w", err)
+		}
+		ll, err := dial.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return ll.Items, nil
+	}
+
+	return listTyped[corev1.Node](tf, client.NodeGVR, client.ClusterScope)
+}
+
+// listEvents returns the events in a namespace, optionally only those about
+// the named object, from the cache or the API server.
+func (tf *ToolFactory) listEvents(ns, involved string, live bool) ([]corev1.Event, error) {
+	if live || !tf.cached(coreEvGVR, ns) {
+		dial, err := tf.conn.Dial()
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to cluster: %w", err)
+		}
+		var opts metav1.ListOptions
+		if involved != "" {
+			opts.FieldSelector = "involvedObject.name=" + involved
+		}
+		ll, err := dial.CoreV1().Events(ns).List(context.Background(), opts)
+		if err != nil {
+			return nil, err
+		}
+		return ll.Items, nil
+	}
+
+	ee, err := listTyped[corev1.Event](tf, coreEvGVR, ns)
+	if err != nil || involved == "" {
+		return ee, err
+	}
+	out := ee[:0]
+	for _, ev := range ee {
+		if ev.InvolvedObject.Name == involved {
+			out = append(out, ev)
+		}
+	}
+
+	return out, nil
+}
+
+// listTyped lists cached resources converted to their typed form.
+func listTyped[T any](tf *ToolFactory, gvr *client.GVR, ns string) ([]T, error) {
+	oo, err := tf.factory.List(gvr, ns, true, labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	tt := make([]T, 0, len(oo))
+	for _, o := range oo {
+		var t T
+		if err := fromUnstructured(o, &t); err != nil {
+			return nil, err
+		}
+		tt = append(tt, t)
+	}
+
+	return tt, nil
+}
+
+func fromUnstructured(o runtime.Object, v any) error {
+	u, ok := o.(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("expecting unstructured but got %T", o)
+	}
+
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, v)
+}
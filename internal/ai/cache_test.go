@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"testing"
+
+	"github.com/derailed/k9s/internal/client"
+	"github.com/derailed/k9s/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestListEventsCached(t *testing.T) {
+	f := newTestFactory()
+	f.add(coreEvGVR, &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "e1", Namespace: "ns1"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "p1"},
+		Reason:         "BackOff",
+	})
+	f.add(coreEvGVR, &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "e2", Namespace: "ns1"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "p2"},
+		Reason:         "Pulled",
+	})
+	tf := NewToolFactory(f, nil, config.AI{}, nil)
+
+	ee, err := tf.listEvents("ns1", "", false)
+	require.NoError(t, err)
+	assert.Len(t, ee, 2)
+
+	ee, err = tf.listEvents("ns1", "p1", false)
+	require.NoError(t, err)
+	require.Len(t, ee, 1)
+	assert.Equal(t, "BackOff", ee[0].Reason)
+}
+
+func TestGetPodCached(t *testing.T) {
+	f := newTestFactory()
+	f.add(client.PodGVR, &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "p1", Namespace: "ns1"},
+		Spec:       corev1.PodSpec{NodeName: "n1"},
+	})
+	tf := NewToolFactory(f, nil, config.AI{}, nil)
+
+	pod, err := tf.getPod("ns1", "p1", false)
+	require.NoError(t, err)
+	assert.Equal(t, "n1", pod.Spec.NodeName)
+
+	_, err = tf.getPod("ns1", "p2", false)
+	assert.Error(t, err)
+}
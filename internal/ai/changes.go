@@ -0,0 +1,230 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/derailed/k9s/internal/client"
+	copilot "github.com/github/copilot-sdk/go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// maxChangeWindow caps how far back recent_changes looks, in minutes.
+	maxChangeWindow = 24 * 60
+
+	// maxChangeScan caps the resources listed per kind by recent_changes.
+	maxChangeScan = 500
+
+	// maxChanges caps the changelog entries returned by recent_changes.
+	maxChanges = 300
+
+	// changesCaveat tells the model how far the changelog can be trusted.
+	changesCaveat = "Best effort, not an audit log: managedFields only keep the latest write of each manager, " +
+		"deletions leave no trace, status updates are skipped and events expire after about an hour. " +
+		"Use the API server audit log for a complete history."
+)
+
+var (
+	// changeGVRs are the resources scanned for recent changes: workloads and
+	// their configuration.
+	changeGVRs = []*client.GVR{
+		client.NewGVR("apps/v1/deployments"),
+		client.NewGVR("apps/v1/statefulsets"),
+		client.NewGVR("apps/v1/daemonsets"),
+		client.NewGVR("batch/v1/cronjobs"),
+		client.NewGVR("autoscaling/v2/horizontalpodautoscalers"),
+		client.NewGVR("v1/configmaps"),
+		client.NewGVR("v1/secrets"),
+		client.NewGVR("v1/services"),
+		client.NewGVR("networking.k8s.io/v1/ingresses"),
+	}
+
+	// changeEventKinds are the involved object kinds whose events record
+	// changes, e.g. rollouts scaling replica sets or autoscalers rescaling.
+	changeEventKinds = []string{
+		"Deployment", "StatefulSet", "DaemonSet", "ReplicaSet",
+		"CronJob", "HorizontalPodAutoscaler",
+	}
+)
+
+// --- recent_changes tool ---
+
+type recentChangesParams struct {
+	Namespace     string `json:"namespace,omitempty" jsonschema:"Namespace to scan. Empty means all namespaces"`
+	WindowMinutes int    `json:"windowMinutes,omitempty" jsonschema:"How far back to look, in minutes (default 60, max 1440)"`
+	Limit         int    `json:"limit,omitempty" jsonschema:"Maximum number of changes to return (default 100, max 300)"`
+}
+
+// changeEntry is a changelog line.
+type changeEntry struct {
+	Time     string   `json:"time"`
+	Source   string   `json:"source"`
+	Kind     string   `json:"kind"`
+	Resource string   `json:"resource"`
+	Change   string   `json:"change"`
+	Manager  string   `json:"manager,omitempty"`
+	Images   []string `json:"images,omitempty"`
+
+	at time.Time
+}
+
+func (tf *ToolFactory) recentChangesTool() copilot.Tool {
+	return copilot.DefineTool(
+		"recent_changes",
+		"List what changed recently, oldest first: workloads, ConfigMaps, Secrets, Services, Ingresses, autoscalers and CronJobs created or updated within a window, "+
+			"with the field manager that made the change, merged with rollout and rescale events. "+
+			"Use first after an unexpected incident to answer 'what changed in the last hour?'. Best effort, not an audit log.",
+		func(params recentChangesParams, inv copilot.ToolInvocation) (any, error) {
+			ns, err := tf.listNamespace(params.Namespace)
+			if err != nil {
+				return nil, err
+			}
+			window := params.WindowMinutes
+			if window <= 0 {
+				window = 60
+			}
+			window = min(window, maxChangeWindow)
+			limit := params.Limit
+			if limit <= 0 {
+				limit = 100
+			}
+			limit = min(limit, maxChanges)
+			since := time.Now().Add(-time.Duration(window) * time.Minute)
+
+			dyn, err := tf.conn.DynDial()
+			if err != nil {
+				return nil, fmt.Errorf("failed to connect to cluster: %w", err)
+			}
+			ctx, opts := context.Background(), metav1.ListOptions{Limit: maxChangeScan}
+
+			var (
+				cc              []changeEntry
+				errs, truncated []string
+			)
+			for _, gvr := range changeGVRs {
+				ll, err := dyn.Resource(gvr.GVR()).Namespace(ns).List(ctx, opts)
+				if err != nil {
+					errs = append(errs, gvr.R()+": "+err.Error())
+					continue
+				}
+				if ll.GetContinue() != "" {
+					truncated = append(truncated, gvr.R())
+				}
+				for i := range ll.Items {
+					if !tf.nsAllowed(ll.Items[i].GetNamespace()) {
+						continue
+					}
+					if c, ok := resourceChange(&ll.Items[i], since); ok {
+						c.Kind = cmp.Or(c.Kind, gvr.R())
+						cc = append(cc, c)
+					}
+				}
+			}
+
+			dial, err := tf.conn.Dial()
+			if err != nil {
+				return nil, fmt.Errorf("failed to connect to cluster: %w", err)
+			}
+			if events, err := dial.CoreV1().Events(ns).List(ctx, metav1.ListOptions{}); err != nil {
+				errs = append(errs, "events: "+err.Error())
+			} else {
+				for i := range events.Items {
+					ev := &events.Items[i]
+					at := eventTime(ev)
+					if at.Before(since) || !tf.nsAllowed(ev.Namespace) || !slices.Contains(changeEventKinds, ev.InvolvedObject.Kind) {
+						continue
+					}
+					cc = append(cc, changeEntry{
+						at:       at,
+						Source:   "event",
+						Kind:     ev.InvolvedObject.Kind,
+						Resource: client.FQN(ev.Namespace, ev.InvolvedObject.Name),
+						Change:   ev.Reason + ": " + ev.Message,
+					})
+				}
+			}
+
+			slices.SortStableFunc(cc, func(a, b changeEntry) int { return a.at.Compare(b.at) })
+			total := len(cc)
+			if total > limit {
+				cc = cc[total-limit:]
+			}
+			for i := range cc {
+				cc[i].Time = cc[i].at.UTC().Format(time.RFC3339)
+			}
+
+			result := map[string]any{
+				"window":      fmt.Sprintf("last %dm (since %s)", window, since.UTC().Format(time.RFC3339)),
+				"total":       total,
+				"changes":     cc,
+				"limitations": changesCaveat,
+			}
+			if total > limit {
+				result["truncated"] = fmt.Sprintf("showing the most recent %d of %d changes", limit, total)
+			}
+			if len(truncated) > 0 {
+				result["partialScan"] = fmt.Sprintf("only the first %d of each of %v were scanned, pass a namespace to narrow the scan", maxChangeScan, truncated)
+			}
+			if len(errs) > 0 {
+				result["errors"] = errs
+			}
+
+			return result, nil
+		},
+	)
+}
+
+// resourceChange returns the latest change of a resource if it happened
+// after since. Writes to subresources such as status are not changes.
+func resourceChange(o *unstructured.Unstructured, since time.Time) (changeEntry, bool) {
+	c := changeEntry{
+		at:       o.GetCreationTimestamp().Time,
+		Source:   "resource",
+		Kind:     o.GetKind(),
+		Resource: client.FQN(o.GetNamespace(), o.GetName()),
+		Change:   "created",
+	}
+	for _, mf := range o.GetManagedFields() {
+		if mf.Time == nil || mf.Subresource != "" {
+			continue
+		}
+		if c.Manager == "" {
+			c.Manager = mf.Manager
+		}
+		if mf.Time.After(c.at) {
+			c.at, c.Change, c.Manager = mf.Time.Time, "updated", mf.Manager
+		}
+	}
+	if c.at.Before(since) {
+		return changeEntry{}, false
+	}
+	c.Images = templateImages(o)
+
+	return c, true
+}
+
+// templateImages returns the container images of a workload's pod template.
+func templateImages(o *unstructured.Unstructured) []string {
+	cc, _, _ := unstructured.NestedSlice(o.Object, "spec", "template", "spec", "containers")
+	if len(cc) == 0 {
+		cc, _, _ = unstructured.NestedSlice(o.Object, "spec", "jobTemplate", "spec", "template", "spec", "containers")
+	}
+	var ii []string
+	for _, c := range cc {
+		if m, ok := c.(map[string]any); ok {
+			if img, ok := m["image"].(string); ok {
+				ii = append(ii, img)
+			}
+		}
+	}
+
+	return ii
+}
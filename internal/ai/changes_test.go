@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestResourceChange(t *testing.T) {
+	since := toolNow.Add(-time.Hour)
+	uu := map[string]struct {
+		created time.Time
+		mff     []metav1.ManagedFieldsEntry
+		ok      bool
+		change  string
+		manager string
+	}{
+		"created": {
+			created: toolNow.Add(-10 * time.Minute),
+			mff:     []metav1.ManagedFieldsEntry{managedBy("kubectl-create", "", toolNow.Add(-10*time.Minute))},
+			ok:      true,
+			change:  "created",
+			manager: "kubectl-create",
+		},
+		"updated": {
+			created: toolNow.Add(-48 * time.Hour),
+			mff: []metav1.ManagedFieldsEntry{
+				managedBy("helm", "", toolNow.Add(-48*time.Hour)),
+				managedBy("kubectl-edit", "", toolNow.Add(-5*time.Minute)),
+			},
+			ok:      true,
+			change:  "updated",
+			manager: "kubectl-edit",
+		},
+		"status-only": {
+			created: toolNow.Add(-48 * time.Hour),
+			mff: []metav1.ManagedFieldsEntry{
+				managedBy("helm", "", toolNow.Add(-48*time.Hour)),
+				managedBy("kube-controller-manager", "status", toolNow.Add(-time.Minute)),
+			},
+		},
+		"old": {
+			created: toolNow.Add(-48 * time.Hour),
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			o := &unstructured.Unstructured{}
+			o.SetNamespace("ns1")
+			o.SetName("fred")
+			o.SetCreationTimestamp(metav1.NewTime(u.created))
+			o.SetManagedFields(u.mff)
+
+			c, ok := resourceChange(o, since)
+			assert.Equal(t, u.ok, ok)
+			if !ok {
+				return
+			}
+			assert.Equal(t, "ns1/fred", c.Resource)
+			assert.Equal(t, u.change, c.Change)
+			assert.Equal(t, u.manager, c.Manager)
+		})
+	}
+}
+
+func TestTemplateImages(t *testing.T) {
+	o := &unstructured.Unstructured{Object: map[string]any{
+		"spec": map[string]any{
+			"jobTemplate": map[string]any{"spec": map[string]any{"template": map[string]any{"spec": map[string]any{
+				"containers": []any{map[string]any{"name": "c1", "image": "busybox:1.36"}},
+			}}}},
+		},
+	}}
+	assert.Equal(t, []string{"busybox:1.36"}, templateImages(o))
+	assert.Empty(t, templateImages(&unstructured.Unstructured{Object: map[string]any{}}))
+}
+
+func TestRecentChangesTool(t *testing.T) {
+	now := time.Now()
+	dp := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "ns1",
+			Name:              "web",
+			CreationTimestamp: metav1.NewTime(now.Add(-48 * time.Hour)),
+			ManagedFields:     []metav1.ManagedFieldsEntry{managedBy("kubectl-set", "", now.Add(-10*time.Minute))},
+		},
+		Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "web", Image: "nginx:1.27"}},
+		}}},
+	}
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Namespace:         "ns1",
+		Name:              "settings",
+		CreationTimestamp: metav1.NewTime(now.Add(-48 * time.Hour)),
+	}}
+	scaled := makeEvent("ns1", "e1", "web-5d8f", "Normal", "ScalingReplicaSet", now.Add(-9*time.Minute))
+	scaled.InvolvedObject.Kind = "Deployment"
+	scaled.Message = "Scaled up replica set web-5d8f to 1"
+	crash := makeEvent("ns1", "e2", "web-5d8f-abcde", "Warning", "BackOff", now.Add(-5*time.Minute))
+	tf := newTestToolFactory(newTestFactory(), newTestConn(dp, cm, scaled, crash))
+
+	m := callToolJSON(t, tf, "recent_changes", map[string]any{"namespace": "ns1"})
+	assert.Equal(t, float64(2), m["total"])
+	assert.Equal(t, changesCaveat, m["limitations"])
+	cc := m["changes"].([]any)
+	first, second := cc[0].(map[string]any), cc[1].(map[string]any)
+	assert.Equal(t, "ns1/web", first["resource"])
+	assert.Equal(t, "updated", first["change"])
+	assert.Equal(t, "kubectl-set", first["manager"])
+	assert.Equal(t, []any{"nginx:1.27"}, first["images"])
+	assert.Equal(t, "event", second["source"])
+	assert.Equal(t, "ScalingReplicaSet: Scaled up replica set web-5d8f to 1", second["change"])
+
+	m = callToolJSON(t, tf, "recent_changes", map[string]any{"namespace": "ns1", "limit": 1})
+	assert.Equal(t, float64(2), m["total"])
+	assert.Len(t, m["changes"], 1)
+	assert.Contains(t, m["truncated"], "1 of 2")
+}
+
+// Helpers...
+
+func managedBy(manager, subresource string, at time.Time) metav1.ManagedFieldsEntry {
+	return metav1.ManagedFieldsEntry{
+		Manager:     manager,
+		Operation:   metav1.ManagedFieldsOperationUpdate,
+		Subresource: subresource,
+		Time:        &metav1.Time{Time: at},
+	}
+}
@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+
+	copilot "github.com/github/copilot-sdk/go"
+)
+
+// CitationRX matches citation markers such as " [3]" in model answers. The
+// leading space keeps index expressions like items[0] out.
+var CitationRX = regexp.MustCompile(`\s\[(\d{1,3})\]`)
+
+// Source records a tool result the model may cite.
+type Source struct {
+	ID          int
+	Tool        string
+	Description string
+}
+
+// String returns a one-line rendition of the source.
+func (s Source) String() string {
+	return fmt.Sprintf("[%d] %s: %s", s.ID, s.Tool, s.Description)
+}
+
+// recordSource registers a tool result as citable and returns the hint
+// handed to the model along with the result.
+func (c *AIClient) recordSource(tool string, args map[string]any) string {
+	if !c.isClusterTool(tool) {
+		return ""
+	}
+
+	c.mx.Lock()
+	src := Source{ID: len(c.sources) + 1, Tool: tool, Description: FormatToolDescription(tool, args)}
+	c.sources = append(c.sources, src)
+	c.mx.Unlock()
+
+	return fmt.Sprintf("This result is source [%d]. Cite it as [%d] after claims based on it.", src.ID, src.ID)
+}
+
+// isClusterTool returns true for registered tools that return cluster data.
+func (c *AIClient) isClusterTool(name string) bool {
+	if name == "get_skill_playbook" || IsMutationTool(name) {
+		return false
+	}
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	return slices.ContainsFunc(c.allTools, func(t copilot.Tool) bool {
+		return t.Name == name
+	})
+}
+
+// TurnSources returns the sources recorded during the last turn.
+func (c *AIClient) TurnSources() []Source {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	return slices.Clone(c.sources[min(c.turnSources, len(c.sources)):])
+}
+
+// CitedIDs returns the distinct source ids cited in text, in order of appearance.
+func CitedIDs(text string) []int {
+	var ids []int
+	for _, m := range CitationRX.FindAllStringSubmatch(text, -1) {
+		id, err := strconv.Atoi(m[1])
+		if err != nil || id == 0 || slices.Contains(ids, id) {
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	return ids
+}
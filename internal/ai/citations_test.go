@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"testing"
+
+	"github.com/derailed/k9s/internal/config"
+	copilot "github.com/github/copilot-sdk/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCitedIDs(t *testing.T) {
+	uu := map[string]struct {
+		text string
+		e    []int
+	}{
+		"none": {
+			text: "All good.",
+		},
+		"many": {
+			text: "Pod is OOMKilled [2]. Limit is 128Mi [1]. Restarted 5 times [2].",
+			e:    []int{2, 1},
+		},
+		"zero": {
+			text: "list[0] is empty [3]",
+			e:    []int{3},
+		},
+		"not-numeric": {
+			text: "see [a] and [12345]",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, CitedIDs(u.text))
+		})
+	}
+}
+
+func TestRecordSource(t *testing.T) {
+	c := NewAIClient(config.AI{}, nil)
+	c.SetTools([]copilot.Tool{{Name: "get_resource"}, {Name: "patch_resource"}, {Name: "get_skill_playbook"}})
+
+	assert.Equal(t,
+		"This result is source [1]. Cite it as [1] after claims based on it.",
+		c.recordSource("get_resource", map[string]any{"gvr": "v1/pods", "name": "fred", "namespace": "ns1"}),
+	)
+	assert.Empty(t, c.recordSource("patch_resource", nil))
+	assert.Empty(t, c.recordSource("get_skill_playbook", nil))
+	assert.Empty(t, c.recordSource("report_intent", nil))
+
+	c.turnSources = 1
+	assert.Empty(t, c.TurnSources())
+	c.recordSource("get_resource", map[string]any{"gvr": "v1/pods", "name": "blee"})
+	assert.Equal(t, []Source{{ID: 2, Tool: "get_resource", Description: `Fetching pods "blee"`}}, c.TurnSources())
+	assert.Equal(t, `[2] get_resource: Fetching pods "blee"`, c.TurnSources()[0].String())
+
+	c.ResetSession()
+	assert.Empty(t, c.TurnSources())
+}
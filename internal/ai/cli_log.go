@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultCopilotLogLevel is the CLI server log level unless
+	// ai.copilotLogLevel says otherwise.
+	defaultCopilotLogLevel = "error"
+
+	// maxCLILogFiles caps the CLI server log files kept in the log dir. The
+	// CLI writes one file per server process.
+	maxCLILogFiles = 5
+
+	// cliLogTailLines is how many trailing log lines are echoed to the K9s
+	// log when the CLI server fails to start.
+	cliLogTailLines = 20
+)
+
+// copilotLogDir returns the directory the CLI server writes its logs to.
+func copilotLogDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		base = os.TempDir()
+	}
+	dir := filepath.Join(base, cacheDirName, "logs")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("creating log dir: %w", err)
+	}
+
+	return dir, nil
+}
+
+// cliLogs returns the log files in dir, most recent first.
+func cliLogs(dir string) []string {
+	ee, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	type logFile struct {
+		path string
+		mod  time.Time
+	}
+	ll := make([]logFile, 0, len(ee))
+	for _, e := range ee {
+		if !e.Type().IsRegular() || filepath.Ext(e.Name()) != ".log" {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		ll = append(ll, logFile{path: filepath.Join(dir, e.Name()), mod: info.ModTime()})
+	}
+	slices.SortFunc(ll, func(a, b logFile) int {
+		return b.mod.Compare(a.mod)
+	})
+
+	pp := make([]string, 0, len(ll))
+	for _, l := range ll {
+		pp = append(pp, l.path)
+	}
+
+	return pp
+}
+
+// pruneCLILogs removes all but the keep most recent log files in dir.
+func pruneCLILogs(dir string, keep int) {
+	ll := cliLogs(dir)
+	for _, l := range ll[min(keep, len(ll)):] {
+		_ = os.Remove(l)
+	}
+}
+
+// latestCLILog returns the most recent log file in dir, if any.
+func latestCLILog(dir string) string {
+	if ll := cliLogs(dir); len(ll) > 0 {
+		return ll[0]
+	}
+
+	return ""
+}
+
+// tailLines returns up to n trailing non-blank lines of a file.
+func tailLines(path string, n int) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var ll []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if l := strings.TrimSpace(scanner.Text()); l != "" {
+			ll = append(ll, l)
+		}
+	}
+
+	return ll[max(0, len(ll)-n):]
+}
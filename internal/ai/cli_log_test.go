@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeCLILog(t *testing.T, dir, name, body string, age time.Duration) string {
+	t.Helper()
+
+	p := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(p, []byte(body), 0o600))
+	at := time.Now().Add(-age)
+	require.NoError(t, os.Chtimes(p, at, at))
+
+	return p
+}
+
+func TestPruneCLILogs(t *testing.T) {
+	dir := t.TempDir()
+	oldest := writeCLILog(t, dir, "a.log", "", 3*time.Hour)
+	older := writeCLILog(t, dir, "b.log", "", 2*time.Hour)
+	newest := writeCLILog(t, dir, "c.log", "", time.Hour)
+	other := writeCLILog(t, dir, "notes.txt", "", 4*time.Hour)
+
+	assert.Equal(t, newest, latestCLILog(dir))
+
+	pruneCLILogs(dir, 1)
+	assert.NoFileExists(t, oldest)
+	assert.NoFileExists(t, older)
+	assert.FileExists(t, newest)
+	assert.FileExists(t, other)
+
+	pruneCLILogs(dir, 5)
+	assert.FileExists(t, newest)
+}
+
+func TestLatestCLILogMissingDir(t *testing.T) {
+	assert.Empty(t, latestCLILog(""))
+	assert.Empty(t, latestCLILog(filepath.Join(t.TempDir(), "nope")))
+}
+
+func TestTailLines(t *testing.T) {
+	dir := t.TempDir()
+	var b strings.Builder
+	for _, l := range []string{"one", "", "two", "three", "four"} {
+		b.WriteString(l + "\n")
+	}
+	p := writeCLILog(t, dir, "a.log", b.String(), 0)
+
+	assert.Equal(t, []string{"three", "four"}, tailLines(p, 2))
+	assert.Equal(t, []string{"one", "two", "three", "four"}, tailLines(p, 10))
+	assert.Empty(t, tailLines(filepath.Join(dir, "nope.log"), 2))
+}
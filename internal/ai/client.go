@@ -4,17 +4,22 @@
 package ai
 
 import (
+	"cmp"
 	"context"
 	"embed"
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/derailed/k9s/internal/config"
 	"github.com/derailed/k9s/internal/slogs"
 	copilot "github.com/github/copilot-sdk/go"
+	"github.com/github/copilot-sdk/go/rpc"
 )
 
 //go:embed skills/diagnostics/SKILL.md skills/security/SKILL.md skills/optimization/SKILL.md skills/observation/SKILL.md
@@ -49,6 +54,17 @@ type Listener interface {
 	AIToolStart(toolName string)
 	// AIToolComplete is called when a tool finishes executing.
 	AIToolComplete(toolName string)
+	// AIToolBudgetExceeded is called once per turn when the tool call budget is spent.
+	AIToolBudgetExceeded(limit int)
+	// AIResponseTruncated is called after AIResponseComplete when the response
+	// was cut off by the output limit and can be continued.
+	AIResponseTruncated()
+	// AIStreamUnavailable is called when the provider doesn't stream events,
+	// the response then only arrives with AIResponseComplete.
+	AIStreamUnavailable()
+	// AIRetrying is called before a turn that failed with a transient error
+	// is resent. attempt counts from 1 up to max.
+	AIRetrying(attempt, max int, err error)
 }
 
 // ToolActivityFunc is called when a tool starts execution, for UI display.
@@ -56,33 +72,125 @@ type Listener interface {
 type ToolActivityFunc func(toolName, description string, isMutation bool)
 
 // ApprovalFunc is called for mutation tools. It must block until the user
-// decides. Returns true to allow the mutation, false to deny.
-type ApprovalFunc func(toolName, description string, args map[string]any) bool
+// decides. When confirm is non-empty the user must type it to approve.
+// Returns true to allow the mutation, false to deny.
+type ApprovalFunc func(toolName, description string, args map[string]any, confirm string) bool
 
 // Client manages the GitHub Copilot SDK lifecycle.
 var Client *AIClient
 
+// stopGracePeriod bounds how long Stop waits for in-flight requests to
+// unwind before tearing down the session.
+const stopGracePeriod = 2 * time.Second
+
+// streamStallTimeout is how long a turn may go without streamed events
+// before the provider is deemed not to stream.
+const streamStallTimeout = 15 * time.Second
+
+// modelsTTL is how long the model list is served from cache.
+const modelsTTL = 5 * time.Minute
+
 // ModelInfo describes an available model.
 type ModelInfo struct {
 	ID   string
 	Name string
+	// Preview is set for models offered as a preview.
+	Preview bool
+	// Deprecated is set for models being retired.
+	Deprecated bool
+	// Disabled is set when the account policy doesn't enable the model.
+	Disabled bool
+	// Multiplier is the premium request multiplier, 0 when unknown.
+	Multiplier float64
+	// ContextWindow is the max context window in tokens, 0 when unknown.
+	ContextWindow int
+	// MaxOutput is the max output tokens per response, 0 when unknown.
+	MaxOutput int
+	// Vision is set for models accepting images.
+	Vision bool
+	// ReasoningEfforts lists the supported reasoning efforts, if any.
+	ReasoningEfforts []string
+}
+
+// newModelInfo converts a model description from the models.list RPC.
+func newModelInfo(m *rpc.Model) ModelInfo {
+	mi := ModelInfo{
+		ID:               m.ID,
+		Name:             m.Name,
+		ContextWindow:    int(m.Capabilities.Limits.MaxContextWindowTokens),
+		Vision:           m.Capabilities.Supports.Vision != nil && *m.Capabilities.Supports.Vision,
+		ReasoningEfforts: m.SupportedReasoningEfforts,
+	}
+	if n := m.Capabilities.Limits.MaxOutputTokens; n != nil {
+		mi.MaxOutput = int(*n)
+	}
+	switch modelLabel(m.Name) {
+	case "preview":
+		mi.Preview = true
+	case "deprecated":
+		mi.Deprecated = true
+	}
+	if m.Policy != nil {
+		mi.Disabled = m.Policy.State != "" && m.Policy.State != "enabled"
+	}
+	if m.Billing != nil {
+		mi.Multiplier = m.Billing.Multiplier
+	}
+
+	return mi
+}
+
+// modelLabel returns the lowercased release label the server appends to a
+// model's display name, e.g. preview for "GPT-5 (Preview)". The model list
+// carries no other lifecycle metadata.
+func modelLabel(name string) string {
+	name = strings.TrimSpace(name)
+	if !strings.HasSuffix(name, ")") {
+		return ""
+	}
+	i := strings.LastIndex(name, " (")
+	if i < 0 {
+		return ""
+	}
+
+	return strings.ToLower(name[i+2 : len(name)-1])
 }
 
 // AIClient wraps the Copilot SDK client with k9s-specific configuration.
 type AIClient struct {
-	client          *copilot.Client
-	session         *copilot.Session
-	cfg             config.AI
-	tools           []copilot.Tool
-	allTools        []copilot.Tool
-	skills          *SkillRegistry
-	initialized     bool
-	approvalFn      ApprovalFunc
-	toolActivityFn  ToolActivityFunc
-	planPresented   bool // set after first mutation denied; persists across turns
-	autoApprove     bool // set when user responds after a plan; mutations auto-allowed
-	mx              sync.RWMutex
-	log             *slog.Logger
+	client         *copilot.Client
+	session        liveSession
+	leases         map[liveSession]int // Sends using each session
+	cfg            config.AI
+	tools          []copilot.Tool
+	allTools       []copilot.Tool
+	skills         *SkillRegistry
+	kindSkill      string // skill picked by ai.skillByKind for the chat scope
+	initialized    bool
+	approvalFn     ApprovalFunc
+	toolActivityFn ToolActivityFunc
+	intentFn       IntentFunc
+	preflightFn    PreflightFunc
+	planPresented  bool         // set after first mutation denied; persists across turns
+	autoApprove    bool         // set when user responds after a plan; mutations auto-allowed
+	planSteps      []IntentStep // mutating steps left on a plan approved via report_intent
+	cliPath        string
+	cliLogDir      string // where the CLI server writes its logs
+	usage          TokenUsage
+	toolCalls      int          // tool calls made during the current turn
+	turnListener   Listener     // listener for the turn in flight, if any
+	turnAudit      *auditRecord // audit record of the turn in flight, if any
+	unstreamed     bool         // the provider answered a turn without streaming deltas
+	audit          *auditSink   // mirrors turns to ai.auditSink, if set
+	fingerprintFn  func(context.Context) string
+	sources        []Source              // citable tool results for the current session
+	turnSources    int                   // index of the first source of the current turn
+	notes          scratchpad            // notes the model keeps for the session
+	inflight       map[*request]struct{} // requests Stop must cancel
+	models         []ModelInfo           // cached model list
+	modelsAt       time.Time             // when models was fetched
+	mx             sync.RWMutex
+	log            *slog.Logger
 }
 
 // NewAIClient creates a new AI client instance.
@@ -90,10 +198,14 @@ func NewAIClient(cfg config.AI, logger *slog.Logger) *AIClient {
 	if logger == nil {
 		logger = slog.Default()
 	}
+	skills := NewSkillRegistry()
+	skills.LoadCustomSkills(cfg.CustomSkills, logger)
+
 	return &AIClient{
 		cfg:    cfg,
 		log:    logger,
-		skills: NewSkillRegistry(),
+		skills: skills,
+		audit:  newAuditSink(cfg.AuditSink, NewRedactor(cfg.Redaction), logger),
 	}
 }
 
@@ -121,12 +233,22 @@ func (c *AIClient) Init(ctx context.Context) error {
 	c.log.Info("🤖 Initializing AI/Copilot integration...")
 
 	opts := &copilot.ClientOptions{
-		LogLevel: "error",
+		LogLevel: cmp.Or(c.cfg.CopilotLogLevel, defaultCopilotLogLevel),
+	}
+
+	// Keep the CLI server logs around so SDK-side failures can be diagnosed.
+	if dir, err := copilotLogDir(); err != nil {
+		c.log.Warn("Cannot determine log dir for copilot CLI", slogs.Error, err)
+	} else {
+		pruneCLILogs(dir, maxCLILogFiles-1)
+		opts.CLIArgs = []string{"--log-dir", dir}
+		c.cliLogDir = dir
 	}
 
 	// Resolve the copilot CLI binary (check PATH, cache, or auto-download).
-	if cliPath := ResolveCopilotCLIPath(c.log); cliPath != "" {
+	if cliPath := ResolveCopilotCLIPath(c.log, c.cfg.NpmRegistry); cliPath != "" {
 		opts.CLIPath = cliPath
+		c.cliPath = cliPath
 	}
 
 	// Wire GitHub authentication.
@@ -137,6 +259,12 @@ func (c *AIClient) Init(ctx context.Context) error {
 	c.client = copilot.NewClient(opts)
 	if err := c.client.Start(ctx); err != nil {
 		c.log.Error("Failed to start Copilot CLI server", slogs.Error, err)
+		if l := latestCLILog(c.cliLogDir); l != "" {
+			for _, line := range tailLines(l, cliLogTailLines) {
+				c.log.Error("Copilot CLI", "log", line)
+			}
+			return fmt.Errorf("copilot init failed: %w (see CLI log %s)", err, l)
+		}
 		return fmt.Errorf("copilot init failed: %w", err)
 	}
 
@@ -146,20 +274,22 @@ func (c *AIClient) Init(ctx context.Context) error {
 	return nil
 }
 
-// Stop shuts down the Copilot SDK client gracefully.
+// Stop shuts down the Copilot SDK client gracefully. In-flight requests are
+// canceled and given a grace period to finish before the session goes away.
 func (c *AIClient) Stop() {
+	c.cancelRequests(stopGracePeriod)
+	c.audit.flush(stopGracePeriod)
+
 	c.mx.Lock()
 	defer c.mx.Unlock()
 
-	if c.session != nil {
-		_ = c.session.Destroy()
-		c.session = nil
-	}
+	c.dropSession()
 	if c.client != nil {
 		_ = c.client.Stop()
 		c.client = nil
 	}
 	c.initialized = false
+	c.models = nil
 	c.log.Info("AI/Copilot integration stopped")
 }
 
@@ -168,8 +298,17 @@ func (c *AIClient) SetTools(tools []copilot.Tool) {
 	c.mx.Lock()
 	defer c.mx.Unlock()
 
+	c.skills.CheckCustomTools(tools, c.log)
 	c.allTools = tools
-	c.tools = c.skills.FilterTools(c.cfg.ActiveSkill, tools)
+	c.tools = c.skills.FilterTools(c.activeSkill(), tools)
+}
+
+// SetFingerprinter registers a callback describing the cluster environment.
+// Its output is appended to the system message of new sessions.
+func (c *AIClient) SetFingerprinter(fn func(context.Context) string) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	c.fingerprintFn = fn
 }
 
 // Skills returns the skill registry.
@@ -191,27 +330,43 @@ func (c *AIClient) SetToolActivityFunc(fn ToolActivityFunc) {
 	c.toolActivityFn = fn
 }
 
+// SetPreflight registers a check run on mutation tool calls before the user
+// is asked to approve them.
+func (c *AIClient) SetPreflight(fn PreflightFunc) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	c.preflightFn = fn
+}
+
+// SetIntentFunc registers a callback that shows plans declared with
+// report_intent.
+func (c *AIClient) SetIntentFunc(fn IntentFunc) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	c.intentFn = fn
+}
+
 // ResetCallbacks clears all UI callbacks.
 func (c *AIClient) ResetCallbacks() {
 	c.mx.Lock()
 	defer c.mx.Unlock()
 	c.approvalFn = nil
 	c.toolActivityFn = nil
+	c.intentFn = nil
 }
 
-// SetSkill switches the active skill and refilters tools.
+// SetSkill switches the active skill and refilters tools. An explicit choice
+// overrides the skill picked for the chat scope.
 func (c *AIClient) SetSkill(name string) {
 	c.mx.Lock()
 	defer c.mx.Unlock()
 
 	c.cfg.ActiveSkill = name
+	c.kindSkill = ""
 	c.tools = c.skills.FilterTools(name, c.allTools)
 
-	// Destroy current session so next Send() creates one with new skill context.
-	if c.session != nil {
-		_ = c.session.Destroy()
-		c.session = nil
-	}
+	// Drop current session so next Send() creates one with new skill context.
+	c.dropSession()
 }
 
 // ActiveSkill returns the currently active skill name (empty = all tools).
@@ -219,7 +374,33 @@ func (c *AIClient) ActiveSkill() string {
 	c.mx.RLock()
 	defer c.mx.RUnlock()
 
-	return c.cfg.ActiveSkill
+	return c.activeSkill()
+}
+
+// SelectSkillFor activates the skill ai.skillByKind maps to a resource kind,
+// falling back to the globally active skill when there is no mapping. The
+// session is only reset when the effective skill changes.
+func (c *AIClient) SelectSkillFor(kind string) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	skill := c.cfg.SkillFor(kind)
+	if _, ok := c.skills.Get(skill); skill != "" && !ok {
+		c.log.Warn("Ignoring unknown AI skill in skillByKind", "kind", kind, "skill", skill)
+		skill = ""
+	}
+	prev := c.activeSkill()
+	c.kindSkill = skill
+	if c.activeSkill() == prev {
+		return
+	}
+	c.tools = c.skills.FilterTools(c.activeSkill(), c.allTools)
+	c.dropSession()
+}
+
+// activeSkill returns the effective skill. Callers must hold c.mx.
+func (c *AIClient) activeSkill() string {
+	return cmp.Or(c.kindSkill, c.cfg.ActiveSkill)
 }
 
 // SetModel switches the active model and resets the session.
@@ -228,10 +409,39 @@ func (c *AIClient) SetModel(model string) {
 	defer c.mx.Unlock()
 
 	c.cfg.Model = model
-	if c.session != nil {
-		_ = c.session.Destroy()
-		c.session = nil
+	c.unstreamed = false
+	c.dropSession()
+}
+
+// SetAudience switches the audience answers are written for and resets the
+// session.
+func (c *AIClient) SetAudience(audience string) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.cfg.Audience = audience
+	c.dropSession()
+}
+
+// Audience returns the audience answers are written for.
+func (c *AIClient) Audience() string {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	return cmp.Or(c.cfg.Audience, config.AIAudienceSRE)
+}
+
+// outputLimit returns the max output tokens of the active model, 0 when the
+// model list wasn't loaded or doesn't say.
+func (c *AIClient) outputLimit() int {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	if i := slices.IndexFunc(c.models, func(m ModelInfo) bool { return m.ID == c.cfg.Model }); i >= 0 {
+		return c.models[i].MaxOutput
 	}
+
+	return 0
 }
 
 // ActiveModel returns the currently active model name.
@@ -243,7 +453,22 @@ func (c *AIClient) ActiveModel() string {
 }
 
 // ListModels returns the models available from the user's Copilot account.
+// The list is cached for modelsTTL.
 func (c *AIClient) ListModels(ctx context.Context) ([]ModelInfo, error) {
+	c.mx.RLock()
+	mm, at := c.models, c.modelsAt
+	c.mx.RUnlock()
+	if mm != nil && time.Since(at) < modelsTTL {
+		return slices.Clone(mm), nil
+	}
+
+	return c.RefreshModels(ctx)
+}
+
+// RefreshModels fetches the model list, bypassing the cache. The SDK caches
+// its ListModels result for the life of the client, so the models.list RPC
+// is called directly.
+func (c *AIClient) RefreshModels(ctx context.Context) ([]ModelInfo, error) {
 	// Lazy retry: if Init() failed before, try again now.
 	if !c.isInitialized() {
 		if err := c.Init(ctx); err != nil {
@@ -252,26 +477,32 @@ func (c *AIClient) ListModels(ctx context.Context) ([]ModelInfo, error) {
 	}
 
 	c.mx.RLock()
-	defer c.mx.RUnlock()
-
-	if !c.initialized || c.client == nil {
+	cl := c.client
+	if !c.initialized {
+		cl = nil
+	}
+	c.mx.RUnlock()
+	if cl == nil {
 		return nil, fmt.Errorf("AI client not initialized")
 	}
 
-	models, err := c.client.ListModels(ctx)
+	if cl.RPC == nil {
+		return nil, fmt.Errorf("AI client not connected")
+	}
+	res, err := cl.RPC.Models.List(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list models: %w", err)
 	}
 
-	var result []ModelInfo
-	for _, m := range models {
-		result = append(result, ModelInfo{
-			ID:   m.ID,
-			Name: m.Name,
-		})
+	result := make([]ModelInfo, 0, len(res.Models))
+	for i := range res.Models {
+		result = append(result, newModelInfo(&res.Models[i]))
 	}
+	c.mx.Lock()
+	c.models, c.modelsAt = result, time.Now()
+	c.mx.Unlock()
 
-	return result, nil
+	return slices.Clone(result), nil
 }
 
 // createSession creates a new Copilot session with k9s system message and tools.
@@ -279,28 +510,68 @@ func (c *AIClient) createSession(ctx context.Context) (*copilot.Session, error)
 	if !c.initialized || c.client == nil {
 		return nil, fmt.Errorf("AI client not initialized")
 	}
+	if err := c.cfg.ProviderError(); err != nil {
+		return nil, fmt.Errorf("invalid AI provider config: %w", err)
+	}
 
 	systemMsg := k9sSystemMessage()
+	if sfx := c.skills.SystemMessageSuffix(c.activeSkill()); sfx != "" {
+		systemMsg += "\n\n" + sfx
+	}
+	if g := audienceGuidance(c.cfg.Audience); g != "" {
+		systemMsg += "\n\n" + g
+	}
+	if c.fingerprintFn != nil {
+		if fp := c.fingerprintFn(ctx); fp != "" {
+			systemMsg += "\n\n" + fp
+		}
+	}
 	sessionCfg := &copilot.SessionConfig{
 		Model:               c.cfg.Model,
-		Streaming:            c.cfg.Streaming,
-		Tools:               c.tools,
+		Streaming:           c.cfg.Streaming,
+		Tools:               append(append(slices.Clone(c.tools), c.reportIntentTool()), c.noteTools()...),
 		OnPermissionRequest: copilot.PermissionHandler.ApproveAll,
 		SystemMessage: &copilot.SystemMessageConfig{
 			Content: systemMsg,
 		},
 		InfiniteSessions: &copilot.InfiniteSessionConfig{
-			Enabled:                        copilot.Bool(true),
-			BackgroundCompactionThreshold:  copilot.Float64(0.80),
-			BufferExhaustionThreshold:      copilot.Float64(0.95),
+			Enabled:                       copilot.Bool(true),
+			BackgroundCompactionThreshold: copilot.Float64(0.80),
+			BufferExhaustionThreshold:     copilot.Float64(0.95),
 		},
 		Hooks: &copilot.SessionHooks{
 			OnPreToolUse: func(input copilot.PreToolUseHookInput, inv copilot.HookInvocation) (*copilot.PreToolUseHookOutput, error) {
 				c.log.Debug("Tool invoked", "tool", input.ToolName)
 
+				// Enforce the per-turn tool call budget so a looping model
+				// can't keep the turn alive forever.
+				c.mx.Lock()
+				c.toolCalls++
+				calls, limit, l := c.toolCalls, c.cfg.ToolCallBudget(), c.turnListener
+				c.mx.Unlock()
+				if calls > limit {
+					if calls == limit+1 {
+						c.log.Warn("Tool call budget exhausted", "limit", limit, "tool", input.ToolName)
+						if l != nil {
+							l.AIToolBudgetExceeded(limit)
+						}
+					}
+					return &copilot.PreToolUseHookOutput{
+						PermissionDecision: "deny",
+						PermissionDecisionReason: fmt.Sprintf(
+							"DENIED: the tool call budget of %d for this turn is spent. Do NOT call any more tools. "+
+								"Answer the user now with the information gathered so far and mention what could not be checked.",
+							limit,
+						),
+					}, nil
+				}
+
 				args, _ := input.ToolArgs.(map[string]any)
+				c.mx.RLock()
+				c.turnAudit.addToolCall(input.ToolName, args)
+				c.mx.RUnlock()
 				desc := FormatToolDescription(input.ToolName, args)
-				mutation := IsMutationTool(input.ToolName)
+				mutation := IsMutationCall(input.ToolName, args)
 
 				// Notify UI of tool activity (step display).
 				// For mutation tools, defer notification until after approval.
@@ -308,7 +579,8 @@ func (c *AIClient) createSession(ctx context.Context) (*copilot.Session, error)
 				actFn := c.toolActivityFn
 				c.mx.RUnlock()
 
-				if !mutation && actFn != nil {
+				// Plans render as their own block.
+				if !mutation && actFn != nil && input.ToolName != "report_intent" {
 					actFn(input.ToolName, desc, mutation)
 				}
 
@@ -317,21 +589,58 @@ func (c *AIClient) createSession(ctx context.Context) (*copilot.Session, error)
 				// Flow:
 				//  Turn 1 – model tries mutation → denied, must explain plan.
 				//  Turn 2 – user says "apply" → autoApprove=true → mutations allowed.
+				// A plan approved via report_intent allows the calls matching its
+				// mutating steps upfront.
 				if mutation {
 					c.mx.RLock()
-					auto := c.autoApprove
+					auto := c.autoApprove || c.planStep(input.ToolName, args) >= 0
 					planned := c.planPresented
+					preflight := c.preflightFn
 					c.mx.RUnlock()
 
-					// User already confirmed after seeing the plan → auto-allow.
+					// Don't ask for approval of a write the user isn't allowed to make.
+					if preflight != nil {
+						if err := preflight(input.ToolName, args); err != nil {
+							c.log.Info("Mutation denied — RBAC preflight", "tool", input.ToolName, slogs.Error, err)
+							return &copilot.PreToolUseHookOutput{
+								PermissionDecision: "deny",
+								PermissionDecisionReason: fmt.Sprintf(
+									"DENIED: %s. The write was NOT attempted. Do NOT retry. Tell the user which permission is missing.", err,
+								),
+							}, nil
+						}
+					}
+
+					// User already confirmed after seeing the plan → auto-allow,
+					// unless the confirm policy demands a typed confirmation.
 					if auto {
+						if confirm, ok := c.typedConfirmFor(input.ToolName, args); ok {
+							c.mx.RLock()
+							apprFn := c.approvalFn
+							c.mx.RUnlock()
+							if apprFn == nil || !apprFn(input.ToolName, desc, args, confirm) {
+								c.mx.Lock()
+								c.autoApprove, c.planSteps = false, nil
+								c.mx.Unlock()
+								c.log.Info("Mutation denied — typed confirmation not given", "tool", input.ToolName)
+								return &copilot.PreToolUseHookOutput{
+									PermissionDecision:       "deny",
+									PermissionDecisionReason: "DENIED by the user. Policy requires typing the resource name to confirm and the user declined. Do NOT retry unless the user asks again.",
+								}, nil
+							}
+						}
 						if actFn != nil {
 							actFn(input.ToolName, desc, mutation)
 						}
 						// Reset autoApprove now that a mutation has been consumed.
-						// Next mutation will require a new plan cycle.
+						// Next mutation will require a new plan cycle, unless it
+						// matches another step of an approved plan.
 						c.mx.Lock()
-						c.autoApprove = false
+						if i := c.planStep(input.ToolName, args); i >= 0 {
+							c.planSteps = slices.Delete(c.planSteps, i, i+1)
+						} else {
+							c.autoApprove = false
+						}
 						c.mx.Unlock()
 						c.log.Info("Mutation auto-approved (user confirmed after plan)", "tool", input.ToolName)
 						return &copilot.PreToolUseHookOutput{
@@ -348,11 +657,11 @@ func (c *AIClient) createSession(ctx context.Context) (*copilot.Session, error)
 						c.mx.Unlock()
 						c.log.Info("Mutation deferred — asking model to present plan first", "tool", input.ToolName)
 						return &copilot.PreToolUseHookOutput{
-							PermissionDecision:       "deny",
+							PermissionDecision: "deny",
 							PermissionDecisionReason: fmt.Sprintf(
 								"DENIED (by design). Present your plan to the user: explain what %s will do (resource, namespace, changes). "+
-								"Ask the user to confirm. After confirmation, call %s again with the same arguments — it will succeed. "+
-								"Do NOT interpret this as an error. Do NOT suggest kubectl commands. Do NOT use report_intent.",
+									"Ask the user to confirm. After confirmation, call %s again with the same arguments — it will succeed. "+
+									"Do NOT interpret this as an error. Do NOT suggest kubectl commands.",
 								input.ToolName, input.ToolName,
 							),
 						}, nil
@@ -362,10 +671,10 @@ func (c *AIClient) createSession(ctx context.Context) (*copilot.Session, error)
 					// without waiting for user confirmation → deny again.
 					c.log.Info("Mutation denied — waiting for user confirmation", "tool", input.ToolName)
 					return &copilot.PreToolUseHookOutput{
-						PermissionDecision:       "deny",
+						PermissionDecision: "deny",
 						PermissionDecisionReason: fmt.Sprintf(
 							"DENIED. You already presented the plan. Stop and wait for the user to reply. "+
-								"When the user confirms, call %s again. Do NOT retry now. Do NOT use report_intent.",
+								"When the user confirms, call %s again. Do NOT retry now.",
 							input.ToolName,
 						),
 					}, nil
@@ -378,7 +687,10 @@ func (c *AIClient) createSession(ctx context.Context) (*copilot.Session, error)
 			},
 			OnPostToolUse: func(input copilot.PostToolUseHookInput, inv copilot.HookInvocation) (*copilot.PostToolUseHookOutput, error) {
 				c.log.Debug("Tool completed", "tool", input.ToolName)
-				return &copilot.PostToolUseHookOutput{}, nil
+				args, _ := input.ToolArgs.(map[string]any)
+				return &copilot.PostToolUseHookOutput{
+					AdditionalContext: c.recordSource(input.ToolName, args),
+				}, nil
 			},
 			OnErrorOccurred: func() func(copilot.ErrorOccurredHookInput, copilot.HookInvocation) (*copilot.ErrorOccurredHookOutput, error) {
 				retries := 0
@@ -398,33 +710,15 @@ func (c *AIClient) createSession(ctx context.Context) (*copilot.Session, error)
 		},
 	}
 
-	// Apply reasoning effort if configured.
-	if c.cfg.ReasoningEffort != "" {
+	// Apply reasoning effort if configured, the active skill's first.
+	if s, ok := c.skills.Get(c.activeSkill()); ok && s.ReasoningEffort != "" {
+		sessionCfg.ReasoningEffort = s.ReasoningEffort
+	} else if c.cfg.ReasoningEffort != "" {
 		sessionCfg.ReasoningEffort = c.cfg.ReasoningEffort
 	}
 
 	// Configure BYOK provider if specified.
-	if c.cfg.Provider != nil && c.cfg.Provider.BaseURL != "" {
-		prov := &copilot.ProviderConfig{
-			Type:    c.cfg.Provider.Type,
-			BaseURL: c.cfg.Provider.BaseURL,
-		}
-		if key := c.cfg.Provider.ResolveAPIKey(); key != "" {
-			prov.APIKey = key
-		}
-		if token := c.cfg.Provider.ResolveBearerToken(); token != "" {
-			prov.BearerToken = token
-		}
-		if c.cfg.Provider.WireAPI != "" {
-			prov.WireApi = c.cfg.Provider.WireAPI
-		}
-		if c.cfg.Provider.Azure != nil && c.cfg.Provider.Azure.APIVersion != "" {
-			prov.Azure = &copilot.AzureProviderOptions{
-				APIVersion: c.cfg.Provider.Azure.APIVersion,
-			}
-		}
-		sessionCfg.Provider = prov
-	}
+	sessionCfg.Provider = c.providerConfig()
 
 	session, err := c.client.CreateSession(ctx, sessionCfg)
 	if err != nil {
@@ -443,22 +737,133 @@ func (c *AIClient) createSession(ctx context.Context) (*copilot.Session, error)
 	return session, nil
 }
 
-// EnsureSession returns the current session or creates a new one.
-func (c *AIClient) EnsureSession(ctx context.Context) (*copilot.Session, error) {
+// providerConfig returns the BYOK provider settings or nil when using Copilot.
+func (c *AIClient) providerConfig() *copilot.ProviderConfig {
+	if !c.cfg.IsBYOK() {
+		return nil
+	}
+	prov := &copilot.ProviderConfig{
+		Type:    c.cfg.Provider.Type,
+		BaseURL: c.cfg.Provider.BaseURL,
+	}
+	if key := c.cfg.Provider.ResolveAPIKey(); key != "" {
+		prov.APIKey = key
+	}
+	if token := c.cfg.Provider.ResolveBearerToken(); token != "" {
+		prov.BearerToken = token
+	}
+	if c.cfg.Provider.WireAPI != "" {
+		prov.WireApi = c.cfg.Provider.WireAPI
+	}
+	if c.cfg.Provider.Azure != nil && c.cfg.Provider.Azure.APIVersion != "" {
+		prov.Azure = &copilot.AzureProviderOptions{
+			APIVersion: c.cfg.Provider.Azure.APIVersion,
+		}
+	}
+
+	return prov
+}
+
+// typedConfirmFor returns the text the user must type to approve a mutation
+// when the confirm policy matches it. The text is the target's name, or the
+// tool name when the target has none, so a match never degrades to a plain
+// approval.
+func (c *AIClient) typedConfirmFor(toolName string, args map[string]any) (string, bool) {
+	_, ns, name := mutationTarget(toolName, args)
+	if argNS, _ := args["namespace"].(string); ns == "" {
+		ns = argNS
+	}
+	replicas := -1
+	if r, ok := args["replicas"].(float64); ok {
+		replicas = int(r)
+	}
+
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+	if !c.cfg.RequiresTypedConfirm(toolName, ns, name, replicas) {
+		return "", false
+	}
+
+	return cmp.Or(name, toolName), true
+}
+
+// liveSession is a Copilot session owned by the client.
+type liveSession interface {
+	chatSession
+	Abort(context.Context) error
+	Destroy() error
+}
+
+// acquireSession returns the current session, creating one if needed, and
+// leases it to the caller until the returned func is called. A leased session
+// dropped by a model or skill switch stays alive until its last lease is
+// released, so a Send never loses its session mid-request.
+func (c *AIClient) acquireSession(ctx context.Context) (liveSession, func(), error) {
 	c.mx.Lock()
 	defer c.mx.Unlock()
 
-	if c.session != nil {
-		return c.session, nil
+	if c.session == nil {
+		session, err := c.createSession(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		c.session = session
+		c.usage = TokenUsage{}
+	}
+	s := c.session
+	if c.leases == nil {
+		c.leases = make(map[liveSession]int)
+	}
+	c.leases[s]++
+
+	return s, func() { c.releaseSession(s) }, nil
+}
+
+// releaseSession ends a lease, destroying the session if it was dropped
+// while leased.
+func (c *AIClient) releaseSession(s liveSession) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if c.leases[s]--; c.leases[s] > 0 {
+		return
+	}
+	delete(c.leases, s)
+	if s != c.session {
+		_ = s.Destroy()
+	}
+}
+
+// dropSession detaches the current session so the next Send creates a fresh
+// one. The session is destroyed now unless a Send still holds it, in which
+// case the last releaseSession does. Callers must hold c.mx.
+func (c *AIClient) dropSession() {
+	if c.session == nil {
+		return
+	}
+	if c.leases[c.session] == 0 {
+		_ = c.session.Destroy()
 	}
+	c.session = nil
+}
 
-	session, err := c.createSession(ctx)
+// Prewarm initializes the client and creates the chat session ahead of the
+// first question. Tools should be set beforehand since sessions pick them up
+// when created.
+func (c *AIClient) Prewarm(ctx context.Context) error {
+	if !c.IsEnabled() {
+		return nil
+	}
+	if err := c.Init(ctx); err != nil {
+		return err
+	}
+	_, release, err := c.acquireSession(ctx)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	c.session = session
+	release()
 
-	return session, nil
+	return nil
 }
 
 // isInitialized returns true if the AI client has been successfully initialized.
@@ -481,14 +886,76 @@ func (c *AIClient) Send(ctx context.Context, prompt string, listener Listener) e
 		}
 	}
 
-	// Apply a generous timeout so we never hang indefinitely.
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	// Bound the turn so we never hang indefinitely.
+	d := c.cfg.RequestTimeout()
+	ctx, cancel := context.WithTimeoutCause(ctx, d, &timeoutError{after: d})
 	defer cancel()
 
-	session, err := c.EnsureSession(ctx)
+	session, release, err := c.acquireSession(ctx)
 	if err != nil {
 		return err
 	}
+	defer release()
+
+	return c.sendTurn(ctx, session, prompt, listener)
+}
+
+// ask sends a one-off prompt on a throwaway session with no tools, leaving
+// the chat session alone, and returns the answer.
+func (c *AIClient) ask(ctx context.Context, prompt string) (string, error) {
+	c.mx.RLock()
+	cl := c.client
+	cfg := &copilot.SessionConfig{
+		Model:               c.cfg.Model,
+		Provider:            c.providerConfig(),
+		OnPermissionRequest: copilot.PermissionHandler.ApproveAll,
+	}
+	c.mx.RUnlock()
+	if cl == nil {
+		return "", fmt.Errorf("AI client not initialized")
+	}
+
+	session, err := cl.CreateSession(ctx, cfg)
+	if err != nil {
+		return "", fmt.Errorf("session creation failed: %w", err)
+	}
+	defer func() { _ = session.Destroy() }()
+
+	resp, err := session.SendAndWait(ctx, copilot.MessageOptions{Prompt: prompt})
+	if err != nil {
+		return "", err
+	}
+	if resp == nil || resp.Data.Content == nil {
+		return "", nil
+	}
+
+	return *resp.Data.Content, nil
+}
+
+// chatSession is the part of a Copilot session used to run a turn.
+type chatSession interface {
+	On(copilot.SessionEventHandler) func()
+	Send(context.Context, copilot.MessageOptions) (string, error)
+	SendAndWait(context.Context, copilot.MessageOptions) (*copilot.SessionEvent, error)
+}
+
+// sendTurn runs a single prompt on the session and streams the response to
+// the listener. The turn is canceled if the client stops mid-flight.
+func (c *AIClient) sendTurn(ctx context.Context, session chatSession, prompt string, listener Listener) (err error) {
+	ctx, done := c.trackRequest(ctx, session)
+	defer done()
+
+	var audit *auditRecord
+	if c.audit != nil {
+		audit = &auditRecord{Start: time.Now(), Model: c.ActiveModel(), Prompt: prompt}
+		defer func() {
+			audit.End = time.Now()
+			if err != nil {
+				audit.Error = err.Error()
+			}
+			c.audit.record(audit)
+		}()
+	}
 
 	// If the model presented a mutation plan in a previous turn and the user
 	// is now responding, treat this turn as user-approved (no dialog needed).
@@ -498,22 +965,38 @@ func (c *AIClient) Send(ctx context.Context, prompt string, listener Listener) e
 		c.autoApprove = true
 		c.planPresented = false
 	}
+	c.toolCalls, c.turnListener, c.turnAudit = 0, listener, audit
+	c.planSteps = nil
+	c.turnSources = len(c.sources)
 	c.mx.Unlock()
+	defer func() {
+		c.mx.Lock()
+		c.turnListener, c.turnAudit = nil, nil
+		c.mx.Unlock()
+	}()
 
 	listener.AIResponseStart()
 
+	var (
+		cutOff         atomic.Bool
+		deltas, active atomic.Int64
+		maxOutput      = c.outputLimit()
+	)
 	// Subscribe to events for live activity display (tools, reasoning, deltas).
-	// The response itself is captured reliably via SendAndWait below.
+	// The response itself is captured by sendPrompt below.
 	unsubscribe := session.On(func(event copilot.SessionEvent) {
 		c.log.Debug("Session event", "type", string(event.Type))
 
 		switch event.Type {
 		case copilot.AssistantMessageDelta, copilot.AssistantStreamingDelta:
 			if event.Data.DeltaContent != nil {
+				deltas.Add(1)
+				active.Add(1)
 				listener.AIResponseDelta(*event.Data.DeltaContent)
 			}
 		case copilot.AssistantReasoningDelta:
 			if event.Data.DeltaContent != nil {
+				active.Add(1)
 				listener.AIReasoningDelta(*event.Data.DeltaContent)
 			}
 		case copilot.AssistantReasoning:
@@ -522,6 +1005,7 @@ func (c *AIClient) Send(ctx context.Context, prompt string, listener Listener) e
 			}
 		case copilot.ToolExecutionStart:
 			if event.Data.ToolName != nil {
+				active.Add(1)
 				c.log.Debug("Tool start", "tool", *event.Data.ToolName)
 				listener.AIToolStart(*event.Data.ToolName)
 			}
@@ -530,6 +1014,13 @@ func (c *AIClient) Send(ctx context.Context, prompt string, listener Listener) e
 				c.log.Debug("Tool complete", "tool", *event.Data.ToolName)
 				listener.AIToolComplete(*event.Data.ToolName)
 			}
+		case copilot.AssistantUsage:
+			c.recordUsage(event.Data.InputTokens, event.Data.OutputTokens)
+			// The last model request decides whether the answer was cut off.
+			if out := event.Data.OutputTokens; out != nil {
+				cutOff.Store(isCutOff(int(*out), maxOutput))
+			}
+			audit.addUsage(event.Data.InputTokens, event.Data.OutputTokens)
 		case copilot.SessionError:
 			if event.Data.Message != nil {
 				c.log.Error("Session error event", "msg", *event.Data.Message)
@@ -537,13 +1028,28 @@ func (c *AIClient) Send(ctx context.Context, prompt string, listener Listener) e
 		}
 	})
 	defer unsubscribe()
+	if stop := c.watchStream(listener, &active); stop != nil {
+		defer stop()
+	}
 
-	c.log.Debug("Sending prompt via SendAndWait", "len", len(prompt))
-	response, err := session.SendAndWait(ctx, copilot.MessageOptions{
-		Prompt: prompt,
-	})
+	c.log.Debug("Sending prompt", "mode", cmp.Or(c.cfg.SendMode, config.AISendWait), "len", len(prompt))
+	response, err := c.sendWithRetry(ctx, session, prompt, listener, &active)
 	if err != nil {
-		c.log.Error("SendAndWait failed", "error", err)
+		if errors.Is(context.Cause(ctx), errClientStopped) {
+			// The client is shutting down, don't notify a listener that may be gone.
+			return fmt.Errorf("AI request canceled: %w", err)
+		}
+		if errors.Is(context.Cause(ctx), ErrInterrupted) {
+			// The user cut the turn short on purpose, this is not a failure.
+			return ErrInterrupted
+		}
+		var te *timeoutError
+		if errors.As(context.Cause(ctx), &te) {
+			c.log.Error("Send timed out", "timeout", te.after)
+			listener.AIResponseFailed(te)
+			return te
+		}
+		c.log.Error("Send failed", "error", err)
 		listener.AIResponseFailed(fmt.Errorf("AI request failed: %w", err))
 		return err
 	}
@@ -552,32 +1058,161 @@ func (c *AIClient) Send(ctx context.Context, prompt string, listener Listener) e
 	if response != nil && response.Data.Content != nil {
 		content = *response.Data.Content
 	}
-	c.log.Debug("SendAndWait completed", "hasContent", content != "", "contentLen", len(content))
+	c.log.Debug("Send completed", "hasContent", content != "", "contentLen", len(content))
+	if strings.TrimSpace(content) == "" {
+		c.mx.RLock()
+		calls := c.toolCalls
+		c.mx.RUnlock()
+		c.log.Warn("AI turn ended without a text response", "toolCalls", calls)
+	}
+	if content != "" && deltas.Load() == 0 {
+		c.markUnstreamed()
+	}
+	audit.setResponse(content)
 	listener.AIResponseComplete(content)
+	if cutOff.Load() || hasOpenCodeBlock(content) {
+		c.log.Debug("Response truncated", "contentLen", len(content))
+		listener.AIResponseTruncated()
+	}
 
 	return nil
 }
 
+// watchStream tells the listener when the provider doesn't stream: right
+// away once a turn was answered without deltas, or when a turn gets no
+// streamed event for streamStallTimeout. The returned func stops the watch.
+func (c *AIClient) watchStream(listener Listener, active *atomic.Int64) func() {
+	c.mx.RLock()
+	streaming, unstreamed := c.cfg.Streaming, c.unstreamed
+	c.mx.RUnlock()
+	if !streaming {
+		return nil
+	}
+	if unstreamed {
+		listener.AIStreamUnavailable()
+		return nil
+	}
+	t := time.AfterFunc(streamStallTimeout, func() {
+		if active.Load() > 0 {
+			return
+		}
+		c.log.Info("No streamed events from the AI provider, waiting for the full response", "timeout", streamStallTimeout)
+		listener.AIStreamUnavailable()
+	})
+
+	return func() { t.Stop() }
+}
+
+// markUnstreamed records that the provider answers without streaming deltas
+// so the next turns don't wait on them.
+func (c *AIClient) markUnstreamed() {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if !c.unstreamed && c.cfg.Streaming {
+		c.log.Warn("AI provider sent no streaming deltas, falling back to single-shot rendering", "model", c.cfg.Model)
+	}
+	c.unstreamed = c.cfg.Streaming
+}
+
+// errClientStopped is the cancellation cause of requests interrupted by Stop.
+var errClientStopped = errors.New("AI client stopped")
+
+// timeoutError is the cancellation cause of turns running past
+// ai.requestTimeoutSeconds.
+type timeoutError struct {
+	after time.Duration
+}
+
+func (e *timeoutError) Error() string {
+	return fmt.Sprintf("AI request timed out after %ds, raise ai.requestTimeoutSeconds for slow models", int(e.after.Seconds()))
+}
+
+// request tracks an in-flight Send so Stop can cancel and wait for it.
+type request struct {
+	cancel context.CancelCauseFunc
+	done   chan struct{}
+	// session is the session leased for the turn, which may no longer be
+	// the client's current one after a model or skill switch.
+	session chatSession
+}
+
+// trackRequest registers an in-flight request on session. The returned
+// context is canceled by Stop and the returned func must be called once the
+// request completes.
+func (c *AIClient) trackRequest(ctx context.Context, session chatSession) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	r := request{cancel: cancel, done: make(chan struct{}), session: session}
+
+	c.mx.Lock()
+	if c.inflight == nil {
+		c.inflight = make(map[*request]struct{})
+	}
+	c.inflight[&r] = struct{}{}
+	c.mx.Unlock()
+
+	return ctx, func() {
+		c.mx.Lock()
+		delete(c.inflight, &r)
+		c.mx.Unlock()
+		cancel(nil)
+		close(r.done)
+	}
+}
+
+// cancelRequests cancels all in-flight requests and waits up to grace for
+// them to complete.
+func (c *AIClient) cancelRequests(grace time.Duration) {
+	c.mx.Lock()
+	rr := make([]*request, 0, len(c.inflight))
+	for r := range c.inflight {
+		r.cancel(errClientStopped)
+		rr = append(rr, r)
+	}
+	c.mx.Unlock()
+
+	timeout := time.NewTimer(grace)
+	defer timeout.Stop()
+	for _, r := range rr {
+		select {
+		case <-r.done:
+		case <-timeout.C:
+			c.log.Warn("AI requests still in flight after stop grace period")
+			return
+		}
+	}
+}
+
 // ResetSession destroys the current session so a fresh one is created next time.
 func (c *AIClient) ResetSession() {
 	c.mx.Lock()
 	defer c.mx.Unlock()
 
-	if c.session != nil {
-		_ = c.session.Destroy()
-		c.session = nil
-	}
+	c.dropSession()
+	c.usage = TokenUsage{}
+	c.sources, c.turnSources = nil, 0
+	c.notes.clear()
 }
 
 // IsMutationTool returns true if the named tool modifies cluster resources.
 func IsMutationTool(name string) bool {
 	switch name {
-	case "patch_resource", "scale_resource", "restart_resource", "delete_resource":
+	case "patch_resource", "apply_manifest", "scale_resource", "restart_resource", "delete_resource", "exec_command":
 		return true
 	}
 	return false
 }
 
+// IsMutationCall returns true if a tool invocation modifies cluster resources.
+// Unlike IsMutationTool it inspects the arguments of run_kubectl.
+func IsMutationCall(name string, args map[string]any) bool {
+	if name == "run_kubectl" {
+		return isMutatingKubectl(args)
+	}
+
+	return IsMutationTool(name)
+}
+
 // FormatToolDescription builds a human-readable one-liner for a tool invocation.
 func FormatToolDescription(toolName string, args map[string]any) string {
 	getStr := func(key string) string {
@@ -604,6 +1239,15 @@ func FormatToolDescription(toolName string, args map[string]any) string {
 			return fmt.Sprintf("Fetching %s %q%s", resType, name, inNs)
 		}
 		return fmt.Sprintf("Fetching %s%s", resType, inNs)
+	case "get_secret":
+		return fmt.Sprintf("Fetching secret %q%s (values hidden)", name, inNs)
+	case "get_configmap":
+		return fmt.Sprintf("Fetching configmap %q%s", name, inNs)
+	case "get_helm_release":
+		if name != "" {
+			return fmt.Sprintf("Fetching helm release %q%s", name, inNs)
+		}
+		return fmt.Sprintf("Listing helm releases%s", inNs)
 	case "list_resources":
 		if sel := getStr("labelSelector"); sel != "" {
 			return fmt.Sprintf("Listing %s%s (selector: %s)", resType, inNs, sel)
@@ -620,26 +1264,102 @@ func FormatToolDescription(toolName string, args map[string]any) string {
 		if prev, ok := args["previous"].(bool); ok && prev {
 			desc += " [previous]"
 		}
+		if prefix, ok := args["prefix"].(bool); ok && prefix && getStr("container") == "" {
+			desc += " [all containers]"
+		}
+		if secs, ok := args["sinceSeconds"].(float64); ok && secs > 0 {
+			desc += fmt.Sprintf(" [last %s]", time.Duration(secs)*time.Second)
+		} else if t := getStr("sinceTime"); t != "" {
+			desc += " [since " + t + "]"
+		}
 		return desc
 	case "get_events":
 		if rn := getStr("resourceName"); rn != "" {
 			return fmt.Sprintf("Checking events for %q%s", rn, inNs)
 		}
 		return fmt.Sprintf("Checking events%s", inNs)
+	case "watch_events":
+		secs := defaultWatchSeconds
+		if d, ok := args["durationSeconds"].(float64); ok && d > 0 {
+			secs = min(int(d), maxWatchSeconds)
+		}
+		if rn := getStr("resourceName"); rn != "" {
+			return fmt.Sprintf("Watching events for %q%s for %ds", rn, inNs, secs)
+		}
+		return fmt.Sprintf("Watching events%s for %ds", inNs, secs)
 	case "get_cluster_health":
 		return "Checking cluster health"
+	case "top_pods":
+		return fmt.Sprintf("Finding top pods by %s%s", cmp.Or(getStr("sortBy"), "cpu"), inNs)
+	case "top_nodes":
+		return fmt.Sprintf("Finding top nodes by %s", cmp.Or(getStr("sortBy"), "cpu"))
 	case "get_pod_diagnostics":
 		return fmt.Sprintf("Running diagnostics on pod %q%s", getStr("podName"), inNs)
+	case "get_node_diagnostics":
+		return fmt.Sprintf("Running diagnostics on node %q", getStr("nodeName"))
+	case "get_workload_summary":
+		return fmt.Sprintf("Summarizing %s %q%s", getStr("kind"), name, inNs)
+	case "diagnose_scheduling":
+		return fmt.Sprintf("Diagnosing scheduling for pod %q%s", getStr("podName"), inNs)
+	case "get_pdb_status":
+		if kind := getStr("kind"); kind != "" {
+			return fmt.Sprintf("Checking disruption budgets for %s %q%s", kind, name, inNs)
+		}
+		return fmt.Sprintf("Checking disruption budgets%s", inNs)
+	case "get_admission_context":
+		if op := getStr("operation"); op != "" {
+			return fmt.Sprintf("Checking admission webhooks for %s %s%s", strings.ToUpper(op), resType, inNs)
+		}
+		return fmt.Sprintf("Checking admission webhooks for %s%s", resType, inNs)
+	case "validate_manifest":
+		return "Validating manifest with a server-side dry-run"
+	case "get_incident_timeline":
+		return fmt.Sprintf("Building incident timeline for pod %q%s", getStr("podName"), inNs)
+	case "find_references":
+		if all, ok := args["allNamespaces"].(bool); ok && all {
+			return fmt.Sprintf("Finding references to %s %q in all namespaces", getStr("kind"), name)
+		}
+		return fmt.Sprintf("Finding references to %s %q%s", getStr("kind"), name, inNs)
+	case "compare_resources":
+		ref := func(k string) string {
+			m, _ := args[k].(map[string]any)
+			n, _ := m["name"].(string)
+			if ns, _ := m["namespace"].(string); ns != "" {
+				n = ns + "/" + n
+			}
+			g, _ := m["gvr"].(string)
+			return fmt.Sprintf("%s %q", extractResourceType(g), n)
+		}
+		return fmt.Sprintf("Comparing %s with %s", ref("source"), ref("target"))
 	case "check_rbac":
 		return fmt.Sprintf("Checking RBAC: can %s %s%s", getStr("verb"), getStr("resource"), inNs)
+	case "run_kubectl":
+		return fmt.Sprintf("Running kubectl %s", strings.TrimPrefix(getStr("command"), "kubectl "))
 	case "patch_resource":
 		return fmt.Sprintf("Patching %s %q%s", resType, name, inNs)
+	case "apply_manifest":
+		o, err := decodeSingleManifest(getStr("manifest"))
+		if err != nil {
+			return "Applying manifest"
+		}
+		if ns := o.GetNamespace(); ns != "" {
+			inNs = fmt.Sprintf(" in namespace %q", ns)
+		}
+		return fmt.Sprintf("Applying %s %q%s", o.GetKind(), o.GetName(), inNs)
 	case "scale_resource":
 		return fmt.Sprintf("Scaling %s %q to %v replicas%s", resType, name, args["replicas"], inNs)
 	case "restart_resource":
 		return fmt.Sprintf("Restarting %s %q%s", resType, name, inNs)
 	case "delete_resource":
 		return fmt.Sprintf("Deleting %s %q%s", resType, name, inNs)
+	case "exec_command":
+		var cmd []string
+		if aa, ok := args["command"].([]any); ok {
+			for _, a := range aa {
+				cmd = append(cmd, fmt.Sprint(a))
+			}
+		}
+		return fmt.Sprintf("Running %q in pod %q%s", strings.Join(cmd, " "), getStr("podName"), inNs)
 	default:
 		return fmt.Sprintf("Running %s", toolName)
 	}
@@ -653,10 +1373,31 @@ func extractResourceType(gvr string) string {
 	return parts[len(parts)-1]
 }
 
+// audienceGuidance returns the system message section tuning answers for an
+// audience. The base system message already targets SREs.
+func audienceGuidance(audience string) string {
+	switch audience {
+	case config.AIAudienceBeginner:
+		return `Audience:
+The reader is new to Kubernetes. Overrides "Be concise" above.
+Explain the concepts behind your findings (e.g. what a ReplicaSet or a liveness probe is) in a sentence or two.
+Spell out what each suggested command or change does and why it helps. Avoid unexplained jargon and abbreviations.`
+	case config.AIAudienceExec:
+		return `Audience:
+The reader is a manager who needs the big picture, not the mechanics. Overrides the answer style above.
+Lead with a one-line status (healthy, degraded or down), then the user-facing impact, the cause in plain language and the next step with who should own it.
+Keep it to a few short bullets. Leave out commands, YAML and resource names unless asked.`
+	}
+
+	return ""
+}
+
 func k9sSystemMessage() string {
 	return `You are an expert Kubernetes cluster assistant in K9s, a terminal UI.
 You have read-only tools and mutation tools.
 Use GVR format: 'apps/v1/deployments', 'v1/pods', 'batch/v1/jobs', etc.
+Read Secrets with get_secret and ConfigMaps with get_configmap rather than get_resource: get_secret never exposes secret values, only their keys, lengths and digests.
+Resources labeled app.kubernetes.io/managed-by=Helm belong to a Helm release, check it with get_helm_release and suggest chart value changes rather than direct edits.
 
 Skill playbooks (load via get_skill_playbook):
 - diagnostics: CrashLoopBackOff, OOMKilled, ImagePullBackOff, Pending, ConfigError
@@ -667,10 +1408,13 @@ Load the relevant playbook FIRST when diagnosing, auditing, or optimizing.
 
 Mutation tools (the ONLY tools that modify the cluster):
 - patch_resource: apply a strategic merge patch
+- apply_manifest: create or update a resource from a manifest (server-side apply)
 - scale_resource: change replica count
 - restart_resource: rolling restart
 - delete_resource: delete a resource
-These are the ONLY tools you should use to make changes. Do NOT use report_intent or any other tool to make or announce mutations.
+- exec_command: run an allowlisted command in a container, only registered when the user enables it
+These are the ONLY tools you should use to make changes. report_intent only declares a plan, it never makes changes.
+run_kubectl is an escape hatch for read-only data the other tools don't cover (e.g. get --raw /metrics). Mutating kubectl verbs go through the same approval flow; prefer the tools above.
 
 IMPORTANT — Mutation approval flow:
 Your FIRST call to a mutation tool in a conversation will be AUTOMATICALLY DENIED.
@@ -681,6 +1425,13 @@ This is by design, NOT an error. When the first mutation is denied:
 When the user confirms (says yes/apply/go ahead/etc.), call the SAME mutation tool again with the SAME arguments. It will succeed.
 Do NOT interpret the first denial as a real error. Do NOT suggest manual kubectl commands after a denial.
 
+Plans:
+Before a multi-step run or any mutation, call report_intent with your goal and the steps you intend to take, marking the mutating ones. The plan is shown to the user.
+If report_intent says the user APPROVED the plan, call its mutation tools directly; they will not be denied. If the user REJECTED it, stop and ask how to proceed.
+
+Notes:
+During long investigations, save key findings with note_write (e.g. exit codes, timestamps, suspect resources) and recall them with note_read instead of fetching the same data again.
+
 Workflow:
 1. Investigate with read-only tools. Keep calls minimal.
 2. Present findings: root cause, state, fix options.
@@ -688,5 +1439,18 @@ Workflow:
 4. When user asks for a fix: call the mutation tool directly. It will be denied. Present your plan.
 5. After user confirms: call the same mutation tool again to apply.
 
+Citations:
+Each tool result is tagged with a source number. After a key claim (status, reason, value, count) based on a tool result, append its marker, e.g. "The container was OOMKilled [2]."
+Only cite numbers you were given. Do not cite claims that come from general knowledge.
+
+Prefetched context:
+A question may start with a [PREFETCHED CONTEXT] block fetched when the chat opened. Use it instead of repeating those tool calls, and call tools again when you need fresher or deeper data. It is not a tool result, so do not cite it.
+
+Resumed conversations:
+A question may start with a [RESUMED CONVERSATION] block replaying an earlier conversation the user reopened. Treat it as what was said so far; its findings may be stale, so check the cluster again before relying on them. Do not cite it.
+
+Attached manifests:
+A question may start with an [ATTACHED MANIFEST] block holding a local file the user plans to apply. It is not in the cluster yet. Review it and call validate_manifest with its content to check it against the API server before judging it valid. Do not cite it.
+
 Be concise. Use bullet points. Flag security concerns.`
 }
@@ -0,0 +1,649 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/derailed/k9s/internal/config"
+	copilot "github.com/github/copilot-sdk/go"
+	"github.com/github/copilot-sdk/go/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStopCancelsStreamingSend(t *testing.T) {
+	c := NewAIClient(config.AI{}, nil)
+	s := newStreamingSession()
+	var l countingListener
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- c.sendTurn(context.Background(), s, "hello", &l)
+	}()
+	<-s.streaming
+
+	c.Stop()
+
+	select {
+	case err := <-errs:
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(stopGracePeriod):
+		t.Fatal("send did not return after stop")
+	}
+	assert.Positive(t, l.deltas.Load())
+	assert.Zero(t, l.failed.Load())
+
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+	assert.Empty(t, c.inflight)
+}
+
+func TestStopWithoutRequests(t *testing.T) {
+	c := NewAIClient(config.AI{}, nil)
+	c.Stop()
+
+	s := newStreamingSession()
+	s.limit = 3
+	var l countingListener
+	require.NoError(t, c.sendTurn(context.Background(), s, "hello", &l))
+	assert.Equal(t, int64(3), l.deltas.Load())
+}
+
+func TestInterruptKeepsSession(t *testing.T) {
+	c := NewAIClient(config.AI{}, nil)
+	assert.False(t, c.Interrupt())
+
+	s := newStreamingSession()
+	var l countingListener
+	errs := make(chan error, 1)
+	go func() {
+		errs <- c.sendTurn(context.Background(), s, "hello", &l)
+	}()
+	<-s.streaming
+
+	assert.True(t, c.Interrupt())
+	select {
+	case err := <-errs:
+		assert.ErrorIs(t, err, ErrInterrupted)
+	case <-time.After(stopGracePeriod):
+		t.Fatal("send did not return after interrupt")
+	}
+	assert.Zero(t, l.failed.Load())
+
+	// The client stays usable for the correction.
+	s = newStreamingSession()
+	s.limit = 1
+	require.NoError(t, c.sendTurn(context.Background(), s, CorrectionPrompt("check the db pod"), &l))
+}
+
+func TestSendTimeout(t *testing.T) {
+	c := NewAIClient(config.AI{}, nil)
+	s := newStreamingSession()
+	var l countingListener
+
+	ctx, cancel := context.WithTimeoutCause(context.Background(), 50*time.Millisecond, &timeoutError{after: 90 * time.Second})
+	defer cancel()
+	err := c.sendTurn(ctx, s, "hello", &l)
+	require.EqualError(t, err, "AI request timed out after 90s, raise ai.requestTimeoutSeconds for slow models")
+	assert.Equal(t, int64(1), l.failed.Load())
+	assert.Zero(t, l.retries.Load())
+}
+
+func TestSwitchModelDuringSend(t *testing.T) {
+	c := NewAIClient(config.AI{}, nil)
+	c.initialized = true
+	s := &ownedSession{streamingSession: newStreamingSession()}
+	s.limit = 50
+	c.session = s
+
+	var l countingListener
+	errs := make(chan error, 1)
+	go func() {
+		errs <- c.Send(context.Background(), "hello", &l)
+	}()
+	<-s.streaming
+
+	var wg sync.WaitGroup
+	for _, m := range []string{"gpt-5", "claude-sonnet-4.5", "gpt-5"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.SetModel(m)
+			c.SetSkill("diagnostics")
+		}()
+	}
+	wg.Wait()
+	assert.Zero(t, s.destroyed.Load(), "session destroyed mid-send")
+
+	require.NoError(t, <-errs)
+	assert.Equal(t, int64(50), l.deltas.Load())
+	assert.Zero(t, l.failed.Load())
+	assert.Equal(t, int64(1), s.destroyed.Load())
+
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+	assert.Nil(t, c.session)
+	assert.Empty(t, c.leases)
+}
+
+func TestDropIdleSession(t *testing.T) {
+	c := NewAIClient(config.AI{}, nil)
+	s := &ownedSession{streamingSession: newStreamingSession()}
+	c.session = s
+
+	c.SetModel("gpt-5")
+	assert.Equal(t, int64(1), s.destroyed.Load())
+	assert.Nil(t, c.session)
+}
+
+func TestPrewarm(t *testing.T) {
+	c := NewAIClient(config.AI{Enabled: new(bool)}, nil)
+	require.NoError(t, c.Prewarm(context.Background()))
+	assert.False(t, c.isInitialized())
+
+	c = NewAIClient(config.AI{}, nil)
+	s := &ownedSession{streamingSession: newStreamingSession()}
+	c.initialized, c.session = true, s
+	require.NoError(t, c.Prewarm(context.Background()))
+	assert.Equal(t, s, c.session)
+	assert.Empty(t, c.leases[s])
+	assert.Zero(t, s.destroyed.Load())
+}
+
+func TestUsage(t *testing.T) {
+	c := NewAIClient(config.AI{}, nil)
+	in, out := 120.0, 30.0
+	c.recordUsage(&in, &out)
+	c.recordUsage(&in, nil)
+	assert.Equal(t, TokenUsage{Input: 240, Output: 30, Requests: 2, Context: 120}, c.Usage())
+
+	c.ResetSession()
+	assert.Equal(t, TokenUsage{}, c.Usage())
+}
+
+func TestSetAudience(t *testing.T) {
+	c := NewAIClient(config.AI{}, nil)
+	assert.Equal(t, config.AIAudienceSRE, c.Audience())
+	assert.Empty(t, audienceGuidance(c.cfg.Audience))
+
+	s := &ownedSession{streamingSession: newStreamingSession()}
+	c.session = s
+	c.SetAudience(config.AIAudienceExec)
+	assert.Equal(t, config.AIAudienceExec, c.Audience())
+	assert.Equal(t, int64(1), s.destroyed.Load())
+	assert.Contains(t, audienceGuidance(c.cfg.Audience), "manager")
+}
+
+func TestSteerWithoutTurn(t *testing.T) {
+	c := NewAIClient(config.AI{}, nil)
+
+	assert.ErrorIs(t, c.Steer(context.Background(), "focus on the db pod"), errNoTurn)
+}
+
+func TestSteerLeasedSession(t *testing.T) {
+	c := NewAIClient(config.AI{}, nil)
+	s := &leasedSession{streamingSession: newStreamingSession()}
+	var l countingListener
+	errs := make(chan error, 1)
+	go func() {
+		errs <- c.sendTurn(context.Background(), s, "hello", &l)
+	}()
+	<-s.streaming
+
+	// A model switch dropped the session the turn runs on.
+	require.NoError(t, c.Steer(context.Background(), "focus on the db pod"))
+	assert.True(t, c.Interrupt())
+	select {
+	case err := <-errs:
+		assert.ErrorIs(t, err, ErrInterrupted)
+	case <-time.After(stopGracePeriod):
+		t.Fatal("send did not return after interrupt")
+	}
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	assert.Equal(t, []string{SteerPrompt("focus on the db pod")}, s.steered)
+	assert.Equal(t, 1, s.aborts)
+}
+
+func TestSendTurnTruncated(t *testing.T) {
+	uu := map[string]struct {
+		models []ModelInfo
+		output float64
+		e      int64
+	}{
+		"limit": {
+			models: []ModelInfo{{ID: "gpt-5", MaxOutput: 100}},
+			output: 100,
+			e:      1,
+		},
+		"below": {
+			models: []ModelInfo{{ID: "gpt-5", MaxOutput: 100}},
+			output: 40,
+		},
+		"unknown-limit": {
+			models: []ModelInfo{{ID: "gpt-5"}},
+			output: 100,
+		},
+		"no-models": {
+			output: 100,
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			c := NewAIClient(config.AI{Model: "gpt-5"}, nil)
+			c.models = u.models
+			s := newStreamingSession()
+			s.limit, s.output = 1, u.output
+			var l countingListener
+			require.NoError(t, c.sendTurn(context.Background(), s, "hello", &l))
+			assert.Equal(t, u.e, l.truncated.Load())
+		})
+	}
+}
+
+func TestSendTurnModes(t *testing.T) {
+	uu := map[string]struct {
+		mode string
+	}{
+		"default": {},
+		"wait":    {mode: config.AISendWait},
+		"stream":  {mode: config.AISendStream},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			c := NewAIClient(config.AI{SendMode: u.mode, Model: "gpt-5"}, nil)
+			c.models = []ModelInfo{{ID: "gpt-5", MaxOutput: 3}}
+			s := newStreamingSession()
+			s.limit, s.output = 3, 3
+			var l countingListener
+			require.NoError(t, c.sendTurn(context.Background(), s, "hello", &l))
+			assert.Equal(t, "xxx", l.answer.Load())
+			assert.Equal(t, int64(3), l.deltas.Load())
+			assert.Equal(t, int64(1), l.truncated.Load())
+		})
+	}
+}
+
+func TestSendTurnUnstreamed(t *testing.T) {
+	c := NewAIClient(config.AI{Streaming: true}, nil)
+	s := quietSession{newStreamingSession()}
+
+	var l countingListener
+	require.NoError(t, c.sendTurn(context.Background(), s, "hello", &l))
+	assert.Equal(t, "all good", l.answer.Load())
+	assert.Zero(t, l.unstreamed.Load())
+	assert.True(t, c.unstreamed)
+
+	require.NoError(t, c.sendTurn(context.Background(), s, "hello", &l))
+	assert.Equal(t, int64(1), l.unstreamed.Load())
+
+	c.SetModel("gpt-5")
+	assert.False(t, c.unstreamed)
+}
+
+func TestStreamCollector(t *testing.T) {
+	a, b, final := "Checking pods.", "All good.", "All good."
+	sc := newStreamCollector()
+	sc.handle(copilot.SessionEvent{Type: copilot.AssistantMessageDelta, Data: copilot.Data{DeltaContent: &a}})
+	sc.handle(copilot.SessionEvent{Type: copilot.AssistantMessage, Data: copilot.Data{Content: &a}})
+	sc.handle(copilot.SessionEvent{Type: copilot.AssistantMessageDelta, Data: copilot.Data{DeltaContent: &b}})
+	sc.handle(copilot.SessionEvent{Type: copilot.AssistantMessage, Data: copilot.Data{Content: &final}})
+	sc.handle(copilot.SessionEvent{Type: copilot.SessionIdle})
+
+	require.NoError(t, sc.wait(context.Background()))
+	assert.Equal(t, "Checking pods.\n\nAll good.", *sc.response().Data.Content)
+
+	sc = newStreamCollector()
+	sc.handle(copilot.SessionEvent{Type: copilot.AssistantMessage, Data: copilot.Data{Content: &final}})
+	assert.Equal(t, "All good.", *sc.response().Data.Content)
+
+	msg := "rate limited"
+	sc.handle(copilot.SessionEvent{Type: copilot.SessionError, Data: copilot.Data{Message: &msg}})
+	assert.EqualError(t, sc.wait(context.Background()), "session error: rate limited")
+}
+
+// Helpers...
+
+// streamingSession streams deltas until canceled or, if set, limit deltas
+// were sent. The final message follows a usage report of output tokens when
+// set.
+type streamingSession struct {
+	mx        sync.Mutex
+	handlers  []*copilot.SessionEventHandler
+	streaming chan struct{}
+	limit     int
+	output    float64
+}
+
+func newStreamingSession() *streamingSession {
+	return &streamingSession{streaming: make(chan struct{})}
+}
+
+func (s *streamingSession) On(h copilot.SessionEventHandler) func() {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	s.handlers = append(s.handlers, &h)
+
+	return func() {
+		s.mx.Lock()
+		defer s.mx.Unlock()
+		s.handlers = slices.DeleteFunc(s.handlers, func(p *copilot.SessionEventHandler) bool { return p == &h })
+	}
+}
+
+func (s *streamingSession) SendAndWait(ctx context.Context, _ copilot.MessageOptions) (*copilot.SessionEvent, error) {
+	delta, content := "x", ""
+	for i := 0; s.limit == 0 || i < s.limit; i++ {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+		s.emit(copilot.SessionEvent{Type: copilot.AssistantMessageDelta, Data: copilot.Data{DeltaContent: &delta}})
+		content += delta
+		if i == 0 {
+			close(s.streaming)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if s.output > 0 {
+		s.emit(copilot.SessionEvent{Type: copilot.AssistantUsage, Data: copilot.Data{OutputTokens: &s.output}})
+	}
+	res := copilot.SessionEvent{Type: copilot.AssistantMessage, Data: copilot.Data{Content: &content}}
+
+	return &res, nil
+}
+
+// Send streams the turn in the background, ending it with the final message
+// and an idle event.
+func (s *streamingSession) Send(ctx context.Context, opts copilot.MessageOptions) (string, error) {
+	go func() {
+		res, err := s.SendAndWait(ctx, opts)
+		if err != nil {
+			return
+		}
+		s.emit(*res)
+		s.emit(copilot.SessionEvent{Type: copilot.SessionIdle})
+	}()
+
+	return "m1", nil
+}
+
+// leasedSession is a streaming session recording steering and aborts.
+type leasedSession struct {
+	*streamingSession
+
+	steered []string
+	aborts  int
+}
+
+func (s *leasedSession) Send(ctx context.Context, opts copilot.MessageOptions) (string, error) {
+	if opts.Mode != steerMode {
+		return s.streamingSession.Send(ctx, opts)
+	}
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	s.steered = append(s.steered, opts.Prompt)
+
+	return "m2", nil
+}
+
+func (s *leasedSession) Abort(context.Context) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	s.aborts++
+
+	return nil
+}
+
+func (s *streamingSession) emit(e copilot.SessionEvent) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	for _, h := range s.handlers {
+		(*h)(e)
+	}
+}
+
+// ownedSession is a streaming session the client can abort and destroy. A
+// destroyed session fails its sends like a real one would.
+type ownedSession struct {
+	*streamingSession
+	destroyed atomic.Int64
+}
+
+func (s *ownedSession) SendAndWait(ctx context.Context, opts copilot.MessageOptions) (*copilot.SessionEvent, error) {
+	res, err := s.streamingSession.SendAndWait(ctx, opts)
+	if s.destroyed.Load() > 0 {
+		return nil, errors.New("session not initialized")
+	}
+
+	return res, err
+}
+
+func (*ownedSession) Abort(context.Context) error { return nil }
+
+func (s *ownedSession) Destroy() error {
+	s.destroyed.Add(1)
+	return nil
+}
+
+// quietSession answers without streaming any event, like providers that
+// don't stream.
+type quietSession struct {
+	*streamingSession
+}
+
+func (quietSession) SendAndWait(context.Context, copilot.MessageOptions) (*copilot.SessionEvent, error) {
+	content := "all good"
+	return &copilot.SessionEvent{Type: copilot.AssistantMessage, Data: copilot.Data{Content: &content}}, nil
+}
+
+type countingListener struct {
+	deltas, failed, truncated, unstreamed, retries atomic.Int64
+	answer                                         atomic.Value
+}
+
+func (*countingListener) AIResponseStart()              {}
+func (l *countingListener) AIResponseDelta(string)      { l.deltas.Add(1) }
+func (l *countingListener) AIResponseComplete(s string) { l.answer.Store(s) }
+func (l *countingListener) AIResponseFailed(error)      { l.failed.Add(1) }
+func (*countingListener) AIReasoningDelta(string)       {}
+func (*countingListener) AIReasoningComplete(string)    {}
+func (*countingListener) AIToolStart(string)            {}
+func (*countingListener) AIToolComplete(string)         {}
+func (*countingListener) AIToolBudgetExceeded(int)      {}
+func (l *countingListener) AIResponseTruncated()        { l.truncated.Add(1) }
+func (l *countingListener) AIStreamUnavailable()        { l.unstreamed.Add(1) }
+func (l *countingListener) AIRetrying(int, int, error)  { l.retries.Add(1) }
+
+func TestSelectSkillFor(t *testing.T) {
+	c := NewAIClient(config.AI{
+		ActiveSkill: "optimization",
+		SkillByKind: map[string]string{"networkpolicies": "security", "pods": "bozo"},
+	}, nil)
+
+	c.SelectSkillFor("NetworkPolicy")
+	assert.Equal(t, "security", c.ActiveSkill())
+
+	c.SelectSkillFor("deployments")
+	assert.Equal(t, "optimization", c.ActiveSkill())
+
+	c.SelectSkillFor("pods")
+	assert.Equal(t, "optimization", c.ActiveSkill())
+
+	c.SelectSkillFor("networkpolicies")
+	c.SetSkill("diagnostics")
+	assert.Equal(t, "diagnostics", c.ActiveSkill())
+}
+
+func TestCustomSkills(t *testing.T) {
+	var buff bytes.Buffer
+	c := NewAIClient(config.AI{
+		CustomSkills: []config.AISkillConfig{
+			{
+				Name:         "payments",
+				Description:  "Payments playbook",
+				ToolNames:    []string{"get_logs", "get_bozo"},
+				SystemSuffix: "Check the ledger first.",
+			},
+			{Name: "diagnostics", ToolNames: []string{"get_logs"}},
+			{Name: "empty"},
+			{ToolNames: []string{"get_logs"}},
+		},
+	}, slog.New(slog.NewTextHandler(&buff, nil)))
+
+	s, ok := c.Skills().Get("payments")
+	require.True(t, ok)
+	assert.True(t, s.Custom)
+	assert.Equal(t, "Check the ledger first.", c.Skills().SystemMessageSuffix("payments"))
+	d, _ := c.Skills().Get("diagnostics")
+	assert.False(t, d.Custom)
+	_, ok = c.Skills().Get("empty")
+	assert.False(t, ok)
+	assert.Len(t, c.Skills().All(), 4)
+
+	c.SetTools([]copilot.Tool{{Name: "get_logs"}, {Name: "get_events"}})
+	assert.Contains(t, buff.String(), "Unknown tool in AI custom skill")
+	assert.Contains(t, buff.String(), "tool=get_bozo")
+	assert.NotContains(t, buff.String(), "tool=get_logs")
+
+	c.SetSkill("payments")
+	require.Len(t, c.tools, 1)
+	assert.Equal(t, "get_logs", c.tools[0].Name)
+}
+
+func TestNewModelInfo(t *testing.T) {
+	vision, maxOutput := true, 16384.0
+	uu := map[string]struct {
+		m rpc.Model
+		e ModelInfo
+	}{
+		"full": {
+			m: rpc.Model{
+				ID:   "gpt-5",
+				Name: "GPT-5 (Preview)",
+				Capabilities: rpc.Capabilities{
+					Supports: rpc.Supports{Vision: &vision},
+					Limits:   rpc.Limits{MaxContextWindowTokens: 128000, MaxOutputTokens: &maxOutput},
+				},
+				Policy:                    &rpc.Policy{State: "disabled"},
+				Billing:                   &rpc.Billing{Multiplier: 0.33},
+				SupportedReasoningEfforts: []string{"low", "high"},
+			},
+			e: ModelInfo{
+				ID:               "gpt-5",
+				Name:             "GPT-5 (Preview)",
+				Preview:          true,
+				Disabled:         true,
+				Multiplier:       0.33,
+				ContextWindow:    128000,
+				MaxOutput:        16384,
+				Vision:           true,
+				ReasoningEfforts: []string{"low", "high"},
+			},
+		},
+		"plain": {
+			m: rpc.Model{ID: "gpt-4.1", Name: "GPT-4.1"},
+			e: ModelInfo{ID: "gpt-4.1", Name: "GPT-4.1"},
+		},
+		"deprecated": {
+			m: rpc.Model{ID: "gpt-4", Name: "GPT-4 (Deprecated)", Policy: &rpc.Policy{State: "enabled"}},
+			e: ModelInfo{ID: "gpt-4", Name: "GPT-4 (Deprecated)", Deprecated: true},
+		},
+		"preview-in-id": {
+			m: rpc.Model{ID: "o1-preview", Name: "o1"},
+			e: ModelInfo{ID: "o1-preview", Name: "o1"},
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, newModelInfo(&u.m))
+		})
+	}
+}
+
+func TestListModelsCached(t *testing.T) {
+	c := NewAIClient(config.AI{}, nil)
+	c.models, c.modelsAt = []ModelInfo{{ID: "m1"}}, time.Now()
+
+	mm, err := c.ListModels(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []ModelInfo{{ID: "m1"}}, mm)
+}
+
+func TestTypedConfirmFor(t *testing.T) {
+	c := NewAIClient(config.AI{
+		ConfirmPolicy: []config.AIConfirmRule{
+			{Actions: []string{"delete_resource", "apply_manifest", "exec_command"}},
+		},
+	}, nil)
+
+	uu := map[string]struct {
+		tool string
+		args map[string]any
+		e    string
+		ok   bool
+	}{
+		"namespaced": {
+			tool: "delete_resource",
+			args: map[string]any{"gvr": "apps/v1/deployments", "namespace": "prod", "name": "web"},
+			e:    "web",
+			ok:   true,
+		},
+		"empty-namespace": {
+			tool: "delete_resource",
+			args: map[string]any{"gvr": "apps/v1/deployments", "namespace": "", "name": "web"},
+			e:    "web",
+			ok:   true,
+		},
+		"cluster-scoped": {
+			tool: "delete_resource",
+			args: map[string]any{"gvr": "v1/nodes", "name": "n1"},
+			e:    "n1",
+			ok:   true,
+		},
+		"exec": {
+			tool: "exec_command",
+			args: map[string]any{"namespace": "prod", "podName": "web-0", "command": "ls"},
+			e:    "web-0",
+			ok:   true,
+		},
+		"unnamed": {
+			tool: "apply_manifest",
+			args: map[string]any{"manifest": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  generateName: cm-\n"},
+			e:    "apply_manifest",
+			ok:   true,
+		},
+		"no-rule": {
+			tool: "scale_resource",
+			args: map[string]any{"gvr": "apps/v1/deployments", "namespace": "prod", "name": "web", "replicas": float64(2)},
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			confirm, ok := c.typedConfirmFor(u.tool, u.args)
+			assert.Equal(t, u.ok, ok)
+			assert.Equal(t, u.e, confirm)
+		})
+	}
+}
@@ -0,0 +1,337 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/derailed/k9s/internal/client"
+	copilot "github.com/github/copilot-sdk/go"
+	"github.com/pmezard/go-difflib/difflib"
+	"gopkg.in/yaml.v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// maxCompareGVRs caps the resource types compared by compare_namespaces.
+	maxCompareGVRs = 15
+
+	// maxCompareScan caps the resources listed per type and namespace.
+	maxCompareScan = 300
+
+	// maxCompareDiffs caps the differences reported per resource.
+	maxCompareDiffs = 10
+
+	// compareDiffContext is the number of unchanged lines around each change
+	// of a compare_resources diff.
+	compareDiffContext = 3
+)
+
+// compareIgnored are fields that differ between namespaces by design, e.g.
+// addresses allocated by the cluster.
+var compareIgnored = [][]string{
+	{"metadata", "name"},
+	{"metadata", "namespace"},
+	{"spec", "clusterIP"},
+	{"spec", "clusterIPs"},
+	{"spec", "template", "metadata", "annotations", "kubectl.kubernetes.io/restartedAt"},
+}
+
+// --- compare_namespaces tool ---
+
+type compareNamespacesParams struct {
+	Source string   `json:"source" jsonschema:"Reference namespace, e.g. staging"`
+	Target string   `json:"target" jsonschema:"Namespace compared against the source, e.g. prod"`
+	GVRs   []string `json:"gvrs,omitempty" jsonschema:"Group/Version/Resource identifiers to compare, e.g. apps/v1/deployments (max 15). Defaults to workloads and their configuration"`
+}
+
+// inventoryDiff compares the resources of a type in two namespaces.
+type inventoryDiff struct {
+	GVR          string         `json:"gvr"`
+	OnlyInSource []string       `json:"onlyInSource,omitempty"`
+	OnlyInTarget []string       `json:"onlyInTarget,omitempty"`
+	Different    []resourceDiff `json:"different,omitempty"`
+	Identical    int            `json:"identical"`
+	Partial      bool           `json:"partial,omitempty"`
+}
+
+// resourceDiff lists the fields differing between same-named resources.
+type resourceDiff struct {
+	Name        string      `json:"name"`
+	Differences []fieldDiff `json:"differences"`
+	More        int         `json:"moreDifferences,omitempty"`
+}
+
+// fieldDiff is a field differing between same-named resources.
+type fieldDiff struct {
+	Path   string `json:"path"`
+	Change string `json:"change"`
+	Source any    `json:"source,omitempty"`
+	Target any    `json:"target,omitempty"`
+}
+
+func (tf *ToolFactory) compareNamespacesTool() copilot.Tool {
+	return copilot.DefineTool(
+		"compare_namespaces",
+		"Compare the resource inventories of two namespaces, e.g. staging against prod: lists resources present in only one of them "+
+			"and the fields differing between same-named resources, ignoring status and server-managed fields. "+
+			"Use for environment parity audits, e.g. 'what exists in staging that's missing or different in prod?'. Secret values are never shown.",
+		func(params compareNamespacesParams, inv copilot.ToolInvocation) (any, error) {
+			if params.Source == "" || params.Target == "" {
+				return nil, fmt.Errorf("both source and target namespaces are required")
+			}
+			if params.Source == params.Target {
+				return nil, fmt.Errorf("source and target are the same namespace %q", params.Source)
+			}
+			for _, ns := range []string{params.Source, params.Target} {
+				if err := tf.checkNamespace(ns); err != nil {
+					return nil, err
+				}
+			}
+			gvrs := changeGVRs
+			if len(params.GVRs) > 0 {
+				if len(params.GVRs) > maxCompareGVRs {
+					return nil, fmt.Errorf("too many gvrs: %d (max %d)", len(params.GVRs), maxCompareGVRs)
+				}
+				gvrs = make([]*client.GVR, 0, len(params.GVRs))
+				for _, s := range params.GVRs {
+					if _, err := parseGVR(s); err != nil {
+						return nil, err
+					}
+					gvrs = append(gvrs, client.NewGVR(s))
+				}
+			}
+
+			dyn, err := tf.conn.DynDial()
+			if err != nil {
+				return nil, fmt.Errorf("failed to connect to cluster: %w", err)
+			}
+			ctx, opts := context.Background(), metav1.ListOptions{Limit: maxCompareScan}
+			list := func(gvr *client.GVR, ns string) (map[string]map[string]any, bool, error) {
+				ll, err := dyn.Resource(gvr.GVR()).Namespace(ns).List(ctx, opts)
+				if err != nil {
+					return nil, false, err
+				}
+				oo := make(map[string]map[string]any, len(ll.Items))
+				for i := range ll.Items {
+					oo[ll.Items[i].GetName()] = ll.Items[i].Object
+				}
+				return oo, ll.GetContinue() != "", nil
+			}
+
+			var (
+				dd   []inventoryDiff
+				errs []string
+			)
+			for _, gvr := range gvrs {
+				src, srcPartial, err := list(gvr, params.Source)
+				if err != nil {
+					errs = append(errs, gvr.String()+": "+err.Error())
+					continue
+				}
+				tgt, tgtPartial, err := list(gvr, params.Target)
+				if err != nil {
+					errs = append(errs, gvr.String()+": "+err.Error())
+					continue
+				}
+				d := compareInventories(src, tgt, gvr.R() == "secrets")
+				d.GVR, d.Partial = gvr.String(), srcPartial || tgtPartial
+				dd = append(dd, d)
+			}
+
+			var missing, extra, different int
+			for _, d := range dd {
+				missing += len(d.OnlyInSource)
+				extra += len(d.OnlyInTarget)
+				different += len(d.Different)
+			}
+			result := map[string]any{
+				"source":    params.Source,
+				"target":    params.Target,
+				"resources": dd,
+				"summary": fmt.Sprintf("%d resource(s) missing in %s, %d only in %s, %d differing",
+					missing, params.Target, extra, params.Target, different),
+			}
+			if slices.ContainsFunc(dd, func(d inventoryDiff) bool { return d.Partial }) {
+				result["partialScan"] = fmt.Sprintf("only the first %d resources of each type were compared in types marked partial", maxCompareScan)
+			}
+			if len(errs) > 0 {
+				result["errors"] = errs
+			}
+
+			return result, nil
+		},
+	)
+}
+
+// compareInventories compares same-named resources of two namespaces. The
+// values of differing fields are left out when hideValues is set.
+func compareInventories(src, tgt map[string]map[string]any, hideValues bool) inventoryDiff {
+	var d inventoryDiff
+	for _, n := range slices.Sorted(maps.Keys(src)) {
+		t, ok := tgt[n]
+		if !ok {
+			d.OnlyInSource = append(d.OnlyInSource, n)
+			continue
+		}
+		cc := compareObjects(src[n], t)
+		if len(cc) == 0 {
+			d.Identical++
+			continue
+		}
+		if hideValues {
+			for i := range cc {
+				cc[i].Source, cc[i].Target = nil, nil
+			}
+		}
+		rd := resourceDiff{Name: n, Differences: cc[:min(len(cc), maxCompareDiffs)]}
+		if len(cc) > maxCompareDiffs {
+			rd.More = len(cc) - maxCompareDiffs
+		}
+		d.Different = append(d.Different, rd)
+	}
+	for _, n := range slices.Sorted(maps.Keys(tgt)) {
+		if _, ok := src[n]; !ok {
+			d.OnlyInTarget = append(d.OnlyInTarget, n)
+		}
+	}
+
+	return d
+}
+
+// compareObjects returns the fields differing between two resources, both
+// ways since fields set in the target only are as telling as the ones
+// missing from it.
+func compareObjects(src, tgt map[string]any) []fieldDiff {
+	src, tgt = comparableObject(src), comparableObject(tgt)
+	var ff []fieldDiff
+	for _, c := range diffObjects(src, tgt) {
+		switch c.Change {
+		case "removed":
+			ff = append(ff, fieldDiff{Path: c.Path, Change: "only in source", Source: c.Desired})
+		case "changed":
+			ff = append(ff, fieldDiff{Path: c.Path, Change: "changed", Source: c.Desired, Target: c.Live})
+		}
+	}
+	for _, c := range diffObjects(tgt, src) {
+		if c.Change == "removed" {
+			ff = append(ff, fieldDiff{Path: c.Path, Change: "only in target", Target: c.Desired})
+		}
+	}
+
+	return ff
+}
+
+// comparableObject strips a resource of the fields that don't tell two
+// namespaces apart.
+func comparableObject(o map[string]any) map[string]any {
+	o = stripNoise(runtime.DeepCopyJSON(o))
+	for _, f := range compareIgnored {
+		unstructured.RemoveNestedField(o, f...)
+	}
+
+	return o
+}
+
+// --- compare_resources tool ---
+
+// resourceRef points at a single resource.
+type resourceRef struct {
+	GVR       string `json:"gvr" jsonschema:"Group/Version/Resource identifier, e.g. apps/v1/deployments"`
+	Name      string `json:"name" jsonschema:"Resource name"`
+	Namespace string `json:"namespace,omitempty" jsonschema:"Kubernetes namespace (empty for cluster-scoped)"`
+}
+
+func (r resourceRef) String() string {
+	return r.GVR + " " + client.FQN(r.Namespace, r.Name)
+}
+
+type compareResourcesParams struct {
+	Source resourceRef `json:"source" jsonschema:"Reference resource, e.g. the staging deployment"`
+	Target resourceRef `json:"target" jsonschema:"Resource compared against the source, e.g. the prod deployment"`
+	Live   bool        `json:"live,omitempty" jsonschema:"If true, read from the API server instead of the K9s cache"`
+}
+
+func (tf *ToolFactory) compareResourcesTool() copilot.Tool {
+	return copilot.DefineTool(
+		"compare_resources",
+		"Diff two resources, e.g. a deployment in staging against the one in prod, as a unified YAML diff from source to target. "+
+			"Status and server-managed fields are left out. Use to explain why two copies of a workload behave differently. Secret values are never shown. "+
+			"Served from the K9s cache unless live=true.",
+		func(params compareResourcesParams, inv copilot.ToolInvocation) (any, error) {
+			src, err := tf.comparableYAML(params.Source, params.Live)
+			if err != nil {
+				return nil, err
+			}
+			tgt, err := tf.comparableYAML(params.Target, params.Live)
+			if err != nil {
+				return nil, err
+			}
+
+			diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+				A:        difflib.SplitLines(src),
+				B:        difflib.SplitLines(tgt),
+				FromFile: params.Source.String(),
+				ToFile:   params.Target.String(),
+				Context:  compareDiffContext,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to diff resources: %w", err)
+			}
+			res := map[string]any{
+				"source":    params.Source.String(),
+				"target":    params.Target.String(),
+				"identical": diff == "",
+			}
+			if diff != "" {
+				res["diff"] = diff
+			}
+
+			return res, nil
+		},
+	)
+}
+
+// comparableYAML fetches a resource and renders it as YAML without status,
+// server-managed fields or secret values.
+func (tf *ToolFactory) comparableYAML(ref resourceRef, live bool) (string, error) {
+	if _, err := parseGVR(ref.GVR); err != nil {
+		return "", err
+	}
+	if err := tf.checkNamespace(ref.Namespace); err != nil {
+		return "", err
+	}
+	gvr := client.NewGVR(ref.GVR)
+	o, err := tf.getObject(gvr, ref.Namespace, ref.Name, live)
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s: %w", ref, err)
+	}
+	raw, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+	if err != nil {
+		return "", fmt.Errorf("failed to convert to unstructured: %w", err)
+	}
+	raw = stripNoise(raw)
+	if gvr.R() == "secrets" {
+		for _, f := range []string{"data", "stringData"} {
+			if dd, ok := raw[f].(map[string]any); ok {
+				hidden := make(map[string]any, len(dd))
+				for k := range dd {
+					hidden[k] = "<hidden>"
+				}
+				raw[f] = hidden
+			}
+		}
+	}
+	b, err := yaml.Marshal(raw)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+
+	return string(b), nil
+}
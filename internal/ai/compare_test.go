@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"testing"
+
+	"github.com/derailed/k9s/internal/client"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCompareObjects(t *testing.T) {
+	svc := func(ns, ip string, port int64, extra map[string]any) map[string]any {
+		spec := map[string]any{"clusterIP": ip, "ports": []any{map[string]any{"name": "http", "port": port}}}
+		for k, v := range extra {
+			spec[k] = v
+		}
+		return map[string]any{
+			"metadata": map[string]any{"name": "api", "namespace": ns, "uid": ns + "-api", "resourceVersion": "1"},
+			"spec":     spec,
+			"status":   map[string]any{"loadBalancer": map[string]any{}},
+		}
+	}
+
+	uu := map[string]struct {
+		src, tgt map[string]any
+		e        []fieldDiff
+	}{
+		"same": {
+			src: svc("staging", "10.0.0.1", 80, nil),
+			tgt: svc("prod", "10.0.0.2", 80, nil),
+		},
+		"changed": {
+			src: svc("staging", "10.0.0.1", 80, nil),
+			tgt: svc("prod", "10.0.0.2", 8080, nil),
+			e:   []fieldDiff{{Path: "spec.ports[http].port", Change: "changed", Source: int64(80), Target: int64(8080)}},
+		},
+		"both-ways": {
+			src: svc("staging", "10.0.0.1", 80, map[string]any{"type": "NodePort"}),
+			tgt: svc("prod", "10.0.0.2", 80, map[string]any{"sessionAffinity": "ClientIP"}),
+			e: []fieldDiff{
+				{Path: "spec.type", Change: "only in source", Source: "NodePort"},
+				{Path: "spec.sessionAffinity", Change: "only in target", Target: "ClientIP"},
+			},
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, compareObjects(u.src, u.tgt))
+		})
+	}
+}
+
+func TestCompareInventories(t *testing.T) {
+	secret := func(v string) map[string]any {
+		return map[string]any{"data": map[string]any{"password": v}}
+	}
+	src := map[string]map[string]any{"db": secret("c3RhZ2luZw=="), "cache": secret("eA==")}
+	tgt := map[string]map[string]any{"db": secret("cHJvZA=="), "extra": secret("eA==")}
+
+	d := compareInventories(src, tgt, true)
+	assert.Equal(t, []string{"cache"}, d.OnlyInSource)
+	assert.Equal(t, []string{"extra"}, d.OnlyInTarget)
+	assert.Equal(t, []resourceDiff{{Name: "db", Differences: []fieldDiff{{Path: "data.password", Change: "changed"}}}}, d.Different)
+	assert.Zero(t, d.Identical)
+}
+
+func TestCompareNamespacesTool(t *testing.T) {
+	cm := func(ns, name, level string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name},
+			Data:       map[string]string{"level": level},
+		}
+	}
+	tf := newTestToolFactory(newTestFactory(), newTestConn(
+		cm("ns1", "settings", "debug"),
+		cm("ns1", "flags", "on"),
+		cm("ns2", "settings", "info"),
+	))
+
+	m := callToolJSON(t, tf, "compare_namespaces", map[string]any{
+		"source": "ns1",
+		"target": "ns2",
+		"gvrs":   []any{"v1/configmaps"},
+	})
+	assert.Equal(t, "1 resource(s) missing in ns2, 0 only in ns2, 1 differing", m["summary"])
+	rr := m["resources"].([]any)
+	assert.Len(t, rr, 1)
+	r := rr[0].(map[string]any)
+	assert.Equal(t, []any{"flags"}, r["onlyInSource"])
+	diff := r["different"].([]any)[0].(map[string]any)
+	assert.Equal(t, "settings", diff["name"])
+	assert.Equal(t, []any{map[string]any{"path": "data.level", "change": "changed", "source": "debug", "target": "info"}}, diff["differences"])
+
+	_, err := callTool(t, tf, "compare_namespaces", map[string]any{"source": "ns1", "target": "ns1"})
+	assert.Error(t, err)
+}
+
+func TestCompareResourcesTool(t *testing.T) {
+	f := newTestFactory()
+	f.add(client.CmGVR, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "staging", Name: "settings", ResourceVersion: "12"},
+		Data:       map[string]string{"level": "debug", "region": "us"},
+	})
+	f.add(client.CmGVR, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "settings", ResourceVersion: "34"},
+		Data:       map[string]string{"level": "info", "region": "us"},
+	})
+	f.add(client.SecGVR, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "staging", Name: "creds"},
+		Data:       map[string][]byte{"password": []byte("s3cret")},
+	})
+	f.add(client.SecGVR, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "creds"},
+		Data:       map[string][]byte{"password": []byte("hunter2")},
+	})
+	tf := newTestToolFactory(f, newTestConn())
+
+	m := callToolJSON(t, tf, "compare_resources", map[string]any{
+		"source": map[string]any{"gvr": "v1/configmaps", "namespace": "staging", "name": "settings"},
+		"target": map[string]any{"gvr": "v1/configmaps", "namespace": "prod", "name": "settings"},
+	})
+	assert.Equal(t, false, m["identical"])
+	diff := m["diff"].(string)
+	assert.Contains(t, diff, "--- v1/configmaps staging/settings\n+++ v1/configmaps prod/settings\n")
+	assert.Contains(t, diff, "-    level: debug\n+    level: info\n")
+	assert.NotContains(t, diff, "resourceVersion")
+
+	m = callToolJSON(t, tf, "compare_resources", map[string]any{
+		"source": map[string]any{"gvr": "v1/secrets", "namespace": "staging", "name": "creds"},
+		"target": map[string]any{"gvr": "v1/secrets", "namespace": "prod", "name": "creds"},
+	})
+	assert.NotContains(t, m["diff"], "s3cret")
+	assert.NotContains(t, m["diff"], "hunter2")
+
+	ref := map[string]any{"gvr": "v1/configmaps", "namespace": "prod", "name": "settings"}
+	m = callToolJSON(t, tf, "compare_resources", map[string]any{"source": ref, "target": ref})
+	assert.Equal(t, true, m["identical"])
+	assert.Nil(t, m["diff"])
+
+	_, err := callTool(t, tf, "compare_resources", map[string]any{
+		"source": map[string]any{"gvr": "v1/configmaps", "namespace": "staging", "name": "settings"},
+		"target": map[string]any{"gvr": "v1/configmaps", "namespace": "prod", "name": "nope"},
+	})
+	assert.ErrorContains(t, err, "failed to get v1/configmaps prod/nope")
+}
@@ -5,8 +5,13 @@ package ai
 
 import (
 	"archive/tar"
+	"bytes"
+	"cmp"
 	"compress/gzip"
+	"crypto/sha512"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -15,6 +20,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"time"
 )
@@ -23,7 +29,7 @@ const (
 	// copilotVersion is the pinned version of the Copilot CLI.
 	copilotVersion = "0.0.420"
 
-	// npmRegistryURL is the base URL for the npm registry.
+	// npmRegistryURL is the base URL of the public npm registry.
 	npmRegistryURL = "https://registry.npmjs.org"
 
 	// cacheDirName is the subdirectory under the user's cache dir.
@@ -31,6 +37,9 @@ const (
 )
 
 // platformPackage maps GOOS/GOARCH to the npm package name suffix.
+// These are the only platform packages @github/copilot publishes (see its
+// optionalDependencies); 32-bit ARM, ppc64le, s390x and the BSDs have none,
+// so users there must install the CLI themselves and set COPILOT_CLI_PATH.
 var platformPackage = map[string]string{
 	"darwin/arm64":  "darwin-arm64",
 	"darwin/amd64":  "darwin-x64",
@@ -45,8 +54,8 @@ var platformPackage = map[string]string{
 //  1. COPILOT_CLI_PATH environment variable
 //  2. "copilot" in $PATH
 //  3. Cached binary in user cache dir (previously downloaded)
-//  4. Auto-download from npm registry
-func ResolveCopilotCLIPath(log *slog.Logger) string {
+//  4. Auto-download from the npm registry, see npmRegistry
+func ResolveCopilotCLIPath(log *slog.Logger, registry string) string {
 	// 1. Env override.
 	if p := os.Getenv("COPILOT_CLI_PATH"); p != "" {
 		if _, err := os.Stat(p); err == nil {
@@ -73,7 +82,7 @@ func ResolveCopilotCLIPath(log *slog.Logger) string {
 
 	// 4. Auto-download.
 	log.Info("Copilot CLI not found, downloading...")
-	path, err := downloadCopilotCLI(cacheDir, log)
+	path, err := downloadCopilotCLI(cacheDir, npmRegistry(registry), log)
 	if err != nil {
 		log.Error("Failed to download copilot CLI", "error", err)
 		log.Info("Install manually: npm install -g @github/copilot")
@@ -103,20 +112,21 @@ func copilotBinaryName() string {
 }
 
 // downloadCopilotCLI downloads the platform-specific copilot CLI from npm.
-func downloadCopilotCLI(cacheDir string, log *slog.Logger) (string, error) {
+func downloadCopilotCLI(cacheDir, registry string, log *slog.Logger) (string, error) {
 	platform := runtime.GOOS + "/" + runtime.GOARCH
 	pkg, ok := platformPackage[platform]
 	if !ok {
-		return "", fmt.Errorf("unsupported platform: %s", platform)
+		return "", unsupportedPlatformError(platform)
 	}
 
-	tarURL, err := resolveTarballURL(pkg)
+	log.Info("Resolving copilot CLI", "registry", registry, "proxy", proxyFor(registry))
+	tarURL, integrity, err := resolveTarballURL(registry, pkg)
 	if err != nil {
-		return "", fmt.Errorf("resolving download URL: %w", err)
+		return "", fmt.Errorf("resolving download URL from %s: %w", registry, err)
 	}
-	log.Info("Downloading copilot CLI", "url", tarURL)
+	log.Info("Downloading copilot CLI", "url", tarURL, "proxy", proxyFor(tarURL))
 
-	client := &http.Client{Timeout: 120 * time.Second}
+	client := newInstallClient(120 * time.Second)
 	resp, err := client.Get(tarURL)
 	if err != nil {
 		return "", fmt.Errorf("downloading: %w", err)
@@ -127,8 +137,28 @@ func downloadCopilotCLI(cacheDir string, log *slog.Logger) (string, error) {
 		return "", fmt.Errorf("download returned %d", resp.StatusCode)
 	}
 
+	// Spool the tarball so its digest is checked before anything is extracted.
+	tmp, err := os.CreateTemp(cacheDir, "copilot-*.tgz")
+	if err != nil {
+		return "", fmt.Errorf("creating temp file: %w", err)
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+	h := sha512.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, h), resp.Body); err != nil {
+		return "", fmt.Errorf("downloading: %w", err)
+	}
+	if err := verifyIntegrity(integrity, h.Sum(nil)); err != nil {
+		return "", err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("rewinding download: %w", err)
+	}
+
 	binaryPath := filepath.Join(cacheDir, copilotBinaryName())
-	if err := extractCopilotBinary(resp.Body, binaryPath); err != nil {
+	if err := extractCopilotBinary(tmp, binaryPath); err != nil {
 		return "", fmt.Errorf("extracting: %w", err)
 	}
 
@@ -136,36 +166,122 @@ func downloadCopilotCLI(cacheDir string, log *slog.Logger) (string, error) {
 	return binaryPath, nil
 }
 
-// resolveTarballURL fetches the tarball URL for a specific version from npm.
-func resolveTarballURL(platformSuffix string) (string, error) {
+func unsupportedPlatformError(platform string) error {
+	return fmt.Errorf(
+		"unsupported platform %s: the copilot CLI is only published for %s. Install it manually and point COPILOT_CLI_PATH to the binary",
+		platform,
+		strings.Join(supportedPlatforms(), ", "),
+	)
+}
+
+// supportedPlatforms returns the sorted GOOS/GOARCH pairs with a published CLI.
+func supportedPlatforms() []string {
+	pp := make([]string, 0, len(platformPackage))
+	for p := range platformPackage {
+		pp = append(pp, p)
+	}
+	slices.Sort(pp)
+
+	return pp
+}
+
+// npmRegistry returns the registry base URL to download from: the configured
+// one, then NPM_CONFIG_REGISTRY as npm reads it, then the public registry.
+func npmRegistry(registry string) string {
+	r := cmp.Or(
+		strings.TrimSpace(registry),
+		strings.TrimSpace(os.Getenv("NPM_CONFIG_REGISTRY")),
+		strings.TrimSpace(os.Getenv("npm_config_registry")),
+		npmRegistryURL,
+	)
+
+	return strings.TrimRight(r, "/")
+}
+
+// newInstallClient returns an HTTP client going through the proxy set by
+// HTTPS_PROXY, HTTP_PROXY and NO_PROXY.
+func newInstallClient(timeout time.Duration) *http.Client {
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	tr.Proxy = http.ProxyFromEnvironment
+
+	return &http.Client{Timeout: timeout, Transport: tr}
+}
+
+// proxyFor returns the proxy requests to rawURL go through, credentials
+// masked, or "none".
+func proxyFor(rawURL string) string {
+	req, err := http.NewRequest(http.MethodGet, rawURL, http.NoBody)
+	if err != nil {
+		return "none"
+	}
+	u, err := http.ProxyFromEnvironment(req)
+	if err != nil || u == nil {
+		return "none"
+	}
+
+	return u.Redacted()
+}
+
+// resolveTarballURL fetches the tarball URL for a specific version from npm
+// along with its subresource integrity string.
+func resolveTarballURL(registry, platformSuffix string) (string, string, error) {
 	scope := "@github"
 	name := "copilot-" + platformSuffix
-	url := fmt.Sprintf("%s/%s/%s/%s", npmRegistryURL, scope, name, copilotVersion)
+	url := fmt.Sprintf("%s/%s/%s/%s", registry, scope, name, copilotVersion)
 
-	client := &http.Client{Timeout: 15 * time.Second}
+	client := newInstallClient(15 * time.Second)
 	resp, err := client.Get(url)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("npm registry returned %d for %s/%s@%s", resp.StatusCode, scope, name, copilotVersion)
+		return "", "", fmt.Errorf("npm registry returned %d for %s/%s@%s", resp.StatusCode, scope, name, copilotVersion)
 	}
 
 	var meta struct {
 		Dist struct {
-			Tarball string `json:"tarball"`
+			Tarball   string `json:"tarball"`
+			Integrity string `json:"integrity"`
 		} `json:"dist"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&meta); err != nil {
-		return "", fmt.Errorf("parsing npm metadata: %w", err)
+		return "", "", fmt.Errorf("parsing npm metadata: %w", err)
 	}
 	if meta.Dist.Tarball == "" {
-		return "", fmt.Errorf("no tarball URL in npm metadata")
+		return "", "", fmt.Errorf("no tarball URL in npm metadata")
+	}
+
+	return meta.Dist.Tarball, meta.Dist.Integrity, nil
+}
+
+// verifyIntegrity checks a tarball sha512 digest against an npm integrity
+// string, e.g. "sha512-<base64>". Downloads without a sha512 hash to check
+// against are refused.
+func verifyIntegrity(integrity string, sum []byte) error {
+	var found bool
+	for _, f := range strings.Fields(integrity) {
+		algo, hash, ok := strings.Cut(f, "-")
+		if !ok || algo != "sha512" {
+			continue
+		}
+		// Integrity entries may carry options after a '?'.
+		hash, _, _ = strings.Cut(hash, "?")
+		want, err := base64.StdEncoding.DecodeString(hash)
+		if err != nil {
+			continue
+		}
+		found = true
+		if bytes.Equal(want, sum) {
+			return nil
+		}
+	}
+	if !found {
+		return errors.New("no sha512 integrity hash in npm metadata, refusing to install the copilot CLI")
 	}
 
-	return meta.Dist.Tarball, nil
+	return fmt.Errorf("copilot CLI download failed its integrity check (got sha512-%s), refusing to install it", base64.StdEncoding.EncodeToString(sum))
 }
 
 // extractCopilotBinary extracts the copilot binary from an npm tarball (.tgz).
@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"crypto/sha512"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnsupportedPlatformError(t *testing.T) {
+	err := unsupportedPlatformError("linux/s390x")
+
+	assert.ErrorContains(t, err, "unsupported platform linux/s390x")
+	assert.ErrorContains(t, err, "darwin/amd64, darwin/arm64, linux/amd64")
+	assert.ErrorContains(t, err, "COPILOT_CLI_PATH")
+}
+
+func TestVerifyIntegrity(t *testing.T) {
+	sum := sha512.Sum512([]byte("tarball"))
+	good := "sha512-" + base64.StdEncoding.EncodeToString(sum[:])
+	other := sha512.Sum512([]byte("tampered"))
+	bad := "sha512-" + base64.StdEncoding.EncodeToString(other[:])
+
+	uu := map[string]struct {
+		integrity string
+		err       string
+	}{
+		"match": {
+			integrity: good,
+		},
+		"multiple": {
+			integrity: "sha1-deadbeef " + bad + " " + good,
+		},
+		"options": {
+			integrity: good + "?foo",
+		},
+		"mismatch": {
+			integrity: bad,
+			err:       "copilot CLI download failed its integrity check",
+		},
+		"missing": {
+			err: "no sha512 integrity hash in npm metadata",
+		},
+		"sha1-only": {
+			integrity: "sha1-deadbeef",
+			err:       "no sha512 integrity hash in npm metadata",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			err := verifyIntegrity(u.integrity, sum[:])
+			if u.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, u.err)
+		})
+	}
+}
+
+func TestNpmRegistry(t *testing.T) {
+	uu := map[string]struct {
+		cfg, env, e string
+	}{
+		"default": {
+			e: npmRegistryURL,
+		},
+		"env": {
+			env: "https://npm.example.com/",
+			e:   "https://npm.example.com",
+		},
+		"config-wins": {
+			cfg: "https://mirror.example.com/npm/",
+			env: "https://npm.example.com",
+			e:   "https://mirror.example.com/npm",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			t.Setenv("NPM_CONFIG_REGISTRY", u.env)
+			t.Setenv("npm_config_registry", "")
+			assert.Equal(t, u.e, npmRegistry(u.cfg))
+		})
+	}
+}
+
+func TestResolveTarballURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/@github/copilot-linux-x64/"+copilotVersion {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"dist":{"tarball":"https://mirror/copilot.tgz","integrity":"sha512-abc="}}`))
+	}))
+	defer srv.Close()
+
+	tarball, integrity, err := resolveTarballURL(srv.URL, "linux-x64")
+	require.NoError(t, err)
+	assert.Equal(t, "https://mirror/copilot.tgz", tarball)
+	assert.Equal(t, "sha512-abc=", integrity)
+
+	_, _, err = resolveTarballURL(srv.URL, "darwin-arm64")
+	assert.ErrorContains(t, err, "npm registry returned 404")
+}
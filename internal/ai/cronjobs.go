@@ -0,0 +1,290 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	copilot "github.com/github/copilot-sdk/go"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+const (
+	// defaultJobHistory is the number of recent Jobs checked per CronJob.
+	defaultJobHistory = 3
+
+	// maxJobHistory caps the recent Jobs checked per CronJob.
+	maxJobHistory = 10
+
+	// maxCronJobs caps the CronJobs scanned by find_failing_jobs.
+	maxCronJobs = 200
+)
+
+// --- find_failing_jobs tool ---
+
+type findFailingJobsParams struct {
+	Namespace string `json:"namespace,omitempty" jsonschema:"Namespace to scan. Empty means all namespaces"`
+	History   int    `json:"history,omitempty" jsonschema:"Number of recent Jobs to check per CronJob (default 3, max 10)"`
+}
+
+// cronJobHealth reports the problems found with a CronJob.
+type cronJobHealth struct {
+	Name              string   `json:"name"`
+	Namespace         string   `json:"namespace"`
+	Schedule          string   `json:"schedule"`
+	Suspended         bool     `json:"suspended,omitempty"`
+	CheckedJobs       int      `json:"checkedJobs"`
+	FailedJobs        int      `json:"failedJobs,omitempty"`
+	LastFailure       string   `json:"lastFailure,omitempty"`
+	LastFailureReason string   `json:"lastFailureReason,omitempty"`
+	LastSchedule      string   `json:"lastSchedule,omitempty"`
+	Problems          []string `json:"problems"`
+
+	lastFailure time.Time
+}
+
+func (tf *ToolFactory) findFailingJobsTool() copilot.Tool {
+	return copilot.DefineTool(
+		"find_failing_jobs",
+		"Find CronJobs in trouble in one call: failures among their most recent Jobs, missed schedules (no run for over twice the schedule period) and suspended CronJobs. "+
+			"Returns the problems ranked by most recent failure. Use for 'which scheduled jobs are failing?' questions.",
+		func(params findFailingJobsParams, inv copilot.ToolInvocation) (any, error) {
+			ns, err := tf.listNamespace(params.Namespace)
+			if err != nil {
+				return nil, err
+			}
+			history := params.History
+			if history <= 0 {
+				history = defaultJobHistory
+			}
+			history = min(history, maxJobHistory)
+
+			dial, err := tf.conn.Dial()
+			if err != nil {
+				return nil, fmt.Errorf("failed to connect to cluster: %w", err)
+			}
+			ctx := context.Background()
+			cjs, err := dial.BatchV1().CronJobs(ns).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list cronjobs: %w", err)
+			}
+			items := keepAllowed(tf, cjs.Items, func(cj batchv1.CronJob) string { return cj.Namespace })
+			result := make(map[string]any)
+			if len(items) > maxCronJobs {
+				result["truncated"] = fmt.Sprintf("scanned the first %d of %d cronjobs, pass a namespace to narrow the scan", maxCronJobs, len(items))
+				items = items[:maxCronJobs]
+			}
+			jobs, err := dial.BatchV1().Jobs(ns).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list jobs: %w", err)
+			}
+
+			owned, now := jobsByCronJob(jobs.Items), time.Now()
+			var hh []cronJobHealth
+			for i := range items {
+				if h := checkCronJob(&items[i], owned[items[i].UID], history, now); len(h.Problems) > 0 {
+					hh = append(hh, h)
+				}
+			}
+			rankCronJobs(hh)
+
+			result["scanned"] = len(items)
+			result["failing"] = len(hh)
+			result["cronJobs"] = hh
+			if len(hh) == 0 {
+				result["summary"] = "No CronJob problems found."
+			}
+
+			return result, nil
+		},
+	)
+}
+
+// jobsByCronJob groups Jobs by the UID of the CronJob controlling them.
+func jobsByCronJob(jobs []batchv1.Job) map[types.UID][]batchv1.Job {
+	m := make(map[types.UID][]batchv1.Job)
+	for i := range jobs {
+		if ref := metav1.GetControllerOf(&jobs[i]); ref != nil && ref.Kind == "CronJob" {
+			m[ref.UID] = append(m[ref.UID], jobs[i])
+		}
+	}
+
+	return m
+}
+
+// checkCronJob looks for failures among the most recent Jobs of a CronJob,
+// missed schedules and suspension.
+func checkCronJob(cj *batchv1.CronJob, jobs []batchv1.Job, history int, now time.Time) cronJobHealth {
+	h := cronJobHealth{
+		Name:      cj.Name,
+		Namespace: cj.Namespace,
+		Schedule:  cj.Spec.Schedule,
+		Suspended: cj.Spec.Suspend != nil && *cj.Spec.Suspend,
+	}
+	if t := cj.Status.LastScheduleTime; t != nil {
+		h.LastSchedule = t.UTC().Format(time.RFC3339)
+	}
+
+	jobs = slices.Clone(jobs)
+	slices.SortFunc(jobs, func(a, b batchv1.Job) int {
+		return b.CreationTimestamp.Compare(a.CreationTimestamp.Time)
+	})
+	jobs = jobs[:min(len(jobs), history)]
+	h.CheckedJobs = len(jobs)
+	for i := range jobs {
+		at, reason, ok := jobFailure(&jobs[i])
+		if !ok {
+			continue
+		}
+		h.FailedJobs++
+		if at.After(h.lastFailure) {
+			h.lastFailure, h.LastFailureReason = at, reason
+		}
+	}
+	if h.FailedJobs > 0 {
+		h.LastFailure = h.lastFailure.UTC().Format(time.RFC3339)
+		h.Problems = append(h.Problems, fmt.Sprintf("%d of the last %d jobs failed", h.FailedJobs, h.CheckedJobs))
+	}
+	if h.Suspended {
+		h.Problems = append(h.Problems, "suspended")
+	} else if missed := missedSchedule(cj, now); missed != "" {
+		h.Problems = append(h.Problems, missed)
+	}
+
+	return h
+}
+
+// jobFailure returns when and why a Job failed, if it did.
+func jobFailure(job *batchv1.Job) (time.Time, string, bool) {
+	for _, c := range job.Status.Conditions {
+		if c.Type != batchv1.JobFailed || c.Status != corev1.ConditionTrue {
+			continue
+		}
+		reason := c.Reason
+		if c.Message != "" {
+			reason += ": " + c.Message
+		}
+		return c.LastTransitionTime.Time, reason, true
+	}
+
+	return time.Time{}, "", false
+}
+
+// missedSchedule describes a CronJob that hasn't run for over twice its
+// schedule period. Schedules whose period can't be told are never reported.
+func missedSchedule(cj *batchv1.CronJob, now time.Time) string {
+	period, ok := cronPeriod(cj.Spec.Schedule)
+	if !ok {
+		return ""
+	}
+	last := cj.CreationTimestamp.Time
+	if t := cj.Status.LastScheduleTime; t != nil {
+		last = t.Time
+	}
+	since := now.Sub(last)
+	if since <= 2*period {
+		return ""
+	}
+	if cj.Status.LastScheduleTime == nil {
+		return fmt.Sprintf("never scheduled since created %s ago, expected every %s",
+			duration.HumanDuration(since), duration.HumanDuration(period))
+	}
+
+	return fmt.Sprintf("missed schedule: last run %s ago, expected every %s",
+		duration.HumanDuration(since), duration.HumanDuration(period))
+}
+
+// cronPeriod returns the longest gap between two runs of a cron schedule.
+// Only common shapes are understood: macros, @every and schedules stepping
+// evenly through minutes or hours. Other fields bound the gap by an hour, a
+// day or a week so irregular schedules are never reported too early.
+func cronPeriod(schedule string) (time.Duration, bool) {
+	const day = 24 * time.Hour
+
+	s := strings.TrimSpace(schedule)
+	if strings.HasPrefix(s, "CRON_TZ=") || strings.HasPrefix(s, "TZ=") {
+		_, s, _ = strings.Cut(s, " ")
+	}
+	switch s {
+	case "@hourly":
+		return time.Hour, true
+	case "@daily", "@midnight":
+		return day, true
+	case "@weekly":
+		return 7 * day, true
+	case "@monthly":
+		return 31 * day, true
+	case "@yearly", "@annually":
+		return 366 * day, true
+	}
+	if every, ok := strings.CutPrefix(s, "@every "); ok {
+		d, err := time.ParseDuration(strings.TrimSpace(every))
+		return d, err == nil && d > 0
+	}
+
+	ff := strings.Fields(s)
+	if len(ff) != 5 {
+		return 0, false
+	}
+	minute, hour, dom, month, dow := ff[0], ff[1], ff[2], ff[3], ff[4]
+	switch {
+	case month != "*":
+		return 0, false
+	case dom != "*":
+		return 31 * day, true
+	case dow != "*":
+		return 7 * day, true
+	case hour != "*":
+		if n, ok := evenStep(hour, 24); ok {
+			return time.Duration(n) * time.Hour, true
+		}
+		return day, true
+	case minute == "*":
+		return time.Minute, true
+	default:
+		if n, ok := evenStep(minute, 60); ok {
+			return time.Duration(n) * time.Minute, true
+		}
+		return time.Hour, true
+	}
+}
+
+// evenStep returns N for a */N cron field when N evenly divides the field
+// range.
+func evenStep(field string, size int) (int, bool) {
+	step, ok := strings.CutPrefix(field, "*/")
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.Atoi(step)
+	if err != nil || n <= 0 || size%n != 0 {
+		return 0, false
+	}
+
+	return n, true
+}
+
+// rankCronJobs puts the most recently failing CronJobs first, then the ones
+// with other problems.
+func rankCronJobs(hh []cronJobHealth) {
+	slices.SortStableFunc(hh, func(a, b cronJobHealth) int {
+		if c := b.lastFailure.Compare(a.lastFailure); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.Namespace, b.Namespace); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+}
@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestCronPeriod(t *testing.T) {
+	uu := map[string]struct {
+		schedule string
+		e        time.Duration
+		ok       bool
+	}{
+		"hourly":        {schedule: "@hourly", e: time.Hour, ok: true},
+		"every":         {schedule: "@every 90m", e: 90 * time.Minute, ok: true},
+		"bad-every":     {schedule: "@every soon"},
+		"every-minute":  {schedule: "* * * * *", e: time.Minute, ok: true},
+		"minute-step":   {schedule: "*/15 * * * *", e: 15 * time.Minute, ok: true},
+		"uneven-step":   {schedule: "*/7 * * * *", e: time.Hour, ok: true},
+		"hour-step":     {schedule: "0 */6 * * *", e: 6 * time.Hour, ok: true},
+		"business-days": {schedule: "30 9 * * 1-5", e: 7 * 24 * time.Hour, ok: true},
+		"daily":         {schedule: "0 2 * * *", e: 24 * time.Hour, ok: true},
+		"monthly":       {schedule: "0 0 1 * *", e: 31 * 24 * time.Hour, ok: true},
+		"timezone":      {schedule: "CRON_TZ=Europe/Paris 0 * * * *", e: time.Hour, ok: true},
+		"month":         {schedule: "0 0 1 6 *"},
+		"garbage":       {schedule: "nope"},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			d, ok := cronPeriod(u.schedule)
+			assert.Equal(t, u.ok, ok)
+			assert.Equal(t, u.e, d)
+		})
+	}
+}
+
+func TestCheckCronJob(t *testing.T) {
+	now := toolNow
+	cj := makeCronJob("ns1", "backup", "0 * * * *", now.Add(-30*time.Minute))
+	jobs := []batchv1.Job{
+		makeJob(cj, "backup-1", now.Add(-3*time.Hour), "BackoffLimitExceeded"),
+		makeJob(cj, "backup-2", now.Add(-2*time.Hour), ""),
+		makeJob(cj, "backup-3", now.Add(-time.Hour), "DeadlineExceeded"),
+		makeJob(cj, "backup-4", now.Add(-30*time.Minute), ""),
+	}
+
+	h := checkCronJob(cj, jobs, 3, now)
+	assert.Equal(t, 3, h.CheckedJobs)
+	assert.Equal(t, 1, h.FailedJobs)
+	assert.Equal(t, "DeadlineExceeded: job failed", h.LastFailureReason)
+	assert.Equal(t, []string{"1 of the last 3 jobs failed"}, h.Problems)
+
+	cj.Status.LastScheduleTime = &metav1.Time{Time: now.Add(-5 * time.Hour)}
+	h = checkCronJob(cj, nil, 3, now)
+	assert.Equal(t, []string{"missed schedule: last run 5h ago, expected every 60m"}, h.Problems)
+
+	suspend := true
+	cj.Spec.Suspend = &suspend
+	h = checkCronJob(cj, nil, 3, now)
+	assert.Equal(t, []string{"suspended"}, h.Problems)
+}
+
+func TestFindFailingJobsTool(t *testing.T) {
+	now := time.Now()
+	ok := makeCronJob("ns1", "ok", "@every 1h", now.Add(-10*time.Minute))
+	old := makeCronJob("ns1", "old", "@every 1h", now.Add(-10*time.Minute))
+	recent := makeCronJob("ns2", "recent", "@every 1h", now.Add(-10*time.Minute))
+	oldJob := makeJob(old, "old-1", now.Add(-2*time.Hour), "BackoffLimitExceeded")
+	recentJob := makeJob(recent, "recent-1", now.Add(-20*time.Minute), "BackoffLimitExceeded")
+	okJob := makeJob(ok, "ok-1", now.Add(-10*time.Minute), "")
+	tf := newTestToolFactory(newTestFactory(), newTestConn(ok, old, recent, &oldJob, &recentJob, &okJob))
+
+	m := callToolJSON(t, tf, "find_failing_jobs", nil)
+	assert.Equal(t, float64(3), m["scanned"])
+	assert.Equal(t, float64(2), m["failing"])
+	cc := m["cronJobs"].([]any)
+	assert.Equal(t, "recent", cc[0].(map[string]any)["name"])
+	assert.Equal(t, "old", cc[1].(map[string]any)["name"])
+
+	m = callToolJSON(t, tf, "find_failing_jobs", map[string]any{"namespace": "ns1"})
+	assert.Equal(t, float64(2), m["scanned"])
+	assert.Equal(t, float64(1), m["failing"])
+}
+
+// Helpers...
+
+func makeCronJob(ns, name, schedule string, lastRun time.Time) *batchv1.CronJob {
+	return &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         ns,
+			Name:              name,
+			UID:               types.UID(ns + "-" + name),
+			CreationTimestamp: metav1.NewTime(lastRun.Add(-24 * time.Hour)),
+		},
+		Spec:   batchv1.CronJobSpec{Schedule: schedule},
+		Status: batchv1.CronJobStatus{LastScheduleTime: &metav1.Time{Time: lastRun}},
+	}
+}
+
+// makeJob returns a Job of the CronJob, failed for the reason if one is given.
+func makeJob(cj *batchv1.CronJob, name string, at time.Time, reason string) batchv1.Job {
+	yes := true
+	job := batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         cj.Namespace,
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(at),
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "CronJob", Name: cj.Name, UID: cj.UID, Controller: &yes},
+			},
+		},
+	}
+	if reason != "" {
+		job.Status.Conditions = []batchv1.JobCondition{{
+			Type:               batchv1.JobFailed,
+			Status:             corev1.ConditionTrue,
+			Reason:             reason,
+			Message:            "job failed",
+			LastTransitionTime: metav1.NewTime(at.Add(time.Minute)),
+		}}
+	}
+
+	return job
+}
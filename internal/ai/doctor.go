@@ -0,0 +1,192 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	doctorTimeout = 45 * time.Second
+	pingPrompt    = "Reply with the single word OK."
+)
+
+// CheckState tracks the outcome of a doctor check.
+type CheckState int
+
+const (
+	// CheckPass indicates the check succeeded.
+	CheckPass CheckState = iota
+
+	// CheckFail indicates the check failed.
+	CheckFail
+
+	// CheckSkip indicates the check could not run because an earlier one failed
+	// or it does not apply to the current provider.
+	CheckSkip
+)
+
+// CheckResult describes a single doctor check.
+type CheckResult struct {
+	Name   string
+	State  CheckState
+	Detail string
+	Hint   string
+}
+
+func pass(name, detail string) CheckResult {
+	return CheckResult{Name: name, State: CheckPass, Detail: detail}
+}
+
+func fail(name, detail, hint string) CheckResult {
+	return CheckResult{Name: name, State: CheckFail, Detail: detail, Hint: hint}
+}
+
+func skip(name, detail string) CheckResult {
+	return CheckResult{Name: name, State: CheckSkip, Detail: detail}
+}
+
+// Doctor runs a series of health checks against the AI subsystem and the
+// current cluster. tf may be nil when no cluster connection is available.
+func (c *AIClient) Doctor(ctx context.Context, tf *ToolFactory) []CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, doctorTimeout)
+	defer cancel()
+
+	rr := make([]CheckResult, 0, 6)
+	c.mx.RLock()
+	cfg := c.cfg
+	c.mx.RUnlock()
+
+	if !cfg.IsEnabled() {
+		rr = append(rr, fail("Config", "AI is disabled", "Set k9s.ai.enabled: true in your k9s config"))
+		return append(rr, skip("CLI", "AI disabled"), skip("Auth", "AI disabled"),
+			skip("Models", "AI disabled"), skip("Round-trip", "AI disabled"), checkTools(tf))
+	}
+	if err := cfg.ProviderError(); err != nil {
+		rr = append(rr, fail("Config", err.Error(), "Fix the ai.provider block in your k9s config or use :byok"))
+		return append(rr, skip("CLI", "invalid config"), skip("Auth", "invalid config"),
+			skip("Models", "invalid config"), skip("Round-trip", "invalid config"), checkTools(tf))
+	}
+	provider := "copilot"
+	if cfg.IsBYOK() {
+		provider = cfg.Provider.Type + " @ " + cfg.Provider.BaseURL
+	}
+	rr = append(rr, pass("Config", fmt.Sprintf("enabled, model %q, provider %s", cfg.Model, provider)))
+
+	if err := c.Init(ctx); err != nil {
+		hint := "Install the CLI with `npm install -g @github/copilot` or set COPILOT_CLI_PATH"
+		if os.Getenv("COPILOT_CLI_PATH") != "" {
+			hint = "Check that COPILOT_CLI_PATH points to a working copilot binary"
+		}
+		if dir := c.Snapshot().CLILogDir; dir != "" {
+			hint += ". CLI logs are in " + dir
+		}
+		rr = append(rr, fail("CLI", err.Error(), hint))
+		return append(rr, skip("Auth", "CLI not running"), skip("Models", "CLI not running"),
+			skip("Round-trip", "CLI not running"), checkTools(tf))
+	}
+
+	st := c.Status(ctx)
+	cli := st.CLIPath
+	if cli == "" {
+		cli = "bundled"
+	}
+	if st.CLIVersion != "" {
+		cli += " (v" + st.CLIVersion + ")"
+	}
+	if st.CLILogDir != "" {
+		cli += ", logs in " + st.CLILogDir
+	}
+	rr = append(rr, pass("CLI", cli))
+
+	switch {
+	case cfg.IsBYOK():
+		// Credentials were already checked along with the provider config.
+		detail := "BYOK credentials present"
+		if cfg.Provider.ResolveAPIKey() == "" && cfg.Provider.ResolveBearerToken() == "" {
+			detail = "local provider, no credentials needed"
+		}
+		rr = append(rr, pass("Auth", detail))
+	case st.Authenticated:
+		detail := "authenticated"
+		if st.Login != "" {
+			detail += " as " + st.Login
+		}
+		rr = append(rr, pass("Auth", detail))
+	default:
+		detail := "not authenticated"
+		if st.AuthMessage != "" {
+			detail += ": " + st.AuthMessage
+		} else if st.Err != nil {
+			detail += ": " + st.Err.Error()
+		}
+		rr = append(rr, fail("Auth", detail,
+			"Run `gh auth login` or `copilot` once to sign in, or set ai.githubToken"))
+	}
+
+	if cfg.IsBYOK() {
+		rr = append(rr, skip("Models", "not available with BYOK providers"))
+	} else if mm, err := c.ListModels(ctx); err != nil {
+		rr = append(rr, fail("Models", err.Error(), "Check your Copilot subscription and network access"))
+	} else {
+		r := pass("Models", fmt.Sprintf("%d models available", len(mm)))
+		if !hasModel(mm, cfg.Model) {
+			r = fail("Models", fmt.Sprintf("model %q is not available to this account", cfg.Model),
+				"Pick another model with :ai models")
+		}
+		rr = append(rr, r)
+	}
+
+	start := time.Now()
+	if err := c.ping(ctx); err != nil {
+		rr = append(rr, fail("Round-trip", err.Error(), "Check network/proxy settings and that the model is reachable"))
+	} else {
+		rr = append(rr, pass("Round-trip", fmt.Sprintf("model answered in %s", time.Since(start).Round(time.Millisecond))))
+	}
+
+	return append(rr, checkTools(tf))
+}
+
+// ping sends a trivial prompt on a throwaway session with no tools.
+func (c *AIClient) ping(ctx context.Context) error {
+	resp, err := c.ask(ctx, pingPrompt)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(resp) == "" {
+		return fmt.Errorf("empty response from model")
+	}
+
+	return nil
+}
+
+func checkTools(tf *ToolFactory) CheckResult {
+	const name = "Tools"
+	if tf == nil || tf.conn == nil || !tf.conn.ConnectionOK() {
+		return fail(name, "no cluster connection", "Check your kubeconfig context and cluster reachability")
+	}
+	v, err := tf.conn.ServerVersion()
+	if err != nil {
+		return fail(name, err.Error(), "Check cluster reachability and your credentials")
+	}
+
+	return pass(name, fmt.Sprintf("%d tools built against cluster %s", len(tf.BuildTools()), v.GitVersion))
+}
+
+func hasModel(mm []ModelInfo, id string) bool {
+	if id == "" {
+		return true
+	}
+	for _, m := range mm {
+		if m.ID == id {
+			return true
+		}
+	}
+
+	return false
+}
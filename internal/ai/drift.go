@@ -0,0 +1,274 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/derailed/k9s/internal/client"
+	copilot "github.com/github/copilot-sdk/go"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// maxDriftChanges caps the differences reported by detect_drift.
+const maxDriftChanges = 100
+
+var (
+	// driftMetadata are the metadata fields owned by the manifest. The rest is
+	// maintained by the API server.
+	driftMetadata = []string{"name", "namespace", "labels", "annotations"}
+
+	// driftTracked are the live maps whose extra keys count as added, e.g. a
+	// label set by kubectl edit. Elsewhere extra live fields are mostly
+	// server defaults and are ignored.
+	driftTracked = []string{"metadata.labels", "metadata.annotations"}
+)
+
+// --- detect_drift tool ---
+
+type detectDriftParams struct {
+	GVR       string `json:"gvr" jsonschema:"Group/Version/Resource identifier, e.g. apps/v1/deployments"`
+	Name      string `json:"name" jsonschema:"Resource name"`
+	Namespace string `json:"namespace" jsonschema:"Kubernetes namespace (empty for cluster-scoped)"`
+	Desired   string `json:"desired,omitempty" jsonschema:"Desired YAML or JSON manifest, e.g. rendered from Helm or Kustomize. Defaults to the last-applied-configuration annotation"`
+}
+
+// driftChange is a field that differs between the desired and live object.
+type driftChange struct {
+	Path    string `json:"path"`
+	Change  string `json:"change"`
+	Desired any    `json:"desired,omitempty"`
+	Live    any    `json:"live,omitempty"`
+}
+
+func (tf *ToolFactory) detectDriftTool() copilot.Tool {
+	return copilot.DefineTool(
+		"detect_drift",
+		"Compare a live resource against its desired state: the manifest last applied with kubectl apply, or a given manifest (e.g. rendered from Helm or Kustomize). "+
+			"Reports added, removed and changed fields, ignoring server-managed fields and defaults. Use to answer whether someone edited a resource by hand.",
+		func(params detectDriftParams, inv copilot.ToolInvocation) (any, error) {
+			if err := tf.checkNamespace(params.Namespace); err != nil {
+				return nil, err
+			}
+			obj, err := tf.getObject(client.NewGVR(params.GVR), params.Namespace, params.Name, true)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get %s %s: %w", params.GVR, client.FQN(params.Namespace, params.Name), err)
+			}
+			live, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert to unstructured: %w", err)
+			}
+
+			source, desired := "desired manifest", map[string]any(nil)
+			if params.Desired != "" {
+				oo, err := decodeManifest(params.Desired)
+				if err != nil {
+					return nil, fmt.Errorf("invalid desired manifest: %w", err)
+				}
+				if len(oo) != 1 {
+					return nil, fmt.Errorf("desired manifest must hold a single document, got %d", len(oo))
+				}
+				desired = oo[0].Object
+			} else {
+				source = lastAppliedAnnotation
+				if desired, err = lastApplied(live); err != nil {
+					return nil, err
+				}
+			}
+
+			cc := diffObjects(stripNoise(desired), stripNoise(live))
+			res := map[string]any{
+				"resource": params.GVR + " " + client.FQN(params.Namespace, params.Name),
+				"source":   source,
+				"drifted":  len(cc) > 0,
+				"changes":  cc[:min(len(cc), maxDriftChanges)],
+			}
+			if len(cc) == 0 {
+				res["summary"] = "The live resource matches the " + source + "."
+			}
+			if len(cc) > maxDriftChanges {
+				res["truncated"] = fmt.Sprintf("showing first %d of %d changes", maxDriftChanges, len(cc))
+			}
+
+			return res, nil
+		},
+	)
+}
+
+// lastApplied decodes the last-applied-configuration of a live object.
+func lastApplied(live map[string]any) (map[string]any, error) {
+	var raw string
+	if md, ok := live["metadata"].(map[string]any); ok {
+		if aa, ok := md["annotations"].(map[string]any); ok {
+			raw, _ = aa[lastAppliedAnnotation].(string)
+		}
+	}
+	if raw == "" {
+		return nil, fmt.Errorf("no %s annotation: the resource was not applied with kubectl apply, pass the desired manifest instead", lastAppliedAnnotation)
+	}
+	var m map[string]any
+	if err := json.Unmarshal([]byte(raw), &m); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", lastAppliedAnnotation, err)
+	}
+
+	return m, nil
+}
+
+// stripNoise returns a sanitized copy of an object without status and the
+// metadata maintained by the API server, so they don't show up as drift.
+func stripNoise(o map[string]any) map[string]any {
+	o = sanitizeObject(o)
+	delete(o, "status")
+	// The metadata is already a copy.
+	if md, ok := o["metadata"].(map[string]any); ok {
+		maps.DeleteFunc(md, func(k string, _ any) bool {
+			return !slices.Contains(driftMetadata, k)
+		})
+	}
+
+	return o
+}
+
+// diffObjects compares the fields set in desired with the live object.
+func diffObjects(desired, live map[string]any) []driftChange {
+	var cc []driftChange
+	diffValue("", desired, live, &cc)
+
+	return cc
+}
+
+func diffValue(path string, desired, live any, cc *[]driftChange) {
+	switch d := desired.(type) {
+	case map[string]any:
+		l, ok := live.(map[string]any)
+		if !ok {
+			*cc = append(*cc, driftChange{Path: path, Change: "changed", Desired: desired, Live: live})
+			return
+		}
+		for _, k := range slices.Sorted(maps.Keys(d)) {
+			lv, ok := l[k]
+			if !ok {
+				*cc = append(*cc, driftChange{Path: joinPath(path, k), Change: "removed", Desired: d[k]})
+				continue
+			}
+			diffValue(joinPath(path, k), d[k], lv, cc)
+		}
+		if !slices.Contains(driftTracked, path) {
+			return
+		}
+		for _, k := range slices.Sorted(maps.Keys(l)) {
+			if _, ok := d[k]; !ok && !isSystemKey(k) {
+				*cc = append(*cc, driftChange{Path: joinPath(path, k), Change: "added", Live: l[k]})
+			}
+		}
+	case []any:
+		l, ok := live.([]any)
+		if !ok {
+			*cc = append(*cc, driftChange{Path: path, Change: "changed", Desired: desired, Live: live})
+			return
+		}
+		diffList(path, d, l, cc)
+	default:
+		if !sameScalar(desired, live) {
+			*cc = append(*cc, driftChange{Path: path, Change: "changed", Desired: desired, Live: live})
+		}
+	}
+}
+
+// isSystemKey checks if a label or annotation belongs to a kubernetes.io
+// domain, e.g. deployment.kubernetes.io/revision. Controllers set those, so
+// they are only compared when the manifest sets them too.
+func isSystemKey(k string) bool {
+	domain, _, ok := strings.Cut(k, "/")
+
+	return ok && (domain == "kubernetes.io" || strings.HasSuffix(domain, ".kubernetes.io"))
+}
+
+// diffList compares list items by name when they have one, e.g. containers
+// or env vars, by position otherwise.
+func diffList(path string, desired, live []any, cc *[]driftChange) {
+	if key := itemNames(desired); key != nil {
+		if lk := itemNames(live); lk != nil {
+			for i, n := range key {
+				j := slices.Index(lk, n)
+				p := path + "[" + n + "]"
+				if j < 0 {
+					*cc = append(*cc, driftChange{Path: p, Change: "removed", Desired: desired[i]})
+					continue
+				}
+				diffValue(p, desired[i], live[j], cc)
+			}
+			for j, n := range lk {
+				if !slices.Contains(key, n) {
+					*cc = append(*cc, driftChange{Path: path + "[" + n + "]", Change: "added", Live: live[j]})
+				}
+			}
+			return
+		}
+	}
+
+	for i := range max(len(desired), len(live)) {
+		p := path + "[" + strconv.Itoa(i) + "]"
+		switch {
+		case i >= len(live):
+			*cc = append(*cc, driftChange{Path: p, Change: "removed", Desired: desired[i]})
+		case i >= len(desired):
+			*cc = append(*cc, driftChange{Path: p, Change: "added", Live: live[i]})
+		default:
+			diffValue(p, desired[i], live[i], cc)
+		}
+	}
+}
+
+// itemNames returns the names of list items, or nil unless every item is a
+// map with a name.
+func itemNames(ll []any) []string {
+	nn := make([]string, 0, len(ll))
+	for _, it := range ll {
+		m, ok := it.(map[string]any)
+		if !ok {
+			return nil
+		}
+		n, ok := m["name"].(string)
+		if !ok || n == "" {
+			return nil
+		}
+		nn = append(nn, n)
+	}
+	if len(nn) == 0 {
+		return nil
+	}
+
+	return nn
+}
+
+// sameScalar compares scalars, treating numbers decoded as int64 or float64
+// alike.
+func sameScalar(a, b any) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+func joinPath(path, k string) string {
+	if strings.ContainsAny(k, "./") {
+		return path + "[" + k + "]"
+	}
+	if path == "" {
+		return k
+	}
+
+	return path + "." + k
+}
@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffObjects(t *testing.T) {
+	desired := map[string]any{
+		"metadata": map[string]any{
+			"name":   "web",
+			"labels": map[string]any{"app": "web"},
+		},
+		"spec": map[string]any{
+			"replicas": float64(3),
+			"containers": []any{
+				map[string]any{"name": "app", "image": "nginx:1.25"},
+				map[string]any{"name": "proxy", "image": "envoy"},
+			},
+			"args": []any{"-v"},
+		},
+	}
+	live := map[string]any{
+		"metadata": map[string]any{
+			"name":            "web",
+			"uid":             "123",
+			"resourceVersion": "42",
+			"labels":          map[string]any{"app": "web", "debug": "true"},
+			"managedFields":   []any{map[string]any{"manager": "kubectl"}},
+			"annotations": map[string]any{
+				lastAppliedAnnotation:               "{}",
+				"deployment.kubernetes.io/revision": "4",
+			},
+		},
+		"spec": map[string]any{
+			"replicas":      int64(3),
+			"dnsPolicy":     "ClusterFirst",
+			"containers":    []any{map[string]any{"name": "app", "image": "nginx:1.27", "imagePullPolicy": "IfNotPresent"}},
+			"args":          []any{"-v", "-x"},
+			"schedulerName": "default-scheduler",
+		},
+		"status": map[string]any{"phase": "Running"},
+	}
+
+	assert.Equal(t, []driftChange{
+		{Path: "metadata.labels.debug", Change: "added", Live: "true"},
+		{Path: "spec.args[1]", Change: "added", Live: "-x"},
+		{Path: "spec.containers[app].image", Change: "changed", Desired: "nginx:1.25", Live: "nginx:1.27"},
+		{Path: "spec.containers[proxy]", Change: "removed", Desired: map[string]any{"name": "proxy", "image": "envoy"}},
+	}, diffObjects(stripNoise(desired), stripNoise(live)))
+}
+
+func TestDiffObjectsNoDrift(t *testing.T) {
+	o := map[string]any{
+		"metadata": map[string]any{
+			"name":        "web",
+			"annotations": map[string]any{"team": "a", "app.kubernetes.io/name": "web"},
+		},
+		"spec": map[string]any{"replicas": int64(2)},
+	}
+
+	assert.Empty(t, diffObjects(stripNoise(o), stripNoise(o)))
+}
+
+func TestDetectDriftTool(t *testing.T) {
+	live := makePod("ns1", "p1", map[string]string{"app": "web", "hotfix": "1"})
+	live.Annotations = map[string]string{
+		lastAppliedAnnotation: `{"apiVersion":"v1","kind":"Pod","metadata":{"name":"p1","namespace":"ns1","labels":{"app":"web"}},"spec":{"containers":[{"name":"app","image":"nginx"}]}}`,
+	}
+	bare := makePod("ns1", "p2", nil)
+	tf := newTestToolFactory(newTestFactory(), newTestConn(live, bare))
+
+	m := callToolJSON(t, tf, "detect_drift", map[string]any{"gvr": "v1/pods", "namespace": "ns1", "name": "p1"})
+	assert.Equal(t, true, m["drifted"])
+	assert.Equal(t, lastAppliedAnnotation, m["source"])
+	assert.Equal(t, []any{
+		map[string]any{"path": "metadata.labels.hotfix", "change": "added", "live": "1"},
+	}, m["changes"])
+
+	m = callToolJSON(t, tf, "detect_drift", map[string]any{
+		"gvr": "v1/pods", "namespace": "ns1", "name": "p2",
+		"desired": "apiVersion: v1\nkind: Pod\nmetadata:\n  name: p2\nspec:\n  containers:\n  - name: app\n    image: nginx\n",
+	})
+	assert.Equal(t, false, m["drifted"])
+	assert.Equal(t, "The live resource matches the desired manifest.", m["summary"])
+
+	_, err := callTool(t, tf, "detect_drift", map[string]any{"gvr": "v1/pods", "namespace": "ns1", "name": "p2"})
+	assert.ErrorContains(t, err, "no kubectl.kubernetes.io/last-applied-configuration annotation")
+}
@@ -0,0 +1,211 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/derailed/k9s/internal/config"
+	copilot "github.com/github/copilot-sdk/go"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/util/exec"
+)
+
+const (
+	// execTimeout bounds an exec_command run.
+	execTimeout = 30 * time.Second
+
+	// execMaxOutput caps the stdout returned to the model.
+	execMaxOutput = 64 * 1024
+
+	// execMaxStderr caps the stderr returned to the model.
+	execMaxStderr = 8 * 1024
+)
+
+// newExecutor opens the exec stream, swapped by tests.
+var newExecutor = func(cfg *restclient.Config, u *url.URL) (remotecommand.Executor, error) {
+	return remotecommand.NewSPDYExecutor(cfg, "POST", u)
+}
+
+// --- exec_command tool ---
+
+type execCommandParams struct {
+	PodName   string   `json:"podName" jsonschema:"Pod name"`
+	Namespace string   `json:"namespace" jsonschema:"Kubernetes namespace"`
+	Container string   `json:"container,omitempty" jsonschema:"Container name (defaults to the first container)"`
+	Command   []string `json:"command" jsonschema:"Command and its arguments, run without a shell, e.g. [\"cat\", \"/etc/nginx/nginx.conf\"]"`
+}
+
+func (tf *ToolFactory) execCommandTool() copilot.Tool {
+	allow := tf.cfg.ExecAllowlist
+	if len(allow) == 0 {
+		allow = slices.Clone(config.DefaultAIExecAllowlist)
+	}
+
+	return copilot.DefineTool(
+		"exec_command",
+		"Run a non-interactive command inside a container and return its stdout, stderr and exit code, e.g. to read a config file or check a listening socket. "+
+			"The command runs without a shell, so pipes and redirections don't work. Allowed command prefixes: "+strings.Join(allow, ", ")+". "+
+			"Paths under service account token and secret mounts are off limits. Requires user approval and is rejected when K9s is read-only.",
+		func(params execCommandParams, inv copilot.ToolInvocation) (any, error) {
+			if len(params.Command) == 0 {
+				return nil, errors.New("command is required")
+			}
+			if arg, ok := config.ExecTouches(params.Command, config.AIExecDeniedPaths); ok {
+				return nil, fmt.Errorf("command argument %q is not allowed: it reads a secret or service account token mount", arg)
+			}
+			if !tf.cfg.ExecAllowed(params.Command) {
+				return nil, fmt.Errorf("command %q is not allowed (allowed prefixes: %s)", params.Command[0], strings.Join(allow, ", "))
+			}
+			if tf.isReadOnly() {
+				return nil, errors.New("exec_command is not allowed: K9s is in read-only mode")
+			}
+			if err := tf.checkNamespace(params.Namespace); err != nil {
+				return nil, err
+			}
+			pod, err := tf.getPod(params.Namespace, params.PodName, true)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get pod %s/%s: %w", params.Namespace, params.PodName, err)
+			}
+			co, err := execContainer(pod, params.Container)
+			if err != nil {
+				return nil, err
+			}
+			if arg, ok := config.ExecTouches(params.Command, secretMounts(pod, co)); ok {
+				return nil, fmt.Errorf("command argument %q is not allowed: it reads a secret or service account token mount", arg)
+			}
+			tf.log.Info("Running command in pod", "ns", params.Namespace, "pod", params.PodName, "container", co, "command", params.Command)
+
+			return tf.execCommand(params.Namespace, params.PodName, co, params.Command)
+		},
+	)
+}
+
+// execContainer picks the container to run in, the first one by default.
+func execContainer(pod *corev1.Pod, co string) (string, error) {
+	if co == "" {
+		if len(pod.Spec.Containers) == 0 {
+			return "", fmt.Errorf("pod %s has no containers", pod.Name)
+		}
+		return pod.Spec.Containers[0].Name, nil
+	}
+	for _, c := range pod.Spec.Containers {
+		if c.Name == co {
+			return co, nil
+		}
+	}
+
+	return "", fmt.Errorf("container %q not found in pod %s", co, pod.Name)
+}
+
+// secretMounts returns where the container mounts secrets and service
+// account tokens.
+func secretMounts(pod *corev1.Pod, co string) []string {
+	vv := make(map[string]struct{})
+	for _, v := range pod.Spec.Volumes {
+		if v.Secret != nil {
+			vv[v.Name] = struct{}{}
+		}
+		if v.Projected == nil {
+			continue
+		}
+		for _, src := range v.Projected.Sources {
+			if src.Secret != nil || src.ServiceAccountToken != nil {
+				vv[v.Name] = struct{}{}
+			}
+		}
+	}
+
+	var mm []string
+	for _, c := range pod.Spec.Containers {
+		if c.Name != co {
+			continue
+		}
+		for _, m := range c.VolumeMounts {
+			if _, ok := vv[m.Name]; ok {
+				mm = append(mm, m.MountPath)
+			}
+		}
+	}
+
+	return mm
+}
+
+func (tf *ToolFactory) execCommand(ns, pod, co string, cmd []string) (any, error) {
+	cfg, err := tf.conn.RestConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to cluster: %w", err)
+	}
+	rc, err := coreRESTClient(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to cluster: %w", err)
+	}
+	req := rc.Post().
+		Resource("pods").
+		Namespace(ns).
+		Name(pod).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: co,
+			Command:   cmd,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+	e, err := newExecutor(cfg, req.URL())
+	if err != nil {
+		return nil, fmt.Errorf("failed to exec in pod %s/%s: %w", ns, pod, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), execTimeout)
+	defer cancel()
+
+	stdout, stderr := newCappedBuffer(execMaxOutput), newCappedBuffer(execMaxStderr)
+	err = e.StreamWithContext(ctx, remotecommand.StreamOptions{Stdout: stdout, Stderr: stderr})
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("%s timed out after %s", cmd[0], execTimeout)
+	}
+	// A non-zero exit is reported to the model along with stderr.
+	var exitCode int
+	var exitErr exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		exitCode = exitErr.ExitStatus()
+	case err != nil:
+		return nil, fmt.Errorf("failed to exec in pod %s/%s: %w", ns, pod, err)
+	}
+
+	result := map[string]any{
+		"command":   strings.Join(cmd, " "),
+		"container": co,
+		"exitCode":  exitCode,
+		"stdout":    stdout.String(),
+	}
+	if s := strings.TrimSpace(stderr.String()); s != "" {
+		result["stderr"] = s
+	}
+	if stdout.truncated {
+		result["truncated"] = fmt.Sprintf("output capped at %d bytes, narrow the command", execMaxOutput)
+	}
+
+	return result, nil
+}
+
+// coreRESTClient builds a core/v1 REST client for the exec subresource.
+func coreRESTClient(cfg *restclient.Config) (*restclient.RESTClient, error) {
+	c := restclient.CopyConfig(cfg)
+	c.APIPath = "/api"
+	c.GroupVersion = &corev1.SchemeGroupVersion
+	c.NegotiatedSerializer = scheme.Codecs.WithoutConversion()
+
+	return restclient.RESTClientFor(c)
+}
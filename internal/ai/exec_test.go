@@ -0,0 +1,143 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"testing"
+
+	"github.com/derailed/k9s/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/util/exec"
+)
+
+// fakeExecutor streams canned output.
+type fakeExecutor struct {
+	stdout, stderr string
+	err            error
+}
+
+func (e *fakeExecutor) Stream(opts remotecommand.StreamOptions) error {
+	return e.StreamWithContext(context.Background(), opts)
+}
+
+func (e *fakeExecutor) StreamWithContext(_ context.Context, opts remotecommand.StreamOptions) error {
+	_, _ = io.WriteString(opts.Stdout, e.stdout)
+	_, _ = io.WriteString(opts.Stderr, e.stderr)
+
+	return e.err
+}
+
+func TestExecCommandTool(t *testing.T) {
+	uu := map[string]struct {
+		args     map[string]any
+		exec     fakeExecutor
+		readOnly bool
+		e        map[string]any
+		err      string
+	}{
+		"happy": {
+			args: map[string]any{"podName": "p1", "namespace": "ns1", "command": []any{"cat", "/etc/hosts"}},
+			exec: fakeExecutor{stdout: "127.0.0.1 localhost\n"},
+			e: map[string]any{
+				"command":   "cat /etc/hosts",
+				"container": "app",
+				"exitCode":  float64(0),
+				"stdout":    "127.0.0.1 localhost\n",
+			},
+		},
+		"exit-code": {
+			args: map[string]any{"podName": "p1", "namespace": "ns1", "container": "app", "command": []any{"ls", "/nope"}},
+			exec: fakeExecutor{
+				stderr: "ls: /nope: No such file or directory\n",
+				err:    exec.CodeExitError{Err: io.EOF, Code: 2},
+			},
+			e: map[string]any{
+				"command":   "ls /nope",
+				"container": "app",
+				"exitCode":  float64(2),
+				"stdout":    "",
+				"stderr":    "ls: /nope: No such file or directory",
+			},
+		},
+		"not-allowed": {
+			args: map[string]any{"podName": "p1", "namespace": "ns1", "command": []any{"rm", "-rf", "/"}},
+			err:  `command "rm" is not allowed`,
+		},
+		"service-account-token": {
+			args: map[string]any{"podName": "p1", "namespace": "ns1", "command": []any{"cat", "/var/run/secrets/kubernetes.io/serviceaccount/token"}},
+			err:  `command argument "/var/run/secrets/kubernetes.io/serviceaccount/token" is not allowed`,
+		},
+		"secret-mount": {
+			args: map[string]any{"podName": "p1", "namespace": "ns1", "command": []any{"cat", "/etc/creds/password"}},
+			err:  `command argument "/etc/creds/password" is not allowed`,
+		},
+		"no-command": {
+			args: map[string]any{"podName": "p1", "namespace": "ns1"},
+			err:  "command is required",
+		},
+		"read-only": {
+			args:     map[string]any{"podName": "p1", "namespace": "ns1", "command": []any{"ls"}},
+			readOnly: true,
+			err:      "K9s is in read-only mode",
+		},
+		"bad-container": {
+			args: map[string]any{"podName": "p1", "namespace": "ns1", "container": "sidecar", "command": []any{"ls"}},
+			err:  `container "sidecar" not found in pod p1`,
+		},
+		"no-pod": {
+			args: map[string]any{"podName": "p2", "namespace": "ns1", "command": []any{"ls"}},
+			err:  "failed to get pod ns1/p2",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			orig := newExecutor
+			defer func() { newExecutor = orig }()
+			newExecutor = func(_ *restclient.Config, u2 *url.URL) (remotecommand.Executor, error) {
+				assert.Equal(t, "/api/v1/namespaces/ns1/pods/p1/exec", u2.Path)
+				return &u.exec, nil
+			}
+
+			po := makePod("ns1", "p1", nil)
+			po.Spec.Volumes = []corev1.Volume{{
+				Name:         "creds",
+				VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "creds"}},
+			}}
+			po.Spec.Containers[0].VolumeMounts = []corev1.VolumeMount{{Name: "creds", MountPath: "/etc/creds"}}
+			tf := newTestToolFactory(newTestFactory(), newTestConn(po))
+			tf.cfg.AllowExec = true
+			tf.SetReadOnlyFunc(func() bool { return u.readOnly })
+
+			if u.err != "" {
+				_, err := callTool(t, tf, "exec_command", u.args)
+				assert.ErrorContains(t, err, u.err)
+				return
+			}
+			assert.Equal(t, u.e, callToolJSON(t, tf, "exec_command", u.args))
+		})
+	}
+}
+
+func TestExecCommandToolDisabled(t *testing.T) {
+	tf := newTestToolFactory(newTestFactory(), newTestConn())
+	for _, tool := range tf.BuildTools() {
+		assert.NotEqual(t, "exec_command", tool.Name)
+	}
+
+	tf.cfg = config.AI{AllowExec: true}
+	var found bool
+	for _, tool := range tf.BuildTools() {
+		found = found || tool.Name == "exec_command"
+	}
+	require.True(t, found)
+}
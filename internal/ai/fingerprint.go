@@ -0,0 +1,203 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	fingerprintTimeout = 10 * time.Second
+	fingerprintNodes   = 100
+)
+
+// fingerprints caches cluster fingerprints by kube context.
+var (
+	fingerprints   = make(map[string]string)
+	fingerprintsMx sync.Mutex
+)
+
+// cniDaemonSets maps well known kube-system daemonsets to their network plugin.
+var cniDaemonSets = []struct {
+	prefix, cni string
+}{
+	{"aws-node", "AWS VPC CNI"},
+	{"calico-node", "Calico"},
+	{"cilium", "Cilium"},
+	{"anetd", "GKE Dataplane V2 (Cilium)"},
+	{"kube-flannel", "Flannel"},
+	{"flannel", "Flannel"},
+	{"weave-net", "Weave Net"},
+	{"antrea-agent", "Antrea"},
+	{"kube-router", "kube-router"},
+	{"azure-cns", "Azure CNI"},
+	{"canal", "Canal"},
+	{"kindnet", "kindnet"},
+	{"ovnkube-node", "OVN-Kubernetes"},
+}
+
+// Fingerprint returns a short description of the cluster environment (version,
+// provider, CNI) to ground the model's advice. Results are cached per context.
+func (tf *ToolFactory) Fingerprint(ctx context.Context) string {
+	if tf.conn == nil || !tf.conn.ConnectionOK() {
+		return ""
+	}
+	kctx := tf.conn.ActiveContext()
+	fingerprintsMx.Lock()
+	fp, ok := fingerprints[kctx]
+	fingerprintsMx.Unlock()
+	if ok {
+		return fp
+	}
+
+	fp = tf.fingerprint(ctx, kctx)
+	if fp != "" {
+		fingerprintsMx.Lock()
+		fingerprints[kctx] = fp
+		fingerprintsMx.Unlock()
+	}
+
+	return fp
+}
+
+func (tf *ToolFactory) fingerprint(ctx context.Context, kctx string) string {
+	ctx, cancel := context.WithTimeout(ctx, fingerprintTimeout)
+	defer cancel()
+
+	var version string
+	if v, err := tf.conn.ServerVersion(); err == nil {
+		version = v.GitVersion
+	}
+	dial, err := tf.conn.Dial()
+	if err != nil {
+		tf.log.Debug("Cluster fingerprint skipped", "error", err)
+		return formatFingerprint(kctx, version, detectProvider(nil, version), nil, nil)
+	}
+
+	var nodes []corev1.Node
+	if nn, err := dial.CoreV1().Nodes().List(ctx, metav1.ListOptions{Limit: fingerprintNodes}); err == nil {
+		nodes = nn.Items
+	}
+	var dss []string
+	if ll, err := dial.AppsV1().DaemonSets("kube-system").List(ctx, metav1.ListOptions{}); err == nil {
+		for i := range ll.Items {
+			dss = append(dss, ll.Items[i].Name)
+		}
+	}
+
+	return formatFingerprint(kctx, version, detectProvider(nodes, version), detectCNI(dss), nodes)
+}
+
+// detectProvider guesses the hosting platform from node metadata and the
+// server version.
+func detectProvider(nodes []corev1.Node, version string) string {
+	for i := range nodes {
+		n := &nodes[i]
+		ll := n.Labels
+		switch {
+		case ll["node.openshift.io/os_id"] != "":
+			return "OpenShift"
+		case hasLabelPrefix(ll, "eks.amazonaws.com/"):
+			return "AWS EKS"
+		case hasLabelPrefix(ll, "cloud.google.com/gke-"):
+			return "Google GKE"
+		case hasLabelPrefix(ll, "kubernetes.azure.com/"):
+			return "Azure AKS"
+		case hasLabelPrefix(ll, "doks.digitalocean.com/"):
+			return "DigitalOcean DOKS"
+		case hasLabelPrefix(ll, "minikube.k8s.io/"):
+			return "minikube"
+		}
+		switch scheme, _, _ := strings.Cut(n.Spec.ProviderID, "://"); scheme {
+		case "aws":
+			return "AWS"
+		case "gce":
+			return "Google Cloud"
+		case "azure":
+			return "Azure"
+		case "kind":
+			return "kind"
+		case "k3s":
+			return "k3s"
+		}
+		if strings.Contains(n.Status.NodeInfo.KubeletVersion, "+k3s") {
+			return "k3s"
+		}
+	}
+	switch {
+	case strings.Contains(version, "-eks-"):
+		return "AWS EKS"
+	case strings.Contains(version, "-gke."):
+		return "Google GKE"
+	case strings.Contains(version, "+k3s"):
+		return "k3s"
+	case strings.Contains(version, "+rke2"):
+		return "RKE2"
+	}
+
+	return ""
+}
+
+func hasLabelPrefix(ll map[string]string, prefix string) bool {
+	for k := range ll {
+		if strings.HasPrefix(k, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// detectCNI returns the network plugins hinted at by kube-system daemonsets.
+func detectCNI(dss []string) []string {
+	var cc []string
+	for _, ds := range dss {
+		for _, c := range cniDaemonSets {
+			if strings.HasPrefix(ds, c.prefix) && !slices.Contains(cc, c.cni) {
+				cc = append(cc, c.cni)
+			}
+		}
+	}
+
+	return cc
+}
+
+func formatFingerprint(kctx, version, provider string, cni []string, nodes []corev1.Node) string {
+	var b strings.Builder
+	b.WriteString("[CLUSTER ENVIRONMENT]\n")
+	if kctx != "" {
+		fmt.Fprintf(&b, "- Context: %s\n", kctx)
+	}
+	if version != "" {
+		fmt.Fprintf(&b, "- Kubernetes: %s\n", version)
+	}
+	if provider != "" {
+		fmt.Fprintf(&b, "- Provider: %s\n", provider)
+	}
+	if len(cni) > 0 {
+		fmt.Fprintf(&b, "- CNI: %s\n", strings.Join(cni, ", "))
+	}
+	if len(nodes) > 0 {
+		info := nodes[0].Status.NodeInfo
+		count := fmt.Sprintf("%d", len(nodes))
+		if len(nodes) == fingerprintNodes {
+			count += "+"
+		}
+		fmt.Fprintf(&b, "- Nodes: %s (%s/%s, %s)\n", count, info.OperatingSystem, info.Architecture, info.ContainerRuntimeVersion)
+	}
+	if version == "" && provider == "" && len(cni) == 0 && len(nodes) == 0 {
+		return ""
+	}
+	b.WriteString("Tailor recommendations to this environment; do not suggest fixes specific to other providers.")
+
+	return b.String()
+}
@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDetectProvider(t *testing.T) {
+	node := func(labels map[string]string, providerID string) corev1.Node {
+		return corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Labels: labels},
+			Spec:       corev1.NodeSpec{ProviderID: providerID},
+		}
+	}
+
+	uu := map[string]struct {
+		nodes   []corev1.Node
+		version string
+		e       string
+	}{
+		"eks-label": {
+			nodes: []corev1.Node{node(map[string]string{"eks.amazonaws.com/nodegroup": "ng1"}, "aws:///us-east-1a/i-1")},
+			e:     "AWS EKS",
+		},
+		"gke-label": {
+			nodes: []corev1.Node{node(map[string]string{"cloud.google.com/gke-nodepool": "pool"}, "")},
+			e:     "Google GKE",
+		},
+		"aks-label": {
+			nodes: []corev1.Node{node(map[string]string{"kubernetes.azure.com/cluster": "c1"}, "")},
+			e:     "Azure AKS",
+		},
+		"provider-id": {
+			nodes: []corev1.Node{node(nil, "kind://docker/kind/kind-control-plane")},
+			e:     "kind",
+		},
+		"version": {
+			version: "v1.29.3-eks-adc7111",
+			e:       "AWS EKS",
+		},
+		"unknown": {
+			nodes:   []corev1.Node{node(nil, "")},
+			version: "v1.30.0",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, detectProvider(u.nodes, u.version))
+		})
+	}
+}
+
+func TestDetectCNI(t *testing.T) {
+	assert.Equal(t,
+		[]string{"Calico", "Cilium"},
+		detectCNI([]string{"kube-proxy", "calico-node", "cilium", "cilium-envoy", "ebs-csi-node"}),
+	)
+	assert.Empty(t, detectCNI([]string{"kube-proxy"}))
+}
+
+func TestFormatFingerprint(t *testing.T) {
+	nodes := []corev1.Node{{Status: corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{
+		OperatingSystem:         "linux",
+		Architecture:            "arm64",
+		ContainerRuntimeVersion: "containerd://1.7.2",
+	}}}}
+
+	assert.Equal(t, `[CLUSTER ENVIRONMENT]
+- Context: prod
+- Kubernetes: v1.29.3-eks-adc7111
+- Provider: AWS EKS
+- CNI: AWS VPC CNI
+- Nodes: 1 (linux/arm64, containerd://1.7.2)
+Tailor recommendations to this environment; do not suggest fixes specific to other providers.`,
+		formatFingerprint("prod", "v1.29.3-eks-adc7111", "AWS EKS", []string{"AWS VPC CNI"}, nodes))
+
+	assert.Empty(t, formatFingerprint("prod", "", "", nil, nil))
+}
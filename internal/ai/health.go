@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Health grades of resource table rows.
+const (
+	HealthHealthy  = "healthy"
+	HealthDegraded = "degraded"
+	HealthCritical = "critical"
+)
+
+// maxGradeRows caps the rows graded by a single model call.
+const maxGradeRows = 20
+
+var healthGrades = []string{HealthHealthy, HealthDegraded, HealthCritical}
+
+// HealthRow is a resource table row to grade.
+type HealthRow struct {
+	ID     string
+	Fields []string
+}
+
+const gradePrompt = `Grade the health of these Kubernetes %s from their table rows.
+Columns: %s
+Rows:
+%s
+Answer with a single JSON object mapping each row id to "healthy", "degraded" or "critical", and nothing else.`
+
+// GradeHealth asks the model to grade resource table rows, on a throwaway
+// session so the chat is left alone. Rows past maxGradeRows are ignored and
+// rows the model doesn't grade are left out of the result.
+func (c *AIClient) GradeHealth(ctx context.Context, kind string, header []string, rows []HealthRow) (map[string]string, error) {
+	if !c.IsEnabled() || !c.isInitialized() {
+		return nil, fmt.Errorf("AI not ready")
+	}
+	rows = rows[:min(len(rows), maxGradeRows)]
+	var b strings.Builder
+	for _, r := range rows {
+		fmt.Fprintf(&b, "%s: %s\n", r.ID, strings.Join(r.Fields, " | "))
+	}
+	prompt := fmt.Sprintf(gradePrompt, kind, strings.Join(header, " | "), b.String())
+
+	resp, err := c.ask(ctx, NewRedactor(c.cfg.Redaction).Redact(prompt))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseGrades(resp, rows)
+}
+
+// parseGrades reads the grades from a model answer, skipping unknown rows
+// and grades. The JSON object may be wrapped in prose or a code fence.
+func parseGrades(resp string, rows []HealthRow) (map[string]string, error) {
+	start, end := strings.Index(resp, "{"), strings.LastIndex(resp, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("no grades in model answer")
+	}
+	var raw map[string]string
+	if err := json.Unmarshal([]byte(resp[start:end+1]), &raw); err != nil {
+		return nil, fmt.Errorf("malformed grades: %w", err)
+	}
+
+	grades := make(map[string]string, len(raw))
+	for _, r := range rows {
+		g := strings.ToLower(strings.TrimSpace(raw[r.ID]))
+		if slices.Contains(healthGrades, g) {
+			grades[r.ID] = g
+		}
+	}
+
+	return grades, nil
+}
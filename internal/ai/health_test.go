@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/derailed/k9s/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGrades(t *testing.T) {
+	rows := []HealthRow{{ID: "ns/a"}, {ID: "ns/b"}, {ID: "ns/c"}}
+
+	uu := map[string]struct {
+		resp string
+		e    map[string]string
+		err  bool
+	}{
+		"plain": {
+			resp: `{"ns/a": "healthy", "ns/b": "Critical"}`,
+			e:    map[string]string{"ns/a": HealthHealthy, "ns/b": HealthCritical},
+		},
+		"fenced": {
+			resp: "Here you go:\n```json\n{\"ns/c\": \"degraded\"}\n```",
+			e:    map[string]string{"ns/c": HealthDegraded},
+		},
+		"unknown": {
+			resp: `{"ns/a": "fine", "ns/z": "critical"}`,
+			e:    map[string]string{},
+		},
+		"none":      {resp: "I can't tell.", err: true},
+		"malformed": {resp: `{"ns/a": 1}`, err: true},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			grades, err := parseGrades(u.resp, rows)
+			if u.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, u.e, grades)
+		})
+	}
+}
+
+func TestGradeHealthNotReady(t *testing.T) {
+	_, err := NewAIClient(config.AI{}, nil).GradeHealth(context.Background(), "pods", nil, []HealthRow{{ID: "ns/a"}})
+	require.Error(t, err)
+}
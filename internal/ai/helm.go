@@ -0,0 +1,194 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"bytes"
+	"cmp"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/derailed/k9s/internal/client"
+	copilot "github.com/github/copilot-sdk/go"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// helmReleaseType is the type of the Secrets Helm v3 stores releases in.
+	helmReleaseType = "helm.sh/release.v1"
+
+	// maxHelmHistory caps the revisions returned for a single release.
+	maxHelmHistory = 10
+)
+
+var gzipMagic = []byte{0x1f, 0x8b, 0x08}
+
+// --- get_helm_release tool ---
+
+type getHelmReleaseParams struct {
+	Namespace string `json:"namespace,omitempty" jsonschema:"Namespace to scan. Empty means all namespaces"`
+	Name      string `json:"name,omitempty" jsonschema:"Release name. Empty lists every release"`
+}
+
+// helmRelease summarizes a release revision.
+type helmRelease struct {
+	Name         string `json:"name"`
+	Namespace    string `json:"namespace"`
+	Chart        string `json:"chart"`
+	ChartVersion string `json:"chartVersion"`
+	AppVersion   string `json:"appVersion,omitempty"`
+	Revision     int    `json:"revision"`
+	Status       string `json:"status"`
+	LastDeployed string `json:"lastDeployed,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+// helmPayload holds the fields read from a release payload, leaving the
+// values and manifests out.
+type helmPayload struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Version   int    `json:"version"`
+	Info      struct {
+		Status       string    `json:"status"`
+		LastDeployed time.Time `json:"last_deployed"`
+		Description  string    `json:"description"`
+	} `json:"info"`
+	Chart struct {
+		Metadata struct {
+			Name       string `json:"name"`
+			Version    string `json:"version"`
+			AppVersion string `json:"appVersion"`
+		} `json:"metadata"`
+	} `json:"chart"`
+}
+
+func (tf *ToolFactory) getHelmReleaseTool() copilot.Tool {
+	return copilot.DefineTool(
+		"get_helm_release",
+		"List the Helm v3 releases of a namespace with their chart, chart version, revision, status and last deploy time, read from the release Secrets. "+
+			"With a name, returns that release along with its recent revisions. "+
+			"Use it when resources carry the app.kubernetes.io/managed-by=Helm label or meta.helm.sh/release-name annotation: "+
+			"changes to them belong in the chart values, a kubectl edit is undone by the next upgrade.",
+		func(params getHelmReleaseParams, inv copilot.ToolInvocation) (any, error) {
+			ns, err := tf.listNamespace(params.Namespace)
+			if err != nil {
+				return nil, err
+			}
+			dial, err := tf.conn.Dial()
+			if err != nil {
+				return nil, fmt.Errorf("failed to connect to cluster: %w", err)
+			}
+			sel := "owner=helm"
+			if params.Name != "" {
+				sel += ",name=" + params.Name
+			}
+			list, err := dial.CoreV1().Secrets(ns).List(context.Background(), metav1.ListOptions{LabelSelector: sel})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list helm release secrets: %w", err)
+			}
+
+			revs := tf.helmRevisions(keepAllowed(tf, list.Items, func(s corev1.Secret) string { return s.Namespace }))
+			if params.Name == "" {
+				return map[string]any{
+					"count":    len(revs),
+					"releases": latestHelmReleases(revs),
+				}, nil
+			}
+			if len(revs) == 0 {
+				where := "any namespace"
+				if ns != "" {
+					where = "namespace " + ns
+				}
+				return nil, fmt.Errorf("no helm release %q found in %s", params.Name, where)
+			}
+			res := map[string]any{"release": revs[0]}
+			if len(revs) > 1 {
+				res["history"] = revs[1:min(len(revs), maxHelmHistory)]
+			}
+
+			return res, nil
+		},
+	)
+}
+
+// helmRevisions decodes release Secrets, newest revision first per release.
+func (tf *ToolFactory) helmRevisions(ss []corev1.Secret) []helmRelease {
+	rr := make([]helmRelease, 0, len(ss))
+	for i := range ss {
+		if ss[i].Type != helmReleaseType {
+			continue
+		}
+		r, err := decodeHelmRelease(ss[i].Data["release"])
+		if err != nil {
+			tf.log.Warn("Skipping undecodable helm release", "secret", client.FQN(ss[i].Namespace, ss[i].Name), "error", err)
+			continue
+		}
+		if r.Namespace == "" {
+			r.Namespace = ss[i].Namespace
+		}
+		rr = append(rr, r)
+	}
+	slices.SortFunc(rr, func(a, b helmRelease) int {
+		return cmp.Or(
+			cmp.Compare(a.Namespace, b.Namespace),
+			cmp.Compare(a.Name, b.Name),
+			cmp.Compare(b.Revision, a.Revision),
+		)
+	})
+
+	return rr
+}
+
+// latestHelmReleases keeps the newest revision of each release.
+func latestHelmReleases(rr []helmRelease) []helmRelease {
+	return slices.CompactFunc(slices.Clone(rr), func(a, b helmRelease) bool {
+		return a.Namespace == b.Namespace && a.Name == b.Name
+	})
+}
+
+// decodeHelmRelease decodes a release payload, base64 encoded JSON that Helm
+// gzips by default.
+func decodeHelmRelease(data []byte) (helmRelease, error) {
+	b, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil {
+		return helmRelease{}, err
+	}
+	if bytes.HasPrefix(b, gzipMagic) {
+		r, err := gzip.NewReader(bytes.NewReader(b))
+		if err != nil {
+			return helmRelease{}, err
+		}
+		defer r.Close()
+		if b, err = io.ReadAll(r); err != nil {
+			return helmRelease{}, err
+		}
+	}
+	var p helmPayload
+	if err := json.Unmarshal(b, &p); err != nil {
+		return helmRelease{}, err
+	}
+	r := helmRelease{
+		Name:         p.Name,
+		Namespace:    p.Namespace,
+		Chart:        p.Chart.Metadata.Name,
+		ChartVersion: p.Chart.Metadata.Version,
+		AppVersion:   p.Chart.Metadata.AppVersion,
+		Revision:     p.Version,
+		Status:       p.Info.Status,
+		Description:  p.Info.Description,
+	}
+	if !p.Info.LastDeployed.IsZero() {
+		r.LastDeployed = toolTime(p.Info.LastDeployed)
+	}
+
+	return r, nil
+}
@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func makeHelmSecret(t *testing.T, ns, name string, rev int, status string, zip bool) *corev1.Secret {
+	t.Helper()
+
+	raw := fmt.Sprintf(`{"name":%q,"namespace":%q,"version":%d,"info":{"status":%q,"last_deployed":%q,"description":"Upgrade complete"},`+
+		`"chart":{"metadata":{"name":"nginx","version":"1.%d.0","appVersion":"1.27"}},"config":{"password":"s3cret"}}`,
+		name, ns, rev, status, toolNow.Add(time.Duration(rev)*time.Hour).Format(time.RFC3339), rev)
+	payload := []byte(raw)
+	if zip {
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		_, err := w.Write(payload)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		payload = buf.Bytes()
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("sh.helm.release.v1.%s.v%d", name, rev),
+			Namespace: ns,
+			Labels:    map[string]string{"owner": "helm", "name": name, "status": status},
+		},
+		Type: helmReleaseType,
+		Data: map[string][]byte{"release": []byte(base64.StdEncoding.EncodeToString(payload))},
+	}
+}
+
+func TestGetHelmReleaseTool(t *testing.T) {
+	bad := makeHelmSecret(t, "ns1", "broken", 1, "deployed", true)
+	bad.Data["release"] = []byte("not base64!")
+	objs := []runtime.Object{
+		makeHelmSecret(t, "ns1", "web", 1, "superseded", true),
+		makeHelmSecret(t, "ns1", "web", 2, "deployed", false),
+		makeHelmSecret(t, "ns1", "api", 1, "failed", true),
+		makeHelmSecret(t, "ns2", "web", 1, "deployed", true),
+		bad,
+	}
+
+	uu := map[string]struct {
+		args     map[string]any
+		releases []string
+		release  string
+		history  []string
+		err      string
+	}{
+		"all": {
+			releases: []string{"ns1/api:1:failed", "ns1/web:2:deployed", "ns2/web:1:deployed"},
+		},
+		"namespace": {
+			args:     map[string]any{"namespace": "ns1"},
+			releases: []string{"ns1/api:1:failed", "ns1/web:2:deployed"},
+		},
+		"named": {
+			args:    map[string]any{"namespace": "ns1", "name": "web"},
+			release: "ns1/web:2:deployed",
+			history: []string{"ns1/web:1:superseded"},
+		},
+		"missing": {
+			args: map[string]any{"namespace": "ns1", "name": "db"},
+			err:  `no helm release "db" found in namespace ns1`,
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			tf := newTestToolFactory(newTestFactory(), newTestConn(objs...))
+
+			if u.err != "" {
+				_, err := callTool(t, tf, "get_helm_release", u.args)
+				assert.EqualError(t, err, u.err)
+				return
+			}
+			raw, err := callTool(t, tf, "get_helm_release", u.args)
+			require.NoError(t, err)
+			assert.NotContains(t, raw, "s3cret")
+			m := callToolJSON(t, tf, "get_helm_release", u.args)
+			if u.release != "" {
+				assert.Equal(t, u.release, helmRef(m["release"]))
+				var hh []string
+				for _, h := range m["history"].([]any) {
+					hh = append(hh, helmRef(h))
+				}
+				assert.Equal(t, u.history, hh)
+				return
+			}
+			var rr []string
+			for _, r := range m["releases"].([]any) {
+				rr = append(rr, helmRef(r))
+			}
+			assert.Equal(t, u.releases, rr)
+		})
+	}
+}
+
+func TestDecodeHelmRelease(t *testing.T) {
+	sec := makeHelmSecret(t, "ns1", "web", 3, "deployed", true)
+
+	r, err := decodeHelmRelease(sec.Data["release"])
+	require.NoError(t, err)
+	assert.Equal(t, "web", r.Name)
+	assert.Equal(t, "ns1", r.Namespace)
+	assert.Equal(t, "nginx", r.Chart)
+	assert.Equal(t, "1.3.0", r.ChartVersion)
+	assert.Equal(t, "1.27", r.AppVersion)
+	assert.Equal(t, 3, r.Revision)
+	assert.Equal(t, "Upgrade complete", r.Description)
+	assert.Contains(t, r.LastDeployed, "2024-05-01T13:00:00Z")
+
+	_, err = decodeHelmRelease([]byte(base64.StdEncoding.EncodeToString([]byte("{"))))
+	assert.Error(t, err)
+}
+
+func helmRef(o any) string {
+	m := o.(map[string]any)
+	return fmt.Sprintf("%s/%s:%v:%s", m["namespace"], m["name"], m["revision"], m["status"])
+}
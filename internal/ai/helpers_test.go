@@ -0,0 +1,184 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"encoding/json"
+	"errors"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/derailed/k9s/internal/client"
+	"github.com/derailed/k9s/internal/config"
+	"github.com/derailed/k9s/internal/dao"
+	"github.com/derailed/k9s/internal/watch"
+	copilot "github.com/github/copilot-sdk/go"
+	"github.com/stretchr/testify/require"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery/cached/disk"
+	"k8s.io/client-go/dynamic"
+	dynfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	restclient "k8s.io/client-go/rest"
+)
+
+// testFactory serves cached resources from an in-memory inventory. Its
+// informers all run unless cold is set.
+type testFactory struct {
+	inventory map[string]map[*client.GVR][]runtime.Object
+	cold      bool
+}
+
+var _ dao.Factory = (*testFactory)(nil)
+
+func newTestFactory() *testFactory {
+	return &testFactory{inventory: make(map[string]map[*client.GVR][]runtime.Object)}
+}
+
+func (f *testFactory) add(gvr *client.GVR, o runtime.Object) {
+	raw, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+	if err != nil {
+		panic(err)
+	}
+	u := &unstructured.Unstructured{Object: raw}
+	if f.inventory[u.GetNamespace()] == nil {
+		f.inventory[u.GetNamespace()] = make(map[*client.GVR][]runtime.Object)
+	}
+	f.inventory[u.GetNamespace()][gvr] = append(f.inventory[u.GetNamespace()][gvr], u)
+}
+
+func (*testFactory) Client() client.Connection {
+	return nil
+}
+
+func (f *testFactory) Get(gvr *client.GVR, fqn string, _ bool, _ labels.Selector) (runtime.Object, error) {
+	ns, n := path.Split(fqn)
+	for _, o := range f.inventory[strings.Trim(ns, "/")][gvr] {
+		if o.(*unstructured.Unstructured).GetName() == n {
+			return o, nil
+		}
+	}
+
+	return nil, kerrors.NewNotFound(*gvr.GR(), n)
+}
+
+func (f *testFactory) List(gvr *client.GVR, ns string, _ bool, sel labels.Selector) ([]runtime.Object, error) {
+	var oo []runtime.Object
+	for n, m := range f.inventory {
+		if !client.IsClusterWide(ns) && n != ns {
+			continue
+		}
+		for _, o := range m[gvr] {
+			if sel == nil || sel.Matches(labels.Set(o.(*unstructured.Unstructured).GetLabels())) {
+				oo = append(oo, o)
+			}
+		}
+	}
+
+	return oo, nil
+}
+
+func (f *testFactory) IsWatching(*client.GVR, string) bool {
+	return !f.cold
+}
+
+func (*testFactory) ForResource(string, *client.GVR) (informers.GenericInformer, error) {
+	return nil, nil
+}
+
+func (*testFactory) CanForResource(string, *client.GVR, []string) (informers.GenericInformer, error) {
+	return nil, nil
+}
+
+func (*testFactory) WaitForCacheSync() {}
+
+func (*testFactory) Forwarders() watch.Forwarders {
+	return nil
+}
+
+func (*testFactory) DeleteForwarder(string) {}
+
+// testConn is a connection backed by fake typed and dynamic clients. Calls
+// not implemented here panic through the nil embedded connection.
+type testConn struct {
+	client.Connection
+
+	dial   *fake.Clientset
+	dyn    *dynfake.FakeDynamicClient
+	denied []string // verb/resource pairs refused by CanI
+}
+
+func newTestConn(oo ...runtime.Object) *testConn {
+	return &testConn{
+		dial: fake.NewClientset(oo...),
+		dyn:  dynfake.NewSimpleDynamicClient(scheme.Scheme, oo...),
+	}
+}
+
+func (c *testConn) Dial() (kubernetes.Interface, error) { return c.dial, nil }
+func (c *testConn) DynDial() (dynamic.Interface, error) { return c.dyn, nil }
+func (*testConn) ConnectionOK() bool                    { return true }
+func (*testConn) HasMetrics() bool                      { return false }
+func (*testConn) ActiveContext() string                 { return "test" }
+func (*testConn) ServerVersion() (*version.Info, error) {
+	return &version.Info{GitVersion: "v1.35.0"}, nil
+}
+func (*testConn) RestConfig() (*restclient.Config, error) {
+	return &restclient.Config{Host: "https://test"}, nil
+}
+func (*testConn) CachedDiscovery() (*disk.CachedDiscoveryClient, error) {
+	return nil, errors.New("no discovery")
+}
+
+func (c *testConn) CanI(_ string, gvr *client.GVR, _ string, verbs []string) (bool, error) {
+	for _, v := range verbs {
+		for _, d := range c.denied {
+			if d == v+"/"+gvr.R() {
+				return false, nil
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// callTool runs the named tool of a factory with the given arguments.
+func callTool(t *testing.T, tf *ToolFactory, name string, args map[string]any) (string, error) {
+	t.Helper()
+
+	for _, tool := range tf.BuildTools() {
+		if tool.Name != name {
+			continue
+		}
+		res, err := tool.Handler(copilot.ToolInvocation{ToolName: name, Arguments: args})
+		return res.TextResultForLLM, err
+	}
+	require.Failf(t, "unknown tool", "no tool named %q", name)
+
+	return "", nil
+}
+
+// callToolJSON runs the named tool and decodes its JSON result.
+func callToolJSON(t *testing.T, tf *ToolFactory, name string, args map[string]any) map[string]any {
+	t.Helper()
+
+	raw, err := callTool(t, tf, name, args)
+	require.NoError(t, err)
+	var m map[string]any
+	require.NoError(t, json.Unmarshal([]byte(raw), &m))
+
+	return m
+}
+
+func newTestToolFactory(f *testFactory, conn *testConn) *ToolFactory {
+	return NewToolFactory(f, conn, config.AI{}, nil)
+}
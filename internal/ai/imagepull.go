@@ -0,0 +1,306 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	copilot "github.com/github/copilot-sdk/go"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// dockerHub is the registry of images without a registry host.
+const dockerHub = "docker.io"
+
+// Image pull failure causes.
+const (
+	pullNotFound       = "notFound"
+	pullUnauthorized   = "unauthorized"
+	pullNotFoundOrAuth = "notFoundOrUnauthorized"
+	pullRateLimited    = "rateLimited"
+	pullUnreachable    = "registryUnreachable"
+	pullInvalidName    = "invalidImageName"
+	pullNeverPull      = "neverPull"
+	pullUnknown        = "unknown"
+)
+
+var (
+	// pullReasons are the waiting reasons of containers whose image can't be
+	// pulled.
+	pullReasons = []string{"ErrImagePull", "ImagePullBackOff", "InvalidImageName", "ErrImageNeverPull"}
+
+	// pullCauses map markers of registry error messages to a cause, checked in
+	// order since messages often carry several, e.g. a 404 from a registry
+	// hiding private repositories reads "pull access denied, repository does
+	// not exist or may require authorization".
+	pullCauses = []struct {
+		cause   string
+		markers []string
+	}{
+		{pullRateLimited, []string{"toomanyrequests", "rate limit", "too many requests"}},
+		{pullNotFoundOrAuth, []string{"may require authorization", "repository does not exist"}},
+		{pullUnauthorized, []string{"unauthorized", "authentication required", "no basic auth credentials", "access denied", "forbidden"}},
+		{pullNotFound, []string{"manifest unknown", "not found"}},
+		{pullUnreachable, []string{"no such host", "i/o timeout", "connection refused", "dial tcp", "x509", "tls:", "context deadline exceeded"}},
+	}
+
+	// pullHints tell the model where to look for each cause.
+	pullHints = map[string]string{
+		pullNotFound:       "The image or tag doesn't exist: check the image name and tag for typos and that the tag was pushed.",
+		pullUnauthorized:   "The registry rejected the credentials: check that an imagePullSecret covers the registry and that its credentials are valid and not expired.",
+		pullNotFoundOrAuth: "The registry hides private repositories: either the repository doesn't exist or credentials are missing. Check the name first, then the imagePullSecrets.",
+		pullRateLimited:    "The registry is rate limiting pulls: authenticate the pulls with an imagePullSecret, use a mirror or pull-through cache, or wait for the limit to reset.",
+		pullUnreachable:    "The node can't reach the registry: check DNS, network policies, proxies and TLS certificates from the node.",
+		pullInvalidName:    "The image reference is malformed: fix the image name.",
+		pullNeverPull:      "imagePullPolicy is Never but the image isn't on the node: preload the image or change the pull policy.",
+		pullUnknown:        "Read the event message for details.",
+	}
+)
+
+// --- diagnose_image_pull tool ---
+
+type diagnoseImagePullParams struct {
+	PodName   string `json:"podName" jsonschema:"Pod name"`
+	Namespace string `json:"namespace" jsonschema:"Pod namespace"`
+}
+
+// imagePullFailure is a container whose image can't be pulled.
+type imagePullFailure struct {
+	Container string `json:"container"`
+	Image     string `json:"image"`
+	Registry  string `json:"registry"`
+	Reason    string `json:"reason"`
+	Message   string `json:"message,omitempty"`
+	Cause     string `json:"cause"`
+	Hint      string `json:"hint"`
+	// Covered tells whether a valid-looking pull secret holds credentials
+	// for the registry.
+	Covered bool `json:"coveredByPullSecret"`
+}
+
+// pullSecretCheck reports whether an imagePullSecret looks usable, without
+// its credentials.
+type pullSecretCheck struct {
+	Name       string   `json:"name"`
+	Source     string   `json:"source"`
+	Type       string   `json:"type,omitempty"`
+	Registries []string `json:"registries,omitempty"`
+	Problem    string   `json:"problem,omitempty"`
+}
+
+func (tf *ToolFactory) diagnoseImagePullTool() copilot.Tool {
+	return copilot.DefineTool(
+		"diagnose_image_pull",
+		"Diagnose ErrImagePull/ImagePullBackOff for a pod: identifies the registry of each failing image, categorizes the cause from the pull error "+
+			"(notFound, unauthorized, rateLimited, registryUnreachable...) and checks that the imagePullSecrets of the pod and its service account exist, "+
+			"are well formed and cover the registry. Use instead of guessing the cause from the waiting reason. Credentials are never returned.",
+		func(params diagnoseImagePullParams, inv copilot.ToolInvocation) (any, error) {
+			if err := tf.checkNamespace(params.Namespace); err != nil {
+				return nil, err
+			}
+			pod, err := tf.getPod(params.Namespace, params.PodName, true)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get pod %s/%s: %w", params.Namespace, params.PodName, err)
+			}
+			// Events are best effort, the waiting message may tell the cause.
+			events, _ := tf.listEvents(pod.Namespace, pod.Name, true)
+
+			ff := imagePullFailures(pod, events)
+			result := map[string]any{
+				"pod":       pod.Name,
+				"namespace": pod.Namespace,
+				"failures":  ff,
+			}
+			if len(ff) == 0 {
+				result["summary"] = "No container of the pod is failing to pull its image."
+				return result, nil
+			}
+
+			ss := tf.checkPullSecrets(pod)
+			for i := range ff {
+				ff[i].Covered = slices.ContainsFunc(ss, func(s pullSecretCheck) bool {
+					return s.Problem == "" && slices.Contains(s.Registries, ff[i].Registry)
+				})
+			}
+			result["pullSecrets"] = ss
+			if len(ss) == 0 {
+				result["pullSecretsNote"] = "Neither the pod nor its service account " + cmp.Or(pod.Spec.ServiceAccountName, "default") + " sets imagePullSecrets."
+			}
+
+			return result, nil
+		},
+	)
+}
+
+// imagePullFailures returns the containers of a pod waiting on their image,
+// with the cause told from the waiting message or the latest pull event.
+func imagePullFailures(pod *corev1.Pod, events []corev1.Event) []imagePullFailure {
+	specImages := make(map[string]string)
+	for _, c := range slices.Concat(pod.Spec.InitContainers, pod.Spec.Containers) {
+		specImages[c.Name] = c.Image
+	}
+
+	var ff []imagePullFailure
+	for _, cs := range slices.Concat(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses) {
+		w := cs.State.Waiting
+		if w == nil || !slices.Contains(pullReasons, w.Reason) {
+			continue
+		}
+		image := cmp.Or(specImages[cs.Name], cs.Image)
+		msg := w.Message
+		if ev := latestPullEvent(events, image); ev != "" && classifyPullError(w.Reason, msg) == pullUnknown {
+			msg = ev
+		}
+		cause := classifyPullError(w.Reason, msg)
+		ff = append(ff, imagePullFailure{
+			Container: cs.Name,
+			Image:     image,
+			Registry:  imageRegistry(image),
+			Reason:    w.Reason,
+			Message:   msg,
+			Cause:     cause,
+			Hint:      pullHints[cause],
+		})
+	}
+
+	return ff
+}
+
+// latestPullEvent returns the message of the latest failed pull of an image.
+// ImagePullBackOff waiting messages only say the pull is backing off, the
+// registry error is in the events.
+func latestPullEvent(events []corev1.Event, image string) string {
+	var (
+		msg    string
+		latest time.Time
+	)
+	for i := range events {
+		ev := &events[i]
+		if ev.Reason != "Failed" || !strings.Contains(ev.Message, image) {
+			continue
+		}
+		if at := eventTime(ev); msg == "" || at.After(latest) {
+			msg, latest = ev.Message, at
+		}
+	}
+
+	return msg
+}
+
+// classifyPullError tells the cause of a pull failure from the waiting reason
+// and the registry error message.
+func classifyPullError(reason, msg string) string {
+	switch reason {
+	case "InvalidImageName":
+		return pullInvalidName
+	case "ErrImageNeverPull":
+		return pullNeverPull
+	}
+	msg = strings.ToLower(msg)
+	for _, c := range pullCauses {
+		if slices.ContainsFunc(c.markers, func(m string) bool { return strings.Contains(msg, m) }) {
+			return c.cause
+		}
+	}
+
+	return pullUnknown
+}
+
+// imageRegistry returns the registry host of an image reference. Images
+// without a host come from Docker Hub.
+func imageRegistry(image string) string {
+	host, _, ok := strings.Cut(image, "/")
+	if !ok || (!strings.ContainsAny(host, ".:") && host != "localhost") {
+		return dockerHub
+	}
+
+	return normalizeRegistry(host)
+}
+
+// normalizeRegistry turns a docker config auths key, e.g.
+// https://index.docker.io/v1/, into a registry host.
+func normalizeRegistry(s string) string {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "https://"), "http://")
+	s, _, _ = strings.Cut(s, "/")
+	switch s {
+	case "index.docker.io", "registry-1.docker.io", "registry.hub.docker.com":
+		return dockerHub
+	}
+
+	return strings.ToLower(s)
+}
+
+// checkPullSecrets checks the imagePullSecrets of a pod and its service
+// account.
+func (tf *ToolFactory) checkPullSecrets(pod *corev1.Pod) []pullSecretCheck {
+	dial, err := tf.conn.Dial()
+	if err != nil {
+		return []pullSecretCheck{{Name: "*", Problem: "failed to connect to cluster: " + err.Error()}}
+	}
+	ctx := context.Background()
+
+	var ss []pullSecretCheck
+	seen := make(map[string]bool)
+	add := func(refs []corev1.LocalObjectReference, source string) {
+		for _, ref := range refs {
+			if seen[ref.Name] {
+				continue
+			}
+			seen[ref.Name] = true
+			sec, err := dial.CoreV1().Secrets(pod.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+			if err != nil {
+				ss = append(ss, pullSecretCheck{Name: ref.Name, Source: source, Problem: err.Error()})
+				continue
+			}
+			ss = append(ss, checkPullSecret(sec, source))
+		}
+	}
+	add(pod.Spec.ImagePullSecrets, "pod")
+	sa := cmp.Or(pod.Spec.ServiceAccountName, "default")
+	if acct, err := dial.CoreV1().ServiceAccounts(pod.Namespace).Get(ctx, sa, metav1.GetOptions{}); err == nil {
+		add(acct.ImagePullSecrets, "serviceAccount/"+sa)
+	}
+
+	return ss
+}
+
+// checkPullSecret tells whether a secret is a well formed docker config and
+// which registries it holds credentials for.
+func checkPullSecret(sec *corev1.Secret, source string) pullSecretCheck {
+	c := pullSecretCheck{Name: sec.Name, Source: source, Type: string(sec.Type)}
+	var cfg struct {
+		Auths map[string]json.RawMessage `json:"auths"`
+	}
+	switch sec.Type {
+	case corev1.SecretTypeDockerConfigJson:
+		if err := json.Unmarshal(sec.Data[corev1.DockerConfigJsonKey], &cfg); err != nil {
+			c.Problem = "malformed " + corev1.DockerConfigJsonKey + ": " + err.Error()
+			return c
+		}
+	case corev1.SecretTypeDockercfg:
+		if err := json.Unmarshal(sec.Data[corev1.DockerConfigKey], &cfg.Auths); err != nil {
+			c.Problem = "malformed " + corev1.DockerConfigKey + ": " + err.Error()
+			return c
+		}
+	default:
+		c.Problem = fmt.Sprintf("type %s is not a pull secret, expected %s", sec.Type, corev1.SecretTypeDockerConfigJson)
+		return c
+	}
+	for k := range cfg.Auths {
+		c.Registries = append(c.Registries, normalizeRegistry(k))
+	}
+	slices.Sort(c.Registries)
+	if len(c.Registries) == 0 {
+		c.Problem = "holds no registry credentials"
+	}
+
+	return c
+}
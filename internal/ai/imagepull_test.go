@@ -0,0 +1,165 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestClassifyPullError(t *testing.T) {
+	uu := map[string]struct {
+		reason, msg, e string
+	}{
+		"not-found": {
+			reason: "ErrImagePull",
+			msg:    `rpc error: code = NotFound desc = failed to pull and unpack image "ghcr.io/acme/api:v9": ghcr.io/acme/api:v9: not found`,
+			e:      pullNotFound,
+		},
+		"manifest-unknown": {
+			reason: "ErrImagePull",
+			msg:    "manifest unknown: manifest unknown",
+			e:      pullNotFound,
+		},
+		"unauthorized": {
+			reason: "ErrImagePull",
+			msg:    "failed to authorize: failed to fetch anonymous token: unexpected status: 401 Unauthorized",
+			e:      pullUnauthorized,
+		},
+		"hidden": {
+			reason: "ErrImagePull",
+			msg:    "pull access denied for acme/api, repository does not exist or may require 'docker login': denied: requested access to the resource is denied",
+			e:      pullNotFoundOrAuth,
+		},
+		"rate-limited": {
+			reason: "ErrImagePull",
+			msg:    "429 Too Many Requests - Server message: toomanyrequests: You have reached your pull rate limit.",
+			e:      pullRateLimited,
+		},
+		"unreachable": {
+			reason: "ErrImagePull",
+			msg:    "dial tcp: lookup registry.internal on 10.96.0.10:53: no such host",
+			e:      pullUnreachable,
+		},
+		"invalid":    {reason: "InvalidImageName", msg: "couldn't parse image reference", e: pullInvalidName},
+		"never-pull": {reason: "ErrImageNeverPull", e: pullNeverPull},
+		"backoff":    {reason: "ImagePullBackOff", msg: `Back-off pulling image "nginx:nope"`, e: pullUnknown},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, classifyPullError(u.reason, u.msg))
+		})
+	}
+}
+
+func TestImageRegistry(t *testing.T) {
+	uu := map[string]string{
+		"nginx":                           dockerHub,
+		"library/nginx:1.27":              dockerHub,
+		"docker.io/library/nginx":         dockerHub,
+		"ghcr.io/acme/api:v1":             "ghcr.io",
+		"registry.internal:5000/team/app": "registry.internal:5000",
+		"localhost/app":                   "localhost",
+		"123.dkr.ecr.us-east-1.amazonaws.com/app@sha256:abc": "123.dkr.ecr.us-east-1.amazonaws.com",
+	}
+
+	for image, e := range uu {
+		assert.Equal(t, e, imageRegistry(image), image)
+	}
+	assert.Equal(t, dockerHub, normalizeRegistry("https://index.docker.io/v1/"))
+}
+
+func TestCheckPullSecret(t *testing.T) {
+	uu := map[string]struct {
+		secret     *corev1.Secret
+		registries []string
+		problem    string
+	}{
+		"dockerconfigjson": {
+			secret:     pullSecret(corev1.SecretTypeDockerConfigJson, `{"auths":{"https://index.docker.io/v1/":{"auth":"eDp5"},"ghcr.io":{"auth":"eDp5"}}}`),
+			registries: []string{dockerHub, "ghcr.io"},
+		},
+		"dockercfg": {
+			secret:     pullSecret(corev1.SecretTypeDockercfg, `{"quay.io":{"auth":"eDp5"}}`),
+			registries: []string{"quay.io"},
+		},
+		"malformed": {
+			secret:  pullSecret(corev1.SecretTypeDockerConfigJson, `{"auths":`),
+			problem: "malformed .dockerconfigjson",
+		},
+		"empty": {
+			secret:  pullSecret(corev1.SecretTypeDockerConfigJson, `{"auths":{}}`),
+			problem: "holds no registry credentials",
+		},
+		"opaque": {
+			secret:  pullSecret(corev1.SecretTypeOpaque, ""),
+			problem: "is not a pull secret",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			c := checkPullSecret(u.secret, "pod")
+			assert.Equal(t, u.registries, c.Registries)
+			if u.problem == "" {
+				assert.Empty(t, c.Problem)
+			} else {
+				assert.Contains(t, c.Problem, u.problem)
+			}
+		})
+	}
+}
+
+func TestDiagnoseImagePullTool(t *testing.T) {
+	pod := makePod("ns1", "p1", nil)
+	pod.Spec.Containers = []corev1.Container{{Name: "app", Image: "ghcr.io/acme/api:v2"}}
+	pod.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "ghcr"}, {Name: "gone"}}
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name:  "app",
+		State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: `Back-off pulling image "ghcr.io/acme/api:v2"`}},
+	}}
+	failed := makeEvent("ns1", "e1", "p1", "Warning", "Failed", toolNow)
+	failed.Message = `Failed to pull image "ghcr.io/acme/api:v2": 403 Forbidden`
+	sec := pullSecret(corev1.SecretTypeDockerConfigJson, `{"auths":{"ghcr.io":{"auth":"eDp5"}}}`)
+	sec.Namespace, sec.Name = "ns1", "ghcr"
+	tf := newTestToolFactory(newTestFactory(), newTestConn(pod, failed, sec))
+
+	m := callToolJSON(t, tf, "diagnose_image_pull", map[string]any{"namespace": "ns1", "podName": "p1"})
+	ff := m["failures"].([]any)
+	require.Len(t, ff, 1)
+	f := ff[0].(map[string]any)
+	assert.Equal(t, "ghcr.io", f["registry"])
+	assert.Equal(t, pullUnauthorized, f["cause"])
+	assert.Equal(t, failed.Message, f["message"])
+	assert.Equal(t, true, f["coveredByPullSecret"])
+
+	ss := m["pullSecrets"].([]any)
+	require.Len(t, ss, 2)
+	assert.Equal(t, []any{"ghcr.io"}, ss[0].(map[string]any)["registries"])
+	assert.Contains(t, ss[1].(map[string]any)["problem"], "not found")
+	raw, err := callTool(t, tf, "diagnose_image_pull", map[string]any{"namespace": "ns1", "podName": "p1"})
+	require.NoError(t, err)
+	assert.NotContains(t, raw, "eDp5")
+}
+
+// Helpers...
+
+func pullSecret(typ corev1.SecretType, cfg string) *corev1.Secret {
+	sec := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "creds"}, Type: typ}
+	switch typ {
+	case corev1.SecretTypeDockerConfigJson:
+		sec.Data = map[string][]byte{corev1.DockerConfigJsonKey: []byte(cfg)}
+	case corev1.SecretTypeDockercfg:
+		sec.Data = map[string][]byte{corev1.DockerConfigKey: []byte(cfg)}
+	}
+
+	return sec
+}
@@ -0,0 +1,141 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/derailed/k9s/internal/client"
+	copilot "github.com/github/copilot-sdk/go"
+)
+
+// IntentStep is a single step of a plan declared with report_intent.
+type IntentStep struct {
+	Action    string `json:"action" jsonschema:"What the step does, e.g. Scale deployment web to 3 replicas"`
+	Tool      string `json:"tool,omitempty" jsonschema:"Tool the step calls, if any"`
+	Mutating  bool   `json:"mutating,omitempty" jsonschema:"True if the step changes the cluster"`
+	GVR       string `json:"gvr,omitempty" jsonschema:"For mutating steps, the gvr argument of the call, e.g. apps/v1/deployments"`
+	Namespace string `json:"namespace,omitempty" jsonschema:"For mutating steps, the namespace the call targets"`
+	Name      string `json:"name,omitempty" jsonschema:"For mutating steps, the name of the resource the call targets"`
+}
+
+// Target returns the resource a step declares, e.g. apps/v1/deployments
+// ns1/web, or blank if it declares none.
+func (s IntentStep) Target() string {
+	if s.Name == "" {
+		return ""
+	}
+
+	return strings.TrimSpace(s.GVR + " " + client.FQN(s.Namespace, s.Name))
+}
+
+// carriesOut reports whether a mutation tool call is the one the step
+// declared: same tool, resource, namespace and name.
+func (s IntentStep) carriesOut(toolName string, args map[string]any) bool {
+	if !s.Mutating || s.Tool != toolName {
+		return false
+	}
+	gvr, ns, name := mutationTarget(toolName, args)
+
+	return name != "" && s.GVR == gvr && s.Namespace == ns && s.Name == name
+}
+
+// Intent is a plan the model declares before acting on it.
+type Intent struct {
+	Goal  string       `json:"goal" jsonschema:"What the plan sets out to achieve"`
+	Steps []IntentStep `json:"steps" jsonschema:"Steps in the order they will be taken"`
+}
+
+// IntentFunc shows a declared plan to the user. When approve is set it must
+// block until the user decides and return true if the plan is approved.
+type IntentFunc func(intent Intent, approve bool) bool
+
+// Mutations returns the number of mutating steps in the plan.
+func (i Intent) Mutations() int {
+	return len(i.mutatingSteps())
+}
+
+// mutatingSteps returns the steps of the plan that change the cluster.
+func (i Intent) mutatingSteps() []IntentStep {
+	var ss []IntentStep
+	for _, s := range i.Steps {
+		if s.Mutating {
+			ss = append(ss, s)
+		}
+	}
+
+	return ss
+}
+
+// String renders the plan as plain text, one numbered step per line.
+func (i Intent) String() string {
+	var b strings.Builder
+	b.WriteString(i.Goal)
+	for n, s := range i.Steps {
+		fmt.Fprintf(&b, "\n%d. %s", n+1, s.Action)
+		if s.Tool != "" {
+			fmt.Fprintf(&b, " [%s]", s.Tool)
+		}
+		if t := s.Target(); t != "" {
+			fmt.Fprintf(&b, " on %s", t)
+		}
+		if s.Mutating {
+			b.WriteString(" (mutating)")
+		}
+	}
+
+	return b.String()
+}
+
+// planStep returns the index of the approved plan step a mutation tool call
+// carries out, or -1 if there is none. The caller must hold the lock.
+func (c *AIClient) planStep(toolName string, args map[string]any) int {
+	return slices.IndexFunc(c.planSteps, func(s IntentStep) bool {
+		return s.carriesOut(toolName, args)
+	})
+}
+
+// --- report_intent tool ---
+
+func (c *AIClient) reportIntentTool() copilot.Tool {
+	return copilot.DefineTool(
+		"report_intent",
+		"Declare your plan before a multi-step investigation or any change: the goal and the steps you intend to take, "+
+			"marking the ones that change the cluster. For those, give the tool along with the gvr, namespace and name it targets: "+
+			"an approved plan only covers calls matching them. The plan is shown to the user and may need their approval. Never makes changes itself.",
+		func(in Intent, inv copilot.ToolInvocation) (any, error) {
+			if len(in.Steps) == 0 {
+				return nil, errors.New("a plan needs at least one step")
+			}
+			c.mx.RLock()
+			fn := c.intentFn
+			approve := c.cfg.ApprovePlans && in.Mutations() > 0
+			c.mx.RUnlock()
+
+			if fn == nil {
+				return "Plan noted. Proceed with it.", nil
+			}
+			if !approve {
+				fn(in, false)
+				return "The plan is shown to the user. Proceed with it.", nil
+			}
+			if !fn(in, true) {
+				c.log.Info("Plan rejected by the user", "goal", in.Goal)
+				return "The user REJECTED this plan. Do NOT carry out any of its steps. Ask the user how they would like to proceed.", nil
+			}
+
+			c.mx.Lock()
+			c.planSteps = in.mutatingSteps()
+			c.planPresented = false
+			c.mx.Unlock()
+			c.log.Info("Plan approved by the user", "goal", in.Goal, "mutations", in.Mutations())
+
+			return fmt.Sprintf("The user APPROVED this plan. Carry it out now. Its %d mutating step(s) will run without further confirmation "+
+				"when called with the tool, gvr, namespace and name they declare. Any other change needs the user's confirmation.", in.Mutations()), nil
+		},
+	)
+}
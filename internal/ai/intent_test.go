@@ -0,0 +1,160 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"testing"
+
+	"github.com/derailed/k9s/internal/config"
+	copilot "github.com/github/copilot-sdk/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntentString(t *testing.T) {
+	in := Intent{
+		Goal: "Fix OOMKilled web pods",
+		Steps: []IntentStep{
+			{Action: "Check memory usage", Tool: "top_pods"},
+			{Action: "Raise the memory limit to 512Mi", Tool: "patch_resource", Mutating: true, GVR: "apps/v1/deployments", Namespace: "ns1", Name: "web"},
+			{Action: "Verify the rollout"},
+		},
+	}
+
+	assert.Equal(t, 1, in.Mutations())
+	assert.Equal(t, "Fix OOMKilled web pods\n"+
+		"1. Check memory usage [top_pods]\n"+
+		"2. Raise the memory limit to 512Mi [patch_resource] on apps/v1/deployments ns1/web (mutating)\n"+
+		"3. Verify the rollout", in.String())
+}
+
+func TestReportIntentTool(t *testing.T) {
+	plan := map[string]any{
+		"goal": "Scale web",
+		"steps": []any{
+			map[string]any{"action": "Scale web to 3", "tool": "scale_resource", "mutating": true},
+			map[string]any{"action": "Restart web", "tool": "restart_resource", "mutating": true},
+		},
+	}
+
+	uu := map[string]struct {
+		approvePlans, approved bool
+		args                   map[string]any
+		asked                  bool
+		mutations              int
+		err                    bool
+	}{
+		"shown": {
+			args: plan,
+		},
+		"approved": {
+			approvePlans: true,
+			approved:     true,
+			args:         plan,
+			asked:        true,
+			mutations:    2,
+		},
+		"rejected": {
+			approvePlans: true,
+			args:         plan,
+			asked:        true,
+		},
+		"read-only": {
+			approvePlans: true,
+			args: map[string]any{
+				"goal":  "Find the crash",
+				"steps": []any{map[string]any{"action": "Read logs", "tool": "get_logs"}},
+			},
+		},
+		"no-steps": {
+			args: map[string]any{"goal": "Nothing"},
+			err:  true,
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			c := NewAIClient(config.AI{ApprovePlans: u.approvePlans}, nil)
+			var (
+				shown Intent
+				asked bool
+			)
+			c.SetIntentFunc(func(in Intent, approve bool) bool {
+				shown, asked = in, approve
+				return u.approved
+			})
+
+			res, err := c.reportIntentTool().Handler(copilot.ToolInvocation{ToolName: "report_intent", Arguments: u.args})
+			if u.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, res.TextResultForLLM)
+			assert.Equal(t, u.args["goal"], shown.Goal)
+			assert.Equal(t, u.asked, asked)
+			assert.Len(t, c.planSteps, u.mutations)
+		})
+	}
+}
+
+func TestPlanStep(t *testing.T) {
+	c := NewAIClient(config.AI{ApprovePlans: true}, nil)
+	c.SetIntentFunc(func(Intent, bool) bool { return true })
+	_, err := c.reportIntentTool().Handler(copilot.ToolInvocation{ToolName: "report_intent", Arguments: map[string]any{
+		"goal": "Scale web",
+		"steps": []any{
+			map[string]any{"action": "Check web", "tool": "get_resource"},
+			map[string]any{
+				"action": "Scale web to 3", "tool": "scale_resource", "mutating": true,
+				"gvr": "apps/v1/deployments", "namespace": "ns1", "name": "web",
+			},
+		},
+	}})
+	require.NoError(t, err)
+
+	uu := map[string]struct {
+		tool string
+		args map[string]any
+		e    int
+	}{
+		"declared": {
+			tool: "scale_resource",
+			args: map[string]any{"gvr": "apps/v1/deployments", "namespace": "ns1", "name": "web", "replicas": 3},
+		},
+		"other-name": {
+			tool: "scale_resource",
+			args: map[string]any{"gvr": "apps/v1/deployments", "namespace": "ns1", "name": "db", "replicas": 3},
+			e:    -1,
+		},
+		"other-namespace": {
+			tool: "scale_resource",
+			args: map[string]any{"gvr": "apps/v1/deployments", "namespace": "ns2", "name": "web", "replicas": 3},
+			e:    -1,
+		},
+		"other-gvr": {
+			tool: "scale_resource",
+			args: map[string]any{"gvr": "apps/v1/statefulsets", "namespace": "ns1", "name": "web", "replicas": 3},
+			e:    -1,
+		},
+		"other-tool": {
+			tool: "delete_resource",
+			args: map[string]any{"gvr": "apps/v1/deployments", "namespace": "ns1", "name": "web"},
+			e:    -1,
+		},
+		"no-target": {
+			tool: "run_kubectl",
+			args: map[string]any{"command": "scale deploy/web -n ns1 --replicas 3"},
+			e:    -1,
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, c.planStep(u.tool, u.args))
+		})
+	}
+}
@@ -0,0 +1,455 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	copilot "github.com/github/copilot-sdk/go"
+)
+
+const (
+	kubectlTimeout   = 30 * time.Second
+	kubectlMaxOutput = 64 * 1024
+	kubectlMaxStderr = 4 * 1024
+)
+
+var (
+	// kubectlReadVerbs are always allowed.
+	kubectlReadVerbs = []string{
+		"get", "describe", "top", "api-resources", "api-versions", "explain", "version",
+	}
+
+	// kubectlMutatingVerbs are rejected in read-only mode and otherwise go
+	// through the mutation approval flow.
+	kubectlMutatingVerbs = []string{
+		"annotate", "autoscale", "cordon", "create", "delete", "drain", "expose",
+		"label", "patch", "rollout", "scale", "set", "taint", "uncordon",
+	}
+
+	// kubectlVerbFlags lists the flags each verb accepts. Anything else, such
+	// as context, credential, file, template file or watch flags, is rejected.
+	kubectlVerbFlags = map[string]kubectlFlags{
+		"get": kubectlFlagSets(kubectlNSFlags, kubectlSelectFlags, kubectlOutputFlags, kubectlFlags{
+			"--raw": kubectlValue, "-L": kubectlValue, "--label-columns": kubectlValue,
+			"--sort-by": kubectlValue, "--chunk-size": kubectlValue, "--subresource": kubectlValue,
+			"--show-labels": kubectlBool, "--show-kind": kubectlBool, "--no-headers": kubectlBool,
+			"--ignore-not-found": kubectlBool, "--show-managed-fields": kubectlBool,
+		}),
+		"describe": kubectlFlagSets(kubectlNSFlags, kubectlSelectFlags, kubectlFlags{
+			"--show-events": kubectlBool, "--chunk-size": kubectlValue,
+		}),
+		"top": kubectlFlagSets(kubectlNSFlags, kubectlSelectFlags, kubectlFlags{
+			"--containers": kubectlBool, "--no-headers": kubectlBool, "--sum": kubectlBool,
+			"--show-capacity": kubectlBool, "--use-protocol-buffers": kubectlBool, "--sort-by": kubectlValue,
+		}),
+		"api-resources": kubectlFlagSets(kubectlOutputFlags, kubectlFlags{
+			"--namespaced": kubectlBool, "--no-headers": kubectlBool, "--cached": kubectlBool,
+			"--api-group": kubectlValue, "--verbs": kubectlValue, "--categories": kubectlValue, "--sort-by": kubectlValue,
+		}),
+		"api-versions": {},
+		"explain": kubectlFlagSets(kubectlOutputFlags, kubectlFlags{
+			"--recursive": kubectlBool, "--api-version": kubectlValue,
+		}),
+		"version": kubectlFlagSets(kubectlOutputFlags, kubectlFlags{"--client": kubectlBool}),
+
+		"annotate": kubectlFlagSets(kubectlNSFlags, kubectlSelectFlags, kubectlWriteFlags, kubectlFlags{
+			"--all": kubectlBool, "--overwrite": kubectlBool, "--list": kubectlBool, "--resource-version": kubectlValue,
+		}),
+		"autoscale": kubectlFlagSets(kubectlNSFlags, kubectlWriteFlags, kubectlFlags{
+			"--min": kubectlValue, "--max": kubectlValue, "--cpu-percent": kubectlValue, "--name": kubectlValue,
+		}),
+		"cordon": kubectlFlagSets(kubectlSelectFlags, kubectlFlags{"--dry-run": kubectlInline}),
+		"create": kubectlFlagSets(kubectlNSFlags, kubectlWriteFlags, kubectlFlags{
+			"--image": kubectlValue, "--replicas": kubectlValue, "--port": kubectlValue, "--schedule": kubectlValue,
+			"--from": kubectlValue, "--from-literal": kubectlValue, "--tcp": kubectlValue, "--clusterrole": kubectlValue,
+			"--role": kubectlValue, "--serviceaccount": kubectlValue, "--verb": kubectlValue, "--resource": kubectlValue,
+			"--resource-name": kubectlValue,
+		}),
+		"delete": kubectlFlagSets(kubectlNSFlags, kubectlSelectFlags, kubectlOutputFlags, kubectlFlags{
+			"--all": kubectlBool, "--force": kubectlBool, "--wait": kubectlBool, "--now": kubectlBool,
+			"--ignore-not-found": kubectlBool, "--grace-period": kubectlValue, "--timeout": kubectlValue,
+			"--cascade": kubectlInline, "--dry-run": kubectlInline,
+		}),
+		"drain": kubectlFlagSets(kubectlSelectFlags, kubectlFlags{
+			"--ignore-daemonsets": kubectlBool, "--delete-emptydir-data": kubectlBool, "--force": kubectlBool,
+			"--disable-eviction": kubectlBool, "--grace-period": kubectlValue, "--timeout": kubectlValue,
+			"--pod-selector": kubectlValue, "--skip-wait-for-delete-timeout": kubectlValue, "--dry-run": kubectlInline,
+		}),
+		"expose": kubectlFlagSets(kubectlNSFlags, kubectlSelectFlags, kubectlWriteFlags, kubectlFlags{
+			"--port": kubectlValue, "--target-port": kubectlValue, "--type": kubectlValue,
+			"--name": kubectlValue, "--protocol": kubectlValue,
+		}),
+		"label": kubectlFlagSets(kubectlNSFlags, kubectlSelectFlags, kubectlWriteFlags, kubectlFlags{
+			"--all": kubectlBool, "--overwrite": kubectlBool, "--list": kubectlBool, "--resource-version": kubectlValue,
+		}),
+		"patch": kubectlFlagSets(kubectlNSFlags, kubectlWriteFlags, kubectlFlags{
+			"-p": kubectlValue, "--patch": kubectlValue, "--type": kubectlValue, "--subresource": kubectlValue,
+		}),
+		"rollout": kubectlFlagSets(kubectlNSFlags, kubectlSelectFlags, kubectlWriteFlags, kubectlFlags{
+			"--revision": kubectlValue, "--to-revision": kubectlValue, "--timeout": kubectlValue,
+		}),
+		"scale": kubectlFlagSets(kubectlNSFlags, kubectlSelectFlags, kubectlWriteFlags, kubectlFlags{
+			"--all": kubectlBool, "--replicas": kubectlValue, "--current-replicas": kubectlValue,
+			"--resource-version": kubectlValue, "--timeout": kubectlValue,
+		}),
+		"set": kubectlFlagSets(kubectlNSFlags, kubectlSelectFlags, kubectlWriteFlags, kubectlFlags{
+			"--all": kubectlBool, "--overwrite": kubectlBool, "-c": kubectlValue, "--containers": kubectlValue,
+			"--limits": kubectlValue, "--requests": kubectlValue, "-e": kubectlValue, "--env": kubectlValue,
+			"--from": kubectlValue, "--keys": kubectlValue, "--prefix": kubectlValue, "--resource-version": kubectlValue,
+		}),
+		"taint": kubectlFlagSets(kubectlSelectFlags, kubectlWriteFlags, kubectlFlags{
+			"--all": kubectlBool, "--overwrite": kubectlBool,
+		}),
+		"uncordon": kubectlFlagSets(kubectlSelectFlags, kubectlFlags{"--dry-run": kubectlInline}),
+	}
+
+	kubectlNSFlags = kubectlFlags{
+		"-n": kubectlValue, "--namespace": kubectlValue, "-A": kubectlBool, "--all-namespaces": kubectlBool,
+	}
+	kubectlSelectFlags = kubectlFlags{
+		"-l": kubectlValue, "--selector": kubectlValue, "--field-selector": kubectlValue,
+	}
+	kubectlOutputFlags = kubectlFlags{"-o": kubectlValue, "--output": kubectlValue}
+	kubectlWriteFlags  = kubectlFlagSets(kubectlOutputFlags, kubectlFlags{
+		"--dry-run": kubectlInline, "--field-manager": kubectlValue,
+	})
+)
+
+// kubectlFlagKind tells how a kubectl flag takes its value.
+type kubectlFlagKind int
+
+const (
+	// kubectlBool flags take no value, or an inline one as in --flag=false.
+	kubectlBool kubectlFlagKind = iota
+
+	// kubectlValue flags take an inline value or the next argument.
+	kubectlValue
+
+	// kubectlInline flags have a default, so only take an inline value.
+	kubectlInline
+)
+
+// kubectlFlags maps flag names to how they take their value.
+type kubectlFlags map[string]kubectlFlagKind
+
+// kubectlFlagSets merges flag sets.
+func kubectlFlagSets(ss ...kubectlFlags) kubectlFlags {
+	ff := make(kubectlFlags)
+	for _, s := range ss {
+		maps.Copy(ff, s)
+	}
+
+	return ff
+}
+
+// kubectlFlag is a flag of a kubectl invocation.
+type kubectlFlag struct {
+	name, value string
+}
+
+// --- run_kubectl tool ---
+
+type runKubectlParams struct {
+	Command string `json:"command" jsonschema:"kubectl arguments without the kubectl prefix, e.g. get --raw /metrics or top pods -n kube-system"`
+}
+
+func (tf *ToolFactory) runKubectlTool() copilot.Tool {
+	return copilot.DefineTool(
+		"run_kubectl",
+		"Run a kubectl command against the current context and return its output. Escape hatch for data the other tools don't cover (e.g. get --raw /metrics, api-resources). "+
+			"Allowed verbs: "+strings.Join(kubectlReadVerbs, ", ")+". Mutating verbs ("+strings.Join(kubectlMutatingVerbs, ", ")+") are rejected when K9s is read-only and otherwise require user approval. "+
+			"Each verb only accepts its namespace, selector, output and common flags: context, credential, file and watch flags are not allowed. "+
+			"Secrets can't be printed, read them with get_secret. Prefer the dedicated tools when they apply.",
+		func(params runKubectlParams, inv copilot.ToolInvocation) (any, error) {
+			args, err := splitKubectlCommand(params.Command)
+			if err != nil {
+				return nil, err
+			}
+			mutating, err := checkKubectlArgs(args)
+			if err != nil {
+				return nil, err
+			}
+			if mutating && tf.isReadOnly() {
+				return nil, fmt.Errorf("kubectl %s is not allowed: K9s is in read-only mode", args[0])
+			}
+			if err := tf.checkKubectlNamespace(args); err != nil {
+				return nil, err
+			}
+			tf.log.Info("Running kubectl", "args", args)
+
+			return tf.runKubectl(args)
+		},
+	)
+}
+
+// SetReadOnlyFunc registers a callback reporting whether K9s is read-only.
+func (tf *ToolFactory) SetReadOnlyFunc(fn func() bool) {
+	tf.readOnly = fn
+}
+
+func (tf *ToolFactory) isReadOnly() bool {
+	return tf.readOnly != nil && tf.readOnly()
+}
+
+func (tf *ToolFactory) runKubectl(args []string) (any, error) {
+	bin, err := exec.LookPath("kubectl")
+	if errors.Is(err, exec.ErrDot) {
+		return nil, fmt.Errorf("kubectl command must not be in the current working directory: %w", err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("kubectl command is not in your path: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), kubectlTimeout)
+	defer cancel()
+
+	stdout, stderr := newCappedBuffer(kubectlMaxOutput), newCappedBuffer(kubectlMaxStderr)
+	cmd := exec.CommandContext(ctx, bin, append(tf.kubectlFlags(), args...)...)
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	err = cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("kubectl %s timed out after %s", args[0], kubectlTimeout)
+	}
+	// A non-zero exit is reported to the model along with stderr.
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return nil, fmt.Errorf("kubectl %s failed: %w", args[0], err)
+	}
+
+	result := map[string]any{
+		"command":  "kubectl " + strings.Join(args, " "),
+		"exitCode": cmd.ProcessState.ExitCode(),
+		"stdout":   stdout.String(),
+	}
+	if s := strings.TrimSpace(stderr.String()); s != "" {
+		result["stderr"] = s
+	}
+	if stdout.truncated {
+		result["truncated"] = fmt.Sprintf("output capped at %d bytes, narrow the query", kubectlMaxOutput)
+	}
+
+	return result, nil
+}
+
+// kubectlFlags pins kubectl to the connection k9s is using.
+func (tf *ToolFactory) kubectlFlags() []string {
+	var ff []string
+	if tf.conn == nil {
+		return ff
+	}
+	cfg := tf.conn.Config()
+	if u, err := cfg.ImpersonateUser(); err == nil {
+		ff = append(ff, "--as", u)
+	}
+	if g, err := cfg.ImpersonateGroups(); err == nil {
+		ff = append(ff, "--as-group", g)
+	}
+	if insecure := cfg.Flags().Insecure; insecure != nil && *insecure {
+		ff = append(ff, "--insecure-skip-tls-verify")
+	}
+	ff = append(ff, "--context", tf.conn.ActiveContext())
+	if kc := cfg.Flags().KubeConfig; kc != nil && *kc != "" {
+		ff = append(ff, "--kubeconfig", *kc)
+	}
+
+	return ff
+}
+
+// checkKubectlArgs validates a kubectl invocation and reports whether it
+// mutates the cluster.
+func checkKubectlArgs(args []string) (bool, error) {
+	if len(args) == 0 {
+		return false, errors.New("missing kubectl command")
+	}
+	verb := args[0]
+	if strings.HasPrefix(verb, "-") {
+		return false, fmt.Errorf("kubectl command must start with a verb, got %q", verb)
+	}
+	if _, ok := kubectlVerbFlags[verb]; !ok {
+		return false, fmt.Errorf("kubectl %s is not supported. Allowed verbs: %s", verb, strings.Join(kubectlReadVerbs, ", "))
+	}
+	ff, targets, err := parseKubectlFlags(args)
+	if err != nil {
+		return false, err
+	}
+	printing := verb == "get"
+	for _, f := range ff {
+		switch f.name {
+		case "-o", "--output":
+			// File based formats, e.g. go-template-file, read local files.
+			if format, _, _ := strings.Cut(f.value, "="); strings.HasSuffix(format, "-file") {
+				return false, fmt.Errorf("kubectl output %q is not allowed", format)
+			}
+			printing = true
+		case "--raw":
+			if !strings.HasPrefix(f.value, "/") {
+				return false, fmt.Errorf("kubectl --raw must be a server path, got %q", f.value)
+			}
+			if isSecretPath(f.value) {
+				return false, fmt.Errorf("kubectl --raw %s is not allowed: use get_secret, it masks secret values", f.value)
+			}
+		}
+	}
+	// Printed secrets would reach the model unmasked.
+	if printing && slices.ContainsFunc(targets, isSecretTarget) {
+		return false, fmt.Errorf("kubectl %s on secrets is not allowed: use get_secret, it masks secret values", verb)
+	}
+
+	return slices.Contains(kubectlMutatingVerbs, verb), nil
+}
+
+// isSecretTarget checks if a kubectl resource argument names secrets, e.g.
+// secret, secrets/db, secret.v1 or pods,secrets.
+func isSecretTarget(arg string) bool {
+	for _, r := range strings.Split(arg, ",") {
+		r, _, _ = strings.Cut(r, "/")
+		r, _, _ = strings.Cut(r, ".")
+		if r = strings.ToLower(r); r == "secret" || r == "secrets" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isSecretPath checks if a raw API path reads secrets.
+func isSecretPath(path string) bool {
+	path, _, _ = strings.Cut(path, "?")
+
+	return slices.Contains(strings.Split(path, "/"), "secrets")
+}
+
+// parseKubectlFlags returns the flags and the other arguments of a kubectl
+// invocation, rejecting the flags its verb doesn't accept. Flags are
+// normalized the way kubectl parses them: --flag=value, --flag value,
+// -fvalue, -f=value and -f value.
+func parseKubectlFlags(args []string) ([]kubectlFlag, []string, error) {
+	allowed := kubectlVerbFlags[args[0]]
+	var (
+		ff   []kubectlFlag
+		rest []string
+	)
+	for i := 1; i < len(args); i++ {
+		a := args[i]
+		if !strings.HasPrefix(a, "-") || a == "-" {
+			rest = append(rest, a)
+			continue
+		}
+		var (
+			name, value string
+			inline      bool
+		)
+		switch {
+		case strings.HasPrefix(a, "--"):
+			name, value, inline = strings.Cut(a, "=")
+		default:
+			name, value = a[:2], a[2:]
+			value, inline = strings.CutPrefix(value, "=")
+			inline = inline || value != ""
+		}
+		kind, ok := allowed[name]
+		if !ok {
+			return nil, nil, fmt.Errorf("kubectl flag %q is not allowed", name)
+		}
+		switch {
+		case kind == kubectlValue && !inline:
+			if i+1 == len(args) {
+				return nil, nil, fmt.Errorf("kubectl flag %q needs a value", name)
+			}
+			i++
+			value = args[i]
+		case kind != kubectlValue && inline && !strings.HasPrefix(a, name+"="):
+			// Grouped short flags, e.g. -Aw.
+			return nil, nil, fmt.Errorf("kubectl flag %q is not allowed", a)
+		}
+		ff = append(ff, kubectlFlag{name: name, value: value})
+	}
+
+	return ff, rest, nil
+}
+
+// isMutatingKubectl reports whether run_kubectl arguments would change the cluster.
+func isMutatingKubectl(args map[string]any) bool {
+	command, _ := args["command"].(string)
+	aa, err := splitKubectlCommand(command)
+	if err != nil {
+		return false
+	}
+	mutating, err := checkKubectlArgs(aa)
+
+	return err == nil && mutating
+}
+
+// splitKubectlCommand splits a command line into arguments, honoring single
+// and double quotes. A leading kubectl is dropped. No shell is involved.
+func splitKubectlCommand(s string) ([]string, error) {
+	var (
+		args    []string
+		cur     strings.Builder
+		quote   rune
+		pending bool
+	)
+	for _, r := range s {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			cur.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote, pending = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if pending {
+				args, pending = append(args, cur.String()), false
+				cur.Reset()
+			}
+		default:
+			cur.WriteRune(r)
+			pending = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in kubectl command %q", s)
+	}
+	if pending {
+		args = append(args, cur.String())
+	}
+	if len(args) > 0 && args[0] == "kubectl" {
+		args = args[1:]
+	}
+
+	return args, nil
+}
+
+// cappedBuffer keeps the first max bytes written and drops the rest.
+type cappedBuffer struct {
+	bytes.Buffer
+
+	max       int
+	truncated bool
+}
+
+func newCappedBuffer(limit int) *cappedBuffer {
+	return &cappedBuffer{max: limit}
+}
+
+// Write implements io.Writer. It never fails so the command isn't killed by
+// a short write.
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - b.Len(); len(p) > room {
+		b.truncated = true
+		b.Buffer.Write(p[:max(room, 0)])
+		return len(p), nil
+	}
+
+	return b.Buffer.Write(p)
+}
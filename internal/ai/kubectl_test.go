@@ -0,0 +1,236 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitKubectlCommand(t *testing.T) {
+	uu := map[string]struct {
+		cmd string
+		e   []string
+		err bool
+	}{
+		"empty": {},
+		"plain": {
+			cmd: "get --raw /metrics",
+			e:   []string{"get", "--raw", "/metrics"},
+		},
+		"prefix": {
+			cmd: "kubectl top pods  -n kube-system",
+			e:   []string{"top", "pods", "-n", "kube-system"},
+		},
+		"quotes": {
+			cmd: `get pods -o jsonpath='{.items[*].metadata.name}' -l "app in (a, b)"`,
+			e:   []string{"get", "pods", "-o", "jsonpath={.items[*].metadata.name}", "-l", "app in (a, b)"},
+		},
+		"empty-quotes": {
+			cmd: `get pods -l ""`,
+			e:   []string{"get", "pods", "-l", ""},
+		},
+		"unterminated": {
+			cmd: `get pods -l "app=a`,
+			err: true,
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			aa, err := splitKubectlCommand(u.cmd)
+			if u.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, u.e, aa)
+		})
+	}
+}
+
+func TestCheckKubectlArgs(t *testing.T) {
+	uu := map[string]struct {
+		args     []string
+		mutating bool
+		err      string
+	}{
+		"empty": {
+			err: "missing kubectl command",
+		},
+		"read": {
+			args: []string{"get", "--raw", "/metrics"},
+		},
+		"top": {
+			args: []string{"top", "pods", "-A"},
+		},
+		"mutating": {
+			args:     []string{"scale", "deploy/fred", "--replicas=2"},
+			mutating: true,
+		},
+		"flag-first": {
+			args: []string{"-n", "default", "get", "pods"},
+			err:  `kubectl command must start with a verb, got "-n"`,
+		},
+		"context": {
+			args: []string{"get", "pods", "--context=prod"},
+			err:  `kubectl flag "--context" is not allowed`,
+		},
+		"watch": {
+			args: []string{"get", "pods", "-w"},
+			err:  `kubectl flag "-w" is not allowed`,
+		},
+		"file": {
+			args: []string{"delete", "-f", "manifest.yaml"},
+			err:  `kubectl flag "-f" is not allowed`,
+		},
+		"file-attached": {
+			args: []string{"delete", "-f/etc/x"},
+			err:  `kubectl flag "-f" is not allowed`,
+		},
+		"server-attached": {
+			args: []string{"get", "pods", "-shttps://evil"},
+			err:  `kubectl flag "-s" is not allowed`,
+		},
+		"server-inline": {
+			args: []string{"get", "pods", "--server=https://evil"},
+			err:  `kubectl flag "--server" is not allowed`,
+		},
+		"template-file": {
+			args: []string{"get", "pods", "-o", "go-template-file=/etc/x"},
+			err:  `kubectl output "go-template-file" is not allowed`,
+		},
+		"jsonpath-file-attached": {
+			args: []string{"get", "pods", "-ojsonpath-file=/etc/x"},
+			err:  `kubectl output "jsonpath-file" is not allowed`,
+		},
+		"jsonpath-file-inline": {
+			args: []string{"get", "pods", "--output=jsonpath-file=/etc/x"},
+			err:  `kubectl output "jsonpath-file" is not allowed`,
+		},
+		"template": {
+			args: []string{"get", "pods", "-o", "go-template", "--template=/etc/x"},
+			err:  `kubectl flag "--template" is not allowed`,
+		},
+		"profile-output": {
+			args: []string{"get", "pods", "--profile-output", "/tmp/x"},
+			err:  `kubectl flag "--profile-output" is not allowed`,
+		},
+		"cache-dir": {
+			args: []string{"get", "pods", "--cache-dir=/tmp/x"},
+			err:  `kubectl flag "--cache-dir" is not allowed`,
+		},
+		"grouped": {
+			args: []string{"get", "pods", "-Aw"},
+			err:  `kubectl flag "-Aw" is not allowed`,
+		},
+		"terminator": {
+			args: []string{"get", "pods", "--", "-f"},
+			err:  `kubectl flag "--" is not allowed`,
+		},
+		"verb-flag": {
+			args: []string{"describe", "--raw", "/metrics"},
+			err:  `kubectl flag "--raw" is not allowed`,
+		},
+		"raw-url": {
+			args: []string{"get", "--raw", "https://evil/metrics"},
+			err:  `kubectl --raw must be a server path, got "https://evil/metrics"`,
+		},
+		"missing-value": {
+			args: []string{"get", "pods", "-n"},
+			err:  `kubectl flag "-n" needs a value`,
+		},
+		"value-forms": {
+			args: []string{"get", "pods", "-nkube-system", "-l=app=fred", "--field-selector", "status.phase=Running", "-ojsonpath={.items[*].metadata.name}"},
+		},
+		"inline-bool": {
+			args: []string{"get", "pods", "-A=true", "--show-labels=false"},
+		},
+		"dry-run": {
+			args:     []string{"delete", "pod", "fred", "--dry-run", "--grace-period", "0"},
+			mutating: true,
+		},
+		"secret-yaml": {
+			args: []string{"get", "secret", "db", "-o", "yaml"},
+			err:  "kubectl get on secrets is not allowed: use get_secret, it masks secret values",
+		},
+		"secret-jsonpath": {
+			args: []string{"get", "secrets/db", "-n", "prod", "-ojsonpath={.data.password}"},
+			err:  "kubectl get on secrets is not allowed: use get_secret, it masks secret values",
+		},
+		"secret-list": {
+			args: []string{"get", "pods,Secrets.v1", "-A"},
+			err:  "kubectl get on secrets is not allowed: use get_secret, it masks secret values",
+		},
+		"secret-mutation-output": {
+			args: []string{"label", "secret", "db", "team=a", "--output=json"},
+			err:  "kubectl label on secrets is not allowed: use get_secret, it masks secret values",
+		},
+		"secret-mutation": {
+			args:     []string{"label", "secret", "db", "team=a"},
+			mutating: true,
+		},
+		"secret-describe": {
+			args: []string{"describe", "secret", "db"},
+		},
+		"secret-namespace": {
+			args: []string{"get", "pods", "-n", "secrets"},
+		},
+		"secret-raw": {
+			args: []string{"get", "--raw", "/api/v1/namespaces/prod/secrets/db"},
+			err:  "kubectl --raw /api/v1/namespaces/prod/secrets/db is not allowed: use get_secret, it masks secret values",
+		},
+		"secret-raw-list": {
+			args: []string{"get", "--raw=/api/v1/secrets?limit=1"},
+			err:  "kubectl --raw /api/v1/secrets?limit=1 is not allowed: use get_secret, it masks secret values",
+		},
+		"exec": {
+			args: []string{"exec", "fred", "--", "sh"},
+			err:  "kubectl exec is not supported. Allowed verbs: get, describe, top, api-resources, api-versions, explain, version",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			mutating, err := checkKubectlArgs(u.args)
+			if u.err != "" {
+				assert.EqualError(t, err, u.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, u.mutating, mutating)
+		})
+	}
+}
+
+func TestIsMutationCall(t *testing.T) {
+	assert.True(t, IsMutationCall("patch_resource", nil))
+	assert.False(t, IsMutationCall("get_resource", nil))
+	assert.False(t, IsMutationCall("run_kubectl", map[string]any{"command": "get pods"}))
+	assert.True(t, IsMutationCall("run_kubectl", map[string]any{"command": "kubectl delete pod fred"}))
+	assert.False(t, IsMutationCall("run_kubectl", map[string]any{"command": "exec fred"}))
+	assert.False(t, IsMutationCall("run_kubectl", nil))
+}
+
+func TestCappedBuffer(t *testing.T) {
+	b := newCappedBuffer(5)
+	n, err := b.Write([]byte("abc"))
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.False(t, b.truncated)
+
+	n, err = b.Write([]byte("defg"))
+	require.NoError(t, err)
+	assert.Equal(t, 4, n)
+	assert.True(t, b.truncated)
+
+	n, err = b.Write([]byte("h"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, "abcde", b.String())
+}
@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/derailed/k9s/internal/config"
+)
+
+const (
+	// defaultLogContext is the number of lines kept around each line matching
+	// the get_logs filter.
+	defaultLogContext = 2
+
+	// maxLogContext caps the context lines kept around each match.
+	maxLogContext = 10
+
+	// maxLogScanBytes caps the log bytes a filtered get_logs call reads per
+	// container. Only the matches count against maxLogBytes.
+	maxLogScanBytes = 64 * 1024 * 1024
+
+	// grepTailLines is the log window a get_logs grep scans without
+	// tailLines or a since window.
+	grepTailLines = 20_000
+)
+
+// stackTraceRx matches stack trace lines of common runtimes: Java and
+// friends, Python, Go and Node.
+var stackTraceRx = regexp.MustCompile(`^(\s+at\s|Caused by:|Traceback \(most recent call last\)|\s+File ".+", line \d+|goroutine \d+ \[|panic:|\s+\S+\.go:\d+)`)
+
+// logFilter keeps the log lines matching a pattern, along with their context.
+type logFilter struct {
+	pattern string
+	rx      *regexp.Regexp
+	context int
+}
+
+// newLogFilter returns a filter for a regular expression, or for errors,
+// warnings and stack traces when the pattern is config.AILogFilterErrors.
+func newLogFilter(pattern string, context int) (*logFilter, error) {
+	if context <= 0 {
+		context = defaultLogContext
+	}
+	f := logFilter{pattern: pattern, context: min(context, maxLogContext)}
+	if pattern == config.AILogFilterErrors {
+		return &f, nil
+	}
+	rx, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid grep pattern: %w", err)
+	}
+	f.rx = rx
+
+	return &f, nil
+}
+
+func (f *logFilter) match(l string) bool {
+	if f.rx != nil {
+		return f.rx.MatchString(l)
+	}
+
+	return logProblemRx.MatchString(l) || stackTraceRx.MatchString(l)
+}
+
+// logScan is the outcome of filtering a log stream.
+type logScan struct {
+	logs        string
+	total, kept int
+	// capped is set when the scan stopped before the end of the stream.
+	capped bool
+}
+
+// scan filters the lines of r as they stream in, keeping the matching lines
+// with their context, non-adjacent groups separated by "--" like grep does.
+// Lines are sanitized before matching. The scan stops once the output
+// reaches limit bytes, unless limit is 0, or after maxLogScanBytes.
+func (f *logFilter) scan(r io.Reader, limit int) (logScan, error) {
+	var (
+		s      logScan
+		b      strings.Builder
+		before []string
+		after  int
+		last   = -1
+	)
+	lr := &io.LimitedReader{R: r, N: maxLogScanBytes}
+	br := bufio.NewReader(lr)
+	keep := func(i int, l string) {
+		if last >= 0 && i > last+1 {
+			b.WriteString("--\n")
+		}
+		b.WriteString(l)
+		b.WriteByte('\n')
+		last = i
+		s.kept++
+	}
+	for i := 0; ; i++ {
+		l, err := br.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return s, err
+		}
+		if l == "" && err != nil {
+			break
+		}
+		l = sanitizeText([]byte(strings.TrimSuffix(l, "\n")))
+		s.total++
+		switch {
+		case f.match(l):
+			for j, bl := range before {
+				keep(i-len(before)+j, bl)
+			}
+			before, after = nil, f.context
+			keep(i, l)
+		case after > 0:
+			after--
+			keep(i, l)
+		default:
+			if before = append(before, l); len(before) > f.context {
+				before = before[1:]
+			}
+		}
+		if limit > 0 && b.Len() >= limit {
+			s.capped = true
+			break
+		}
+		if err != nil {
+			break
+		}
+	}
+	if lr.N == 0 {
+		s.capped = true
+	}
+	s.logs = b.String()
+
+	return s, nil
+}
+
+// note tells the model what the filter left out.
+func (f *logFilter) note(total, kept int, capped bool) string {
+	var stop string
+	if capped {
+		stop = "; the scan stopped early at the size cap, narrow it with sinceTime or tailLines to see later lines"
+	}
+	if kept == 0 {
+		return fmt.Sprintf("[filtered: none of %d lines match %q%s; call again with another grep or raw=true for full logs]\n", total, f.pattern, stop)
+	}
+
+	return fmt.Sprintf("[filtered: kept %d of %d lines (%d filtered out) matching %q with %d context line(s)%s; call again with raw=true for full logs]\n",
+		kept, total, total-kept, f.pattern, f.context, stop)
+}
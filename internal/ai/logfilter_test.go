@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogFilter(t *testing.T) {
+	logs := "starting\n" +
+		"listening on :8080\n" +
+		"GET /healthz 200\n" +
+		"GET /healthz 200\n" +
+		"ERROR db connection lost\n" +
+		"retrying\n" +
+		"GET /healthz 200\n" +
+		"GET /healthz 200\n" +
+		"GET /healthz 200\n" +
+		"Exception in thread \"main\" java.lang.NullPointerException\n" +
+		"    at com.acme.Api.handle(Api.java:42)\n" +
+		"done\n"
+
+	uu := map[string]struct {
+		pattern     string
+		context     int
+		e           string
+		total, kept int
+	}{
+		"errors": {
+			pattern: "errors",
+			context: 1,
+			e: "GET /healthz 200\nERROR db connection lost\nretrying\n--\n" +
+				"GET /healthz 200\nException in thread \"main\" java.lang.NullPointerException\n    at com.acme.Api.handle(Api.java:42)\ndone\n",
+			total: 12,
+			kept:  7,
+		},
+		"regex": {
+			pattern: "^listening",
+			e:       "starting\nlistening on :8080\nGET /healthz 200\nGET /healthz 200\n",
+			total:   12,
+			kept:    4,
+		},
+		"none": {
+			pattern: "panic",
+			total:   12,
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			f, err := newLogFilter(u.pattern, u.context)
+			require.NoError(t, err)
+			s, err := f.scan(strings.NewReader(logs), 0)
+			require.NoError(t, err)
+			assert.Equal(t, u.e, s.logs)
+			assert.Equal(t, u.total, s.total)
+			assert.Equal(t, u.kept, s.kept)
+			assert.False(t, s.capped)
+		})
+	}
+}
+
+func TestLogFilterScanLimit(t *testing.T) {
+	f, err := newLogFilter("ERROR", 1)
+	require.NoError(t, err)
+
+	// Matches deep in a chatty log are found, output stops at the limit.
+	logs := strings.Repeat("GET /healthz 200\n", 50_000) + "ERROR boom\n\x1b[31mdone\x1b[0m\n" + strings.Repeat("ERROR again\n", 100)
+	s, err := f.scan(strings.NewReader(logs), 64)
+	require.NoError(t, err)
+	assert.Equal(t, "GET /healthz 200\nERROR boom\ndone\n"+strings.Repeat("ERROR again\n", 3), s.logs)
+	assert.Equal(t, 50_005, s.total)
+	assert.True(t, s.capped)
+}
+
+func TestLogFilterStackTraces(t *testing.T) {
+	f, err := newLogFilter("errors", 0)
+	require.NoError(t, err)
+	for _, l := range []string{
+		"goroutine 1 [running]:",
+		"\t/app/main.go:12 +0x1d",
+		`  File "/app/main.py", line 3, in <module>`,
+		"Traceback (most recent call last):",
+		"Caused by: java.io.IOException",
+	} {
+		assert.True(t, f.match(l), l)
+	}
+	assert.False(t, f.match("GET /healthz 200"))
+}
+
+func TestLogFilterNote(t *testing.T) {
+	f, err := newLogFilter("errors", 20)
+	require.NoError(t, err)
+	assert.Equal(t, maxLogContext, f.context)
+	assert.Contains(t, f.note(100, 12, false), "kept 12 of 100 lines (88 filtered out)")
+	assert.Contains(t, f.note(100, 0, false), "none of 100 lines match")
+	assert.Contains(t, f.note(100, 12, true), "the scan stopped early")
+
+	_, err = newLogFilter("(", 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// kubectlClusterVerbs don't read namespaced objects, so they run regardless of
+// ai.allowedNamespaces.
+var kubectlClusterVerbs = []string{"api-resources", "api-versions", "explain", "version"}
+
+// nsAllowed reports whether tools may inspect namespace ns. Cluster-scoped
+// objects, with a blank namespace, are always allowed.
+func (tf *ToolFactory) nsAllowed(ns string) bool {
+	return ns == "" || tf.cfg.NamespaceAllowed(ns)
+}
+
+// checkNamespace rejects targets outside ai.allowedNamespaces.
+func (tf *ToolFactory) checkNamespace(ns string) error {
+	if tf.nsAllowed(ns) {
+		return nil
+	}
+
+	return fmt.Errorf("namespace %q is outside the namespaces AI tools may inspect (%s)", ns, strings.Join(tf.cfg.AllowedNamespaces, ", "))
+}
+
+// listNamespace returns the namespace a list tool should query. A blank
+// namespace means all namespaces: it stays blank and results are clamped with
+// keepAllowed, unless a single literal namespace is allowed.
+func (tf *ToolFactory) listNamespace(ns string) (string, error) {
+	if ns != "" {
+		return ns, tf.checkNamespace(ns)
+	}
+	if aa := tf.cfg.AllowedNamespaces; len(aa) == 1 && !strings.ContainsAny(aa[0], `*?[\`) {
+		return aa[0], nil
+	}
+
+	return ns, nil
+}
+
+// keepAllowed drops the items that live outside ai.allowedNamespaces.
+func keepAllowed[T any](tf *ToolFactory, items []T, nsOf func(T) string) []T {
+	if len(tf.cfg.AllowedNamespaces) == 0 {
+		return items
+	}
+
+	return slices.DeleteFunc(items, func(t T) bool {
+		return !tf.nsAllowed(nsOf(t))
+	})
+}
+
+// checkKubectlNamespace confines a kubectl invocation to ai.allowedNamespaces.
+// Namespaced verbs must name an allowed namespace explicitly.
+func (tf *ToolFactory) checkKubectlNamespace(args []string) error {
+	if len(tf.cfg.AllowedNamespaces) == 0 || slices.Contains(kubectlClusterVerbs, args[0]) {
+		return nil
+	}
+
+	ff, _, err := parseKubectlFlags(args)
+	if err != nil {
+		return err
+	}
+	var ns string
+	for _, f := range ff {
+		switch f.name {
+		case "-A", "--all-namespaces":
+			return fmt.Errorf("kubectl %s across all namespaces is not allowed: AI tools are limited to %s", f.name, strings.Join(tf.cfg.AllowedNamespaces, ", "))
+		case "-n", "--namespace":
+			ns = f.value
+		case "--raw":
+			// Raw paths name their namespace, if any, in the URL.
+			return fmt.Errorf("kubectl %s --raw is not allowed: AI tools are limited to %s", args[0], strings.Join(tf.cfg.AllowedNamespaces, ", "))
+		}
+	}
+	if ns == "" {
+		return fmt.Errorf("kubectl %s needs an explicit -n: AI tools are limited to %s", args[0], strings.Join(tf.cfg.AllowedNamespaces, ", "))
+	}
+
+	return tf.checkNamespace(ns)
+}
@@ -0,0 +1,169 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"testing"
+
+	"github.com/derailed/k9s/internal/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func newScopedToolFactory(f *testFactory, conn *testConn, nss ...string) *ToolFactory {
+	tf := newTestToolFactory(f, conn)
+	tf.cfg.AllowedNamespaces = nss
+
+	return tf
+}
+
+func TestListNamespace(t *testing.T) {
+	uu := map[string]struct {
+		allowed []string
+		ns, e   string
+		err     bool
+	}{
+		"unrestricted": {},
+		"explicit":     {allowed: []string{"ns1", "ns2"}, ns: "ns2", e: "ns2"},
+		"outside":      {allowed: []string{"ns1"}, ns: "ns2", err: true},
+		"single":       {allowed: []string{"ns1"}, e: "ns1"},
+		"several":      {allowed: []string{"ns1", "ns2"}},
+		"glob":         {allowed: []string{"team-*"}},
+		"glob-match":   {allowed: []string{"team-*"}, ns: "team-a", e: "team-a"},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			tf := newScopedToolFactory(newTestFactory(), newTestConn(), u.allowed...)
+			ns, err := tf.listNamespace(u.ns)
+			if u.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, u.e, ns)
+		})
+	}
+}
+
+func TestAllowedNamespacesListTools(t *testing.T) {
+	f := newTestFactory()
+	f.add(client.PodGVR, makePod("team-a", "p1", nil))
+	f.add(client.PodGVR, makePod("team-b", "p2", nil))
+	f.add(client.PodGVR, makePod("kube-system", "p3", nil))
+	f.add(coreEvGVR, makeEvent("team-a", "e1", "p1", "Warning", "BackOff", toolNow))
+	f.add(coreEvGVR, makeEvent("kube-system", "e2", "p3", "Warning", "Failed", toolNow))
+	tf := newScopedToolFactory(f, newTestConn(), "team-*")
+
+	m := callToolJSON(t, tf, "list_resources", map[string]any{"gvr": "v1/pods"})
+	assert.Equal(t, "Found 2 v1/pods resources", m["summary"])
+
+	m = callToolJSON(t, tf, "get_events", map[string]any{})
+	assert.Equal(t, float64(1), m["total"])
+
+	_, err := callTool(t, tf, "list_resources", map[string]any{"gvr": "v1/pods", "namespace": "kube-system"})
+	assert.ErrorContains(t, err, `namespace "kube-system" is outside the namespaces AI tools may inspect (team-*)`)
+}
+
+func TestAllowedNamespacesGetTools(t *testing.T) {
+	f := newTestFactory()
+	f.add(client.PodGVR, makePod("kube-system", "p1", nil))
+	f.add(client.PodGVR, makePod("team-a", "p2", nil))
+	tf := newScopedToolFactory(f, newTestConn(), "team-a")
+
+	uu := map[string]struct {
+		tool string
+		args map[string]any
+	}{
+		"get": {
+			tool: "get_resource",
+			args: map[string]any{"gvr": "v1/pods", "namespace": "kube-system", "name": "p1"},
+		},
+		"describe": {
+			tool: "describe_resource",
+			args: map[string]any{"gvr": "v1/pods", "namespace": "kube-system", "name": "p1"},
+		},
+		"logs": {
+			tool: "get_logs",
+			args: map[string]any{"namespace": "kube-system", "podName": "p1"},
+		},
+		"diagnostics": {
+			tool: "get_pod_diagnostics",
+			args: map[string]any{"namespace": "kube-system", "podName": "p1"},
+		},
+		"patch": {
+			tool: "patch_resource",
+			args: map[string]any{"gvr": "v1/pods", "namespace": "kube-system", "name": "p1", "patch": `{"metadata":{"labels":{"a":"b"}}}`},
+		},
+		"scale": {
+			tool: "scale_resource",
+			args: map[string]any{"gvr": "apps/v1/deployments", "namespace": "kube-system", "name": "d1", "replicas": 2},
+		},
+		"restart": {
+			tool: "restart_resource",
+			args: map[string]any{"gvr": "apps/v1/deployments", "namespace": "kube-system", "name": "d1"},
+		},
+		"delete": {
+			tool: "delete_resource",
+			args: map[string]any{"gvr": "v1/pods", "namespace": "kube-system", "name": "p1"},
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			_, err := callTool(t, tf, u.tool, u.args)
+			assert.ErrorContains(t, err, "is outside the namespaces AI tools may inspect")
+		})
+	}
+
+	out, err := callTool(t, tf, "get_resource", map[string]any{"gvr": "v1/pods", "namespace": "team-a", "name": "p2"})
+	assert.NoError(t, err)
+	assert.Contains(t, out, "name: p2")
+}
+
+func TestCheckKubectlNamespace(t *testing.T) {
+	tf := newScopedToolFactory(newTestFactory(), newTestConn(), "team-a")
+
+	uu := map[string]struct {
+		args []string
+		err  string
+	}{
+		"allowed":       {args: []string{"get", "pods", "-n", "team-a"}},
+		"allowed-equal": {args: []string{"get", "pods", "--namespace=team-a"}},
+		"cluster-verb":  {args: []string{"api-resources"}},
+		"outside-attached": {
+			args: []string{"get", "pods", "-nkube-system"},
+			err:  `namespace "kube-system" is outside the namespaces AI tools may inspect (team-a)`,
+		},
+		"outside": {
+			args: []string{"get", "pods", "-n", "kube-system"},
+			err:  `namespace "kube-system" is outside the namespaces AI tools may inspect (team-a)`,
+		},
+		"all-namespaces": {
+			args: []string{"get", "pods", "-A"},
+			err:  "kubectl -A across all namespaces is not allowed: AI tools are limited to team-a",
+		},
+		"raw": {
+			args: []string{"get", "--raw", "/api/v1/namespaces/kube-system/secrets"},
+			err:  "kubectl get --raw is not allowed: AI tools are limited to team-a",
+		},
+		"no-namespace": {
+			args: []string{"top", "pods"},
+			err:  "kubectl top needs an explicit -n: AI tools are limited to team-a",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			err := tf.checkKubectlNamespace(u.args)
+			if u.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, u.err)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"fmt"
+
+	copilot "github.com/github/copilot-sdk/go"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// --- get_node_diagnostics tool ---
+
+type getNodeDiagnosticsParams struct {
+	NodeName string `json:"nodeName" jsonschema:"Node name"`
+	Live     bool   `json:"live,omitempty" jsonschema:"If true, read from the API server instead of the K9s cache"`
+}
+
+func (tf *ToolFactory) getNodeDiagnosticsTool() copilot.Tool {
+	return copilot.DefineTool(
+		"get_node_diagnostics",
+		"Get diagnostics for a specific node: capacity vs allocatable, conditions (Ready, MemoryPressure, DiskPressure, PIDPressure), taints, cordon, kubelet and kube-proxy versions, and the pods scheduled on it by phase. Use it when a node is suspected of pressure or of keeping pods Pending. Served from the K9s cache unless live=true.",
+		func(params getNodeDiagnosticsParams, inv copilot.ToolInvocation) (any, error) {
+			node, err := tf.getNode(params.NodeName, params.Live)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get node %s: %w", params.NodeName, err)
+			}
+
+			diag := map[string]any{
+				"name":             node.Name,
+				"unschedulable":    node.Spec.Unschedulable,
+				"capacity":         resourceStrings(node.Status.Capacity),
+				"allocatable":      resourceStrings(node.Status.Allocatable),
+				"kubeletVersion":   node.Status.NodeInfo.KubeletVersion,
+				"kubeProxyVersion": node.Status.NodeInfo.KubeProxyVersion, //nolint:staticcheck // Deprecated but still set by older kubelets.
+				"containerRuntime": node.Status.NodeInfo.ContainerRuntimeVersion,
+				"osImage":          node.Status.NodeInfo.OSImage,
+			}
+
+			var conditions []map[string]string
+			for _, cond := range node.Status.Conditions {
+				conditions = append(conditions, map[string]string{
+					"type":    string(cond.Type),
+					"status":  string(cond.Status),
+					"reason":  cond.Reason,
+					"message": cond.Message,
+					"since":   toolTime(cond.LastTransitionTime.Time),
+				})
+			}
+			diag["conditions"] = conditions
+
+			var taints []map[string]string
+			for _, t := range node.Spec.Taints {
+				taints = append(taints, map[string]string{
+					"key":    t.Key,
+					"value":  t.Value,
+					"effect": string(t.Effect),
+				})
+			}
+			if len(taints) > 0 {
+				diag["taints"] = taints
+			}
+
+			// Pods are counted as they are scanned, like get_cluster_health.
+			ns, err := tf.listNamespace("")
+			if err != nil {
+				return nil, err
+			}
+			var total int
+			phases := make(map[string]int)
+			limit := tf.cfg.ClusterHealthPodCap()
+			_, partial, err := tf.scanPods(ns, params.Live, limit, func(p *corev1.Pod) {
+				if p.Spec.NodeName != node.Name || !tf.nsAllowed(p.Namespace) {
+					return
+				}
+				total++
+				phases[string(p.Status.Phase)]++
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list pods: %w", err)
+			}
+			diag["pods"] = map[string]any{
+				"total":   total,
+				"byPhase": phases,
+			}
+			if partial {
+				diag["partial"] = true
+				diag["note"] = fmt.Sprintf("The cluster has more than %d pods: pod counts come from a partial scan of the first %d.", limit, limit)
+			}
+
+			return diag, nil
+		},
+	)
+}
+
+// resourceStrings renders a resource list, e.g. {"cpu": "4", "memory": "16Gi"}.
+func resourceStrings(rl corev1.ResourceList) map[string]string {
+	m := make(map[string]string, len(rl))
+	for k, q := range rl {
+		m[string(k)] = q.String()
+	}
+
+	return m
+}
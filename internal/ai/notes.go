@@ -0,0 +1,145 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	copilot "github.com/github/copilot-sdk/go"
+)
+
+const (
+	// maxNotes caps the notes kept per session. The oldest note is dropped
+	// to make room for a new one.
+	maxNotes = 50
+
+	// maxNoteLen caps the length of a single note, in bytes.
+	maxNoteLen = 2 * 1024
+)
+
+// scratchpad is the model's working memory for the current session.
+type scratchpad struct {
+	keys []string // note keys, oldest first
+	vals map[string]string
+	mx   sync.Mutex
+}
+
+// write stores a note under key, replacing any previous one. A blank value
+// removes the note.
+func (s *scratchpad) write(key, val string) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	s.keys = slices.DeleteFunc(s.keys, func(k string) bool { return k == key })
+	delete(s.vals, key)
+	if val == "" {
+		return
+	}
+	if s.vals == nil {
+		s.vals = make(map[string]string)
+	}
+	if len(s.keys) >= maxNotes {
+		delete(s.vals, s.keys[0])
+		s.keys = s.keys[1:]
+	}
+	s.keys = append(s.keys, key)
+	s.vals[key] = val
+}
+
+// read returns the note stored under key.
+func (s *scratchpad) read(key string) (string, bool) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	v, ok := s.vals[key]
+
+	return v, ok
+}
+
+// all returns the notes, oldest first, as key: value lines.
+func (s *scratchpad) all() []string {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	ll := make([]string, 0, len(s.keys))
+	for _, k := range s.keys {
+		ll = append(ll, k+": "+s.vals[k])
+	}
+
+	return ll
+}
+
+// len returns the number of notes.
+func (s *scratchpad) len() int {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	return len(s.keys)
+}
+
+// clear drops all notes.
+func (s *scratchpad) clear() {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	s.keys, s.vals = nil, nil
+}
+
+// --- note_write/note_read tools ---
+
+type noteWriteParams struct {
+	Key   string `json:"key" jsonschema:"Short key for the note, e.g. web-1 exit code"`
+	Value string `json:"value" jsonschema:"What to remember. Empty removes the note"`
+}
+
+type noteReadParams struct {
+	Key string `json:"key,omitempty" jsonschema:"Key of the note to read. Empty returns all notes"`
+}
+
+func (c *AIClient) noteTools() []copilot.Tool {
+	return []copilot.Tool{
+		copilot.DefineTool(
+			"note_write",
+			fmt.Sprintf("Jot down a finding to recall later in this conversation, e.g. pod web-1 exit code 137 at 10:31Z, instead of fetching it again. "+
+				"Notes are kept for the session only, up to %d of them, the oldest dropped first.", maxNotes),
+			func(in noteWriteParams, inv copilot.ToolInvocation) (any, error) {
+				key := strings.TrimSpace(in.Key)
+				if key == "" {
+					return nil, errors.New("a note needs a key")
+				}
+				if len(in.Value) > maxNoteLen {
+					return nil, fmt.Errorf("note is %d bytes, keep it under %d", len(in.Value), maxNoteLen)
+				}
+				c.notes.write(key, in.Value)
+				if in.Value == "" {
+					return fmt.Sprintf("Note %q removed.", key), nil
+				}
+
+				return fmt.Sprintf("Noted %q (%d/%d notes).", key, c.notes.len(), maxNotes), nil
+			},
+		),
+		copilot.DefineTool(
+			"note_read",
+			"Recall a note written with note_write, or all notes when no key is given.",
+			func(in noteReadParams, inv copilot.ToolInvocation) (any, error) {
+				key := strings.TrimSpace(in.Key)
+				if key == "" {
+					if ll := c.notes.all(); len(ll) > 0 {
+						return strings.Join(ll, "\n"), nil
+					}
+					return "No notes yet.", nil
+				}
+				if v, ok := c.notes.read(key); ok {
+					return v, nil
+				}
+
+				return fmt.Sprintf("No note %q. Call note_read without a key to list all notes.", key), nil
+			},
+		),
+	}
+}
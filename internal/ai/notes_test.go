@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/derailed/k9s/internal/config"
+	copilot "github.com/github/copilot-sdk/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScratchpad(t *testing.T) {
+	var s scratchpad
+
+	s.write("exit", "137")
+	s.write("node", "n1")
+	s.write("exit", "143")
+	assert.Equal(t, []string{"node: n1", "exit: 143"}, s.all())
+
+	v, ok := s.read("exit")
+	assert.True(t, ok)
+	assert.Equal(t, "143", v)
+
+	s.write("node", "")
+	_, ok = s.read("node")
+	assert.False(t, ok)
+	assert.Equal(t, 1, s.len())
+
+	s.clear()
+	assert.Empty(t, s.all())
+}
+
+func TestScratchpadBounded(t *testing.T) {
+	var s scratchpad
+	for i := range maxNotes + 2 {
+		s.write(fmt.Sprintf("k%d", i), "v")
+	}
+
+	assert.Equal(t, maxNotes, s.len())
+	_, ok := s.read("k0")
+	assert.False(t, ok)
+	_, ok = s.read(fmt.Sprintf("k%d", maxNotes+1))
+	assert.True(t, ok)
+}
+
+func TestNoteTools(t *testing.T) {
+	c := NewAIClient(config.AI{}, nil)
+	call := func(name string, args map[string]any) (string, error) {
+		for _, tool := range c.noteTools() {
+			if tool.Name == name {
+				res, err := tool.Handler(copilot.ToolInvocation{ToolName: name, Arguments: args})
+				return res.TextResultForLLM, err
+			}
+		}
+		require.Failf(t, "unknown tool", "no tool named %q", name)
+		return "", nil
+	}
+
+	out, err := call("note_read", map[string]any{})
+	require.NoError(t, err)
+	assert.Contains(t, out, "No notes yet.")
+
+	out, err = call("note_write", map[string]any{"key": "web-1", "value": "exit code 137 at 10:31Z"})
+	require.NoError(t, err)
+	assert.Contains(t, out, `Noted "web-1" (1/50 notes).`)
+
+	out, err = call("note_read", map[string]any{"key": "web-1"})
+	require.NoError(t, err)
+	assert.Contains(t, out, "exit code 137 at 10:31Z")
+
+	_, err = call("note_write", map[string]any{"key": " "})
+	assert.EqualError(t, err, "a note needs a key")
+	_, err = call("note_write", map[string]any{"key": "big", "value": strings.Repeat("x", maxNoteLen+1)})
+	assert.ErrorContains(t, err, "keep it under")
+
+	c.ResetSession()
+	out, err = call("note_read", map[string]any{"key": "web-1"})
+	require.NoError(t, err)
+	assert.Contains(t, out, `No note "web-1"`)
+}
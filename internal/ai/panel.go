@@ -0,0 +1,129 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/derailed/k9s/internal/config"
+)
+
+// panelPreamble tells each panel member its answer is a second opinion
+// compared against other models.
+const panelPreamble = "[PANEL] Several models answer this question independently and their answers are compared side by side. " +
+	"Answer on your own, do not change the cluster, and state how confident you are in any remediation you suggest."
+
+// PanelAnswer is the answer of a panel member.
+type PanelAnswer struct {
+	Model   string
+	Content string
+	Err     error
+	Usage   TokenUsage
+}
+
+// AskPanel sends the prompt to each model concurrently, on a session of its
+// own so the chat session is left alone, and returns the answers in the
+// order of the models. Models past config.MaxAIPanelModels are ignored.
+func (c *AIClient) AskPanel(ctx context.Context, models []string, prompt string) ([]PanelAnswer, error) {
+	if !c.IsEnabled() {
+		return nil, fmt.Errorf("AI features are disabled. Enable in config: k9s.ai.enabled=true")
+	}
+	if !c.isInitialized() {
+		if err := c.Init(ctx); err != nil {
+			return nil, fmt.Errorf("AI not ready: %w", err)
+		}
+	}
+	models = models[:min(len(models), config.MaxAIPanelModels)]
+
+	aa := make([]PanelAnswer, len(models))
+	var wg sync.WaitGroup
+	for i, model := range models {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p := c.panelist(model)
+			defer p.dismiss()
+
+			var l panelListener
+			err := p.Send(ctx, panelPreamble+"\n\n"+prompt, &l)
+			aa[i] = PanelAnswer{Model: model, Content: l.answer(), Err: err, Usage: p.Snapshot().Usage}
+		}()
+	}
+	wg.Wait()
+
+	return aa, nil
+}
+
+// panelist returns a client asking the given model, sharing the Copilot
+// client, tools and callbacks of c but with sessions and turn state of its
+// own. Mutations are never approved since a panelist has no approval
+// callback.
+func (c *AIClient) panelist(model string) *AIClient {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	cfg := c.cfg
+	cfg.Model = model
+	p := AIClient{
+		client:        c.client,
+		cfg:           cfg,
+		tools:         c.tools,
+		allTools:      c.allTools,
+		skills:        c.skills,
+		kindSkill:     c.kindSkill,
+		initialized:   c.initialized,
+		preflightFn:   c.preflightFn,
+		cliPath:       c.cliPath,
+		cliLogDir:     c.cliLogDir,
+		audit:         c.audit,
+		fingerprintFn: c.fingerprintFn,
+		log:           c.log.With("panelist", model),
+	}
+
+	return &p
+}
+
+// dismiss destroys the sessions of a panelist, leaving the shared Copilot
+// client running.
+func (c *AIClient) dismiss() {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.dropSession()
+}
+
+// panelListener collects a panelist's answer. Panel answers are shown once
+// all members are done, so nothing is streamed.
+type panelListener struct {
+	mx      sync.Mutex
+	content string
+}
+
+func (l *panelListener) answer() string {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	return l.content
+}
+
+func (l *panelListener) AIResponseComplete(s string) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	l.content = s
+}
+
+func (*panelListener) AIResponseStart()           {}
+func (*panelListener) AIResponseDelta(string)     {}
+func (*panelListener) AIResponseFailed(error)     {}
+func (*panelListener) AIReasoningDelta(string)    {}
+func (*panelListener) AIReasoningComplete(string) {}
+func (*panelListener) AIToolStart(string)         {}
+func (*panelListener) AIToolComplete(string)      {}
+func (*panelListener) AIToolBudgetExceeded(int)   {}
+func (*panelListener) AIResponseTruncated()       {}
+func (*panelListener) AIStreamUnavailable()       {}
+func (*panelListener) AIRetrying(int, int, error) {}
@@ -0,0 +1,34 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/derailed/k9s/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPanelist(t *testing.T) {
+	c := NewAIClient(config.AI{Model: "gpt-4.1", ActiveSkill: "diagnostics"}, nil)
+	c.planPresented, c.autoApprove = true, true
+	c.usage = TokenUsage{Input: 10}
+
+	p := c.panelist("o3")
+	assert.Equal(t, "o3", p.ActiveModel())
+	assert.Equal(t, "diagnostics", p.ActiveSkill())
+	assert.False(t, p.planPresented)
+	assert.False(t, p.autoApprove)
+	assert.Nil(t, p.approvalFn)
+	assert.Zero(t, p.Snapshot().Usage)
+	assert.Equal(t, "gpt-4.1", c.ActiveModel())
+	p.dismiss()
+}
+
+func TestAskPanelDisabled(t *testing.T) {
+	_, err := NewAIClient(config.AI{Enabled: new(bool)}, nil).AskPanel(context.Background(), []string{"o3", "gpt-5"}, "why?")
+	require.Error(t, err)
+}
@@ -0,0 +1,160 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	copilot "github.com/github/copilot-sdk/go"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// --- get_pdb_status tool ---
+
+type getPDBStatusParams struct {
+	Namespace string `json:"namespace,omitempty" jsonschema:"Namespace to list PodDisruptionBudgets in. Empty means all namespaces"`
+	Kind      string `json:"kind,omitempty" jsonschema:"Optional workload kind (deployment, statefulset, daemonset or pod) to keep only the PDBs covering its pods"`
+	Name      string `json:"name,omitempty" jsonschema:"Workload name, required with kind"`
+}
+
+// pdbStatus reports a PodDisruptionBudget's policy and its current budget.
+type pdbStatus struct {
+	Name               string `json:"name"`
+	Namespace          string `json:"namespace"`
+	MinAvailable       string `json:"minAvailable,omitempty"`
+	MaxUnavailable     string `json:"maxUnavailable,omitempty"`
+	CurrentHealthy     int32  `json:"currentHealthy"`
+	DesiredHealthy     int32  `json:"desiredHealthy"`
+	ExpectedPods       int32  `json:"expectedPods"`
+	DisruptionsAllowed int32  `json:"disruptionsAllowed"`
+	MatchedPods        int    `json:"matchedPods,omitempty"`
+}
+
+func (tf *ToolFactory) getPDBStatusTool() copilot.Tool {
+	return copilot.DefineTool(
+		"get_pdb_status",
+		"List PodDisruptionBudgets with minAvailable/maxUnavailable, currentHealthy, desiredHealthy and disruptionsAllowed. "+
+			"Give a workload kind and name to keep only the PDBs covering its pods. "+
+			"Use before recommending drains, scale-downs or node maintenance: a PDB allowing 0 disruptions blocks evictions.",
+		func(params getPDBStatusParams, inv copilot.ToolInvocation) (any, error) {
+			if (params.Kind == "") != (params.Name == "") {
+				return nil, errors.New("kind and name must be given together")
+			}
+			if params.Kind != "" && params.Namespace == "" {
+				return nil, errors.New("namespace is required to match a workload")
+			}
+			ns, err := tf.listNamespace(params.Namespace)
+			if err != nil {
+				return nil, err
+			}
+			dial, err := tf.conn.Dial()
+			if err != nil {
+				return nil, fmt.Errorf("failed to connect to cluster: %w", err)
+			}
+			ctx := context.Background()
+
+			var pods []corev1.Pod
+			switch {
+			case params.Kind == "":
+			case isPodKind(params.Kind):
+				po, err := dial.CoreV1().Pods(params.Namespace).Get(ctx, params.Name, metav1.GetOptions{})
+				if err != nil {
+					return nil, fmt.Errorf("failed to get pod %s/%s: %w", params.Namespace, params.Name, err)
+				}
+				pods = []corev1.Pod{*po}
+			default:
+				_, pp, err := workloadSummary(ctx, dial, params.Kind, params.Name, params.Namespace)
+				if err != nil {
+					return nil, err
+				}
+				pods = pp
+			}
+
+			list, err := dial.PolicyV1().PodDisruptionBudgets(ns).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list pod disruption budgets: %w", err)
+			}
+			pdbs := keepAllowed(tf, list.Items, func(p policyv1.PodDisruptionBudget) string { return p.Namespace })
+			ss := pdbStatuses(pdbs, pods, params.Kind != "")
+			result := map[string]any{
+				"count": len(ss),
+				"pdbs":  ss,
+			}
+			if blocking := blockingPDBs(ss); len(blocking) > 0 {
+				result["blocking"] = blocking
+			}
+
+			return result, nil
+		},
+	)
+}
+
+// pdbStatuses summarizes the given PDBs. When match is set only the PDBs
+// selecting at least one of the pods are kept.
+func pdbStatuses(pdbs []policyv1.PodDisruptionBudget, pods []corev1.Pod, match bool) []pdbStatus {
+	ss := make([]pdbStatus, 0, len(pdbs))
+	for i := range pdbs {
+		pdb := &pdbs[i]
+		var matched int
+		if match {
+			matched = matchingPods(pdb, pods)
+			if matched == 0 {
+				continue
+			}
+		}
+		s := pdbStatus{
+			Name:               pdb.Name,
+			Namespace:          pdb.Namespace,
+			CurrentHealthy:     pdb.Status.CurrentHealthy,
+			DesiredHealthy:     pdb.Status.DesiredHealthy,
+			ExpectedPods:       pdb.Status.ExpectedPods,
+			DisruptionsAllowed: pdb.Status.DisruptionsAllowed,
+			MatchedPods:        matched,
+		}
+		if v := pdb.Spec.MinAvailable; v != nil {
+			s.MinAvailable = v.String()
+		}
+		if v := pdb.Spec.MaxUnavailable; v != nil {
+			s.MaxUnavailable = v.String()
+		}
+		ss = append(ss, s)
+	}
+
+	return ss
+}
+
+// matchingPods counts the pods a PDB selects. A PDB without a selector
+// selects nothing.
+func matchingPods(pdb *policyv1.PodDisruptionBudget, pods []corev1.Pod) int {
+	sel, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+	if err != nil {
+		return 0
+	}
+	var n int
+	for i := range pods {
+		if pods[i].Namespace == pdb.Namespace && sel.Matches(labels.Set(pods[i].Labels)) {
+			n++
+		}
+	}
+
+	return n
+}
+
+// blockingPDBs lists the PDBs that currently allow no disruptions.
+func blockingPDBs(ss []pdbStatus) []string {
+	var bb []string
+	for _, s := range ss {
+		if s.DisruptionsAllowed == 0 {
+			bb = append(bb, fmt.Sprintf("%s/%s allows 0 disruptions (%d/%d healthy): evictions of its pods will be refused",
+				s.Namespace, s.Name, s.CurrentHealthy, s.DesiredHealthy))
+		}
+	}
+
+	return bb
+}
@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestPDBStatuses(t *testing.T) {
+	one, zero := intstr.FromInt32(1), intstr.FromString("0%")
+	pdbs := []policyv1.PodDisruptionBudget{
+		makePDB("api", "default", map[string]string{"app": "api"}, &one, nil, 2),
+		makePDB("db", "default", map[string]string{"app": "db"}, nil, &zero, 0),
+		makePDB("none", "default", nil, &one, nil, 1),
+		makePDB("other-ns", "prod", map[string]string{"app": "api"}, &one, nil, 0),
+	}
+	pods := []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "api-1", Namespace: "default", Labels: map[string]string{"app": "api"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "api-2", Namespace: "default", Labels: map[string]string{"app": "api", "tier": "web"}}},
+	}
+
+	uu := map[string]struct {
+		match bool
+		e     []string
+	}{
+		"all": {
+			e: []string{"api", "db", "none", "other-ns"},
+		},
+		"workload": {
+			match: true,
+			e:     []string{"api"},
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			ss := pdbStatuses(pdbs, pods, u.match)
+			names := make([]string, 0, len(ss))
+			for _, s := range ss {
+				names = append(names, s.Name)
+			}
+			assert.Equal(t, u.e, names)
+		})
+	}
+
+	ss := pdbStatuses(pdbs[:2], pods, false)
+	assert.Equal(t, pdbStatus{
+		Name:               "db",
+		Namespace:          "default",
+		MaxUnavailable:     "0%",
+		CurrentHealthy:     3,
+		DesiredHealthy:     3,
+		ExpectedPods:       3,
+		DisruptionsAllowed: 0,
+	}, ss[1])
+	assert.Equal(t, 2, pdbStatuses(pdbs[:1], pods, true)[0].MatchedPods)
+}
+
+func TestBlockingPDBs(t *testing.T) {
+	bb := blockingPDBs([]pdbStatus{
+		{Name: "api", Namespace: "default", DisruptionsAllowed: 1},
+		{Name: "db", Namespace: "default", CurrentHealthy: 2, DesiredHealthy: 3},
+	})
+
+	assert.Equal(t, []string{
+		"default/db allows 0 disruptions (2/3 healthy): evictions of its pods will be refused",
+	}, bb)
+}
+
+func makePDB(name, ns string, sel map[string]string, minAvail, maxUnavail *intstr.IntOrString, allowed int32) policyv1.PodDisruptionBudget {
+	pdb := policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MinAvailable:   minAvail,
+			MaxUnavailable: maxUnavail,
+		},
+		Status: policyv1.PodDisruptionBudgetStatus{
+			CurrentHealthy:     3,
+			DesiredHealthy:     3,
+			ExpectedPods:       3,
+			DisruptionsAllowed: allowed,
+		},
+	}
+	if sel != nil {
+		pdb.Spec.Selector = &metav1.LabelSelector{MatchLabels: sel}
+	}
+
+	return pdb
+}
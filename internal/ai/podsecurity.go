@@ -0,0 +1,351 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	copilot "github.com/github/copilot-sdk/go"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	pssBaseline   = "baseline"
+	pssRestricted = "restricted"
+
+	// pssHardening tags checks that go beyond the Pod Security Standards.
+	pssHardening = "hardening"
+
+	// pssLabelPrefix prefixes the namespace labels enforcing the Pod Security
+	// Standards.
+	pssLabelPrefix = "pod-security.kubernetes.io/"
+)
+
+// baselineCapabilities lists the capabilities the baseline profile allows
+// containers to add.
+var baselineCapabilities = []corev1.Capability{
+	"AUDIT_WRITE", "CHOWN", "DAC_OVERRIDE", "FOWNER", "FSETID", "KILL", "MKNOD",
+	"NET_BIND_SERVICE", "SETFCAP", "SETGID", "SETPCAP", "SETUID", "SYS_CHROOT",
+}
+
+// --- check_pod_security tool ---
+
+type checkPodSecurityParams struct {
+	Kind      string `json:"kind" jsonschema:"Kind to check: pod, deployment, statefulset, daemonset, job or cronjob"`
+	Name      string `json:"name" jsonschema:"Resource name"`
+	Namespace string `json:"namespace" jsonschema:"Resource namespace"`
+}
+
+// podSecurityCheck is the outcome of a Pod Security Standards check.
+type podSecurityCheck struct {
+	Check      string   `json:"check"`
+	Profile    string   `json:"profile"`
+	Passed     bool     `json:"passed"`
+	Violations []string `json:"violations,omitempty"`
+}
+
+func (tf *ToolFactory) checkPodSecurityTool() copilot.Tool {
+	return copilot.DefineTool(
+		"check_pod_security",
+		"Evaluate a pod or the pod template of a workload against the baseline and restricted Pod Security Standards: "+
+			"privileged, host namespaces, hostPath volumes, host ports, capabilities, seccomp, allowPrivilegeEscalation and runAsNonRoot, plus readOnlyRootFilesystem as extra hardening. "+
+			"Returns pass/fail per check with the violating field paths and the Pod Security labels enforced on the namespace. "+
+			"Use for security audits instead of inspecting security contexts by hand.",
+		func(params checkPodSecurityParams, inv copilot.ToolInvocation) (any, error) {
+			if err := tf.checkNamespace(params.Namespace); err != nil {
+				return nil, err
+			}
+			dial, err := tf.conn.Dial()
+			if err != nil {
+				return nil, fmt.Errorf("failed to connect to cluster: %w", err)
+			}
+			ctx := context.Background()
+			spec, base, err := podSpecOf(ctx, dial, params.Kind, params.Name, params.Namespace)
+			if err != nil {
+				return nil, err
+			}
+
+			checks := evaluatePodSecurity(spec, base)
+			baseline, restricted := pssLevel(checks)
+			result := map[string]any{
+				"resource":   params.Kind + " " + params.Namespace + "/" + params.Name,
+				"baseline":   baseline,
+				"restricted": restricted,
+				"checks":     checks,
+			}
+			if ns, err := dial.CoreV1().Namespaces().Get(ctx, params.Namespace, metav1.GetOptions{}); err != nil {
+				result["namespaceLabels"] = "unavailable: " + err.Error()
+			} else if ll := pssLabels(ns.Labels); len(ll) > 0 {
+				result["namespaceLabels"] = ll
+			} else {
+				result["namespaceLabels"] = "none, the cluster default applies (usually privileged)"
+			}
+
+			return result, nil
+		},
+	)
+}
+
+// podSpecOf returns the pod spec of a pod or the pod template of a workload,
+// along with the field path of the spec.
+func podSpecOf(ctx context.Context, dial kubernetes.Interface, kind, name, ns string) (*corev1.PodSpec, string, error) {
+	const template = "spec.template.spec"
+
+	if isPodKind(kind) {
+		po, err := dial.CoreV1().Pods(ns).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get pod %s/%s: %w", ns, name, err)
+		}
+		return &po.Spec, "spec", nil
+	}
+	switch workloadKind(kind) {
+	case "deployment":
+		dp, err := dial.AppsV1().Deployments(ns).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get deployment %s/%s: %w", ns, name, err)
+		}
+		return &dp.Spec.Template.Spec, template, nil
+	case "statefulset":
+		sts, err := dial.AppsV1().StatefulSets(ns).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get statefulset %s/%s: %w", ns, name, err)
+		}
+		return &sts.Spec.Template.Spec, template, nil
+	case "daemonset":
+		ds, err := dial.AppsV1().DaemonSets(ns).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get daemonset %s/%s: %w", ns, name, err)
+		}
+		return &ds.Spec.Template.Spec, template, nil
+	}
+	switch strings.ToLower(kind) {
+	case "job", "jobs":
+		job, err := dial.BatchV1().Jobs(ns).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get job %s/%s: %w", ns, name, err)
+		}
+		return &job.Spec.Template.Spec, template, nil
+	case "cronjob", "cronjobs", "cj":
+		cj, err := dial.BatchV1().CronJobs(ns).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get cronjob %s/%s: %w", ns, name, err)
+		}
+		return &cj.Spec.JobTemplate.Spec.Template.Spec, "spec.jobTemplate." + template, nil
+	}
+
+	return nil, "", fmt.Errorf("unsupported kind %q: use pod, deployment, statefulset, daemonset, job or cronjob", kind)
+}
+
+// specContainer is a container of a pod spec along with its field path.
+type specContainer struct {
+	path, name string
+	sc         *corev1.SecurityContext
+	ports      []corev1.ContainerPort
+	ephemeral  bool
+}
+
+func (c specContainer) field(f string) string {
+	return fmt.Sprintf("%s.%s (container %q)", c.path, f, c.name)
+}
+
+func specContainers(spec *corev1.PodSpec, base string) []specContainer {
+	var cc []specContainer
+	for i, c := range spec.InitContainers {
+		cc = append(cc, specContainer{
+			path: fmt.Sprintf("%s.initContainers[%d]", base, i),
+			name: c.Name, sc: c.SecurityContext, ports: c.Ports,
+		})
+	}
+	for i, c := range spec.Containers {
+		cc = append(cc, specContainer{
+			path: fmt.Sprintf("%s.containers[%d]", base, i),
+			name: c.Name, sc: c.SecurityContext, ports: c.Ports,
+		})
+	}
+	for i, c := range spec.EphemeralContainers {
+		cc = append(cc, specContainer{
+			path: fmt.Sprintf("%s.ephemeralContainers[%d]", base, i),
+			name: c.Name, sc: c.SecurityContext, ports: c.Ports, ephemeral: true,
+		})
+	}
+
+	return cc
+}
+
+// evaluatePodSecurity runs the Pod Security Standards checks on a pod spec
+// found at the base field path.
+func evaluatePodSecurity(spec *corev1.PodSpec, base string) []podSecurityCheck {
+	var (
+		cc  = specContainers(spec, base)
+		psc = spec.SecurityContext
+	)
+	if psc == nil {
+		psc = &corev1.PodSecurityContext{}
+	}
+	check := func(name, profile string, vv []string) podSecurityCheck {
+		return podSecurityCheck{Check: name, Profile: profile, Passed: len(vv) == 0, Violations: vv}
+	}
+
+	var host []string
+	for f, on := range map[string]bool{"hostNetwork": spec.HostNetwork, "hostPID": spec.HostPID, "hostIPC": spec.HostIPC} {
+		if on {
+			host = append(host, base+"."+f+": true")
+		}
+	}
+	slices.Sort(host)
+
+	var hostPaths []string
+	for i, v := range spec.Volumes {
+		if v.HostPath != nil {
+			hostPaths = append(hostPaths, fmt.Sprintf("%s.volumes[%d].hostPath: %s (volume %q)", base, i, v.HostPath.Path, v.Name))
+		}
+	}
+
+	var privileged, hostPorts, addCaps, dropAll, escalation, readOnly []string
+	for _, c := range cc {
+		sc := c.sc
+		if sc == nil {
+			sc = &corev1.SecurityContext{}
+		}
+		if sc.Privileged != nil && *sc.Privileged {
+			privileged = append(privileged, c.field("securityContext.privileged")+": true")
+		}
+		for j, p := range c.ports {
+			if p.HostPort != 0 {
+				hostPorts = append(hostPorts, c.field(fmt.Sprintf("ports[%d].hostPort", j))+fmt.Sprintf(": %d", p.HostPort))
+			}
+		}
+		var caps corev1.Capabilities
+		if sc.Capabilities != nil {
+			caps = *sc.Capabilities
+		}
+		for _, capability := range caps.Add {
+			if !slices.Contains(baselineCapabilities, capability) {
+				addCaps = append(addCaps, c.field("securityContext.capabilities.add")+": "+string(capability))
+			}
+		}
+		if !slices.Contains(caps.Drop, "ALL") {
+			dropAll = append(dropAll, c.field("securityContext.capabilities.drop")+": must include ALL")
+		}
+		for _, capability := range caps.Add {
+			if slices.Contains(baselineCapabilities, capability) && capability != "NET_BIND_SERVICE" {
+				dropAll = append(dropAll, c.field("securityContext.capabilities.add")+": "+string(capability))
+			}
+		}
+		if sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation {
+			escalation = append(escalation, c.field("securityContext.allowPrivilegeEscalation")+": must be false")
+		}
+		if !c.ephemeral && (sc.ReadOnlyRootFilesystem == nil || !*sc.ReadOnlyRootFilesystem) {
+			readOnly = append(readOnly, c.field("securityContext.readOnlyRootFilesystem")+": should be true")
+		}
+	}
+
+	return []podSecurityCheck{
+		check("privileged", pssBaseline, privileged),
+		check("hostNamespaces", pssBaseline, host),
+		check("hostPathVolumes", pssBaseline, hostPaths),
+		check("hostPorts", pssBaseline, hostPorts),
+		check("capabilities", pssBaseline, addCaps),
+		check("seccompUnconfined", pssBaseline, seccompViolations(psc, cc, base, false)),
+		check("allowPrivilegeEscalation", pssRestricted, escalation),
+		check("runAsNonRoot", pssRestricted, nonRootViolations(psc, cc, base)),
+		check("restrictedCapabilities", pssRestricted, dropAll),
+		check("seccompProfile", pssRestricted, seccompViolations(psc, cc, base, true)),
+		check("readOnlyRootFilesystem", pssHardening, readOnly),
+	}
+}
+
+// seccompViolations reports Unconfined seccomp profiles. When required is
+// set, a RuntimeDefault or Localhost profile must also apply to every
+// container, either directly or from the pod.
+func seccompViolations(psc *corev1.PodSecurityContext, cc []specContainer, base string, required bool) []string {
+	var vv []string
+	podType := corev1.SeccompProfileType("")
+	if psc.SeccompProfile != nil {
+		podType = psc.SeccompProfile.Type
+	}
+	if podType == corev1.SeccompProfileTypeUnconfined {
+		vv = append(vv, base+".securityContext.seccompProfile.type: Unconfined")
+	}
+	for _, c := range cc {
+		t := corev1.SeccompProfileType("")
+		if c.sc != nil && c.sc.SeccompProfile != nil {
+			t = c.sc.SeccompProfile.Type
+		}
+		switch {
+		case t == corev1.SeccompProfileTypeUnconfined:
+			vv = append(vv, c.field("securityContext.seccompProfile.type")+": Unconfined")
+		case required && t == "" && podType != corev1.SeccompProfileTypeRuntimeDefault && podType != corev1.SeccompProfileTypeLocalhost:
+			vv = append(vv, c.field("securityContext.seccompProfile.type")+": must be RuntimeDefault or Localhost, here or on the pod")
+		}
+	}
+
+	return vv
+}
+
+// nonRootViolations reports containers that may run as root: runAsNonRoot
+// must be true on each container or on the pod, and runAsUser must not be 0.
+func nonRootViolations(psc *corev1.PodSecurityContext, cc []specContainer, base string) []string {
+	var vv []string
+	podNonRoot := psc.RunAsNonRoot != nil && *psc.RunAsNonRoot
+	if psc.RunAsNonRoot != nil && !*psc.RunAsNonRoot {
+		vv = append(vv, base+".securityContext.runAsNonRoot: false")
+	}
+	if psc.RunAsUser != nil && *psc.RunAsUser == 0 {
+		vv = append(vv, base+".securityContext.runAsUser: 0")
+	}
+	for _, c := range cc {
+		var nonRoot *bool
+		if c.sc != nil {
+			nonRoot = c.sc.RunAsNonRoot
+			if u := c.sc.RunAsUser; u != nil && *u == 0 {
+				vv = append(vv, c.field("securityContext.runAsUser")+": 0")
+			}
+		}
+		switch {
+		case nonRoot != nil && !*nonRoot:
+			vv = append(vv, c.field("securityContext.runAsNonRoot")+": false")
+		case nonRoot == nil && !podNonRoot:
+			vv = append(vv, c.field("securityContext.runAsNonRoot")+": must be true, here or on the pod")
+		}
+	}
+
+	return vv
+}
+
+// pssLevel tells whether the checks meet the baseline and restricted
+// profiles. Hardening checks don't count towards either.
+func pssLevel(checks []podSecurityCheck) (baseline, restricted bool) {
+	baseline, restricted = true, true
+	for _, c := range checks {
+		if c.Passed {
+			continue
+		}
+		switch c.Profile {
+		case pssBaseline:
+			baseline, restricted = false, false
+		case pssRestricted:
+			restricted = false
+		}
+	}
+
+	return baseline, restricted
+}
+
+// pssLabels returns the Pod Security Admission labels of a namespace.
+func pssLabels(labels map[string]string) map[string]string {
+	ll := make(map[string]string)
+	for k, v := range labels {
+		if strings.HasPrefix(k, pssLabelPrefix) {
+			ll[k] = v
+		}
+	}
+
+	return ll
+}
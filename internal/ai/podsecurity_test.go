@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEvaluatePodSecurity(t *testing.T) {
+	yes, no, root := true, false, int64(0)
+	restricted := func() corev1.PodSpec {
+		return corev1.PodSpec{
+			SecurityContext: &corev1.PodSecurityContext{
+				RunAsNonRoot:   &yes,
+				SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+			},
+			Containers: []corev1.Container{{
+				Name: "app",
+				SecurityContext: &corev1.SecurityContext{
+					AllowPrivilegeEscalation: &no,
+					ReadOnlyRootFilesystem:   &yes,
+					Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}, Add: []corev1.Capability{"NET_BIND_SERVICE"}},
+				},
+			}},
+		}
+	}
+
+	uu := map[string]struct {
+		spec                 func() corev1.PodSpec
+		baseline, restricted bool
+		failed               map[string][]string
+	}{
+		"restricted": {
+			spec:       restricted,
+			baseline:   true,
+			restricted: true,
+		},
+		"empty": {
+			spec: func() corev1.PodSpec {
+				return corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}}
+			},
+			baseline: true,
+			failed: map[string][]string{
+				"allowPrivilegeEscalation": {`spec.containers[0].securityContext.allowPrivilegeEscalation (container "app"): must be false`},
+				"runAsNonRoot":             {`spec.containers[0].securityContext.runAsNonRoot (container "app"): must be true, here or on the pod`},
+				"restrictedCapabilities":   {`spec.containers[0].securityContext.capabilities.drop (container "app"): must include ALL`},
+				"seccompProfile":           {`spec.containers[0].securityContext.seccompProfile.type (container "app"): must be RuntimeDefault or Localhost, here or on the pod`},
+				"readOnlyRootFilesystem":   {`spec.containers[0].securityContext.readOnlyRootFilesystem (container "app"): should be true`},
+			},
+		},
+		"privileged": {
+			spec: func() corev1.PodSpec {
+				s := restricted()
+				s.HostNetwork, s.HostPID = true, true
+				s.Volumes = []corev1.Volume{{Name: "docker", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/var/run/docker.sock"}}}}
+				c := &s.Containers[0]
+				c.SecurityContext.Privileged = &yes
+				c.SecurityContext.RunAsUser = &root
+				c.SecurityContext.Capabilities.Add = []corev1.Capability{"SYS_ADMIN", "CHOWN"}
+				c.SecurityContext.SeccompProfile = &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeUnconfined}
+				c.Ports = []corev1.ContainerPort{{ContainerPort: 80, HostPort: 8080}}
+				return s
+			},
+			failed: map[string][]string{
+				"privileged":        {`spec.containers[0].securityContext.privileged (container "app"): true`},
+				"hostNamespaces":    {"spec.hostNetwork: true", "spec.hostPID: true"},
+				"hostPathVolumes":   {`spec.volumes[0].hostPath: /var/run/docker.sock (volume "docker")`},
+				"hostPorts":         {`spec.containers[0].ports[0].hostPort (container "app"): 8080`},
+				"capabilities":      {`spec.containers[0].securityContext.capabilities.add (container "app"): SYS_ADMIN`},
+				"seccompUnconfined": {`spec.containers[0].securityContext.seccompProfile.type (container "app"): Unconfined`},
+				"runAsNonRoot":      {`spec.containers[0].securityContext.runAsUser (container "app"): 0`},
+				"restrictedCapabilities": {
+					`spec.containers[0].securityContext.capabilities.add (container "app"): CHOWN`,
+				},
+				"seccompProfile": {`spec.containers[0].securityContext.seccompProfile.type (container "app"): Unconfined`},
+			},
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			spec := u.spec()
+			checks := evaluatePodSecurity(&spec, "spec")
+			baseline, restricted := pssLevel(checks)
+			assert.Equal(t, u.baseline, baseline)
+			assert.Equal(t, u.restricted, restricted)
+
+			failed := make(map[string][]string)
+			for _, c := range checks {
+				if !c.Passed {
+					failed[c.Check] = c.Violations
+				}
+			}
+			if len(u.failed) == 0 {
+				assert.Empty(t, failed)
+				return
+			}
+			assert.Equal(t, u.failed, failed)
+		})
+	}
+}
+
+func TestCheckPodSecurityTool(t *testing.T) {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "ns1",
+		Labels: map[string]string{"pod-security.kubernetes.io/enforce": "restricted", "team": "web"},
+	}}
+	yes := true
+	dp := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "web"},
+		Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "web", SecurityContext: &corev1.SecurityContext{Privileged: &yes}}},
+		}}},
+	}
+	tf := newTestToolFactory(newTestFactory(), newTestConn(ns, dp))
+
+	m := callToolJSON(t, tf, "check_pod_security", map[string]any{"kind": "deploy", "name": "web", "namespace": "ns1"})
+	assert.Equal(t, false, m["baseline"])
+	assert.Equal(t, false, m["restricted"])
+	assert.Equal(t, map[string]any{"pod-security.kubernetes.io/enforce": "restricted"}, m["namespaceLabels"])
+	checks := m["checks"].([]any)
+	require.NotEmpty(t, checks)
+	first := checks[0].(map[string]any)
+	assert.Equal(t, "privileged", first["check"])
+	assert.Equal(t, []any{`spec.template.spec.containers[0].securityContext.privileged (container "web"): true`}, first["violations"])
+}
@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/derailed/k9s/internal/client"
+	"github.com/derailed/k9s/internal/config"
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	prefetchTimeout = 10 * time.Second
+	maxBundlePods   = 20
+
+	// PrefetchHeader marks context bundles injected into scoped chats.
+	PrefetchHeader = "[PREFETCHED CONTEXT]"
+)
+
+// ContextBundle prefetches a compact snapshot of a resource, limited to the
+// given config.AIPrefetchParts, so the first question of a scoped chat needs
+// fewer tool calls. Parts that don't apply to the resource kind are skipped.
+func (tf *ToolFactory) ContextBundle(ctx context.Context, kind, name, ns string, parts []string) (string, error) {
+	dial, err := tf.conn.Dial()
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to cluster: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, prefetchTimeout)
+	defer cancel()
+
+	var (
+		bundle = make(map[string]any, len(parts))
+		pods   []corev1.Pod
+	)
+	switch {
+	case workloadKind(kind) != "":
+		summary, pp, err := workloadSummary(ctx, dial, kind, name, ns)
+		if err != nil {
+			return "", err
+		}
+		pods = pp
+		if slices.Contains(parts, config.AIPrefetchSummary) {
+			bundle[config.AIPrefetchSummary] = summary
+		}
+	case isPodKind(kind):
+		po, err := dial.CoreV1().Pods(ns).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return "", fmt.Errorf("failed to get pod %s/%s: %w", ns, name, err)
+		}
+		pods = []corev1.Pod{*po}
+	}
+	if slices.Contains(parts, config.AIPrefetchPods) && len(pods) > 0 {
+		bundle[config.AIPrefetchPods] = podStatuses(pods)
+	}
+	if slices.Contains(parts, config.AIPrefetchEvents) {
+		events, err := dial.CoreV1().Events(ns).List(ctx, metav1.ListOptions{FieldSelector: "type=Warning"})
+		if err != nil {
+			return "", fmt.Errorf("failed to list events: %w", err)
+		}
+		if ww := workloadWarnings(events.Items, name, podNames(pods)); len(ww) > 0 {
+			bundle[config.AIPrefetchEvents] = ww
+		} else {
+			bundle[config.AIPrefetchEvents] = "no recent warnings"
+		}
+	}
+	if len(bundle) == 0 {
+		return "", nil
+	}
+
+	raw, err := yaml.Marshal(bundle)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s %s %s, fetched %s\n%s",
+		PrefetchHeader, kind, client.FQN(ns, name), time.Now().UTC().Format(time.RFC3339),
+		NewRedactor(tf.cfg.Redaction).Redact(string(raw))), nil
+}
+
+func isPodKind(kind string) bool {
+	switch strings.ToLower(kind) {
+	case "pod", "pods", "po":
+		return true
+	default:
+		return false
+	}
+}
+
+// podStatuses returns a one-line status per pod, unhealthy pods first.
+func podStatuses(pods []corev1.Pod) []string {
+	_, unhealthy := podHealth(pods)
+	pp := slices.Clone(pods)
+	slices.SortStableFunc(pp, func(a, b corev1.Pod) int {
+		_, ua := unhealthy[a.Name]
+		_, ub := unhealthy[b.Name]
+		switch {
+		case ua == ub:
+			return strings.Compare(a.Name, b.Name)
+		case ua:
+			return -1
+		default:
+			return 1
+		}
+	})
+
+	ss := make([]string, 0, min(len(pp), maxBundlePods+1))
+	for i := range pp {
+		if i == maxBundlePods {
+			ss = append(ss, fmt.Sprintf("... %d more pods", len(pp)-maxBundlePods))
+			break
+		}
+		p := &pp[i]
+		var ready, restarts int
+		for _, cs := range p.Status.ContainerStatuses {
+			restarts += int(cs.RestartCount)
+			if cs.Ready {
+				ready++
+			}
+		}
+		s := fmt.Sprintf("%s %s ready=%d/%d restarts=%d node=%s",
+			p.Name, p.Status.Phase, ready, len(p.Spec.Containers), restarts, p.Spec.NodeName)
+		if r := unhealthy[p.Name]; r != "" {
+			s += " reason=" + r
+		}
+		ss = append(ss, s)
+	}
+
+	return ss
+}
@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestIsPodKind(t *testing.T) {
+	uu := map[string]struct {
+		kind string
+		e    bool
+	}{
+		"pods":   {kind: "pods", e: true},
+		"alias":  {kind: "po", e: true},
+		"upper":  {kind: "Pod", e: true},
+		"deploy": {kind: "deployments"},
+		"blank":  {},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, isPodKind(u.kind))
+		})
+	}
+}
+
+func TestPodStatuses(t *testing.T) {
+	pods := []corev1.Pod{
+		makeWorkloadPod("p1", corev1.PodRunning, true, "", 0),
+		makeWorkloadPod("p2", corev1.PodRunning, false, "CrashLoopBackOff", 5),
+		makeWorkloadPod("p3", corev1.PodPending, false, "", 0),
+	}
+	for i := range pods {
+		pods[i].Spec.Containers = []corev1.Container{{Name: "c1"}}
+		pods[i].Spec.NodeName = "n1"
+	}
+	pods[0].Status.ContainerStatuses[0].Ready = true
+
+	assert.Equal(t, []string{
+		"p2 Running ready=0/1 restarts=5 node=n1 reason=CrashLoopBackOff",
+		"p3 Pending ready=0/1 restarts=0 node=n1 reason=Pending",
+		"p1 Running ready=1/1 restarts=0 node=n1",
+	}, podStatuses(pods))
+}
+
+func TestPodStatusesCapped(t *testing.T) {
+	pods := make([]corev1.Pod, maxBundlePods+5)
+	for i := range pods {
+		pods[i] = makeWorkloadPod(fmt.Sprintf("p%02d", i), corev1.PodRunning, true, "", 0)
+	}
+
+	ss := podStatuses(pods)
+	assert.Len(t, ss, maxBundlePods+1)
+	assert.Equal(t, "... 5 more pods", ss[maxBundlePods])
+}
@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"fmt"
+
+	"github.com/derailed/k9s/internal/client"
+)
+
+// mutationVerbs maps mutation tools to the RBAC verb their write needs.
+// Scale and restart patch the workload rather than its scale subresource,
+// server-side applies are patches too.
+var mutationVerbs = map[string]string{
+	"patch_resource":   "patch",
+	"apply_manifest":   "patch",
+	"scale_resource":   "patch",
+	"restart_resource": "patch",
+	"delete_resource":  "delete",
+	"exec_command":     "create",
+}
+
+// PreflightFunc checks whether a mutation tool call may proceed before the
+// user is asked to approve it.
+type PreflightFunc func(toolName string, args map[string]any) error
+
+// Preflight checks the current user may perform a mutation tool call. Tools
+// it does not know about pass.
+func (tf *ToolFactory) Preflight(toolName string, args map[string]any) error {
+	verb, ok := mutationVerbs[toolName]
+	if !ok {
+		return nil
+	}
+	gvr, ns, name := mutationTarget(toolName, args)
+	if toolName == "exec_command" {
+		gvr += ":exec"
+	}
+
+	return tf.canMutate(verb, gvr, ns, name)
+}
+
+// mutationTarget returns the resource a mutation tool call writes to. The
+// name is blank when the arguments don't tell, e.g. for a bad manifest.
+func mutationTarget(toolName string, args map[string]any) (gvr, ns, name string) {
+	gvr, _ = args["gvr"].(string)
+	ns, _ = args["namespace"].(string)
+	name, _ = args["name"].(string)
+	switch toolName {
+	case "exec_command":
+		gvr = "v1/pods"
+		name, _ = args["podName"].(string)
+	case "apply_manifest":
+		// Bad manifests are left for the tool to report.
+		raw, _ := args["manifest"].(string)
+		o, err := decodeSingleManifest(raw)
+		if err != nil {
+			return "", "", ""
+		}
+		res, err := applyTarget(o, ns)
+		if err != nil {
+			return "", "", ""
+		}
+		gvr, ns, name = res.String(), o.GetNamespace(), o.GetName()
+	}
+
+	return gvr, ns, name
+}
+
+// canMutate runs an RBAC access review for a write so a denial surfaces
+// before anything is sent to the API server. Review failures don't block the
+// write, the API server has the final say.
+func (tf *ToolFactory) canMutate(verb, gvr, ns, name string) error {
+	if tf.conn == nil || gvr == "" {
+		return nil
+	}
+	res := client.NewGVR(gvr)
+	ok, err := tf.conn.CanI(ns, res, name, []string{verb})
+	if err != nil {
+		tf.log.Warn("RBAC preflight failed", "verb", verb, "gvr", gvr, "error", err)
+		return nil
+	}
+	if ok {
+		return nil
+	}
+	what := res.R()
+	if sub := res.SubResource(); sub != "" {
+		what += "/" + sub
+	}
+	where := "namespace " + ns
+	if ns == "" {
+		where = "cluster scope"
+	}
+
+	return fmt.Errorf("you lack permission to %s %s in %s", verb, what, where)
+}
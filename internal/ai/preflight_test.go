@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreflight(t *testing.T) {
+	conn := newTestConn()
+	conn.denied = []string{"delete/pods", "patch/nodes", "patch/configmaps", "create/pods"}
+	tf := newTestToolFactory(newTestFactory(), conn)
+	registerConfigMapMeta()
+
+	uu := map[string]struct {
+		tool string
+		args map[string]any
+		err  string
+	}{
+		"allowed": {
+			tool: "delete_resource",
+			args: map[string]any{"gvr": "apps/v1/deployments", "namespace": "ns1", "name": "web"},
+		},
+		"denied": {
+			tool: "delete_resource",
+			args: map[string]any{"gvr": "v1/pods", "namespace": "ns1", "name": "p1"},
+			err:  "you lack permission to delete pods in namespace ns1",
+		},
+		"cluster-scoped": {
+			tool: "patch_resource",
+			args: map[string]any{"gvr": "v1/nodes", "name": "n1"},
+			err:  "you lack permission to patch nodes in cluster scope",
+		},
+		"apply": {
+			tool: "apply_manifest",
+			args: map[string]any{"manifest": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm1\n", "namespace": "ns1"},
+			err:  "you lack permission to patch configmaps in namespace ns1",
+		},
+		"apply-bad-manifest": {
+			tool: "apply_manifest",
+			args: map[string]any{"manifest": "kind: ConfigMap"},
+		},
+		"exec": {
+			tool: "exec_command",
+			args: map[string]any{"podName": "p1", "namespace": "ns1", "command": []any{"ls"}},
+			err:  "you lack permission to create pods/exec in namespace ns1",
+		},
+		"not-a-mutation": {
+			tool: "get_resource",
+			args: map[string]any{"gvr": "v1/pods", "namespace": "ns1", "name": "p1"},
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			err := tf.Preflight(u.tool, u.args)
+			if u.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, u.err)
+		})
+	}
+}
+
+func TestMutationToolsSkipDeniedWrites(t *testing.T) {
+	conn := newTestConn(makePod("ns1", "p1", nil))
+	conn.denied = []string{"delete/pods"}
+	tf := newTestToolFactory(newTestFactory(), conn)
+
+	_, err := callTool(t, tf, "delete_resource", map[string]any{"gvr": "v1/pods", "namespace": "ns1", "name": "p1"})
+	require.EqualError(t, err, "you lack permission to delete pods in namespace ns1")
+	for _, a := range conn.dyn.Actions() {
+		assert.NotEqual(t, "delete", a.GetVerb())
+	}
+}
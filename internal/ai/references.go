@@ -0,0 +1,232 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	copilot "github.com/github/copilot-sdk/go"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// --- find_references tool ---
+
+type findReferencesParams struct {
+	Kind          string `json:"kind" jsonschema:"Referenced kind: secret or configmap"`
+	Name          string `json:"name" jsonschema:"Secret or ConfigMap name"`
+	Namespace     string `json:"namespace" jsonschema:"Namespace to scan"`
+	AllNamespaces bool   `json:"allNamespaces,omitempty" jsonschema:"Scan every namespace for a same-named Secret/ConfigMap (default: false)"`
+}
+
+// workloadRef describes a workload consuming a Secret or ConfigMap.
+type workloadRef struct {
+	Kind      string   `json:"kind"`
+	Namespace string   `json:"namespace"`
+	Name      string   `json:"name"`
+	Usages    []string `json:"usages"`
+}
+
+func (tf *ToolFactory) findReferencesTool() copilot.Tool {
+	return copilot.DefineTool(
+		"find_references",
+		"Find the workloads consuming a Secret or ConfigMap: envFrom, env valueFrom key refs, volumes (incl. projected) and imagePullSecrets across Deployments, StatefulSets, DaemonSets, ReplicaSets, Jobs, CronJobs and Pods. Use before deleting or rotating a Secret/ConfigMap.",
+		func(params findReferencesParams, inv copilot.ToolInvocation) (any, error) {
+			var kind string
+			switch strings.ToLower(params.Kind) {
+			case "secret", "secrets", "sec":
+				kind = "Secret"
+			case "configmap", "configmaps", "cm":
+				kind = "ConfigMap"
+			default:
+				return nil, fmt.Errorf("unsupported kind %q: use secret or configmap", params.Kind)
+			}
+
+			dial, err := tf.conn.Dial()
+			if err != nil {
+				return nil, fmt.Errorf("failed to connect to cluster: %w", err)
+			}
+			ctx := context.Background()
+			ns, opts := params.Namespace, metav1.ListOptions{}
+			if params.AllNamespaces {
+				ns = ""
+			} else if err := tf.checkNamespace(ns); err != nil {
+				return nil, err
+			}
+
+			var (
+				refs []workloadRef
+				errs []string
+			)
+			add := func(k string, meta *metav1.ObjectMeta, spec *corev1.PodSpec) {
+				if !tf.nsAllowed(meta.Namespace) {
+					return
+				}
+				if uu := podSpecRefs(spec, kind, params.Name); len(uu) > 0 {
+					refs = append(refs, workloadRef{Kind: k, Namespace: meta.Namespace, Name: meta.Name, Usages: uu})
+				}
+			}
+
+			apps, batch := dial.AppsV1(), dial.BatchV1()
+			if ll, err := apps.Deployments(ns).List(ctx, opts); err != nil {
+				errs = append(errs, "deployments: "+err.Error())
+			} else {
+				for i := range ll.Items {
+					add("Deployment", &ll.Items[i].ObjectMeta, &ll.Items[i].Spec.Template.Spec)
+				}
+			}
+			if ll, err := apps.StatefulSets(ns).List(ctx, opts); err != nil {
+				errs = append(errs, "statefulsets: "+err.Error())
+			} else {
+				for i := range ll.Items {
+					add("StatefulSet", &ll.Items[i].ObjectMeta, &ll.Items[i].Spec.Template.Spec)
+				}
+			}
+			if ll, err := apps.DaemonSets(ns).List(ctx, opts); err != nil {
+				errs = append(errs, "daemonsets: "+err.Error())
+			} else {
+				for i := range ll.Items {
+					add("DaemonSet", &ll.Items[i].ObjectMeta, &ll.Items[i].Spec.Template.Spec)
+				}
+			}
+			if ll, err := batch.CronJobs(ns).List(ctx, opts); err != nil {
+				errs = append(errs, "cronjobs: "+err.Error())
+			} else {
+				for i := range ll.Items {
+					add("CronJob", &ll.Items[i].ObjectMeta, &ll.Items[i].Spec.JobTemplate.Spec.Template.Spec)
+				}
+			}
+			// Jobs, ReplicaSets and Pods are skipped only when a controller scanned
+			// here covers them. Anything else, e.g. operator managed pods, is kept.
+			if ll, err := batch.Jobs(ns).List(ctx, opts); err != nil {
+				errs = append(errs, "jobs: "+err.Error())
+			} else {
+				for i := range ll.Items {
+					if !controlledBy(&ll.Items[i].ObjectMeta, "CronJob") {
+						add("Job", &ll.Items[i].ObjectMeta, &ll.Items[i].Spec.Template.Spec)
+					}
+				}
+			}
+			if ll, err := apps.ReplicaSets(ns).List(ctx, opts); err != nil {
+				errs = append(errs, "replicasets: "+err.Error())
+			} else {
+				for i := range ll.Items {
+					if !controlledBy(&ll.Items[i].ObjectMeta, "Deployment") {
+						add("ReplicaSet", &ll.Items[i].ObjectMeta, &ll.Items[i].Spec.Template.Spec)
+					}
+				}
+			}
+			if ll, err := dial.CoreV1().Pods(ns).List(ctx, opts); err != nil {
+				errs = append(errs, "pods: "+err.Error())
+			} else {
+				for i := range ll.Items {
+					if !controlledBy(&ll.Items[i].ObjectMeta, "ReplicaSet", "StatefulSet", "DaemonSet", "Job") {
+						add("Pod", &ll.Items[i].ObjectMeta, &ll.Items[i].Spec)
+					}
+				}
+			}
+
+			scope := "namespace " + params.Namespace
+			if params.AllNamespaces {
+				scope = "all namespaces"
+			}
+			res := map[string]any{
+				"target":     kind + " " + params.Name,
+				"scope":      scope,
+				"references": refs,
+				"inUse":      len(refs) > 0,
+			}
+			if len(refs) == 0 {
+				res["summary"] = fmt.Sprintf("No workloads reference %s %q in %s.", kind, params.Name, scope)
+			}
+			if len(errs) > 0 {
+				res["errors"] = errs
+			}
+
+			return res, nil
+		},
+	)
+}
+
+// podSpecRefs returns how a pod spec consumes the named Secret or ConfigMap.
+func podSpecRefs(spec *corev1.PodSpec, kind, name string) []string {
+	var uu []string
+	secret := kind == "Secret"
+
+	cc := make([]corev1.Container, 0, len(spec.InitContainers)+len(spec.Containers))
+	cc = append(cc, spec.InitContainers...)
+	cc = append(cc, spec.Containers...)
+	for _, c := range cc {
+		for _, ef := range c.EnvFrom {
+			if (secret && ef.SecretRef != nil && ef.SecretRef.Name == name) ||
+				(!secret && ef.ConfigMapRef != nil && ef.ConfigMapRef.Name == name) {
+				uu = append(uu, fmt.Sprintf("envFrom (container %s)", c.Name))
+			}
+		}
+		for _, e := range c.Env {
+			if e.ValueFrom == nil {
+				continue
+			}
+			if ref := e.ValueFrom.SecretKeyRef; secret && ref != nil && ref.Name == name {
+				uu = append(uu, fmt.Sprintf("env %s from key %q (container %s)", e.Name, ref.Key, c.Name))
+			}
+			if ref := e.ValueFrom.ConfigMapKeyRef; !secret && ref != nil && ref.Name == name {
+				uu = append(uu, fmt.Sprintf("env %s from key %q (container %s)", e.Name, ref.Key, c.Name))
+			}
+		}
+	}
+
+	for _, v := range spec.Volumes {
+		if (secret && v.Secret != nil && v.Secret.SecretName == name) ||
+			(!secret && v.ConfigMap != nil && v.ConfigMap.Name == name) {
+			uu = append(uu, fmt.Sprintf("volume %s%s", v.Name, mountedBy(cc, v.Name)))
+		}
+		if v.Projected == nil {
+			continue
+		}
+		for _, src := range v.Projected.Sources {
+			if (secret && src.Secret != nil && src.Secret.Name == name) ||
+				(!secret && src.ConfigMap != nil && src.ConfigMap.Name == name) {
+				uu = append(uu, fmt.Sprintf("projected volume %s%s", v.Name, mountedBy(cc, v.Name)))
+			}
+		}
+	}
+
+	if secret {
+		for _, ps := range spec.ImagePullSecrets {
+			if ps.Name == name {
+				uu = append(uu, "imagePullSecrets")
+			}
+		}
+	}
+
+	return uu
+}
+
+// controlledBy reports whether the object's controller is one of the given kinds.
+func controlledBy(meta *metav1.ObjectMeta, kinds ...string) bool {
+	ref := metav1.GetControllerOfNoCopy(meta)
+
+	return ref != nil && slices.Contains(kinds, ref.Kind)
+}
+
+// mountedBy lists the containers mounting the named volume and where.
+func mountedBy(cc []corev1.Container, volume string) string {
+	var mm []string
+	for _, c := range cc {
+		for _, m := range c.VolumeMounts {
+			if m.Name == volume {
+				mm = append(mm, c.Name+":"+m.MountPath)
+			}
+		}
+	}
+	if len(mm) == 0 {
+		return " (not mounted)"
+	}
+
+	return " mounted at " + strings.Join(mm, ", ")
+}
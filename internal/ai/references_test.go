@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestPodSpecRefs(t *testing.T) {
+	spec := corev1.PodSpec{
+		InitContainers: []corev1.Container{{
+			Name:    "init",
+			EnvFrom: []corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "creds"}}}},
+		}},
+		Containers: []corev1.Container{{
+			Name: "app",
+			Env: []corev1.EnvVar{
+				{Name: "PASS", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "creds"}, Key: "password",
+				}}},
+				{Name: "MODE", ValueFrom: &corev1.EnvVarSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "settings"}, Key: "mode",
+				}}},
+			},
+			VolumeMounts: []corev1.VolumeMount{{Name: "certs", MountPath: "/certs"}},
+		}},
+		Volumes: []corev1.Volume{
+			{Name: "certs", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "creds"}}},
+			{Name: "all", VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{{ConfigMap: &corev1.ConfigMapProjection{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "settings"},
+				}}},
+			}}},
+		},
+		ImagePullSecrets: []corev1.LocalObjectReference{{Name: "creds"}},
+	}
+
+	uu := map[string]struct {
+		kind, name string
+		e          []string
+	}{
+		"secret": {
+			kind: "Secret",
+			name: "creds",
+			e: []string{
+				"envFrom (container init)",
+				`env PASS from key "password" (container app)`,
+				"volume certs mounted at app:/certs",
+				"imagePullSecrets",
+			},
+		},
+		"configmap": {
+			kind: "ConfigMap",
+			name: "settings",
+			e: []string{
+				`env MODE from key "mode" (container app)`,
+				"projected volume all (not mounted)",
+			},
+		},
+		"unused": {
+			kind: "Secret",
+			name: "settings",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, podSpecRefs(&spec, u.kind, u.name))
+		})
+	}
+}
+
+func TestFindReferencesOwnedPods(t *testing.T) {
+	owned := func(name, kind string) *corev1.Pod {
+		po := makePod("default", name, nil)
+		po.Spec.Volumes = []corev1.Volume{{
+			Name:         "creds",
+			VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "creds"}},
+		}}
+		if kind != "" {
+			yes := true
+			po.OwnerReferences = []metav1.OwnerReference{{Kind: kind, Name: "owner", Controller: &yes}}
+		}
+		return po
+	}
+	rs := appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "rs"}}
+	rs.Spec.Template.Spec = owned("", "").Spec
+
+	uu := map[string]struct {
+		oo []runtime.Object
+		e  []any
+	}{
+		"standalone": {
+			oo: []runtime.Object{owned("p1", "")},
+			e:  []any{"Pod/p1"},
+		},
+		"operator": {
+			oo: []runtime.Object{owned("p1", "Database")},
+			e:  []any{"Pod/p1"},
+		},
+		"replicaset": {
+			oo: []runtime.Object{&rs, owned("p1", "ReplicaSet")},
+			e:  []any{"ReplicaSet/rs"},
+		},
+		"job": {
+			oo: []runtime.Object{owned("p1", "Job")},
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			tf := newTestToolFactory(newTestFactory(), newTestConn(u.oo...))
+			m := callToolJSON(t, tf, "find_references", map[string]any{"kind": "secret", "name": "creds", "namespace": "default"})
+
+			var rr []any
+			refs, _ := m["references"].([]any)
+			for _, r := range refs {
+				ref := r.(map[string]any)
+				rr = append(rr, ref["kind"].(string)+"/"+ref["name"].(string))
+			}
+			assert.Equal(t, u.e, rr)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"net"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	copilot "github.com/github/copilot-sdk/go"
+)
+
+// retryBackoff is the wait before the first resend, doubling on each attempt.
+var retryBackoff = time.Second
+
+// retryStatusRX matches the HTTP statuses worth retrying: rate limits and
+// server side failures.
+var retryStatusRX = regexp.MustCompile(`\b(429|5(?:0[0-4]|29))\b`)
+
+// retryHints are error fragments of transient provider or network failures.
+var retryHints = []string{
+	"rate limit",
+	"too many requests",
+	"overloaded",
+	"temporarily unavailable",
+	"connection reset",
+}
+
+// sendWithRetry sends the prompt and resends it up to ai.maxRetries times
+// with exponential backoff while it fails with a retryable error. A turn
+// that already streamed content or ran tools is never resent, it would
+// render twice or replay the tool calls.
+func (c *AIClient) sendWithRetry(ctx context.Context, session chatSession, prompt string, listener Listener, active *atomic.Int64) (*copilot.SessionEvent, error) {
+	retries, wait := c.cfg.SendRetries(), retryBackoff
+	for attempt := 1; ; attempt++ {
+		response, err := c.sendPrompt(ctx, session, prompt)
+		if err == nil || attempt > retries || active.Load() > 0 || ctx.Err() != nil || !isRetryable(err) {
+			return response, err
+		}
+		c.log.Warn("AI request failed, retrying", "attempt", attempt, "max", retries, "backoff", wait, "error", err)
+		listener.AIRetrying(attempt, retries, err)
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
+
+// isRetryable checks if a failed send may succeed when resent. Timeouts and
+// cancellations are final.
+func isRetryable(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var ne net.Error
+	if errors.As(err, &ne) {
+		return !ne.Timeout()
+	}
+	msg := strings.ToLower(err.Error())
+	if retryStatusRX.MatchString(msg) {
+		return true
+	}
+	for _, h := range retryHints {
+		if strings.Contains(msg, h) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,165 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/derailed/k9s/internal/config"
+	copilot "github.com/github/copilot-sdk/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsRetryable(t *testing.T) {
+	uu := map[string]struct {
+		err error
+		e   bool
+	}{
+		"rate-limit": {
+			err: errors.New("HTTP 429: Too Many Requests"),
+			e:   true,
+		},
+		"server": {
+			err: errors.New("provider returned status 503"),
+			e:   true,
+		},
+		"overloaded": {
+			err: errors.New("session error: Overloaded"),
+			e:   true,
+		},
+		"network": {
+			err: fmt.Errorf("send: %w", &net.OpError{Op: "read", Err: errors.New("connection reset by peer")}),
+			e:   true,
+		},
+		"bad-request": {
+			err: errors.New("HTTP 400: invalid model"),
+		},
+		"deadline": {
+			err: fmt.Errorf("status 503: %w", context.DeadlineExceeded),
+		},
+		"canceled": {
+			err: context.Canceled,
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, isRetryable(u.err))
+		})
+	}
+}
+
+func TestSendTurnRetries(t *testing.T) {
+	defer func(d time.Duration) { retryBackoff = d }(retryBackoff)
+	retryBackoff = time.Millisecond
+
+	uu := map[string]struct {
+		cfg      config.AI
+		fails    int
+		err      error
+		sends    int64
+		retries  int64
+		failed   bool
+		streamed bool
+	}{
+		"recovers": {
+			fails:   2,
+			err:     errors.New("HTTP 429"),
+			sends:   3,
+			retries: 2,
+		},
+		"exhausted": {
+			fails:   5,
+			err:     errors.New("HTTP 502"),
+			sends:   3,
+			retries: 2,
+			failed:  true,
+		},
+		"configured": {
+			cfg:     config.AI{MaxRetries: 1},
+			fails:   5,
+			err:     errors.New("HTTP 502"),
+			sends:   2,
+			retries: 1,
+			failed:  true,
+		},
+		"disabled": {
+			cfg:    config.AI{MaxRetries: -1},
+			fails:  1,
+			err:    errors.New("HTTP 429"),
+			sends:  1,
+			failed: true,
+		},
+		"not-retryable": {
+			fails:  1,
+			err:    errors.New("HTTP 401"),
+			sends:  1,
+			failed: true,
+		},
+		"deadline": {
+			fails:  1,
+			err:    context.DeadlineExceeded,
+			sends:  1,
+			failed: true,
+		},
+		"streamed": {
+			fails:    1,
+			err:      errors.New("HTTP 503"),
+			sends:    1,
+			failed:   true,
+			streamed: true,
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			c := NewAIClient(u.cfg, nil)
+			s := &flakySession{streamingSession: newStreamingSession(), fails: u.fails, err: u.err, stream: u.streamed}
+			var l countingListener
+
+			err := c.sendTurn(context.Background(), s, "hello", &l)
+			if u.failed {
+				require.Error(t, err)
+				assert.Equal(t, int64(1), l.failed.Load())
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "all good", l.answer.Load())
+			}
+			assert.Equal(t, u.sends, s.sends.Load())
+			assert.Equal(t, u.retries, l.retries.Load())
+		})
+	}
+}
+
+// flakySession fails its first sends with err, optionally after streaming a
+// delta, then answers.
+type flakySession struct {
+	*streamingSession
+	fails  int
+	err    error
+	stream bool
+	sends  atomic.Int64
+}
+
+func (s *flakySession) SendAndWait(context.Context, copilot.MessageOptions) (*copilot.SessionEvent, error) {
+	if n := s.sends.Add(1); int(n) <= s.fails {
+		if s.stream {
+			delta := "x"
+			s.emit(copilot.SessionEvent{Type: copilot.AssistantMessageDelta, Data: copilot.Data{DeltaContent: &delta}})
+		}
+		return nil, s.err
+	}
+	content := "all good"
+
+	return &copilot.SessionEvent{Type: copilot.AssistantMessage, Data: copilot.Data{Content: &content}}, nil
+}
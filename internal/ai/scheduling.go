@@ -0,0 +1,259 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	copilot "github.com/github/copilot-sdk/go"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	resourcehelper "k8s.io/component-helpers/resource"
+	"k8s.io/component-helpers/scheduling/corev1/nodeaffinity"
+	"k8s.io/klog/v2"
+)
+
+// --- diagnose_scheduling tool ---
+
+type diagnoseSchedulingParams struct {
+	PodName   string `json:"podName" jsonschema:"Name of the Pending pod"`
+	Namespace string `json:"namespace" jsonschema:"Pod namespace"`
+}
+
+func (tf *ToolFactory) diagnoseSchedulingTool() copilot.Tool {
+	return copilot.DefineTool(
+		"diagnose_scheduling",
+		"Explain why a pod is stuck Pending. Checks every node against the pod's resource requests, nodeSelector/affinity, taints/tolerations, cordons and readiness, verifies PVC binding, and includes FailedScheduling events. Prefer this over multiple calls when a pod will not schedule.",
+		func(params diagnoseSchedulingParams, inv copilot.ToolInvocation) (any, error) {
+			if err := tf.checkNamespace(params.Namespace); err != nil {
+				return nil, err
+			}
+			dial, err := tf.conn.Dial()
+			if err != nil {
+				return nil, fmt.Errorf("failed to connect to cluster: %w", err)
+			}
+			ctx := context.Background()
+
+			pod, err := dial.CoreV1().Pods(params.Namespace).Get(ctx, params.PodName, metav1.GetOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get pod %s/%s: %w", params.Namespace, params.PodName, err)
+			}
+			if pod.Spec.NodeName != "" {
+				return map[string]any{
+					"pod":     params.Namespace + "/" + params.PodName,
+					"phase":   string(pod.Status.Phase),
+					"summary": fmt.Sprintf("Pod is already scheduled on node %q; scheduling is not the problem.", pod.Spec.NodeName),
+				}, nil
+			}
+
+			nodes, err := dial.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list nodes: %w", err)
+			}
+			pods, err := dial.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+				FieldSelector: "status.phase!=Succeeded,status.phase!=Failed",
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list pods: %w", err)
+			}
+
+			requests := resourcehelper.PodRequests(pod, resourcehelper.PodResourcesOptions{})
+			usage := nodeUsage(pods.Items)
+
+			var (
+				blockers  = make(map[string]int)
+				nodeNotes = make([]map[string]any, 0, len(nodes.Items))
+				fits      []string
+			)
+			for i := range nodes.Items {
+				n := &nodes.Items[i]
+				reasons := nodeFitReasons(pod, n, requests, usage[n.Name])
+				if len(reasons) == 0 {
+					fits = append(fits, n.Name)
+				}
+				for _, r := range reasons {
+					blockers[r]++
+				}
+				nodeNotes = append(nodeNotes, map[string]any{
+					"node":    n.Name,
+					"fits":    len(reasons) == 0,
+					"reasons": reasons,
+				})
+			}
+
+			var summary []string
+			for r, c := range blockers {
+				summary = append(summary, fmt.Sprintf("%d/%d node(s): %s", c, len(nodes.Items), r))
+			}
+			sort.Strings(summary)
+
+			pvcs, pvcBlockers := tf.pvcBindings(ctx, pod)
+			summary = append(summary, pvcBlockers...)
+			if len(summary) == 0 && len(fits) > 0 {
+				summary = append(summary, fmt.Sprintf("%d node(s) appear to fit; the scheduler may not have retried yet or a scheduler plugin/quota is blocking", len(fits)))
+			}
+
+			var schedEvents []string
+			events, err := dial.CoreV1().Events(params.Namespace).List(ctx, metav1.ListOptions{
+				FieldSelector: "involvedObject.name=" + params.PodName + ",reason=FailedScheduling",
+			})
+			if err == nil {
+				for _, ev := range events.Items {
+					schedEvents = append(schedEvents, ev.Message)
+				}
+			}
+
+			return map[string]any{
+				"pod":   params.Namespace + "/" + params.PodName,
+				"phase": string(pod.Status.Phase),
+				"requests": map[string]string{
+					"cpu":    requests.Cpu().String(),
+					"memory": requests.Memory().String(),
+				},
+				"nodeSelector":           pod.Spec.NodeSelector,
+				"tolerations":            len(pod.Spec.Tolerations),
+				"blockers":               summary,
+				"candidateNodes":         fits,
+				"nodes":                  nodeNotes,
+				"pvcs":                   pvcs,
+				"failedSchedulingEvents": schedEvents,
+			}, nil
+		},
+	)
+}
+
+type nodeAlloc struct {
+	cpu, memory resource.Quantity
+	pods        int
+}
+
+// nodeUsage sums the requests of all non-terminal pods per node.
+func nodeUsage(pods []corev1.Pod) map[string]*nodeAlloc {
+	usage := make(map[string]*nodeAlloc)
+	for i := range pods {
+		p := &pods[i]
+		if p.Spec.NodeName == "" {
+			continue
+		}
+		u, ok := usage[p.Spec.NodeName]
+		if !ok {
+			u = new(nodeAlloc)
+			usage[p.Spec.NodeName] = u
+		}
+		rr := resourcehelper.PodRequests(p, resourcehelper.PodResourcesOptions{})
+		u.cpu.Add(*rr.Cpu())
+		u.memory.Add(*rr.Memory())
+		u.pods++
+	}
+
+	return usage
+}
+
+// nodeFitReasons returns why a pod cannot land on a node, mirroring the
+// scheduler's main filter plugins. An empty result means the node fits.
+func nodeFitReasons(pod *corev1.Pod, node *corev1.Node, requests corev1.ResourceList, used *nodeAlloc) []string {
+	var reasons []string
+
+	if node.Spec.Unschedulable {
+		reasons = append(reasons, "node is cordoned (unschedulable)")
+	}
+	for _, c := range node.Status.Conditions {
+		if c.Type == corev1.NodeReady && c.Status != corev1.ConditionTrue {
+			reasons = append(reasons, "node is not Ready")
+		}
+	}
+	if ok, _ := nodeaffinity.GetRequiredNodeAffinity(pod).Match(node); !ok {
+		reasons = append(reasons, "node didn't match pod's nodeSelector/required node affinity")
+	}
+	for i := range node.Spec.Taints {
+		t := &node.Spec.Taints[i]
+		if t.Effect == corev1.TaintEffectPreferNoSchedule || tolerates(pod.Spec.Tolerations, t) {
+			continue
+		}
+		reasons = append(reasons, fmt.Sprintf("untolerated taint {%s}", taintString(t)))
+	}
+
+	if used == nil {
+		used = new(nodeAlloc)
+	}
+	alloc := node.Status.Allocatable
+	if free := alloc.Cpu().DeepCopy(); !requests.Cpu().IsZero() {
+		free.Sub(used.cpu)
+		if free.Cmp(*requests.Cpu()) < 0 {
+			reasons = append(reasons, "insufficient cpu")
+		}
+	}
+	if free := alloc.Memory().DeepCopy(); !requests.Memory().IsZero() {
+		free.Sub(used.memory)
+		if free.Cmp(*requests.Memory()) < 0 {
+			reasons = append(reasons, "insufficient memory")
+		}
+	}
+	if capacity := alloc.Pods().Value(); capacity > 0 && int64(used.pods) >= capacity {
+		reasons = append(reasons, "too many pods")
+	}
+
+	return reasons
+}
+
+func tolerates(tt []corev1.Toleration, taint *corev1.Taint) bool {
+	for i := range tt {
+		if tt[i].ToleratesTaint(klog.Background(), taint, false) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func taintString(t *corev1.Taint) string {
+	if t.Value == "" {
+		return t.Key + ":" + string(t.Effect)
+	}
+
+	return t.Key + "=" + t.Value + ":" + string(t.Effect)
+}
+
+// pvcBindings reports the binding state of each claim the pod mounts along
+// with blockers for unbound ones.
+func (tf *ToolFactory) pvcBindings(ctx context.Context, pod *corev1.Pod) ([]map[string]string, []string) {
+	dial, err := tf.conn.Dial()
+	if err != nil {
+		return nil, nil
+	}
+
+	var (
+		pvcs     []map[string]string
+		blockers []string
+	)
+	for _, v := range pod.Spec.Volumes {
+		if v.PersistentVolumeClaim == nil {
+			continue
+		}
+		name := v.PersistentVolumeClaim.ClaimName
+		pvc, err := dial.CoreV1().PersistentVolumeClaims(pod.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			pvcs = append(pvcs, map[string]string{"name": name, "phase": "Missing"})
+			blockers = append(blockers, fmt.Sprintf("PVC %q not found: %s", name, err))
+			continue
+		}
+		sc := ""
+		if pvc.Spec.StorageClassName != nil {
+			sc = *pvc.Spec.StorageClassName
+		}
+		pvcs = append(pvcs, map[string]string{
+			"name":         name,
+			"phase":        string(pvc.Status.Phase),
+			"storageClass": sc,
+		})
+		if pvc.Status.Phase != corev1.ClaimBound {
+			blockers = append(blockers, fmt.Sprintf("PVC %q is %s (storageClass %q)", name, pvc.Status.Phase, sc))
+		}
+	}
+
+	return pvcs, blockers
+}
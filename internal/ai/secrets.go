@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/derailed/k9s/internal/client"
+	copilot "github.com/github/copilot-sdk/go"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// secretDigestLen is the number of hex digits kept from a secret value digest.
+const secretDigestLen = 12
+
+type getConfigDataParams struct {
+	Name      string `json:"name" jsonschema:"Resource name"`
+	Namespace string `json:"namespace" jsonschema:"Kubernetes namespace"`
+	Live      bool   `json:"live,omitempty" jsonschema:"If true, read from the API server instead of the K9s cache"`
+}
+
+// --- get_secret tool ---
+
+func (tf *ToolFactory) getSecretTool() copilot.Tool {
+	return copilot.DefineTool(
+		"get_secret",
+		"Fetch a Secret without its values: type, labels, annotations and, per key, the value length and a SHA256 prefix. "+
+			"Use it instead of get_resource for secrets, e.g. to check a key exists, is empty, or holds the same value as in another secret. "+
+			"Served from the K9s cache unless live=true.",
+		func(params getConfigDataParams, inv copilot.ToolInvocation) (any, error) {
+			if err := tf.checkNamespace(params.Namespace); err != nil {
+				return nil, err
+			}
+			o, err := tf.getObject(client.SecGVR, params.Namespace, params.Name, params.Live)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get secret %s: %w", client.FQN(params.Namespace, params.Name), err)
+			}
+
+			return maskSecret(o)
+		},
+	)
+}
+
+// isSecretGVR checks if gvr names core secrets, whatever its version.
+func isSecretGVR(gvr *client.GVR) bool {
+	return gvr.G() == "" && gvr.R() == "secrets"
+}
+
+// maskSecret describes a secret without its values: per key, the value
+// length and a SHA256 prefix.
+func maskSecret(o runtime.Object) (map[string]any, error) {
+	var sec corev1.Secret
+	if err := fromUnstructured(o, &sec); err != nil {
+		return nil, err
+	}
+
+	// Keys are listed rather than mapped so the redactor leaves the entries
+	// of well known keys, e.g. password, readable.
+	keys := make([]map[string]any, 0, len(sec.Data))
+	for _, k := range slices.Sorted(maps.Keys(sec.Data)) {
+		sum := sha256.Sum256(sec.Data[k])
+		keys = append(keys, map[string]any{
+			"key":    k,
+			"length": len(sec.Data[k]),
+			"sha256": hex.EncodeToString(sum[:])[:secretDigestLen],
+		})
+	}
+	res := configMeta(sec.Name, sec.Namespace, sec.Labels, sec.Annotations)
+	res["type"] = string(sec.Type)
+	res["keys"] = keys
+	if sec.Immutable != nil && *sec.Immutable {
+		res["immutable"] = true
+	}
+
+	return res, nil
+}
+
+// --- get_configmap tool ---
+
+func (tf *ToolFactory) getConfigMapTool() copilot.Tool {
+	return copilot.DefineTool(
+		"get_configmap",
+		"Fetch a ConfigMap with its labels, annotations and data values. Binary data is reported by size only. "+
+			"Served from the K9s cache unless live=true.",
+		func(params getConfigDataParams, inv copilot.ToolInvocation) (any, error) {
+			if err := tf.checkNamespace(params.Namespace); err != nil {
+				return nil, err
+			}
+			o, err := tf.getObject(client.CmGVR, params.Namespace, params.Name, params.Live)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get configmap %s: %w", client.FQN(params.Namespace, params.Name), err)
+			}
+			var cm corev1.ConfigMap
+			if err := fromUnstructured(o, &cm); err != nil {
+				return nil, err
+			}
+
+			res := configMeta(cm.Name, cm.Namespace, cm.Labels, cm.Annotations)
+			res["data"] = cm.Data
+			if len(cm.BinaryData) > 0 {
+				bin := make(map[string]int, len(cm.BinaryData))
+				for k, v := range cm.BinaryData {
+					bin[k] = len(v)
+				}
+				res["binaryDataBytes"] = bin
+			}
+			if cm.Immutable != nil && *cm.Immutable {
+				res["immutable"] = true
+			}
+
+			return res, nil
+		},
+	)
+}
+
+// configMeta returns the metadata shared by the secret and configmap tools.
+// The last applied manifest is left out, it repeats the data.
+func configMeta(name, ns string, ll, aa map[string]string) map[string]any {
+	res := map[string]any{
+		"name":      name,
+		"namespace": ns,
+	}
+	if len(ll) > 0 {
+		res["labels"] = ll
+	}
+	aa = maps.Clone(aa)
+	delete(aa, lastAppliedAnnotation)
+	if len(aa) > 0 {
+		res["annotations"] = aa
+	}
+
+	return res
+}
@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"testing"
+
+	"github.com/derailed/k9s/internal/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetSecretTool(t *testing.T) {
+	f := newTestFactory()
+	f.add(client.SecGVR, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "prod",
+			Name:      "db",
+			Labels:    map[string]string{"app": "db"},
+			Annotations: map[string]string{
+				lastAppliedAnnotation: `{"data":{"password":"czNjcmV0"}}`,
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			"password": []byte("s3cret"),
+			"user":     []byte("admin"),
+			"empty":    {},
+		},
+	})
+	tf := newTestToolFactory(f, newTestConn())
+
+	out, err := callTool(t, tf, "get_secret", map[string]any{"namespace": "prod", "name": "db"})
+	require.NoError(t, err)
+	assert.NotContains(t, out, "s3cret")
+	assert.NotContains(t, out, "czNjcmV0")
+	assert.NotContains(t, out, "admin")
+
+	m := callToolJSON(t, tf, "get_secret", map[string]any{"namespace": "prod", "name": "db"})
+	assert.Equal(t, "Opaque", m["type"])
+	assert.Equal(t, map[string]any{"app": "db"}, m["labels"])
+	assert.Nil(t, m["annotations"])
+	assert.Equal(t, []any{
+		map[string]any{"key": "empty", "length": float64(0), "sha256": "e3b0c44298fc"},
+		map[string]any{"key": "password", "length": float64(6), "sha256": "1ec1c26b50d5"},
+		map[string]any{"key": "user", "length": float64(5), "sha256": "8c6976e5b541"},
+	}, m["keys"])
+
+	_, err = callTool(t, tf, "get_secret", map[string]any{"namespace": "prod", "name": "nope"})
+	assert.ErrorContains(t, err, "failed to get secret prod/nope")
+}
+
+func TestGetResourceMasksSecret(t *testing.T) {
+	f := newTestFactory()
+	f.add(client.SecGVR, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "prod",
+			Name:      "db",
+			Annotations: map[string]string{
+				lastAppliedAnnotation: `{"data":{"password":"czNjcmV0"}}`,
+			},
+		},
+		Data:       map[string][]byte{"password": []byte("s3cret")},
+		StringData: map[string]string{"token": "t0ken"},
+	})
+	tf := newTestToolFactory(f, newTestConn())
+
+	out, err := callTool(t, tf, "get_resource", map[string]any{"gvr": "v1/secrets", "namespace": "prod", "name": "db"})
+	require.NoError(t, err)
+	assert.NotContains(t, out, "s3cret")
+	assert.NotContains(t, out, "czNjcmV0")
+	assert.NotContains(t, out, "t0ken")
+	assert.Contains(t, out, "1ec1c26b50d5")
+}
+
+func TestIsSecretGVR(t *testing.T) {
+	uu := map[string]struct {
+		gvr string
+		e   bool
+	}{
+		"core":      {gvr: "v1/secrets", e: true},
+		"versioned": {gvr: "v2/secrets", e: true},
+		"configmap": {gvr: "v1/configmaps"},
+		"crd":       {gvr: "bitnami.com/v1alpha1/secrets"},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, isSecretGVR(client.NewGVR(u.gvr)))
+		})
+	}
+}
+
+func TestGetConfigMapTool(t *testing.T) {
+	f := newTestFactory()
+	f.add(client.CmGVR, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "settings"},
+		Data:       map[string]string{"level": "debug"},
+		BinaryData: map[string][]byte{"logo.png": make([]byte, 42)},
+		Immutable:  ptrTo(true),
+	})
+	tf := newTestToolFactory(f, newTestConn())
+
+	m := callToolJSON(t, tf, "get_configmap", map[string]any{"namespace": "prod", "name": "settings"})
+	assert.Equal(t, map[string]any{
+		"name":            "settings",
+		"namespace":       "prod",
+		"data":            map[string]any{"level": "debug"},
+		"binaryDataBytes": map[string]any{"logo.png": float64(42)},
+		"immutable":       true,
+	}, m)
+}
@@ -3,7 +3,12 @@
 
 package ai
 
-import copilot "github.com/github/copilot-sdk/go"
+import (
+	"log/slog"
+
+	"github.com/derailed/k9s/internal/config"
+	copilot "github.com/github/copilot-sdk/go"
+)
 
 // Skill represents a named group of tools and a specialized system message.
 type Skill struct {
@@ -12,6 +17,8 @@ type Skill struct {
 	ToolNames       []string
 	SystemSuffix    string
 	ReasoningEffort string
+	// Custom is true for skills defined in ai.customSkills.
+	Custom bool
 }
 
 // SkillRegistry holds all available built-in skills.
@@ -28,11 +35,29 @@ func NewSkillRegistry() *SkillRegistry {
 		Description: "Diagnose unhealthy pods, deployments, and workloads",
 		ToolNames: []string{
 			"get_pod_diagnostics",
+			"get_node_diagnostics",
+			"diagnose_image_pull",
+			"get_workload_summary",
+			"find_failing_jobs",
+			"get_incident_timeline",
+			"recent_changes",
+			"diagnose_scheduling",
+			"get_admission_context",
+			"validate_manifest",
+			"detect_drift",
+			"get_helm_release",
+			"compare_namespaces",
+			"compare_resources",
 			"get_logs",
 			"get_events",
+			"watch_events",
 			"describe_resource",
 			"get_cluster_health",
+			"get_configmap",
+			"get_secret",
 			"get_resource",
+			"run_kubectl",
+			"exec_command",
 		},
 		SystemSuffix: `Focus: Root-cause analysis and remediation.
 Follow the diagnostics playbook: check pod diagnostics, get crash logs (previous=true), review events, analyze exit codes.
@@ -44,6 +69,11 @@ Present your findings clearly. Do NOT attempt fixes unless the user explicitly a
 		Description: "RBAC auditing, security posture, and policy analysis",
 		ToolNames: []string{
 			"check_rbac",
+			"check_pod_security",
+			"find_references",
+			"validate_manifest",
+			"get_secret",
+			"get_configmap",
 			"get_resource",
 			"describe_resource",
 			"list_resources",
@@ -51,6 +81,7 @@ Present your findings clearly. Do NOT attempt fixes unless the user explicitly a
 		SystemSuffix: `Focus: Security posture and RBAC analysis.
 Check for: Overly permissive ClusterRoleBindings, wildcard verbs/resources, secrets mounted unnecessarily, containers running as root, missing network policies.
 Flag any security concerns with severity (Critical/High/Medium/Low).
+Read secrets with get_secret, never get_resource: it shows keys, lengths and digests without exposing values.
 Present findings only. Do NOT attempt fixes unless the user explicitly asks.`,
 	})
 
@@ -59,10 +90,15 @@ Present findings only. Do NOT attempt fixes unless the user explicitly asks.`,
 		Description: "Resource utilization, cost optimization, and scaling",
 		ToolNames: []string{
 			"get_cluster_health",
+			"top_pods",
+			"top_nodes",
 			"list_resources",
 			"get_resource",
 			"describe_resource",
 			"get_pod_diagnostics",
+			"get_node_diagnostics",
+			"get_workload_summary",
+			"get_pdb_status",
 		},
 		SystemSuffix: `Focus: Resource efficiency, cost optimization, and scaling recommendations.
 Analyze: CPU/memory requests vs limits, over-provisioned pods, under-utilized nodes, missing resource requests.
@@ -78,6 +114,52 @@ func (r *SkillRegistry) Register(s *Skill) {
 	r.skills[s.Name] = s
 }
 
+// LoadCustomSkills registers the skills defined in ai.customSkills. Skills
+// without a name or tools, or named after a registered skill, are skipped.
+func (r *SkillRegistry) LoadCustomSkills(ss []config.AISkillConfig, log *slog.Logger) {
+	for _, s := range ss {
+		switch {
+		case s.Name == "":
+			log.Warn("Skipping AI custom skill without a name")
+			continue
+		case len(s.ToolNames) == 0:
+			log.Warn("Skipping AI custom skill without tools", "skill", s.Name)
+			continue
+		}
+		if _, ok := r.skills[s.Name]; ok {
+			log.Warn("Skipping AI custom skill named after a registered skill", "skill", s.Name)
+			continue
+		}
+		r.Register(&Skill{
+			Name:            s.Name,
+			Description:     s.Description,
+			ToolNames:       s.ToolNames,
+			SystemSuffix:    s.SystemSuffix,
+			ReasoningEffort: s.ReasoningEffort,
+			Custom:          true,
+		})
+	}
+}
+
+// CheckCustomTools warns about tools referenced by custom skills that are
+// not among the given tools.
+func (r *SkillRegistry) CheckCustomTools(tools []copilot.Tool, log *slog.Logger) {
+	known := make(map[string]bool, len(tools))
+	for _, t := range tools {
+		known[t.Name] = true
+	}
+	for _, s := range r.skills {
+		if !s.Custom {
+			continue
+		}
+		for _, n := range s.ToolNames {
+			if !known[n] {
+				log.Warn("Unknown tool in AI custom skill", "skill", s.Name, "tool", n)
+			}
+		}
+	}
+}
+
 // Get returns a skill by name.
 func (r *SkillRegistry) Get(name string) (*Skill, bool) {
 	s, ok := r.skills[name]
@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"context"
+	"time"
+)
+
+const statusTimeout = 5 * time.Second
+
+// TokenUsage tracks token consumption for the current session.
+type TokenUsage struct {
+	Input    int64
+	Output   int64
+	Requests int
+	// Context is the size of the session context as of the latest request:
+	// its input, tool outputs included, plus its output.
+	Context int64
+}
+
+// Total returns the combined input and output token count.
+func (u TokenUsage) Total() int64 {
+	return u.Input + u.Output
+}
+
+// Status describes the current state of the AI integration.
+type Status struct {
+	Enabled       bool
+	Ready         bool
+	Model         string
+	Skill         string
+	Provider      string
+	CLIPath       string
+	CLILogDir     string
+	CLIVersion    string
+	Authenticated bool
+	Login         string
+	AuthMessage   string
+	Usage         TokenUsage
+	Err           error
+}
+
+// Snapshot reports the locally known AI state without querying the CLI.
+func (c *AIClient) Snapshot() Status {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	st := Status{
+		Enabled:   c.cfg.IsEnabled(),
+		Ready:     c.initialized,
+		Model:     c.cfg.Model,
+		Skill:     c.cfg.ActiveSkill,
+		Provider:  "copilot",
+		CLIPath:   c.cliPath,
+		CLILogDir: c.cliLogDir,
+		Usage:     c.usage,
+	}
+	if c.cfg.IsBYOK() {
+		st.Provider = c.cfg.Provider.Type
+	}
+
+	return st
+}
+
+// Status reports the current AI state. CLI version and auth details are
+// only available once the CLI server is running.
+func (c *AIClient) Status(ctx context.Context) Status {
+	st := c.Snapshot()
+	c.mx.RLock()
+	cl := c.client
+	c.mx.RUnlock()
+
+	if !st.Ready || cl == nil {
+		return st
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, statusTimeout)
+	defer cancel()
+	if v, err := cl.GetStatus(ctx); err != nil {
+		st.Err = err
+	} else {
+		st.CLIVersion = v.Version
+	}
+	if st.Provider != "copilot" {
+		return st
+	}
+	auth, err := cl.GetAuthStatus(ctx)
+	if err != nil {
+		st.Err = err
+		return st
+	}
+	st.Authenticated = auth.IsAuthenticated
+	if auth.Login != nil {
+		st.Login = *auth.Login
+	}
+	if auth.StatusMessage != nil {
+		st.AuthMessage = *auth.StatusMessage
+	}
+
+	return st
+}
+
+// Usage returns the token usage of the current session.
+func (c *AIClient) Usage() TokenUsage {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+
+	return c.usage
+}
+
+// recordUsage adds token counts reported by the model to the session totals.
+func (c *AIClient) recordUsage(in, out *float64) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.usage.Context = 0
+	if in != nil {
+		c.usage.Input += int64(*in)
+		c.usage.Context += int64(*in)
+	}
+	if out != nil {
+		c.usage.Output += int64(*out)
+		c.usage.Context += int64(*out)
+	}
+	c.usage.Requests++
+}
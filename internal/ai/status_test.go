@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/derailed/k9s/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshot(t *testing.T) {
+	off := false
+	uu := map[string]struct {
+		cfg config.AI
+		e   Status
+	}{
+		"copilot": {
+			cfg: config.AI{Model: "gpt-5", ActiveSkill: "security"},
+			e:   Status{Enabled: true, Model: "gpt-5", Skill: "security", Provider: "copilot"},
+		},
+		"byok": {
+			cfg: config.AI{Provider: &config.AIProvider{Type: "openai", BaseURL: "http://localhost:11434/v1"}},
+			e:   Status{Enabled: true, Provider: "openai"},
+		},
+		"byok-no-url": {
+			cfg: config.AI{Provider: &config.AIProvider{Type: "openai"}},
+			e:   Status{Enabled: true, Provider: "copilot"},
+		},
+		"disabled": {
+			cfg: config.AI{Enabled: &off},
+			e:   Status{Provider: "copilot"},
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			c := NewAIClient(u.cfg, nil)
+			assert.Equal(t, u.e, c.Snapshot())
+			// Until the CLI runs, the status is all the client knows locally.
+			assert.Equal(t, u.e, c.Status(context.Background()))
+		})
+	}
+}
+
+func TestSnapshotUsage(t *testing.T) {
+	c := NewAIClient(config.AI{}, nil)
+	in, out := 100.0, 20.0
+	c.recordUsage(&in, &out)
+
+	st := c.Snapshot()
+	assert.Equal(t, TokenUsage{Input: 100, Output: 20, Requests: 1, Context: 120}, st.Usage)
+	assert.Equal(t, int64(120), st.Usage.Total())
+}
@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	copilot "github.com/github/copilot-sdk/go"
+)
+
+// steerMode delivers a message into the turn in flight rather than queuing
+// it for the next one.
+const steerMode = "immediate"
+
+var (
+	// ErrInterrupted is the cancellation cause of turns interrupted by the user.
+	ErrInterrupted = errors.New("AI request interrupted")
+
+	errNoTurn = errors.New("no AI request in flight")
+)
+
+// SteerPrompt wraps guidance the user sends while a turn is in flight.
+func SteerPrompt(guidance string) string {
+	return "[STEERING] The user is redirecting the task in progress: " + guidance +
+		"\nAdjust your approach accordingly and carry on. Do not start over."
+}
+
+// CorrectionPrompt wraps the correction sent after the user interrupted a
+// turn. The session keeps the interrupted turn so no context is lost.
+func CorrectionPrompt(correction string) string {
+	if correction == "" {
+		return "[CORRECTION] The user interrupted your previous answer. Briefly sum up where you stopped and ask how to proceed."
+	}
+
+	return "[CORRECTION] The user interrupted your previous answer to redirect it: " + correction +
+		"\nPick up the task from where you stopped, taking this into account."
+}
+
+// Steer injects guidance into the turn in flight. It fails when no turn is
+// running or the CLI refuses mid-turn input, in which case callers should
+// Interrupt and resend with CorrectionPrompt.
+func (c *AIClient) Steer(ctx context.Context, guidance string) error {
+	var s chatSession
+	c.mx.RLock()
+	for r := range c.inflight {
+		if r.session != nil {
+			s = r.session
+			break
+		}
+	}
+	c.mx.RUnlock()
+	if s == nil {
+		return errNoTurn
+	}
+	if _, err := s.Send(ctx, copilot.MessageOptions{Prompt: SteerPrompt(guidance), Mode: steerMode}); err != nil {
+		return fmt.Errorf("steering failed: %w", err)
+	}
+	c.log.Info("Steered AI request in flight")
+
+	return nil
+}
+
+// aborter stops the agent loop of a session server side.
+type aborter interface {
+	Abort(context.Context) error
+}
+
+// Interrupt cancels the turns in flight while keeping their sessions, so the
+// conversation can go on with a correction. Returns false when idle.
+func (c *AIClient) Interrupt() bool {
+	var ss []aborter
+	c.mx.Lock()
+	n := len(c.inflight)
+	for r := range c.inflight {
+		r.cancel(ErrInterrupted)
+		if a, ok := r.session.(aborter); ok && !slices.Contains(ss, a) {
+			ss = append(ss, a)
+		}
+	}
+	c.mx.Unlock()
+	if n == 0 {
+		return false
+	}
+
+	// Stop the agent loop server side too, the canceled wait alone won't.
+	ctx, cancel := context.WithTimeout(context.Background(), statusTimeout)
+	defer cancel()
+	for _, s := range ss {
+		if err := s.Abort(ctx); err != nil {
+			c.log.Warn("Failed to abort AI session turn", "error", err)
+		}
+	}
+	c.log.Info("AI request interrupted by the user")
+
+	return true
+}
@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/derailed/k9s/internal/config"
+	copilot "github.com/github/copilot-sdk/go"
+)
+
+// sendPrompt runs the prompt on the session and returns the final assistant
+// message. Per ai.sendMode, the message is either the one SendAndWait hands
+// back or assembled from the streamed deltas.
+func (c *AIClient) sendPrompt(ctx context.Context, session chatSession, prompt string) (*copilot.SessionEvent, error) {
+	opts := copilot.MessageOptions{Prompt: prompt}
+	if c.cfg.SendMode != config.AISendStream {
+		return session.SendAndWait(ctx, opts)
+	}
+
+	sc := newStreamCollector()
+	unsubscribe := session.On(sc.handle)
+	defer unsubscribe()
+	if _, err := session.Send(ctx, opts); err != nil {
+		return nil, err
+	}
+	if err := sc.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	return sc.response(), nil
+}
+
+// streamCollector assembles an answer from the session events of a turn.
+type streamCollector struct {
+	mx     sync.Mutex
+	text   strings.Builder
+	final  *copilot.SessionEvent // last complete assistant message
+	split  bool                  // a message completed, separate the next one
+	idle   chan struct{}
+	failed chan error
+}
+
+func newStreamCollector() *streamCollector {
+	return &streamCollector{
+		idle:   make(chan struct{}, 1),
+		failed: make(chan error, 1),
+	}
+}
+
+func (s *streamCollector) handle(event copilot.SessionEvent) {
+	switch event.Type {
+	case copilot.AssistantMessageDelta, copilot.AssistantStreamingDelta:
+		if event.Data.DeltaContent == nil {
+			return
+		}
+		s.mx.Lock()
+		if s.split {
+			s.text.WriteString("\n\n")
+			s.split = false
+		}
+		s.text.WriteString(*event.Data.DeltaContent)
+		s.mx.Unlock()
+	case copilot.AssistantMessage:
+		s.mx.Lock()
+		s.final, s.split = &event, s.text.Len() > 0
+		s.mx.Unlock()
+	case copilot.SessionIdle:
+		select {
+		case s.idle <- struct{}{}:
+		default:
+		}
+	case copilot.SessionError:
+		msg := "session error"
+		if event.Data.Message != nil {
+			msg = *event.Data.Message
+		}
+		select {
+		case s.failed <- errors.New("session error: " + msg):
+		default:
+		}
+	}
+}
+
+// wait blocks until the session goes idle, fails or ctx is done.
+func (s *streamCollector) wait(ctx context.Context) error {
+	select {
+	case <-s.idle:
+		return nil
+	case err := <-s.failed:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for session.idle: %w", ctx.Err())
+	}
+}
+
+// response returns the streamed answer as an assistant message. When nothing
+// streamed, the content of the last complete message is used instead.
+func (s *streamCollector) response() *copilot.SessionEvent {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	res := copilot.SessionEvent{Type: copilot.AssistantMessage}
+	if s.final != nil {
+		res = *s.final
+	}
+	if s.text.Len() > 0 {
+		content := s.text.String()
+		res.Data.Content = &content
+	}
+
+	return &res
+}
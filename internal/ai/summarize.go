@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// condenseThreshold is the output size above which tool results get condensed.
+	condenseThreshold = 16 * 1024
+
+	// condenseTailLines is the number of trailing log lines always kept.
+	condenseTailLines = 50
+)
+
+var (
+	logProblemRx = regexp.MustCompile(`(?i)\b(error|err|warn(ing)?|fail(ed|ure)?|fatal|panic|exception|refused|denied|timeout|killed)\b`)
+
+	// describeKeepRx matches describe lines worth keeping outside of the
+	// Conditions and Events sections.
+	describeKeepRx = regexp.MustCompile(`^\s*(Name|Namespace|Status|Reason|Message|Node|Image|State|Last State|Ready|Restart Count|Exit Code|Replicas|Limits|Requests|cpu|memory|Started|Finished|Controlled By):`)
+)
+
+// condenseLogs shrinks oversized logs by collapsing repeated lines and, if
+// still too large, keeping only problem lines plus the most recent tail.
+func condenseLogs(logs string) string {
+	if len(logs) <= condenseThreshold {
+		return logs
+	}
+
+	lines := dedupLines(strings.Split(strings.TrimRight(logs, "\n"), "\n"))
+	out := strings.Join(lines, "\n")
+	if len(out) <= condenseThreshold {
+		return "[condensed: repeated lines collapsed; call again with raw=true for full logs]\n" + out
+	}
+
+	tail := max(len(lines)-condenseTailLines, 0)
+	kept := make([]string, 0, condenseTailLines*2)
+	var dropped int
+	for i, l := range lines {
+		if i >= tail || logProblemRx.MatchString(l) {
+			kept = append(kept, l)
+			continue
+		}
+		dropped++
+	}
+
+	return fmt.Sprintf("[condensed: kept error/warning lines and last %d lines, dropped %d; call again with raw=true for full logs]\n%s",
+		condenseTailLines, dropped, strings.Join(kept, "\n"))
+}
+
+// dedupLines collapses consecutive identical lines into one annotated line.
+func dedupLines(lines []string) []string {
+	out := make([]string, 0, len(lines))
+	for i := 0; i < len(lines); {
+		j := i + 1
+		for j < len(lines) && lines[j] == lines[i] {
+			j++
+		}
+		if n := j - i; n > 1 {
+			out = append(out, fmt.Sprintf("%s  [repeated %d times]", lines[i], n))
+		} else {
+			out = append(out, lines[i])
+		}
+		i = j
+	}
+
+	return out
+}
+
+// condenseDescribe trims an oversized describe output down to identity,
+// status fields, conditions and events.
+func condenseDescribe(desc string) string {
+	if len(desc) <= condenseThreshold {
+		return desc
+	}
+
+	var kept []string
+	inSection := false
+	for _, l := range strings.Split(desc, "\n") {
+		// Top level section headers start at column 0.
+		if l != "" && l[0] != ' ' && l[0] != '\t' {
+			inSection = strings.HasPrefix(l, "Conditions:") || strings.HasPrefix(l, "Events:")
+			if inSection || describeKeepRx.MatchString(l) {
+				kept = append(kept, l)
+			}
+			continue
+		}
+		if inSection || describeKeepRx.MatchString(l) {
+			kept = append(kept, l)
+		}
+	}
+
+	return "[condensed: status, conditions and events only; call again with raw=true for the full description]\n" +
+		strings.Join(kept, "\n")
+}
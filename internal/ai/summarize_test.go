@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDedupLines(t *testing.T) {
+	uu := map[string]struct {
+		lines, e []string
+	}{
+		"empty": {
+			e: []string{},
+		},
+		"unique": {
+			lines: []string{"a", "b"},
+			e:     []string{"a", "b"},
+		},
+		"repeats": {
+			lines: []string{"a", "a", "a", "b", "a"},
+			e:     []string{"a  [repeated 3 times]", "b", "a"},
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, dedupLines(u.lines))
+		})
+	}
+}
+
+func TestCondenseLogs(t *testing.T) {
+	small := "hello\nworld\n"
+	assert.Equal(t, small, condenseLogs(small))
+
+	noisy := strings.Repeat("tick\n", 10_000)
+	out := condenseLogs(noisy)
+	assert.Contains(t, out, "tick  [repeated 10000 times]")
+
+	var b strings.Builder
+	for i := range 2_000 {
+		b.WriteString("request served ")
+		b.WriteString(strings.Repeat("x", i%7))
+		b.WriteString("\n")
+		if i == 10 {
+			b.WriteString("ERROR connection refused\n")
+		}
+	}
+	out = condenseLogs(b.String())
+	assert.Contains(t, out, "ERROR connection refused")
+	assert.Less(t, len(out), condenseThreshold)
+}
+
+func TestCondenseDescribe(t *testing.T) {
+	desc := "Name:         fred\nNamespace:    blee\nAnnotations:  " + strings.Repeat("a", condenseThreshold) +
+		"\nConditions:\n  Type   Status\n  Ready  False\nVolumes:\n  data:\n    Type: EmptyDir\nEvents:\n  Warning  BackOff  restarting\n"
+	out := condenseDescribe(desc)
+
+	assert.Contains(t, out, "Name:         fred")
+	assert.Contains(t, out, "  Ready  False")
+	assert.Contains(t, out, "  Warning  BackOff  restarting")
+	assert.NotContains(t, out, "Annotations:")
+	assert.NotContains(t, out, "EmptyDir")
+}
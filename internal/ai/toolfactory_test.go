@@ -0,0 +1,634 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/derailed/k9s/internal/client"
+	"github.com/derailed/k9s/internal/config"
+	"github.com/derailed/k9s/internal/dao"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var toolNow = time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+func makePod(ns, name string, ll map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ns,
+			Labels:    ll,
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationApply},
+			},
+		},
+		Spec: corev1.PodSpec{
+			NodeName:   "n1",
+			Containers: []corev1.Container{{Name: "app", Image: "nginx"}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func makeEvent(ns, name, involved, kind, reason string, at time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: ns},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: involved, Namespace: ns},
+		Type:           kind,
+		Reason:         reason,
+		Count:          1,
+		LastTimestamp:  metav1.NewTime(at),
+	}
+}
+
+func makeNode(name string, ready corev1.ConditionStatus) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: ready, LastTransitionTime: metav1.NewTime(toolNow)},
+			},
+		},
+	}
+}
+
+func TestGetResourceTool(t *testing.T) {
+	f := newTestFactory()
+	f.add(client.PodGVR, makePod("ns1", "p1", nil))
+	live := makePod("ns1", "p2", nil)
+	tf := newTestToolFactory(f, newTestConn(live))
+
+	uu := map[string]struct {
+		args map[string]any
+		name string
+		err  bool
+	}{
+		"cached": {
+			args: map[string]any{"gvr": "v1/pods", "namespace": "ns1", "name": "p1"},
+			name: "name: p1",
+		},
+		"cached-miss": {
+			args: map[string]any{"gvr": "v1/pods", "namespace": "ns1", "name": "p2"},
+			err:  true,
+		},
+		"live": {
+			args: map[string]any{"gvr": "v1/pods", "namespace": "ns1", "name": "p2", "live": true},
+			name: "name: p2",
+		},
+		"live-miss": {
+			args: map[string]any{"gvr": "v1/pods", "namespace": "ns1", "name": "p1", "live": true},
+			err:  true,
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			raw, err := callTool(t, tf, "get_resource", u.args)
+			if u.err {
+				require.Error(t, err)
+				assert.True(t, kerrors.IsNotFound(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Contains(t, raw, u.name)
+			assert.NotContains(t, raw, "managedFields")
+		})
+	}
+}
+
+func TestListResourcesTool(t *testing.T) {
+	f := newTestFactory()
+	f.add(client.PodGVR, makePod("ns1", "web-1", map[string]string{"app": "web"}))
+	f.add(client.PodGVR, makePod("ns1", "web-2", map[string]string{"app": "web"}))
+	f.add(client.PodGVR, makePod("ns1", "db-1", map[string]string{"app": "db"}))
+	f.add(client.PodGVR, makePod("ns2", "web-3", map[string]string{"app": "web"}))
+	tf := newTestToolFactory(f, newTestConn())
+
+	uu := map[string]struct {
+		args    map[string]any
+		count   int
+		summary string
+	}{
+		"namespace": {
+			args:    map[string]any{"gvr": "v1/pods", "namespace": "ns1"},
+			count:   3,
+			summary: "Found 3 v1/pods resources",
+		},
+		"all-namespaces": {
+			args:    map[string]any{"gvr": "v1/pods", "namespace": ""},
+			count:   4,
+			summary: "Found 4 v1/pods resources",
+		},
+		"selector": {
+			args:    map[string]any{"gvr": "v1/pods", "namespace": "ns1", "labelSelector": "app=web"},
+			count:   2,
+			summary: "Found 2 v1/pods resources",
+		},
+		"limit": {
+			args:    map[string]any{"gvr": "v1/pods", "namespace": "ns1", "limit": 1},
+			count:   1,
+			summary: "Found 3 v1/pods resources (showing first 1)",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			m := callToolJSON(t, tf, "list_resources", u.args)
+			assert.Equal(t, u.summary, m["summary"])
+			assert.Len(t, m["resources"], u.count)
+		})
+	}
+}
+
+func TestListResourcesToolBadSelector(t *testing.T) {
+	tf := newTestToolFactory(newTestFactory(), newTestConn())
+
+	_, err := callTool(t, tf, "list_resources", map[string]any{"gvr": "v1/pods", "labelSelector": "app in (web"})
+	assert.ErrorContains(t, err, "invalid label selector")
+}
+
+func TestDescribeResourceToolNoDiscovery(t *testing.T) {
+	tf := newTestToolFactory(newTestFactory(), newTestConn())
+
+	_, err := callTool(t, tf, "describe_resource", map[string]any{"gvr": "v1/pods", "namespace": "ns1", "name": "p1"})
+	assert.ErrorContains(t, err, "failed to describe v1/pods ns1/p1")
+}
+
+func TestGetEventsTool(t *testing.T) {
+	f := newTestFactory()
+	f.add(coreEvGVR, makeEvent("ns1", "e1", "p1", "Normal", "Pulled", toolNow))
+	f.add(coreEvGVR, makeEvent("ns1", "e2", "p1", "Warning", "BackOff", toolNow.Add(2*time.Minute)))
+	f.add(coreEvGVR, makeEvent("ns1", "e3", "p2", "Warning", "Failed", toolNow.Add(time.Minute)))
+	tf := newTestToolFactory(f, newTestConn())
+
+	uu := map[string]struct {
+		args    map[string]any
+		total   float64
+		reasons []string
+	}{
+		"all": {
+			args:    map[string]any{"namespace": "ns1"},
+			total:   3,
+			reasons: []string{"BackOff", "Failed", "Pulled"},
+		},
+		"warnings": {
+			args:    map[string]any{"namespace": "ns1", "eventType": "Warning"},
+			total:   3,
+			reasons: []string{"BackOff", "Failed"},
+		},
+		"resource": {
+			args:    map[string]any{"namespace": "ns1", "resourceName": "p1"},
+			total:   2,
+			reasons: []string{"BackOff", "Pulled"},
+		},
+		"limit": {
+			args:    map[string]any{"namespace": "ns1", "limit": 1},
+			total:   3,
+			reasons: []string{"BackOff"},
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			m := callToolJSON(t, tf, "get_events", u.args)
+			assert.Equal(t, u.total, m["total"])
+			ee, _ := m["events"].([]any)
+			rr := make([]string, 0, len(ee))
+			for _, e := range ee {
+				rr = append(rr, e.(map[string]any)["reason"].(string))
+			}
+			assert.Equal(t, u.reasons, rr)
+		})
+	}
+}
+
+func TestGetEventsToolForbidden(t *testing.T) {
+	conn := newTestConn()
+	conn.dial.PrependReactor("list", "events", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, kerrors.NewForbidden(corev1.Resource("events"), "", nil)
+	})
+	tf := newTestToolFactory(newTestFactory(), conn)
+
+	_, err := callTool(t, tf, "get_events", map[string]any{"namespace": "ns1", "live": true})
+	require.Error(t, err)
+	assert.True(t, kerrors.IsForbidden(err))
+}
+
+func TestGetClusterHealthTool(t *testing.T) {
+	orig := timeNow
+	defer func() { timeNow = orig }()
+	timeNow = func() time.Time { return toolNow.Add(5 * time.Hour) }
+
+	f := newTestFactory()
+	f.add(client.NodeGVR, makeNode("n1", corev1.ConditionTrue))
+	f.add(client.NodeGVR, makeNode("n2", corev1.ConditionFalse))
+	f.add(client.PodGVR, makePod("ns1", "p1", nil))
+	crash := makePod("ns2", "p2", nil)
+	crash.Status.ContainerStatuses = []corev1.ContainerStatus{
+		{Name: "app", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+	}
+	f.add(client.PodGVR, crash)
+	tf := newTestToolFactory(f, newTestConn())
+
+	m := callToolJSON(t, tf, "get_cluster_health", nil)
+	assert.Equal(t, map[string]any{
+		"total":    float64(2),
+		"ready":    float64(1),
+		"notReady": map[string]any{"n2": "since 2024-05-01T10:00:00Z (5h ago)"},
+	}, m["nodes"])
+	assert.Equal(t, map[string]any{
+		"total":         float64(2),
+		"statusSummary": map[string]any{"Running": float64(1), "CrashLoopBackOff": float64(1)},
+	}, m["pods"])
+	assert.Equal(t, "v1.35.0", m["serverVersion"].(map[string]any)["gitVersion"])
+
+	m = callToolJSON(t, tf, "get_cluster_health", map[string]any{"groupByNamespace": true})
+	assert.Equal(t, map[string]any{
+		"ns1": map[string]any{"Running": float64(1)},
+		"ns2": map[string]any{"CrashLoopBackOff": float64(1)},
+	}, m["podsByNamespace"])
+
+	m = callToolJSON(t, tf, "get_cluster_health", map[string]any{"namespace": "ns2"})
+	assert.Equal(t, map[string]any{
+		"total":         float64(1),
+		"statusSummary": map[string]any{"CrashLoopBackOff": float64(1)},
+	}, m["pods"])
+	assert.NotContains(t, m, "podsByNamespace")
+	assert.NotContains(t, m, "partial")
+}
+
+func TestGetClusterHealthToolCap(t *testing.T) {
+	pp := []runtime.Object{
+		makePod("ns1", "p1", nil),
+		makePod("ns1", "p2", nil),
+		makePod("ns2", "p3", nil),
+	}
+	f := newTestFactory()
+	for _, p := range pp {
+		f.add(client.PodGVR, p)
+	}
+
+	uu := map[string]struct {
+		max     int
+		live    bool
+		total   float64
+		partial bool
+	}{
+		"cache":         {max: 5, total: 3},
+		"cache-capped":  {max: 2, total: 2, partial: true},
+		"live":          {max: 5, live: true, total: 3},
+		"live-capped":   {max: 1, live: true, total: 1, partial: true},
+		"live-at-limit": {max: 3, live: true, total: 3},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			tf := newTestToolFactory(f, newTestConn(pp...))
+			tf.cfg.ClusterHealthMaxPods = u.max
+			m := callToolJSON(t, tf, "get_cluster_health", map[string]any{"live": u.live})
+			assert.Equal(t, u.total, m["pods"].(map[string]any)["total"])
+			if !u.partial {
+				assert.NotContains(t, m, "partial")
+				return
+			}
+			assert.Equal(t, true, m["partial"])
+			assert.Contains(t, m["note"], "partial scan")
+		})
+	}
+}
+
+func TestGetPodDiagnosticsTool(t *testing.T) {
+	f := newTestFactory()
+	pod := makePod("ns1", "p1", nil)
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+		{
+			Name:         "app",
+			RestartCount: 3,
+			State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+			LastTerminationState: corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137},
+			},
+		},
+	}
+	f.add(client.PodGVR, pod)
+	f.add(coreEvGVR, makeEvent("ns1", "e1", "p1", "Warning", "BackOff", toolNow))
+	f.add(coreEvGVR, makeEvent("ns1", "e2", "p2", "Warning", "Failed", toolNow))
+	tf := newTestToolFactory(f, newTestConn())
+
+	m := callToolJSON(t, tf, "get_pod_diagnostics", map[string]any{"namespace": "ns1", "podName": "p1"})
+	assert.Equal(t, "Running", m["phase"])
+	assert.Equal(t, "n1", m["node"])
+
+	cc := m["containers"].([]any)
+	require.Len(t, cc, 1)
+	c := cc[0].(map[string]any)
+	assert.Equal(t, "Waiting", c["state"])
+	assert.Equal(t, "CrashLoopBackOff", c["reason"])
+	assert.Equal(t, float64(3), c["restartCount"])
+	lt := c["lastTermination"].(map[string]any)
+	assert.Equal(t, "OOMKilled", lt["reason"])
+	assert.Equal(t, float64(137), lt["exitCode"])
+
+	ee := m["events"].([]any)
+	require.Len(t, ee, 1)
+	assert.Equal(t, "BackOff", ee[0].(map[string]any)["reason"])
+
+	_, err := callTool(t, tf, "get_pod_diagnostics", map[string]any{"namespace": "ns1", "podName": "p2"})
+	assert.True(t, kerrors.IsNotFound(err))
+}
+
+func TestGetNodeDiagnosticsTool(t *testing.T) {
+	f := newTestFactory()
+	node := makeNode("n1", corev1.ConditionTrue)
+	node.Spec.Taints = []corev1.Taint{{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}}
+	node.Status.Capacity = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4"), corev1.ResourcePods: resource.MustParse("110")}
+	node.Status.Allocatable = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("3800m"), corev1.ResourcePods: resource.MustParse("110")}
+	node.Status.Conditions = append(node.Status.Conditions, corev1.NodeCondition{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionTrue, Reason: "KubeletHasInsufficientMemory"})
+	node.Status.NodeInfo.KubeletVersion = "v1.30.1"
+	f.add(client.NodeGVR, node)
+	pending := makePod("ns1", "p2", nil)
+	pending.Status.Phase = corev1.PodPending
+	other := makePod("ns2", "p3", nil)
+	other.Spec.NodeName = "n2"
+	f.add(client.PodGVR, makePod("ns1", "p1", nil))
+	f.add(client.PodGVR, pending)
+	f.add(client.PodGVR, other)
+	tf := newTestToolFactory(f, newTestConn())
+
+	m := callToolJSON(t, tf, "get_node_diagnostics", map[string]any{"nodeName": "n1"})
+	assert.Equal(t, map[string]any{"cpu": "4", "pods": "110"}, m["capacity"])
+	assert.Equal(t, map[string]any{"cpu": "3800m", "pods": "110"}, m["allocatable"])
+	assert.Equal(t, "v1.30.1", m["kubeletVersion"])
+	assert.Equal(t, []any{map[string]any{"key": "dedicated", "value": "gpu", "effect": "NoSchedule"}}, m["taints"])
+	cc := m["conditions"].([]any)
+	require.Len(t, cc, 2)
+	assert.Equal(t, "KubeletHasInsufficientMemory", cc[1].(map[string]any)["reason"])
+	assert.Equal(t, map[string]any{
+		"total":   float64(2),
+		"byPhase": map[string]any{"Running": float64(1), "Pending": float64(1)},
+	}, m["pods"])
+	assert.NotContains(t, m, "partial")
+
+	_, err := callTool(t, tf, "get_node_diagnostics", map[string]any{"nodeName": "n3"})
+	assert.True(t, kerrors.IsNotFound(err))
+}
+
+func TestCheckRBACTool(t *testing.T) {
+	conn := newTestConn()
+	conn.denied = []string{"delete/pods"}
+	tf := newTestToolFactory(newTestFactory(), conn)
+
+	uu := map[string]struct {
+		verb string
+		ok   bool
+	}{
+		"allowed": {verb: "get", ok: true},
+		"denied":  {verb: "delete"},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			m := callToolJSON(t, tf, "check_rbac", map[string]any{"namespace": "ns1", "verb": u.verb, "resource": "pods"})
+			assert.Equal(t, u.ok, m["allowed"])
+		})
+	}
+}
+
+func TestGetLogsTool(t *testing.T) {
+	tf := newTestToolFactory(newTestFactory(), newTestConn(makePod("ns1", "p1", nil)))
+
+	raw, err := callTool(t, tf, "get_logs", map[string]any{"namespace": "ns1", "podName": "p1"})
+	require.NoError(t, err)
+	assert.Equal(t, "fake logs", raw)
+
+	raw, err = callTool(t, tf, "get_logs", map[string]any{"namespace": "ns1", "podName": "p1", "grep": "errors"})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(raw, `[filtered: none of 1 lines match "errors"`))
+
+	raw, err = callTool(t, tf, "get_logs", map[string]any{"namespace": "ns1", "podName": "p1", "grep": "fake"})
+	require.NoError(t, err)
+	assert.Contains(t, raw, "kept 1 of 1 lines (0 filtered out)")
+	assert.True(t, strings.HasSuffix(raw, "fake logs\n"))
+
+	_, err = callTool(t, tf, "get_logs", map[string]any{"namespace": "ns1", "podName": "p1", "grep": "("})
+	assert.ErrorContains(t, err, "invalid grep pattern")
+}
+
+func TestGetIncidentTimelineTool(t *testing.T) {
+	orig := timeNow
+	defer func() { timeNow = orig }()
+	timeNow = func() time.Time { return toolNow }
+
+	tf := newTestToolFactory(newTestFactory(), newTestConn(
+		makePod("ns1", "p1", nil),
+		makeEvent("ns1", "e1", "p1", "Warning", "BackOff", toolNow.Add(-5*time.Minute)),
+		makeEvent("ns1", "e2", "p1", "Normal", "Pulled", toolNow.Add(-2*time.Hour)),
+	))
+
+	m := callToolJSON(t, tf, "get_incident_timeline", map[string]any{"namespace": "ns1", "podName": "p1"})
+	assert.Equal(t, "ns1/p1", m["pod"])
+	assert.Equal(t, "last 30m (since 2024-05-01T09:30:00Z)", m["window"])
+	assert.Equal(t, []any{
+		map[string]any{
+			"time":    "2024-05-01T09:55:00Z (5m ago)",
+			"source":  "event",
+			"type":    "Warning",
+			"message": "BackOff: ",
+		},
+	}, m["timeline"])
+}
+
+func TestGetLogsToolSince(t *testing.T) {
+	uu := map[string]struct {
+		args     map[string]any
+		maxLines int
+		tail     *int64
+		secs     *int64
+		since    *metav1.Time
+		err      string
+	}{
+		"default": {
+			tail: ptrTo[int64](config.DefaultAIMaxContextLines),
+		},
+		"max-context-lines": {
+			maxLines: 50,
+			tail:     ptrTo[int64](50),
+		},
+		"seconds": {
+			args: map[string]any{"sinceSeconds": 300},
+			secs: ptrTo[int64](300),
+		},
+		"time": {
+			args:  map[string]any{"sinceTime": "2024-05-01T10:00:00Z"},
+			since: &metav1.Time{Time: toolNow},
+		},
+		"grep": {
+			args: map[string]any{"grep": "errors"},
+			tail: ptrTo[int64](grepTailLines),
+		},
+		"grep-in-window": {
+			args: map[string]any{"grep": "errors", "sinceSeconds": 60},
+			secs: ptrTo[int64](60),
+		},
+		"tail-in-window": {
+			args: map[string]any{"sinceSeconds": 60, "tailLines": 20},
+			tail: ptrTo[int64](20),
+			secs: ptrTo[int64](60),
+		},
+		"both": {
+			args: map[string]any{"sinceSeconds": 60, "sinceTime": "2024-05-01T10:00:00Z"},
+			err:  "exclusive",
+		},
+		"bad-time": {
+			args: map[string]any{"sinceTime": "5m"},
+			err:  "invalid sinceTime",
+		},
+		"negative": {
+			args: map[string]any{"sinceSeconds": -1},
+			err:  "must be positive",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			conn := newTestConn(makePod("ns1", "p1", nil))
+			tf := newTestToolFactory(newTestFactory(), conn)
+			tf.cfg.MaxContextLines = u.maxLines
+			args := map[string]any{"namespace": "ns1", "podName": "p1"}
+			maps.Copy(args, u.args)
+			_, err := callTool(t, tf, "get_logs", args)
+			if u.err != "" {
+				assert.ErrorContains(t, err, u.err)
+				return
+			}
+			require.NoError(t, err)
+
+			var opts *corev1.PodLogOptions
+			for _, a := range conn.dial.Actions() {
+				if a.GetSubresource() == "log" {
+					opts = a.(k8stesting.GenericAction).GetValue().(*corev1.PodLogOptions)
+				}
+			}
+			require.NotNil(t, opts)
+			assert.Equal(t, u.tail, opts.TailLines)
+			assert.Equal(t, u.secs, opts.SinceSeconds)
+			assert.Equal(t, u.since, opts.SinceTime)
+		})
+	}
+}
+
+func ptrTo[T any](v T) *T {
+	return &v
+}
+
+func TestApplyManifestTool(t *testing.T) {
+	registerConfigMapMeta()
+	cm := &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "cm1"},
+		Data:       map[string]string{"k": "v1"},
+	}
+	const manifest = "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: %s\ndata:\n  k: v2\n"
+
+	uu := map[string]struct {
+		args     map[string]any
+		readOnly bool
+		e        map[string]any
+		err      string
+	}{
+		"update": {
+			args: map[string]any{"manifest": fmt.Sprintf(manifest, "cm1"), "namespace": "ns1"},
+			e:    map[string]any{"status": "updated", "kind": "ConfigMap", "name": "cm1", "ns": "ns1"},
+		},
+		"create": {
+			args: map[string]any{"manifest": fmt.Sprintf(manifest, "cm2")},
+			e:    map[string]any{"status": "created", "kind": "ConfigMap", "name": "cm2", "ns": "default"},
+		},
+		"many": {
+			args: map[string]any{"manifest": fmt.Sprintf(manifest, "cm1") + "---\n" + fmt.Sprintf(manifest, "cm2")},
+			err:  "manifest must hold a single resource, got 2",
+		},
+		"unknown-kind": {
+			args: map[string]any{"manifest": "apiVersion: acme.io/v1\nkind: Widget\nmetadata:\n  name: w1\n"},
+			err:  "unknown resource kind Widget in acme.io/v1",
+		},
+		"read-only": {
+			args:     map[string]any{"manifest": fmt.Sprintf(manifest, "cm1")},
+			readOnly: true,
+			err:      "K9s is in read-only mode",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			conn := newTestConn(cm)
+			// The fake tracker can't apply to typed objects, echo the manifest back.
+			conn.dyn.PrependReactor("patch", "configmaps", func(a k8stesting.Action) (bool, runtime.Object, error) {
+				pa := a.(k8stesting.PatchAction)
+				var o unstructured.Unstructured
+				require.NoError(t, o.UnmarshalJSON(pa.GetPatch()))
+				return true, &o, nil
+			})
+			tf := newTestToolFactory(newTestFactory(), conn)
+			tf.SetReadOnlyFunc(func() bool { return u.readOnly })
+
+			raw, err := callTool(t, tf, "apply_manifest", u.args)
+			if u.err != "" {
+				assert.ErrorContains(t, err, u.err)
+				return
+			}
+			require.NoError(t, err)
+			var m map[string]any
+			require.NoError(t, json.Unmarshal([]byte(raw), &m))
+			delete(m, "version")
+			assert.Equal(t, u.e, m)
+		})
+	}
+}
+
+func registerConfigMapMeta() {
+	dao.MetaAccess.RegisterMeta("v1/configmaps", &metav1.APIResource{
+		Name:       "configmaps",
+		Version:    "v1",
+		Kind:       "ConfigMap",
+		Namespaced: true,
+	})
+}
+
+func TestToolsWithoutInformers(t *testing.T) {
+	f := newTestFactory()
+	f.cold = true
+	f.add(client.PodGVR, makePod("ns1", "stale", nil))
+	tf := newTestToolFactory(f, newTestConn(makePod("ns1", "p1", nil)))
+
+	raw, err := callTool(t, tf, "list_resources", map[string]any{"gvr": "v1/pods", "namespace": "ns1"})
+	require.NoError(t, err)
+	assert.Contains(t, raw, "p1")
+	assert.NotContains(t, raw, "stale")
+
+	pod, err := tf.getPod("ns1", "p1", false)
+	require.NoError(t, err)
+	assert.Equal(t, "p1", pod.Name)
+}
@@ -4,25 +4,35 @@
 package ai
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
+	"regexp"
+	"slices"
+	"sort"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/derailed/k9s/internal/client"
+	"github.com/derailed/k9s/internal/config"
 	"github.com/derailed/k9s/internal/dao"
 	"github.com/derailed/k9s/internal/render"
 	copilot "github.com/github/copilot-sdk/go"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/duration"
 	"gopkg.in/yaml.v3"
 )
 
@@ -30,38 +40,71 @@ import (
 type ToolFactory struct {
 	factory dao.Factory
 	conn    client.Connection
+	cfg     config.AI
 	log     *slog.Logger
+	// readOnly reports whether K9s currently runs read-only.
+	readOnly func() bool
 }
 
 // NewToolFactory creates a new tool factory.
-func NewToolFactory(factory dao.Factory, conn client.Connection, log *slog.Logger) *ToolFactory {
+func NewToolFactory(factory dao.Factory, conn client.Connection, cfg config.AI, log *slog.Logger) *ToolFactory {
 	if log == nil {
 		log = slog.Default()
 	}
 	return &ToolFactory{
 		factory: factory,
 		conn:    conn,
+		cfg:     cfg,
 		log:     log,
 	}
 }
 
 // BuildTools returns all Kubernetes-aware tools for the Copilot session.
+// Tool results are redacted per ai.redaction.
 func (tf *ToolFactory) BuildTools() []copilot.Tool {
-	return []copilot.Tool{
+	tools := []copilot.Tool{
 		tf.getResourceTool(),
+		tf.getSecretTool(),
+		tf.getConfigMapTool(),
+		tf.getHelmReleaseTool(),
 		tf.listResourcesTool(),
 		tf.describeResourceTool(),
 		tf.getLogsTool(),
 		tf.getEventsTool(),
+		tf.watchEventsTool(),
 		tf.getClusterHealthTool(),
+		tf.topPodsTool(),
+		tf.topNodesTool(),
 		tf.getPodDiagnosticsTool(),
+		tf.getNodeDiagnosticsTool(),
+		tf.diagnoseImagePullTool(),
+		tf.getWorkloadSummaryTool(),
+		tf.getPDBStatusTool(),
+		tf.findFailingJobsTool(),
+		tf.checkPodSecurityTool(),
+		tf.getIncidentTimelineTool(),
+		tf.recentChangesTool(),
+		tf.diagnoseSchedulingTool(),
+		tf.getAdmissionContextTool(),
+		tf.validateManifestTool(),
+		tf.detectDriftTool(),
+		tf.compareNamespacesTool(),
+		tf.compareResourcesTool(),
+		tf.findReferencesTool(),
 		tf.checkRBACTool(),
+		tf.runKubectlTool(),
 		tf.patchResourceTool(),
+		tf.applyManifestTool(),
 		tf.scaleResourceTool(),
 		tf.restartResourceTool(),
 		tf.deleteResourceTool(),
 		getSkillPlaybookTool(),
 	}
+	if tf.cfg.AllowExec {
+		tools = append(tools, tf.execCommandTool())
+	}
+
+	return redactTools(tools, NewRedactor(tf.cfg.Redaction))
 }
 
 // --- get_skill_playbook tool ---
@@ -90,22 +133,25 @@ type getResourceParams struct {
 	GVR       string `json:"gvr" jsonschema:"Group/Version/Resource identifier, e.g. v1/pods, apps/v1/deployments"`
 	Name      string `json:"name" jsonschema:"Resource name"`
 	Namespace string `json:"namespace" jsonschema:"Kubernetes namespace (empty for cluster-scoped)"`
+	Live      bool   `json:"live,omitempty" jsonschema:"If true, read from the API server instead of the K9s cache"`
 }
 
 func (tf *ToolFactory) getResourceTool() copilot.Tool {
 	return copilot.DefineTool(
 		"get_resource",
-		"Fetch a specific Kubernetes resource by GVR, name, and namespace. Returns the resource as YAML.",
+		"Fetch a specific Kubernetes resource by GVR, name, and namespace. Returns the resource as YAML, except secrets which are masked as by get_secret. "+
+			"Served from the K9s cache unless live=true.",
 		func(params getResourceParams, inv copilot.ToolInvocation) (any, error) {
-			gvr := client.NewGVR(params.GVR)
-			path := params.Name
-			if params.Namespace != "" {
-				path = params.Namespace + "/" + params.Name
+			if err := tf.checkNamespace(params.Namespace); err != nil {
+				return nil, err
 			}
-
-			obj, err := tf.factory.Get(gvr, path, true, labels.Everything())
+			gvr := client.NewGVR(params.GVR)
+			obj, err := tf.getObject(gvr, params.Namespace, params.Name, params.Live)
 			if err != nil {
-				return nil, fmt.Errorf("failed to get %s %s: %w", params.GVR, path, err)
+				return nil, fmt.Errorf("failed to get %s %s: %w", params.GVR, client.FQN(params.Namespace, params.Name), err)
+			}
+			if isSecretGVR(gvr) {
+				return maskSecret(obj)
 			}
 
 			return objectToYAML(obj)
@@ -120,29 +166,38 @@ type listResourcesParams struct {
 	Namespace     string `json:"namespace" jsonschema:"Kubernetes namespace (empty for all namespaces)"`
 	LabelSelector string `json:"labelSelector,omitempty" jsonschema:"Label selector to filter resources, e.g. app=web"`
 	Limit         int    `json:"limit,omitempty" jsonschema:"Maximum number of resources to return (default 50)"`
+	Live          bool   `json:"live,omitempty" jsonschema:"If true, read from the API server instead of the K9s cache"`
 }
 
 func (tf *ToolFactory) listResourcesTool() copilot.Tool {
 	return copilot.DefineTool(
 		"list_resources",
-		"List Kubernetes resources of a given type. Returns a summary table with key fields (name, namespace, status, age).",
+		"List Kubernetes resources of a given type. Returns a summary table with key fields (name, namespace, status, age). Served from the K9s cache unless live=true.",
 		func(params listResourcesParams, inv copilot.ToolInvocation) (any, error) {
 			gvr := client.NewGVR(params.GVR)
-			ns := params.Namespace
+			ns, err := tf.listNamespace(params.Namespace)
+			if err != nil {
+				return nil, err
+			}
 
 			sel := labels.Everything()
 			if params.LabelSelector != "" {
-				var err error
 				sel, err = labels.Parse(params.LabelSelector)
 				if err != nil {
 					return nil, fmt.Errorf("invalid label selector %q: %w", params.LabelSelector, err)
 				}
 			}
 
-			objs, err := tf.factory.List(gvr, ns, true, sel)
+			objs, err := tf.listObjects(gvr, ns, sel, params.Live)
 			if err != nil {
 				return nil, fmt.Errorf("failed to list %s in %s: %w", params.GVR, ns, err)
 			}
+			objs = keepAllowed(tf, objs, func(o runtime.Object) string {
+				if u, ok := o.(*unstructured.Unstructured); ok {
+					return u.GetNamespace()
+				}
+				return ""
+			})
 
 			limit := params.Limit
 			if limit <= 0 {
@@ -191,6 +246,7 @@ type describeResourceParams struct {
 	GVR       string `json:"gvr" jsonschema:"Group/Version/Resource identifier"`
 	Name      string `json:"name" jsonschema:"Resource name"`
 	Namespace string `json:"namespace" jsonschema:"Kubernetes namespace"`
+	Raw       bool   `json:"raw,omitempty" jsonschema:"If true, return the full description even when it is large"`
 }
 
 func (tf *ToolFactory) describeResourceTool() copilot.Tool {
@@ -198,6 +254,9 @@ func (tf *ToolFactory) describeResourceTool() copilot.Tool {
 		"describe_resource",
 		"Get the full kubectl-style description of a Kubernetes resource, including events and conditions.",
 		func(params describeResourceParams, inv copilot.ToolInvocation) (any, error) {
+			if err := tf.checkNamespace(params.Namespace); err != nil {
+				return nil, err
+			}
 			gvr := client.NewGVR(params.GVR)
 			path := params.Name
 			if params.Namespace != "" {
@@ -208,6 +267,9 @@ func (tf *ToolFactory) describeResourceTool() copilot.Tool {
 			if err != nil {
 				return nil, fmt.Errorf("failed to describe %s %s: %w", params.GVR, path, err)
 			}
+			if tf.cfg.SummarizeToolOutput && !params.Raw {
+				return condenseDescribe(desc), nil
+			}
 
 			return desc, nil
 		},
@@ -217,59 +279,223 @@ func (tf *ToolFactory) describeResourceTool() copilot.Tool {
 // --- get_logs tool ---
 
 type getLogsParams struct {
-	PodName   string `json:"podName" jsonschema:"Pod name"`
-	Namespace string `json:"namespace" jsonschema:"Pod namespace"`
-	Container string `json:"container,omitempty" jsonschema:"Container name (empty for all containers)"`
-	TailLines int64  `json:"tailLines,omitempty" jsonschema:"Number of lines from the end (default 100)"`
-	Previous  bool   `json:"previous,omitempty" jsonschema:"If true, return previous container logs (useful for crash analysis)"`
+	PodName      string `json:"podName" jsonschema:"Pod name"`
+	Namespace    string `json:"namespace" jsonschema:"Pod namespace"`
+	Container    string `json:"container,omitempty" jsonschema:"Container name (empty for the default container, or all containers with prefix)"`
+	TailLines    int64  `json:"tailLines,omitempty" jsonschema:"Number of lines from the end (defaults to ai.maxContextLines, or all lines in the since window)"`
+	SinceSeconds int64  `json:"sinceSeconds,omitempty" jsonschema:"Only return logs newer than this many seconds, e.g. 300 for the last 5 minutes"`
+	SinceTime    string `json:"sinceTime,omitempty" jsonschema:"Only return logs newer than this RFC3339 timestamp, e.g. an event time. Exclusive with sinceSeconds"`
+	Previous     bool   `json:"previous,omitempty" jsonschema:"If true, return previous container logs (useful for crash analysis)"`
+	Raw          bool   `json:"raw,omitempty" jsonschema:"If true, return logs verbatim even when they are large"`
+	Timestamps   bool   `json:"timestamps,omitempty" jsonschema:"If true, prefix each line with its RFC3339 timestamp"`
+	Prefix       bool   `json:"prefix,omitempty" jsonschema:"If true, prefix each line with its container name. With no container set, fetches all containers"`
+	Grep         string `json:"grep,omitempty" jsonschema:"Keep only the lines matching this regular expression, or 'errors' for error/warning lines and stack traces, with surrounding context lines. Scans the last 20000 lines unless tailLines or a since window is set"`
+	Context      int    `json:"context,omitempty" jsonschema:"Context lines kept around each grep match (default 2, max 10)"`
 }
 
+// maxLogBytes caps the log output of a get_logs call across all containers.
+const maxLogBytes = 256 * 1024
+
 func (tf *ToolFactory) getLogsTool() copilot.Tool {
 	return copilot.DefineTool(
 		"get_logs",
-		"Fetch container logs for a pod. Essential for diagnosing CrashLoopBackOff, application errors, and runtime issues.",
+		"Fetch container logs for a pod. Essential for diagnosing CrashLoopBackOff, application errors, and runtime issues. "+
+			"Use timestamps=true to correlate with events and prefix=true (without container) to get every container's logs labeled by container, merged by time when timestamps are on. "+
+			"Use grep='errors' to focus on errors, warnings and stack traces, and sinceSeconds or sinceTime to look at a time window, e.g. around an event. "+
+			fmt.Sprintf("Without tailLines or a since window, the last %d lines are returned. ", tf.cfg.ContextLines())+
+			"Output is capped at 256KB across containers, anything past it is cut off. "+
+			"grep filters the log stream as it is read, so only matching lines and their context count toward the cap: use it to find a crash line or stack trace in chatty logs.",
 		func(params getLogsParams, inv copilot.ToolInvocation) (any, error) {
+			if err := tf.checkNamespace(params.Namespace); err != nil {
+				return nil, err
+			}
 			dial, err := tf.conn.Dial()
 			if err != nil {
 				return nil, fmt.Errorf("failed to connect to cluster: %w", err)
 			}
 
-			tailLines := params.TailLines
-			if tailLines <= 0 {
-				tailLines = 100
+			opts, err := logOptions(params, int64(tf.cfg.ContextLines()))
+			if err != nil {
+				return nil, err
 			}
-
-			opts := &corev1.PodLogOptions{
-				Container: params.Container,
-				TailLines: &tailLines,
-				Previous:  params.Previous,
+			pattern := params.Grep
+			if pattern == "" && !params.Raw {
+				pattern = tf.cfg.LogFilter
 			}
-
-			if params.Container == "" {
-				// If no container specified, omit so we get the default
-				opts.Container = ""
+			var filter *logFilter
+			if pattern != "" {
+				if filter, err = newLogFilter(pattern, params.Context); err != nil {
+					return nil, err
+				}
 			}
 
-			req := dial.CoreV1().Pods(params.Namespace).GetLogs(params.PodName, opts)
+			// If no container is specified, the server picks the default one
+			// unless all containers are requested with prefix.
+			containers := []string{params.Container}
+			if params.Container == "" && params.Prefix {
+				pod, err := tf.getPod(params.Namespace, params.PodName, false)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get pod %s/%s: %w", params.Namespace, params.PodName, err)
+				}
+				containers = podContainerNames(pod)
+			}
 
-			stream, err := req.Stream(context.Background())
-			if err != nil {
-				return nil, fmt.Errorf("failed to stream logs for %s/%s: %w", params.Namespace, params.PodName, err)
+			// A filter runs on the stream so matches past maxLogBytes of raw
+			// logs are still found. Each container is filtered on its own so
+			// context lines don't cross containers.
+			budget := maxLogBytes / len(containers)
+			var sections []containerLogs
+			var total, kept int
+			var capped bool
+			for _, co := range containers {
+				opts := *opts
+				opts.Container = co
+				stream, err := dial.CoreV1().Pods(params.Namespace).GetLogs(params.PodName, &opts).Stream(context.Background())
+				if err != nil {
+					if len(containers) == 1 {
+						return nil, fmt.Errorf("failed to stream logs for %s/%s: %w", params.Namespace, params.PodName, err)
+					}
+					sections = append(sections, containerLogs{container: co, logs: fmt.Sprintf("[logs unavailable: %s]", err)})
+					continue
+				}
+				logs, err := readLogs(stream, filter, budget)
+				stream.Close()
+				if err != nil {
+					return nil, fmt.Errorf("failed to read logs: %w", err)
+				}
+				total, kept, capped = total+logs.total, kept+logs.kept, capped || logs.capped
+				sections = append(sections, containerLogs{container: co, logs: logs.logs})
 			}
-			defer stream.Close()
 
-			var buf bytes.Buffer
-			maxBytes := int64(256 * 1024) // 256KB limit
-			limited := &io.LimitedReader{R: stream, N: maxBytes}
-			if _, err := buf.ReadFrom(limited); err != nil {
-				return nil, fmt.Errorf("failed to read logs: %w", err)
+			var logs string
+			if params.Prefix {
+				logs = mergeContainerLogs(sections, params.Timestamps)
+			} else {
+				logs = sections[0].logs
+			}
+			if tf.cfg.SummarizeToolOutput && !params.Raw {
+				logs = condenseLogs(logs)
+			}
+			if filter != nil {
+				logs = filter.note(total, kept, capped) + logs
 			}
 
-			return buf.String(), nil
+			return logs, nil
 		},
 	)
 }
 
+// readLogs reads a container's log stream, up to budget bytes or, with a
+// filter, up to budget bytes of matches. Binary logs are replaced by a note.
+func readLogs(r io.Reader, filter *logFilter, budget int) (logScan, error) {
+	br := bufio.NewReader(r)
+	if head, _ := br.Peek(8 * 1024); isBinary(head) {
+		return logScan{logs: "[binary content: non-text log output omitted]"}, nil
+	}
+	if filter != nil {
+		return filter.scan(br, budget)
+	}
+
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(&io.LimitedReader{R: br, N: int64(budget)}); err != nil {
+		return logScan{}, err
+	}
+
+	return logScan{logs: sanitizeText(buf.Bytes())}, nil
+}
+
+// logOptions returns the log options of a get_logs call. Without tailLines,
+// a since window returns all its lines, up to maxLogBytes, a grep scans the
+// last grepTailLines and other calls the last tail.
+func logOptions(params getLogsParams, tail int64) (*corev1.PodLogOptions, error) {
+	opts := corev1.PodLogOptions{
+		Previous:   params.Previous,
+		Timestamps: params.Timestamps,
+	}
+	switch {
+	case params.SinceSeconds < 0:
+		return nil, fmt.Errorf("sinceSeconds must be positive, got %d", params.SinceSeconds)
+	case params.SinceSeconds > 0 && params.SinceTime != "":
+		return nil, fmt.Errorf("sinceSeconds and sinceTime are exclusive, pass only one")
+	case params.SinceSeconds > 0:
+		opts.SinceSeconds = &params.SinceSeconds
+	case params.SinceTime != "":
+		t, err := time.Parse(time.RFC3339, params.SinceTime)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sinceTime %q, expecting an RFC3339 timestamp: %w", params.SinceTime, err)
+		}
+		opts.SinceTime = &metav1.Time{Time: t}
+	}
+	switch {
+	case params.TailLines > 0:
+		opts.TailLines = &params.TailLines
+	case opts.SinceSeconds == nil && opts.SinceTime == nil && params.Grep != "":
+		tail := int64(grepTailLines)
+		opts.TailLines = &tail
+	case opts.SinceSeconds == nil && opts.SinceTime == nil:
+		opts.TailLines = &tail
+	}
+
+	return &opts, nil
+}
+
+// containerLogs holds the logs fetched for a single container.
+type containerLogs struct {
+	container, logs string
+}
+
+// podContainerNames returns the init and app container names of a pod.
+func podContainerNames(pod *corev1.Pod) []string {
+	nn := make([]string, 0, len(pod.Spec.InitContainers)+len(pod.Spec.Containers))
+	for _, c := range pod.Spec.InitContainers {
+		nn = append(nn, c.Name)
+	}
+	for _, c := range pod.Spec.Containers {
+		nn = append(nn, c.Name)
+	}
+
+	return nn
+}
+
+// mergeContainerLogs labels each line with its container. With timestamps,
+// lines from all containers are interleaved in time order; lines without a
+// timestamp stay with the line they continue.
+func mergeContainerLogs(cc []containerLogs, timestamps bool) string {
+	type logLine struct {
+		at   time.Time
+		text string
+	}
+
+	var ll []logLine
+	for _, c := range cc {
+		var last time.Time
+		for _, l := range strings.Split(strings.TrimRight(c.logs, "\n"), "\n") {
+			if l == "" {
+				continue
+			}
+			if timestamps {
+				if at, _, ok := parseTimestampedLine(l); ok {
+					last = at
+				}
+			}
+			ll = append(ll, logLine{at: last, text: "[" + c.container + "] " + l})
+		}
+	}
+	if timestamps {
+		sort.SliceStable(ll, func(i, j int) bool {
+			return ll[i].at.Before(ll[j].at)
+		})
+	}
+
+	var b strings.Builder
+	for _, l := range ll {
+		b.WriteString(l.text)
+		b.WriteByte('\n')
+	}
+
+	return b.String()
+}
+
 // --- get_events tool ---
 
 type getEventsParams struct {
@@ -277,122 +503,155 @@ type getEventsParams struct {
 	ResourceName string `json:"resourceName,omitempty" jsonschema:"Filter events by involved object name"`
 	EventType    string `json:"eventType,omitempty" jsonschema:"Filter by event type: Normal or Warning"`
 	Limit        int    `json:"limit,omitempty" jsonschema:"Maximum number of events to return (default 30)"`
+	Live         bool   `json:"live,omitempty" jsonschema:"If true, read from the API server instead of the K9s cache"`
 }
 
 func (tf *ToolFactory) getEventsTool() copilot.Tool {
 	return copilot.DefineTool(
 		"get_events",
-		"Fetch Kubernetes events, optionally filtered by namespace, resource, or type. Events reveal scheduling failures, image pulls, OOM kills, and more.",
+		"Fetch Kubernetes events, optionally filtered by namespace, resource, or type. Events reveal scheduling failures, image pulls, OOM kills, and more. Served from the K9s cache unless live=true.",
 		func(params getEventsParams, inv copilot.ToolInvocation) (any, error) {
-			dial, err := tf.conn.Dial()
+			ns, err := tf.listNamespace(params.Namespace)
 			if err != nil {
-				return nil, fmt.Errorf("failed to connect to cluster: %w", err)
-			}
-
-			ns := params.Namespace
-			opts := metav1.ListOptions{}
-			if params.ResourceName != "" {
-				opts.FieldSelector = "involvedObject.name=" + params.ResourceName
+				return nil, err
 			}
-
-			events, err := dial.CoreV1().Events(ns).List(context.Background(), opts)
+			events, err := tf.listEvents(ns, params.ResourceName, params.Live)
 			if err != nil {
 				return nil, fmt.Errorf("failed to list events: %w", err)
 			}
-
+			events = keepAllowed(tf, events, func(ev corev1.Event) string { return ev.Namespace })
 			limit := params.Limit
 			if limit <= 0 {
 				limit = 30
 			}
 
-			var results []map[string]string
-			for i := len(events.Items) - 1; i >= 0 && len(results) < limit; i-- {
-				ev := events.Items[i]
-				if params.EventType != "" && ev.Type != params.EventType {
-					continue
-				}
-				results = append(results, map[string]string{
-					"type":      ev.Type,
-					"reason":    ev.Reason,
-					"message":   ev.Message,
-					"object":    ev.InvolvedObject.Kind + "/" + ev.InvolvedObject.Name,
-					"count":     fmt.Sprintf("%d", ev.Count),
-					"firstSeen": ev.FirstTimestamp.String(),
-					"lastSeen":  ev.LastTimestamp.String(),
-				})
-			}
-
 			return map[string]any{
-				"total":  len(events.Items),
-				"events": results,
+				"total":  len(events),
+				"events": recentEvents(events, params.EventType, limit),
 			}, nil
 		},
 	)
 }
 
+// recentEvents returns up to limit events of the given type, or all types
+// when blank, most recent first.
+func recentEvents(events []corev1.Event, kind string, limit int) []map[string]string {
+	// Cached events come back unordered.
+	ee := slices.Clone(events)
+	sort.SliceStable(ee, func(i, j int) bool {
+		return eventTime(&ee[i]).After(eventTime(&ee[j]))
+	})
+
+	var results []map[string]string
+	for _, ev := range ee {
+		if len(results) >= limit {
+			break
+		}
+		if kind != "" && ev.Type != kind {
+			continue
+		}
+		results = append(results, eventEntry(&ev))
+	}
+
+	return results
+}
+
+// eventEntry renders an event for the model.
+func eventEntry(ev *corev1.Event) map[string]string {
+	return map[string]string{
+		"type":      ev.Type,
+		"reason":    ev.Reason,
+		"message":   ev.Message,
+		"object":    ev.InvolvedObject.Kind + "/" + ev.InvolvedObject.Name,
+		"count":     fmt.Sprintf("%d", ev.Count),
+		"firstSeen": toolTime(ev.FirstTimestamp.Time),
+		"lastSeen":  toolTime(eventTime(ev)),
+	}
+}
+
 // --- get_cluster_health tool ---
 
 type getClusterHealthParams struct {
-	IncludeNodeDetails bool `json:"includeNodeDetails,omitempty" jsonschema:"If true, include per-node condition details (default false)"`
+	IncludeNodeDetails bool   `json:"includeNodeDetails,omitempty" jsonschema:"If true, include per-node condition details (default false)"`
+	Namespace          string `json:"namespace,omitempty" jsonschema:"Only summarize pods in this namespace (empty for all)"`
+	GroupByNamespace   bool   `json:"groupByNamespace,omitempty" jsonschema:"If true, also break pod statuses down per namespace"`
+	Live               bool   `json:"live,omitempty" jsonschema:"If true, read from the API server instead of the K9s cache"`
 }
 
 func (tf *ToolFactory) getClusterHealthTool() copilot.Tool {
 	return copilot.DefineTool(
 		"get_cluster_health",
-		"Get a high-level cluster health overview: node count, pod counts by status, resource utilization summary.",
+		"Get a high-level cluster health overview: node count, pod counts by status, resource utilization summary. Served from the K9s cache unless live=true. On very large clusters pod counts come from a partial scan, flagged by partial and a note.",
 		func(params getClusterHealthParams, inv copilot.ToolInvocation) (any, error) {
-			dial, err := tf.conn.Dial()
-			if err != nil {
-				return nil, fmt.Errorf("failed to connect to cluster: %w", err)
-			}
-
-			// Get nodes
-			nodes, err := dial.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
+			nodes, err := tf.listNodes(params.Live)
 			if err != nil {
 				return nil, fmt.Errorf("failed to list nodes: %w", err)
 			}
 			readyNodes := 0
-			for _, n := range nodes.Items {
+			notReady := make(map[string]string)
+			for _, n := range nodes {
 				for _, cond := range n.Status.Conditions {
-					if cond.Type == "Ready" && cond.Status == "True" {
+					if cond.Type != "Ready" {
+						continue
+					}
+					if cond.Status == "True" {
 						readyNodes++
+					} else {
+						notReady[n.Name] = "since " + toolTime(cond.LastTransitionTime.Time)
 					}
 				}
 			}
 
-			// Get pods across all namespaces for status summary
-			pods, err := dial.CoreV1().Pods("").List(context.Background(), metav1.ListOptions{})
+			// Pods are counted as they are scanned so huge clusters are never
+			// held in memory at once.
+			ns, err := tf.listNamespace(params.Namespace)
 			if err != nil {
-				return nil, fmt.Errorf("failed to list pods: %w", err)
+				return nil, err
 			}
-
+			var total int
 			statusCounts := make(map[string]int)
-			for _, p := range pods.Items {
-				phase := string(p.Status.Phase)
-				// Override with more specific status
-				for _, cs := range p.Status.ContainerStatuses {
-					if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" {
-						phase = cs.State.Waiting.Reason
-						break
-					}
-					if cs.State.Terminated != nil && cs.State.Terminated.Reason != "" {
-						phase = cs.State.Terminated.Reason
-						break
-					}
+			byNamespace := make(map[string]map[string]int)
+			limit := tf.cfg.ClusterHealthPodCap()
+			_, partial, err := tf.scanPods(ns, params.Live, limit, func(p *corev1.Pod) {
+				if !tf.nsAllowed(p.Namespace) {
+					return
 				}
+				total++
+				phase := podStatus(p)
 				statusCounts[phase]++
+				if !params.GroupByNamespace {
+					return
+				}
+				if byNamespace[p.Namespace] == nil {
+					byNamespace[p.Namespace] = make(map[string]int)
+				}
+				byNamespace[p.Namespace][phase]++
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list pods: %w", err)
 			}
 
+			nodeSummary := map[string]any{
+				"total": len(nodes),
+				"ready": readyNodes,
+			}
+			if len(notReady) > 0 {
+				nodeSummary["notReady"] = notReady
+			}
 			result := map[string]any{
-				"nodes": map[string]any{
-					"total": len(nodes.Items),
-					"ready": readyNodes,
-				},
+				"nodes": nodeSummary,
 				"pods": map[string]any{
-					"total":         len(pods.Items),
+					"total":         total,
 					"statusSummary": statusCounts,
 				},
 			}
+			if partial {
+				result["partial"] = true
+				result["note"] = fmt.Sprintf("The cluster has more than %d pods: pod counts come from a partial scan of the first %d. Pass a namespace for exact counts.", limit, limit)
+			}
+			if params.GroupByNamespace {
+				result["podsByNamespace"] = byNamespace
+			}
 
 			// Try to get server version
 			if v, err := tf.conn.ServerVersion(); err == nil {
@@ -404,26 +663,41 @@ func (tf *ToolFactory) getClusterHealthTool() copilot.Tool {
 	)
 }
 
+// podStatus returns a pod's phase, or the reason its first waiting or
+// terminated container gives, which is more specific.
+func podStatus(p *corev1.Pod) string {
+	for _, cs := range p.Status.ContainerStatuses {
+		if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" {
+			return cs.State.Waiting.Reason
+		}
+		if cs.State.Terminated != nil && cs.State.Terminated.Reason != "" {
+			return cs.State.Terminated.Reason
+		}
+	}
+
+	return string(p.Status.Phase)
+}
+
 // --- get_pod_diagnostics tool ---
 
+// maxDiagnosticEvents caps the events reported by get_pod_diagnostics.
+const maxDiagnosticEvents = 10
+
 type getPodDiagnosticsParams struct {
 	PodName   string `json:"podName" jsonschema:"Pod name"`
 	Namespace string `json:"namespace" jsonschema:"Pod namespace"`
+	Live      bool   `json:"live,omitempty" jsonschema:"If true, read from the API server instead of the K9s cache"`
 }
 
 func (tf *ToolFactory) getPodDiagnosticsTool() copilot.Tool {
 	return copilot.DefineTool(
 		"get_pod_diagnostics",
-		"Get comprehensive diagnostics for a specific pod: phase, container states, restart counts, exit codes, resource usage, probe status, and recent events.",
+		"Get comprehensive diagnostics for a specific pod: phase, container states, restart counts, exit codes, resource usage, probe status, and recent events. Served from the K9s cache unless live=true.",
 		func(params getPodDiagnosticsParams, inv copilot.ToolInvocation) (any, error) {
-			dial, err := tf.conn.Dial()
-			if err != nil {
-				return nil, fmt.Errorf("failed to connect: %w", err)
+			if err := tf.checkNamespace(params.Namespace); err != nil {
+				return nil, err
 			}
-
-			pod, err := dial.CoreV1().Pods(params.Namespace).Get(
-				context.Background(), params.PodName, metav1.GetOptions{},
-			)
+			pod, err := tf.getPod(params.Namespace, params.PodName, params.Live)
 			if err != nil {
 				return nil, fmt.Errorf("failed to get pod %s/%s: %w", params.Namespace, params.PodName, err)
 			}
@@ -453,17 +727,21 @@ func (tf *ToolFactory) getPodDiagnosticsTool() copilot.Tool {
 
 				if cs.State.Running != nil {
 					c["state"] = "Running"
-					c["startedAt"] = cs.State.Running.StartedAt.String()
+					c["startedAt"] = toolTime(cs.State.Running.StartedAt.Time)
 				} else if cs.State.Waiting != nil {
 					c["state"] = "Waiting"
 					c["reason"] = cs.State.Waiting.Reason
 					c["message"] = cs.State.Waiting.Message
+					if slices.Contains(pullReasons, cs.State.Waiting.Reason) {
+						c["imagePullCause"] = classifyPullError(cs.State.Waiting.Reason, cs.State.Waiting.Message)
+					}
 				} else if cs.State.Terminated != nil {
 					c["state"] = "Terminated"
 					c["reason"] = cs.State.Terminated.Reason
 					c["exitCode"] = cs.State.Terminated.ExitCode
 					c["signal"] = cs.State.Terminated.Signal
 					c["message"] = cs.State.Terminated.Message
+					c["finishedAt"] = toolTime(cs.State.Terminated.FinishedAt.Time)
 				}
 
 				// Last termination state (useful for CrashLoopBackOff)
@@ -474,7 +752,8 @@ func (tf *ToolFactory) getPodDiagnosticsTool() copilot.Tool {
 						"exitCode":   lt.ExitCode,
 						"signal":     lt.Signal,
 						"message":    lt.Message,
-						"finishedAt": lt.FinishedAt.String(),
+						"startedAt":  toolTime(lt.StartedAt.Time),
+						"finishedAt": toolTime(lt.FinishedAt.Time),
 					}
 				}
 
@@ -494,6 +773,13 @@ func (tf *ToolFactory) getPodDiagnosticsTool() copilot.Tool {
 			}
 			diag["conditions"] = conditions
 
+			// Events are best effort, the pod status alone is still useful.
+			if events, err := tf.listEvents(pod.Namespace, pod.Name, params.Live); err == nil {
+				diag["events"] = recentEvents(events, "", maxDiagnosticEvents)
+			} else {
+				diag["eventsError"] = err.Error()
+			}
+
 			// Resource requests/limits
 			for _, c := range pod.Spec.Containers {
 				for _, cd := range containers {
@@ -529,6 +815,207 @@ func (tf *ToolFactory) getPodDiagnosticsTool() copilot.Tool {
 	)
 }
 
+// --- get_incident_timeline tool ---
+
+type getIncidentTimelineParams struct {
+	PodName       string `json:"podName" jsonschema:"Pod name"`
+	Namespace     string `json:"namespace" jsonschema:"Pod namespace"`
+	Container     string `json:"container,omitempty" jsonschema:"Container name (empty for the default container)"`
+	WindowMinutes int64  `json:"windowMinutes,omitempty" jsonschema:"How far back to look, in minutes (default 30)"`
+	Limit         int    `json:"limit,omitempty" jsonschema:"Maximum number of timeline entries to return (default 200)"`
+}
+
+type timelineEntry struct {
+	at      time.Time
+	source  string
+	kind    string
+	message string
+}
+
+func (tf *ToolFactory) getIncidentTimelineTool() copilot.Tool {
+	return copilot.DefineTool(
+		"get_incident_timeline",
+		"Build a single chronological timeline for a pod by merging its events and log lines over a recent window. All timestamps are normalized to UTC. Use this to correlate an event (e.g. BackOff, OOMKilled) with what the application logged at the same moment.",
+		func(params getIncidentTimelineParams, inv copilot.ToolInvocation) (any, error) {
+			if err := tf.checkNamespace(params.Namespace); err != nil {
+				return nil, err
+			}
+			dial, err := tf.conn.Dial()
+			if err != nil {
+				return nil, fmt.Errorf("failed to connect to cluster: %w", err)
+			}
+
+			window := params.WindowMinutes
+			if window <= 0 {
+				window = 30
+			}
+			limit := params.Limit
+			if limit <= 0 {
+				limit = 200
+			}
+			since := timeNow().Add(-time.Duration(window) * time.Minute)
+
+			// A hung API server or kubelet must not hold the tool call.
+			ctx, cancel := context.WithTimeout(context.Background(), timelineTimeout)
+			defer cancel()
+
+			var entries []timelineEntry
+
+			events, err := dial.CoreV1().Events(params.Namespace).List(ctx, metav1.ListOptions{
+				FieldSelector: "involvedObject.name=" + params.PodName,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list events: %w", err)
+			}
+			for _, ev := range events.Items {
+				at := eventTime(&ev)
+				if at.Before(since) {
+					continue
+				}
+				msg := ev.Reason + ": " + ev.Message
+				if ev.Count > 1 {
+					msg += fmt.Sprintf(" (x%d)", ev.Count)
+				}
+				entries = append(entries, timelineEntry{at: at, source: "event", kind: ev.Type, message: msg})
+			}
+
+			sinceSeconds := window * 60
+			opts := &corev1.PodLogOptions{
+				Container:    params.Container,
+				Timestamps:   true,
+				SinceSeconds: &sinceSeconds,
+			}
+			var logErr string
+			stream, err := dial.CoreV1().Pods(params.Namespace).GetLogs(params.PodName, opts).Stream(ctx)
+			if err != nil {
+				// Pending pods have no logs yet; the events alone are still useful.
+				logErr = err.Error()
+			} else {
+				defer stream.Close()
+				lines, err := tailLogLines(stream, timelineLogBytes)
+				if err != nil {
+					logErr = err.Error()
+				}
+				for _, l := range lines {
+					at, line, ok := parseTimestampedLine(l)
+					if !ok {
+						continue
+					}
+					line = sanitizeText([]byte(line))
+					entries = append(entries, timelineEntry{at: at, source: "log", message: line})
+				}
+			}
+
+			sort.SliceStable(entries, func(i, j int) bool {
+				return entries[i].at.Before(entries[j].at)
+			})
+			total := len(entries)
+			if total > limit {
+				entries = entries[total-limit:]
+			}
+
+			results := make([]map[string]string, 0, len(entries))
+			for _, e := range entries {
+				item := map[string]string{
+					"time":    toolTime(e.at),
+					"source":  e.source,
+					"message": e.message,
+				}
+				if e.kind != "" {
+					item["type"] = e.kind
+				}
+				results = append(results, item)
+			}
+
+			result := map[string]any{
+				"pod":      params.Namespace + "/" + params.PodName,
+				"window":   fmt.Sprintf("last %dm (since %s)", window, since.UTC().Format(time.RFC3339)),
+				"total":    total,
+				"timeline": results,
+			}
+			if total > limit {
+				result["truncated"] = fmt.Sprintf("showing the most recent %d of %d entries", limit, total)
+			}
+			if logErr != "" {
+				result["logsError"] = logErr
+			}
+
+			return result, nil
+		},
+	)
+}
+
+const (
+	// timelineTimeout bounds the events and logs reads of an incident timeline.
+	timelineTimeout = 30 * time.Second
+
+	// timelineLogBytes caps the log lines an incident timeline keeps.
+	timelineLogBytes = 256 * 1024
+)
+
+// tailLogLines reads r to the end and returns its most recent lines, up to
+// budget bytes. Lines read before a scan error are still returned.
+func tailLogLines(r io.Reader, budget int) ([]string, error) {
+	var (
+		ll   []string
+		size int
+	)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), budget)
+	for scanner.Scan() {
+		ll = append(ll, scanner.Text())
+		size += len(scanner.Bytes())
+		for size > budget {
+			size -= len(ll[0])
+			ll = ll[1:]
+		}
+	}
+
+	return ll, scanner.Err()
+}
+
+// eventTime returns the most precise time an event last occurred.
+func eventTime(ev *corev1.Event) time.Time {
+	switch {
+	case !ev.LastTimestamp.IsZero():
+		return ev.LastTimestamp.Time
+	case !ev.EventTime.IsZero():
+		return ev.EventTime.Time
+	case !ev.FirstTimestamp.IsZero():
+		return ev.FirstTimestamp.Time
+	default:
+		return ev.CreationTimestamp.Time
+	}
+}
+
+// timeNow returns the current time. Tests pin it to get stable ages.
+var timeNow = time.Now
+
+// toolTime formats t for tool results as an absolute RFC3339 time followed by
+// its relative age, so the model doesn't have to work out recency itself.
+func toolTime(t time.Time) string {
+	if t.IsZero() {
+		return render.UnknownValue
+	}
+
+	return t.UTC().Format(time.RFC3339) + " (" + duration.HumanDuration(timeNow().Sub(t)) + " ago)"
+}
+
+// parseTimestampedLine splits a log line emitted with Timestamps=true into
+// its RFC3339 timestamp and message.
+func parseTimestampedLine(line string) (time.Time, string, bool) {
+	ts, msg, ok := strings.Cut(line, " ")
+	if !ok {
+		ts, msg = line, ""
+	}
+	at, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return time.Time{}, "", false
+	}
+
+	return at, msg, true
+}
+
 // --- check_rbac tool ---
 
 type checkRBACParams struct {
@@ -575,12 +1062,18 @@ func (tf *ToolFactory) patchResourceTool() copilot.Tool {
 		"patch_resource",
 		"Apply a strategic merge patch to a Kubernetes resource. Use this to update deployments, services, configmaps, etc. For example: fix a bad container image, change environment variables, update labels, or modify resource limits. Always include the container 'name' field when patching containers so the correct container is targeted.",
 		func(params patchResourceParams, inv copilot.ToolInvocation) (any, error) {
+			if err := tf.checkNamespace(params.Namespace); err != nil {
+				return nil, err
+			}
 			tf.log.Info("Patching resource", "gvr", params.GVR, "name", params.Name, "ns", params.Namespace)
 
 			gvr, err := parseGVR(params.GVR)
 			if err != nil {
 				return nil, err
 			}
+			if err := tf.canMutate("patch", params.GVR, params.Namespace, params.Name); err != nil {
+				return nil, err
+			}
 
 			dynClient, err := tf.conn.DynDial()
 			if err != nil {
@@ -631,6 +1124,66 @@ func (tf *ToolFactory) patchResourceTool() copilot.Tool {
 	)
 }
 
+// --- apply_manifest tool ---
+
+type applyManifestParams struct {
+	Manifest  string `json:"manifest" jsonschema:"YAML or JSON manifest of the single resource to create or update"`
+	Namespace string `json:"namespace,omitempty" jsonschema:"Namespace for a namespaced resource that doesn't set one (default: default)"`
+}
+
+func (tf *ToolFactory) applyManifestTool() copilot.Tool {
+	return copilot.DefineTool(
+		"apply_manifest",
+		"Create or update a single resource from a YAML or JSON manifest with a server-side apply, e.g. to add a PodDisruptionBudget or a NetworkPolicy. "+
+			"Check the manifest with validate_manifest first. Fields owned by another manager are not taken over: the apply fails listing the conflicts, use patch_resource for those.",
+		func(params applyManifestParams, inv copilot.ToolInvocation) (any, error) {
+			o, err := decodeSingleManifest(params.Manifest)
+			if err != nil {
+				return nil, err
+			}
+			if tf.isReadOnly() {
+				return nil, fmt.Errorf("apply_manifest is not allowed: K9s is in read-only mode")
+			}
+			gvr, err := applyTarget(o, params.Namespace)
+			if err != nil {
+				return nil, err
+			}
+			ns, name := o.GetNamespace(), o.GetName()
+			if err := tf.checkNamespace(ns); err != nil {
+				return nil, err
+			}
+			tf.log.Info("Applying manifest", "gvr", gvr, "name", name, "ns", ns)
+			if err := tf.canMutate("patch", gvr.String(), ns, name); err != nil {
+				return nil, err
+			}
+
+			dial, err := tf.conn.DynDial()
+			if err != nil {
+				return nil, fmt.Errorf("failed to connect to cluster: %w", err)
+			}
+			res := resourceFor(dial, gvr, o)
+			_, err = res.Get(context.Background(), name, metav1.GetOptions{})
+			created := kerrors.IsNotFound(err)
+			result, err := res.Apply(context.Background(), name, o, metav1.ApplyOptions{FieldManager: aiFieldManager})
+			if err != nil {
+				return nil, fmt.Errorf("failed to apply %s %s: %w", o.GetKind(), client.FQN(ns, name), err)
+			}
+
+			status := "updated"
+			if created {
+				status = "created"
+			}
+			return map[string]any{
+				"status":  status,
+				"kind":    result.GetKind(),
+				"name":    result.GetName(),
+				"ns":      result.GetNamespace(),
+				"version": result.GetResourceVersion(),
+			}, nil
+		},
+	)
+}
+
 // --- scale_resource tool ---
 
 type scaleResourceParams struct {
@@ -645,6 +1198,9 @@ func (tf *ToolFactory) scaleResourceTool() copilot.Tool {
 		"scale_resource",
 		"Scale a Kubernetes workload (Deployment, StatefulSet, ReplicaSet) to the desired number of replicas.",
 		func(params scaleResourceParams, inv copilot.ToolInvocation) (any, error) {
+			if err := tf.checkNamespace(params.Namespace); err != nil {
+				return nil, err
+			}
 			tf.log.Info("Scaling resource", "gvr", params.GVR, "name", params.Name, "replicas", params.Replicas)
 
 			patch := fmt.Sprintf(`{"spec":{"replicas":%d}}`, params.Replicas)
@@ -653,6 +1209,9 @@ func (tf *ToolFactory) scaleResourceTool() copilot.Tool {
 			if err != nil {
 				return nil, err
 			}
+			if err := tf.canMutate("patch", params.GVR, params.Namespace, params.Name); err != nil {
+				return nil, err
+			}
 
 			dynClient, err := tf.conn.DynDial()
 			if err != nil {
@@ -689,6 +1248,9 @@ func (tf *ToolFactory) restartResourceTool() copilot.Tool {
 		"restart_resource",
 		"Perform a rolling restart of a Kubernetes workload (Deployment, StatefulSet, DaemonSet) by updating the restartedAt annotation, equivalent to kubectl rollout restart.",
 		func(params restartResourceParams, inv copilot.ToolInvocation) (any, error) {
+			if err := tf.checkNamespace(params.Namespace); err != nil {
+				return nil, err
+			}
 			tf.log.Info("Restarting resource", "gvr", params.GVR, "name", params.Name)
 
 			now := time.Now().UTC().Format(time.RFC3339)
@@ -701,6 +1263,9 @@ func (tf *ToolFactory) restartResourceTool() copilot.Tool {
 			if err != nil {
 				return nil, err
 			}
+			if err := tf.canMutate("patch", params.GVR, params.Namespace, params.Name); err != nil {
+				return nil, err
+			}
 
 			dynClient, err := tf.conn.DynDial()
 			if err != nil {
@@ -736,12 +1301,18 @@ func (tf *ToolFactory) deleteResourceTool() copilot.Tool {
 		"delete_resource",
 		"Delete a Kubernetes resource. Use cautiously. Useful for removing stuck pods, cleaning up failed jobs, or deleting resources that need recreation.",
 		func(params deleteResourceParams, inv copilot.ToolInvocation) (any, error) {
+			if err := tf.checkNamespace(params.Namespace); err != nil {
+				return nil, err
+			}
 			tf.log.Info("Deleting resource", "gvr", params.GVR, "name", params.Name, "ns", params.Namespace)
 
 			gvr, err := parseGVR(params.GVR)
 			if err != nil {
 				return nil, err
 			}
+			if err := tf.canMutate("delete", params.GVR, params.Namespace, params.Name); err != nil {
+				return nil, err
+			}
 
 			dynClient, err := tf.conn.DynDial()
 			if err != nil {
@@ -769,6 +1340,54 @@ func (tf *ToolFactory) deleteResourceTool() copilot.Tool {
 
 // --- Helpers ---
 
+// ansiRx matches ANSI CSI escape sequences such as color codes.
+var ansiRx = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]`)
+
+// isBinary reports whether content looks like binary data rather than text.
+// Only a leading sample is inspected.
+func isBinary(bb []byte) bool {
+	if len(bb) > 8*1024 {
+		bb = bb[:8*1024]
+	}
+	if len(bb) == 0 {
+		return false
+	}
+	if bytes.IndexByte(bb, 0) >= 0 {
+		return true
+	}
+
+	// Treat the sample as binary when over 30% of it is undecodable or
+	// unexpected control characters.
+	total, bad := len(bb), 0
+	for len(bb) > 0 {
+		r, size := utf8.DecodeRune(bb)
+		if r == utf8.RuneError && size <= 1 || r < 0x20 && r != '\n' && r != '\t' && r != '\r' && r != 0x1b {
+			bad += size
+		}
+		bb = bb[size:]
+	}
+
+	return bad*10 > total*3
+}
+
+// sanitizeText makes raw output safe to hand to the model and to render:
+// invalid UTF-8 is replaced, ANSI escapes are removed and control
+// characters other than newline and tab are dropped.
+func sanitizeText(bb []byte) string {
+	s := strings.ToValidUTF8(string(bb), "\uFFFD")
+	s = ansiRx.ReplaceAllString(s, "")
+
+	return strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' {
+			return r
+		}
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
 // parseGVR converts a string like "apps/v1/deployments" or "v1/pods" into a schema.GroupVersionResource.
 func parseGVR(gvrStr string) (schema.GroupVersionResource, error) {
 	parts := strings.Split(gvrStr, "/")
@@ -798,12 +1417,7 @@ func objectToYAML(obj runtime.Object) (string, error) {
 		return "", fmt.Errorf("failed to convert to unstructured: %w", err)
 	}
 
-	// Remove managed fields to reduce noise
-	if md, ok := data["metadata"].(map[string]any); ok {
-		delete(md, "managedFields")
-	}
-
-	b, err := yaml.Marshal(data)
+	b, err := yaml.Marshal(sanitizeObject(data))
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal YAML: %w", err)
 	}
@@ -811,3 +1425,30 @@ func objectToYAML(obj runtime.Object) (string, error) {
 	return string(b), nil
 }
 
+// lastAppliedAnnotation holds the manifest last applied with kubectl apply.
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// sanitizeObject returns a copy of an unstructured object without the noise
+// tools never hand the model: managed fields and the last applied manifest,
+// which repeats the object.
+func sanitizeObject(o map[string]any) map[string]any {
+	o = maps.Clone(o)
+	md, ok := o["metadata"].(map[string]any)
+	if !ok {
+		return o
+	}
+	md = maps.Clone(md)
+	delete(md, "managedFields")
+	if aa, ok := md["annotations"].(map[string]any); ok {
+		aa = maps.Clone(aa)
+		delete(aa, lastAppliedAnnotation)
+		md["annotations"] = aa
+		if len(aa) == 0 {
+			delete(md, "annotations")
+		}
+	}
+	o["metadata"] = md
+
+	return o
+}
+
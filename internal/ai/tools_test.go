@@ -0,0 +1,366 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseTimestampedLine(t *testing.T) {
+	uu := map[string]struct {
+		line string
+		at   time.Time
+		msg  string
+		ok   bool
+	}{
+		"plain": {
+			line: "2024-05-01T10:31:00.123456789Z starting server",
+			at:   time.Date(2024, 5, 1, 10, 31, 0, 123456789, time.UTC),
+			msg:  "starting server",
+			ok:   true,
+		},
+		"offset": {
+			line: "2024-05-01T12:31:00+02:00 boom",
+			at:   time.Date(2024, 5, 1, 10, 31, 0, 0, time.UTC),
+			msg:  "boom",
+			ok:   true,
+		},
+		"empty-message": {
+			line: "2024-05-01T10:31:00Z",
+			at:   time.Date(2024, 5, 1, 10, 31, 0, 0, time.UTC),
+			ok:   true,
+		},
+		"no-timestamp": {
+			line: "panic: runtime error",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			at, msg, ok := parseTimestampedLine(u.line)
+			assert.Equal(t, u.ok, ok)
+			assert.True(t, u.at.Equal(at))
+			assert.Equal(t, u.msg, msg)
+		})
+	}
+}
+
+func TestTailLogLines(t *testing.T) {
+	uu := map[string]struct {
+		r      io.Reader
+		budget int
+		e      []string
+		err    bool
+	}{
+		"fits": {
+			r:      strings.NewReader("a\nb\nc\n"),
+			budget: 10,
+			e:      []string{"a", "b", "c"},
+		},
+		"keeps-recent": {
+			r:      strings.NewReader("old\nmid\nnew\n"),
+			budget: 6,
+			e:      []string{"mid", "new"},
+		},
+		"empty": {
+			r:      strings.NewReader(""),
+			budget: 10,
+		},
+		"read-error": {
+			r:      io.MultiReader(strings.NewReader("a\nb\n"), iotest.ErrReader(errors.New("boom"))),
+			budget: 10,
+			e:      []string{"a", "b"},
+			err:    true,
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			ll, err := tailLogLines(u.r, u.budget)
+			assert.Equal(t, u.err, err != nil)
+			assert.Equal(t, u.e, ll)
+		})
+	}
+}
+
+func TestEventTime(t *testing.T) {
+	first := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	last := first.Add(time.Minute)
+
+	uu := map[string]struct {
+		ev corev1.Event
+		e  time.Time
+	}{
+		"last": {
+			ev: corev1.Event{FirstTimestamp: metav1.NewTime(first), LastTimestamp: metav1.NewTime(last)},
+			e:  last,
+		},
+		"event-time": {
+			ev: corev1.Event{EventTime: metav1.NewMicroTime(last)},
+			e:  last,
+		},
+		"first": {
+			ev: corev1.Event{FirstTimestamp: metav1.NewTime(first)},
+			e:  first,
+		},
+		"created": {
+			ev: corev1.Event{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(first)}},
+			e:  first,
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.True(t, u.e.Equal(eventTime(&u.ev)))
+		})
+	}
+}
+
+func TestToolTime(t *testing.T) {
+	at := time.Now().Add(-5 * time.Minute).Truncate(time.Second)
+
+	uu := map[string]struct {
+		t time.Time
+		e string
+	}{
+		"zero": {e: "<unknown>"},
+		"recent": {
+			t: at,
+			e: at.UTC().Format(time.RFC3339) + " (5m ago)",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, toolTime(u.t))
+		})
+	}
+}
+
+func TestNodeFitReasons(t *testing.T) {
+	pod := corev1.Pod{
+		Spec: corev1.PodSpec{
+			NodeSelector: map[string]string{"disk": "ssd"},
+			Containers: []corev1.Container{{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("1"),
+						corev1.ResourceMemory: resource.MustParse("1Gi"),
+					},
+				},
+			}},
+			Tolerations: []corev1.Toleration{{Key: "gpu", Operator: corev1.TolerationOpExists}},
+		},
+	}
+	requests := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("1"),
+		corev1.ResourceMemory: resource.MustParse("1Gi"),
+	}
+	newNode := func(labels map[string]string, cpu string, taints ...corev1.Taint) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "n1", Labels: labels},
+			Spec:       corev1.NodeSpec{Taints: taints},
+			Status: corev1.NodeStatus{
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse(cpu),
+					corev1.ResourceMemory: resource.MustParse("4Gi"),
+					corev1.ResourcePods:   resource.MustParse("110"),
+				},
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+			},
+		}
+	}
+
+	uu := map[string]struct {
+		node *corev1.Node
+		used *nodeAlloc
+		e    []string
+	}{
+		"fits": {
+			node: newNode(map[string]string{"disk": "ssd"}, "2"),
+		},
+		"selector": {
+			node: newNode(nil, "2"),
+			e:    []string{"node didn't match pod's nodeSelector/required node affinity"},
+		},
+		"cpu": {
+			node: newNode(map[string]string{"disk": "ssd"}, "2"),
+			used: &nodeAlloc{cpu: resource.MustParse("1500m")},
+			e:    []string{"insufficient cpu"},
+		},
+		"tolerated-taint": {
+			node: newNode(map[string]string{"disk": "ssd"}, "2", corev1.Taint{Key: "gpu", Effect: corev1.TaintEffectNoSchedule}),
+		},
+		"taint": {
+			node: newNode(map[string]string{"disk": "ssd"}, "2", corev1.Taint{Key: "dedicated", Value: "db", Effect: corev1.TaintEffectNoSchedule}),
+			e:    []string{"untolerated taint {dedicated=db:NoSchedule}"},
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, nodeFitReasons(&pod, u.node, requests, u.used))
+		})
+	}
+}
+
+func TestIsBinary(t *testing.T) {
+	uu := map[string]struct {
+		bb []byte
+		e  bool
+	}{
+		"empty": {},
+		"text": {
+			bb: []byte("2024-05-01 starting server\n\tport=8080\n"),
+		},
+		"ansi": {
+			bb: []byte("\x1b[31mERROR\x1b[0m boom\r\n"),
+		},
+		"nul": {
+			bb: []byte("abc\x00def"),
+			e:  true,
+		},
+		"garbage": {
+			bb: []byte{0xff, 0xfe, 0x01, 0x02, 0x03, 'a', 0x80, 0x81, 0x07, 0x08},
+			e:  true,
+		},
+		"mostly-text": {
+			bb: append([]byte("a normal log line with one bad byte "), 0xff, '\n'),
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, isBinary(u.bb))
+		})
+	}
+}
+
+func TestSanitizeText(t *testing.T) {
+	uu := map[string]struct {
+		bb []byte
+		e  string
+	}{
+		"plain": {
+			bb: []byte("hello\n\tworld\n"),
+			e:  "hello\n\tworld\n",
+		},
+		"ansi": {
+			bb: []byte("\x1b[1;31mERROR\x1b[0m boom\r\n"),
+			e:  "ERROR boom\n",
+		},
+		"invalid-utf8": {
+			bb: []byte("bad \xff byte"),
+			e:  "bad � byte",
+		},
+		"controls": {
+			bb: []byte("bell\x07 back\x08space"),
+			e:  "bell backspace",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, sanitizeText(u.bb))
+		})
+	}
+}
+
+func TestSanitizeObject(t *testing.T) {
+	uu := map[string]struct {
+		o, e map[string]any
+	}{
+		"no-metadata": {
+			o: map[string]any{"kind": "Pod"},
+			e: map[string]any{"kind": "Pod"},
+		},
+		"noise": {
+			o: map[string]any{"metadata": map[string]any{
+				"name":          "p1",
+				"managedFields": []any{map[string]any{"manager": "kubectl"}},
+				"annotations":   map[string]any{lastAppliedAnnotation: "{}", "team": "a"},
+			}},
+			e: map[string]any{"metadata": map[string]any{
+				"name":        "p1",
+				"annotations": map[string]any{"team": "a"},
+			}},
+		},
+		"only-last-applied": {
+			o: map[string]any{"metadata": map[string]any{
+				"name":        "p1",
+				"annotations": map[string]any{lastAppliedAnnotation: "{}"},
+			}},
+			e: map[string]any{"metadata": map[string]any{"name": "p1"}},
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			orig := fmt.Sprint(u.o)
+			assert.Equal(t, u.e, sanitizeObject(u.o))
+			assert.Equal(t, orig, fmt.Sprint(u.o))
+		})
+	}
+}
+
+func TestMergeContainerLogs(t *testing.T) {
+	cc := []containerLogs{
+		{container: "app", logs: "2024-05-01T10:00:01Z started\n2024-05-01T10:00:03Z panic: boom\n\tat main.go:12\n"},
+		{container: "proxy", logs: "2024-05-01T10:00:02Z upstream reset\n"},
+	}
+
+	uu := map[string]struct {
+		timestamps bool
+		e          string
+	}{
+		"timestamps": {
+			timestamps: true,
+			e: "[app] 2024-05-01T10:00:01Z started\n" +
+				"[proxy] 2024-05-01T10:00:02Z upstream reset\n" +
+				"[app] 2024-05-01T10:00:03Z panic: boom\n" +
+				"[app] \tat main.go:12\n",
+		},
+		"by-container": {
+			e: "[app] 2024-05-01T10:00:01Z started\n" +
+				"[app] 2024-05-01T10:00:03Z panic: boom\n" +
+				"[app] \tat main.go:12\n" +
+				"[proxy] 2024-05-01T10:00:02Z upstream reset\n",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, mergeContainerLogs(cc, u.timestamps))
+		})
+	}
+}
+
+func TestPodContainerNames(t *testing.T) {
+	pod := corev1.Pod{Spec: corev1.PodSpec{
+		InitContainers: []corev1.Container{{Name: "init"}},
+		Containers:     []corev1.Container{{Name: "app"}, {Name: "sidecar"}},
+	}}
+
+	assert.Equal(t, []string{"init", "app", "sidecar"}, podContainerNames(&pod))
+}
@@ -0,0 +1,437 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/derailed/k9s/internal/client"
+	copilot "github.com/github/copilot-sdk/go"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	resourcehelper "k8s.io/component-helpers/resource"
+)
+
+const (
+	defaultTopLimit = 10
+	promTimeout     = 10 * time.Second
+
+	sourcePrometheus    = "prometheus"
+	sourceMetricsServer = "metrics-server"
+)
+
+// --- top_pods / top_nodes tools ---
+
+type topPodsParams struct {
+	Namespace string `json:"namespace,omitempty" jsonschema:"Namespace to inspect (default: all namespaces)"`
+	SortBy    string `json:"sortBy,omitempty" jsonschema:"Sort by cpu or memory (default: cpu)"`
+	Limit     int    `json:"limit,omitempty" jsonschema:"Max results (default: 10)"`
+}
+
+type topNodesParams struct {
+	SortBy string `json:"sortBy,omitempty" jsonschema:"Sort by cpu or memory (default: cpu)"`
+	Limit  int    `json:"limit,omitempty" jsonschema:"Max results (default: 10)"`
+}
+
+// resUsage tracks observed cpu (millicores) and memory (bytes) usage.
+// Peaks are only known when backed by Prometheus.
+type resUsage struct {
+	cpu, mem         int64
+	peakCPU, peakMem int64
+}
+
+func (tf *ToolFactory) topPodsTool() copilot.Tool {
+	return copilot.DefineTool(
+		"top_pods",
+		"List the pods using the most CPU or memory (like kubectl top pods), with usage as a percentage of requests and limits. Uses Prometheus when configured (adds 1h peaks), otherwise metrics-server. Use to find hotspots and over/under-provisioned pods.",
+		func(params topPodsParams, inv copilot.ToolInvocation) (any, error) {
+			sortBy, err := topSortBy(params.SortBy)
+			if err != nil {
+				return nil, err
+			}
+			ns, err := tf.listNamespace(params.Namespace)
+			if err != nil {
+				return nil, err
+			}
+			ctx := context.Background()
+
+			usage, source, errs := tf.podUsage(ctx, ns)
+			if usage == nil {
+				return noMetrics(errs), nil
+			}
+			maps.DeleteFunc(usage, func(fqn string, _ resUsage) bool {
+				pns, _ := client.Namespaced(fqn)
+				return !tf.nsAllowed(pns)
+			})
+			dial, err := tf.conn.Dial()
+			if err != nil {
+				return nil, fmt.Errorf("failed to connect to cluster: %w", err)
+			}
+			pods, err := dial.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list pods: %w", err)
+			}
+
+			res := map[string]any{
+				"source": source,
+				"sortBy": sortBy,
+				"total":  len(usage),
+				"pods":   topPodRows(pods.Items, usage, sortBy, topLimit(params.Limit)),
+			}
+			if len(errs) > 0 {
+				res["errors"] = errs
+			}
+
+			return res, nil
+		},
+	)
+}
+
+func (tf *ToolFactory) topNodesTool() copilot.Tool {
+	return copilot.DefineTool(
+		"top_nodes",
+		"List nodes by CPU or memory usage (like kubectl top nodes), with usage as a percentage of allocatable. Uses Prometheus when configured (adds 1h peaks), otherwise metrics-server.",
+		func(params topNodesParams, inv copilot.ToolInvocation) (any, error) {
+			sortBy, err := topSortBy(params.SortBy)
+			if err != nil {
+				return nil, err
+			}
+			ctx := context.Background()
+
+			usage, source, errs := tf.nodeUsage(ctx)
+			if usage == nil {
+				return noMetrics(errs), nil
+			}
+			dial, err := tf.conn.Dial()
+			if err != nil {
+				return nil, fmt.Errorf("failed to connect to cluster: %w", err)
+			}
+			nodes, err := dial.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list nodes: %w", err)
+			}
+
+			res := map[string]any{
+				"source": source,
+				"sortBy": sortBy,
+				"total":  len(usage),
+				"nodes":  topNodeRows(nodes.Items, usage, sortBy, topLimit(params.Limit)),
+			}
+			if len(errs) > 0 {
+				res["errors"] = errs
+			}
+
+			return res, nil
+		},
+	)
+}
+
+func topSortBy(s string) (string, error) {
+	switch strings.ToLower(s) {
+	case "", "cpu":
+		return "cpu", nil
+	case "memory", "mem":
+		return "memory", nil
+	default:
+		return "", fmt.Errorf("unsupported sortBy %q: use cpu or memory", s)
+	}
+}
+
+func topLimit(n int) int {
+	if n <= 0 {
+		return defaultTopLimit
+	}
+
+	return n
+}
+
+func noMetrics(errs []string) map[string]any {
+	return map[string]any{
+		"available": false,
+		"summary":   "No usage metrics available: metrics-server is not installed and no working ai.prometheusURL is configured. Fall back to comparing requests/limits.",
+		"errors":    errs,
+	}
+}
+
+// podUsage returns pod usage keyed by namespace/name, preferring Prometheus.
+// A nil map means no source could be reached.
+func (tf *ToolFactory) podUsage(ctx context.Context, ns string) (map[string]resUsage, string, []string) {
+	var errs []string
+	if base := tf.cfg.PrometheusURL; base != "" {
+		sel := `container!="",container!="POD"`
+		if ns != "" {
+			sel += fmt.Sprintf(",namespace=%q", ns)
+		}
+		cpu := fmt.Sprintf("sum by (namespace, pod) (rate(container_cpu_usage_seconds_total{%s}[5m]))", sel)
+		mem := fmt.Sprintf("sum by (namespace, pod) (container_memory_working_set_bytes{%s})", sel)
+		uu, err := promUsage(ctx, base, cpu, mem, "namespace", "pod")
+		if err == nil {
+			return uu, sourcePrometheus, nil
+		}
+		errs = append(errs, "prometheus: "+err.Error())
+	}
+
+	if !tf.conn.HasMetrics() {
+		return nil, "", append(errs, "metrics-server: metrics API not available")
+	}
+	mx, err := client.DialMetrics(tf.conn).FetchPodsMetrics(ctx, ns)
+	if err != nil {
+		return nil, "", append(errs, "metrics-server: "+err.Error())
+	}
+	uu := make(map[string]resUsage, len(mx.Items))
+	for i := range mx.Items {
+		var u resUsage
+		for _, c := range mx.Items[i].Containers {
+			u.cpu += c.Usage.Cpu().MilliValue()
+			u.mem += c.Usage.Memory().Value()
+		}
+		uu[client.FQN(mx.Items[i].Namespace, mx.Items[i].Name)] = u
+	}
+
+	return uu, sourceMetricsServer, errs
+}
+
+// nodeUsage returns node usage keyed by node name, preferring Prometheus.
+func (tf *ToolFactory) nodeUsage(ctx context.Context) (map[string]resUsage, string, []string) {
+	var errs []string
+	if base := tf.cfg.PrometheusURL; base != "" {
+		cpu := `sum by (node) (rate(container_cpu_usage_seconds_total{id="/"}[5m]))`
+		mem := `sum by (node) (container_memory_working_set_bytes{id="/"})`
+		uu, err := promUsage(ctx, base, cpu, mem, "node")
+		if err == nil {
+			return uu, sourcePrometheus, nil
+		}
+		errs = append(errs, "prometheus: "+err.Error())
+	}
+
+	if !tf.conn.HasMetrics() {
+		return nil, "", append(errs, "metrics-server: metrics API not available")
+	}
+	mx, err := client.DialMetrics(tf.conn).FetchNodesMetrics(ctx)
+	if err != nil {
+		return nil, "", append(errs, "metrics-server: "+err.Error())
+	}
+	uu := make(map[string]resUsage, len(mx.Items))
+	for i := range mx.Items {
+		uu[mx.Items[i].Name] = resUsage{
+			cpu: mx.Items[i].Usage.Cpu().MilliValue(),
+			mem: mx.Items[i].Usage.Memory().Value(),
+		}
+	}
+
+	return uu, sourceMetricsServer, errs
+}
+
+// promUsage runs the current cpu (cores) and memory (bytes) queries plus their
+// 1h peaks. Peaks are best effort since subqueries may be disabled.
+func promUsage(ctx context.Context, base, cpuQ, memQ string, keys ...string) (map[string]resUsage, error) {
+	ctx, cancel := context.WithTimeout(ctx, promTimeout)
+	defer cancel()
+
+	cpu, err := promQuery(ctx, base, cpuQ, keys...)
+	if err != nil {
+		return nil, err
+	}
+	if len(cpu) == 0 {
+		return nil, fmt.Errorf("no samples for %s", cpuQ)
+	}
+	mem, err := promQuery(ctx, base, memQ, keys...)
+	if err != nil {
+		return nil, err
+	}
+	peakCPU, _ := promQuery(ctx, base, "max_over_time(("+cpuQ+")[1h:5m])", keys...)
+	peakMem, _ := promQuery(ctx, base, "max_over_time(("+memQ+")[1h:5m])", keys...)
+
+	uu := make(map[string]resUsage, len(cpu))
+	for k, v := range cpu {
+		uu[k] = resUsage{
+			cpu:     int64(v * 1000),
+			mem:     int64(mem[k]),
+			peakCPU: int64(peakCPU[k] * 1000),
+			peakMem: int64(peakMem[k]),
+		}
+	}
+
+	return uu, nil
+}
+
+// promQuery runs an instant query and returns the sample values keyed by the
+// given labels joined with "/".
+func promQuery(ctx context.Context, base, query string, keys ...string) (map[string]float64, error) {
+	u := strings.TrimSuffix(base, "/") + "/api/v1/query?query=" + url.QueryEscape(query)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("prometheus returned %s", resp.Status)
+	}
+
+	return parsePromVector(resp.Body, keys...)
+}
+
+// parsePromVector decodes a Prometheus instant vector response.
+func parsePromVector(r io.Reader, keys ...string) (map[string]float64, error) {
+	var body struct {
+		Status string `json:"status"`
+		Error  string `json:"error"`
+		Data   struct {
+			ResultType string `json:"resultType"`
+			Result     []struct {
+				Metric map[string]string `json:"metric"`
+				Value  [2]any            `json:"value"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(r).Decode(&body); err != nil {
+		return nil, fmt.Errorf("decoding prometheus response: %w", err)
+	}
+	if body.Status != "success" {
+		return nil, fmt.Errorf("prometheus query failed: %s", body.Error)
+	}
+	if body.Data.ResultType != "vector" {
+		return nil, fmt.Errorf("unexpected prometheus result type %q", body.Data.ResultType)
+	}
+
+	vv := make(map[string]float64, len(body.Data.Result))
+	for _, s := range body.Data.Result {
+		raw, ok := s.Value[1].(string)
+		if !ok {
+			continue
+		}
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			continue
+		}
+		ll := make([]string, 0, len(keys))
+		for _, k := range keys {
+			ll = append(ll, s.Metric[k])
+		}
+		vv[strings.Join(ll, "/")] = f
+	}
+
+	return vv, nil
+}
+
+func topPodRows(pods []corev1.Pod, usage map[string]resUsage, sortBy string, limit int) []map[string]any {
+	type entry struct {
+		fqn string
+		u   resUsage
+		pod *corev1.Pod
+	}
+	ee := make([]entry, 0, len(pods))
+	for i := range pods {
+		fqn := client.FQN(pods[i].Namespace, pods[i].Name)
+		if u, ok := usage[fqn]; ok {
+			ee = append(ee, entry{fqn: fqn, u: u, pod: &pods[i]})
+		}
+	}
+	sort.SliceStable(ee, func(i, j int) bool {
+		return usageLess(ee[j].u, ee[i].u, sortBy, ee[j].fqn, ee[i].fqn)
+	})
+
+	rows := make([]map[string]any, 0, min(len(ee), limit))
+	for _, e := range ee[:min(len(ee), limit)] {
+		row := map[string]any{
+			"pod":    e.fqn,
+			"node":   e.pod.Spec.NodeName,
+			"cpu":    fmtCPU(e.u.cpu),
+			"memory": fmtMem(e.u.mem),
+		}
+		rr := resourcehelper.PodRequests(e.pod, resourcehelper.PodResourcesOptions{})
+		ll := resourcehelper.PodLimits(e.pod, resourcehelper.PodResourcesOptions{})
+		addPct(row, "cpuRequestPct", e.u.cpu, rr.Cpu().MilliValue())
+		addPct(row, "cpuLimitPct", e.u.cpu, ll.Cpu().MilliValue())
+		addPct(row, "memRequestPct", e.u.mem, rr.Memory().Value())
+		addPct(row, "memLimitPct", e.u.mem, ll.Memory().Value())
+		addPeaks(row, e.u)
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+func topNodeRows(nodes []corev1.Node, usage map[string]resUsage, sortBy string, limit int) []map[string]any {
+	type entry struct {
+		u    resUsage
+		node *corev1.Node
+	}
+	ee := make([]entry, 0, len(nodes))
+	for i := range nodes {
+		if u, ok := usage[nodes[i].Name]; ok {
+			ee = append(ee, entry{u: u, node: &nodes[i]})
+		}
+	}
+	sort.SliceStable(ee, func(i, j int) bool {
+		return usageLess(ee[j].u, ee[i].u, sortBy, ee[j].node.Name, ee[i].node.Name)
+	})
+
+	rows := make([]map[string]any, 0, min(len(ee), limit))
+	for _, e := range ee[:min(len(ee), limit)] {
+		row := map[string]any{
+			"node":   e.node.Name,
+			"cpu":    fmtCPU(e.u.cpu),
+			"memory": fmtMem(e.u.mem),
+		}
+		alloc := e.node.Status.Allocatable
+		addPct(row, "cpuPct", e.u.cpu, alloc.Cpu().MilliValue())
+		addPct(row, "memPct", e.u.mem, alloc.Memory().Value())
+		addPeaks(row, e.u)
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+// usageLess reports whether a uses less of the sort resource than b. Ties are
+// broken by name so that a descending sort lists names alphabetically.
+func usageLess(a, b resUsage, sortBy, an, bn string) bool {
+	av, bv := a.cpu, b.cpu
+	if sortBy == "memory" {
+		av, bv = a.mem, b.mem
+	}
+	if av != bv {
+		return av < bv
+	}
+
+	return an > bn
+}
+
+func addPct(row map[string]any, key string, used, total int64) {
+	if total > 0 {
+		row[key] = int(used * 100 / total)
+	}
+}
+
+func addPeaks(row map[string]any, u resUsage) {
+	if u.peakCPU > 0 {
+		row["cpuPeak1h"] = fmtCPU(u.peakCPU)
+	}
+	if u.peakMem > 0 {
+		row["memoryPeak1h"] = fmtMem(u.peakMem)
+	}
+}
+
+func fmtCPU(m int64) string {
+	return strconv.FormatInt(m, 10) + "m"
+}
+
+func fmtMem(b int64) string {
+	return strconv.FormatInt(b/(1024*1024), 10) + "Mi"
+}
@@ -0,0 +1,169 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParsePromVector(t *testing.T) {
+	uu := map[string]struct {
+		body string
+		e    map[string]float64
+		err  string
+	}{
+		"happy": {
+			body: `{"status":"success","data":{"resultType":"vector","result":[
+				{"metric":{"namespace":"ns1","pod":"p1"},"value":[1700000000,"0.25"]},
+				{"metric":{"namespace":"ns1","pod":"p2"},"value":[1700000000,"NaN-ish"]}]}}`,
+			e: map[string]float64{"ns1/p1": 0.25},
+		},
+		"error": {
+			body: `{"status":"error","error":"bad query"}`,
+			err:  "prometheus query failed: bad query",
+		},
+		"matrix": {
+			body: `{"status":"success","data":{"resultType":"matrix","result":[]}}`,
+			err:  `unexpected prometheus result type "matrix"`,
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			vv, err := parsePromVector(strings.NewReader(u.body), "namespace", "pod")
+			if u.err != "" {
+				assert.EqualError(t, err, u.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, u.e, vv)
+		})
+	}
+}
+
+func TestPromUsage(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query().Get("query")
+		v := "0.5"
+		switch {
+		case strings.HasPrefix(q, "max_over_time") && strings.Contains(q, "memory"):
+			v = "268435456"
+		case strings.HasPrefix(q, "max_over_time"):
+			v = "0.9"
+		case strings.Contains(q, "memory"):
+			v = "134217728"
+		}
+		fmt.Fprintf(w, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"node":"n1"},"value":[0,%q]}]}}`, v)
+	}))
+	defer srv.Close()
+
+	uu, err := promUsage(context.Background(), srv.URL+"/", "cpu", "memory", "node")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]resUsage{
+		"n1": {cpu: 500, mem: 128 << 20, peakCPU: 900, peakMem: 256 << 20},
+	}, uu)
+}
+
+func TestTopPodRows(t *testing.T) {
+	pod := func(name, cpuReq, cpuLim, memReq string) corev1.Pod {
+		rr := corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpuReq),
+				corev1.ResourceMemory: resource.MustParse(memReq),
+			},
+			Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpuLim)},
+		}
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name},
+			Spec: corev1.PodSpec{
+				NodeName:   "n1",
+				Containers: []corev1.Container{{Name: "c", Resources: rr}},
+			},
+		}
+	}
+	pods := []corev1.Pod{
+		pod("p1", "100m", "200m", "64Mi"),
+		pod("p2", "500m", "1", "256Mi"),
+		pod("p3", "100m", "100m", "64Mi"),
+	}
+	usage := map[string]resUsage{
+		"ns/p1": {cpu: 150, mem: 32 << 20},
+		"ns/p2": {cpu: 250, mem: 128 << 20, peakCPU: 800},
+		"ns/p3": {cpu: 10, mem: 256 << 20},
+	}
+
+	rows := topPodRows(pods, usage, "cpu", 2)
+	assert.Equal(t, []map[string]any{
+		{
+			"pod": "ns/p2", "node": "n1", "cpu": "250m", "memory": "128Mi",
+			"cpuRequestPct": 50, "cpuLimitPct": 25, "memRequestPct": 50, "cpuPeak1h": "800m",
+		},
+		{
+			"pod": "ns/p1", "node": "n1", "cpu": "150m", "memory": "32Mi",
+			"cpuRequestPct": 150, "cpuLimitPct": 75, "memRequestPct": 50,
+		},
+	}, rows)
+
+	rows = topPodRows(pods, usage, "memory", 1)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "ns/p3", rows[0]["pod"])
+	assert.Equal(t, 400, rows[0]["memRequestPct"])
+}
+
+func TestTopNodeRows(t *testing.T) {
+	node := func(name string) corev1.Node {
+		return corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("2"),
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			}},
+		}
+	}
+	nodes := []corev1.Node{node("n1"), node("n2"), node("n3")}
+	usage := map[string]resUsage{
+		"n1": {cpu: 500, mem: 512 << 20},
+		"n2": {cpu: 1500, mem: 256 << 20},
+	}
+
+	assert.Equal(t, []map[string]any{
+		{"node": "n2", "cpu": "1500m", "memory": "256Mi", "cpuPct": 75, "memPct": 25},
+		{"node": "n1", "cpu": "500m", "memory": "512Mi", "cpuPct": 25, "memPct": 50},
+	}, topNodeRows(nodes, usage, "cpu", 10))
+}
+
+func TestTopSortBy(t *testing.T) {
+	uu := map[string]struct {
+		s, e string
+		err  bool
+	}{
+		"default": {e: "cpu"},
+		"mem":     {s: "Mem", e: "memory"},
+		"bad":     {s: "disk", err: true},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			s, err := topSortBy(u.s)
+			if u.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.Equal(t, u.e, s)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"strings"
+)
+
+// ContinuePrompt asks the model to resume a truncated answer. The
+// continuation is appended verbatim to the cut off answer.
+const ContinuePrompt = "Your previous answer was cut off by the output limit. " +
+	"Continue exactly where it stopped, starting mid-sentence or mid-line if needed. " +
+	"Do NOT repeat anything already written, do NOT add a preamble and do NOT reopen a code block that was already open."
+
+// isCutOff checks whether a response used up the model's output limit. The
+// SDK reports no stop reason, so the token count is the only sign of a
+// response stopped by the limit. An unknown limit never matches.
+func isCutOff(outputTokens, limit int) bool {
+	return limit > 0 && outputTokens >= limit
+}
+
+// hasOpenCodeBlock returns true when content ends inside a code block, a
+// telltale sign of an answer cut off mid-output.
+func hasOpenCodeBlock(content string) bool {
+	var open bool
+	for _, l := range strings.Split(content, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(l), "```") {
+			open = !open
+		}
+	}
+
+	return open
+}
@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsCutOff(t *testing.T) {
+	uu := map[string]struct {
+		output, limit int
+		e             bool
+	}{
+		"at-limit":      {output: 4096, limit: 4096, e: true},
+		"below-limit":   {output: 812, limit: 4096},
+		"unknown-limit": {output: 4096},
+		"blank":         {},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, isCutOff(u.output, u.limit))
+		})
+	}
+}
+
+func TestHasOpenCodeBlock(t *testing.T) {
+	uu := map[string]struct {
+		content string
+		e       bool
+	}{
+		"prose": {
+			content: "All good.",
+		},
+		"closed": {
+			content: "Apply:\n```yaml\nkind: Pod\n```\nDone.",
+		},
+		"open": {
+			content: "Apply:\n```yaml\nkind: Pod\nmetadata:\n  name: fr",
+			e:       true,
+		},
+		"indented": {
+			content: "1. Run\n   ```bash\n   kubectl get po",
+			e:       true,
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, hasOpenCodeBlock(u.content))
+		})
+	}
+}
@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/derailed/k9s/internal/client"
+	"github.com/derailed/k9s/internal/dao"
+	copilot "github.com/github/copilot-sdk/go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	yamlutil "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+)
+
+// aiFieldManager owns the server-side applies made by validate_manifest and
+// apply_manifest.
+const aiFieldManager = "k9s-ai"
+
+// --- validate_manifest tool ---
+
+type validateManifestParams struct {
+	Manifest  string `json:"manifest" jsonschema:"YAML or JSON manifest to validate. Multiple documents may be separated by ---"`
+	Namespace string `json:"namespace,omitempty" jsonschema:"Namespace for namespaced resources that don't set one (default: default)"`
+}
+
+// manifestCheck reports the validation outcome of one manifest document.
+type manifestCheck struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Valid     bool   `json:"valid"`
+	Error     string `json:"error,omitempty"`
+}
+
+func (tf *ToolFactory) validateManifestTool() copilot.Tool {
+	return copilot.DefineTool(
+		"validate_manifest",
+		"Validate a manifest against the API server with a server-side dry-run apply. Nothing is persisted. "+
+			"Catches schema errors, unknown fields, admission webhook denials and immutable field changes before the manifest is applied.",
+		func(params validateManifestParams, inv copilot.ToolInvocation) (any, error) {
+			objs, err := decodeManifest(params.Manifest)
+			if err != nil {
+				return nil, fmt.Errorf("invalid manifest: %w", err)
+			}
+			if len(objs) == 0 {
+				return nil, errors.New("manifest holds no resources")
+			}
+			dial, err := tf.conn.DynDial()
+			if err != nil {
+				return nil, fmt.Errorf("failed to connect to cluster: %w", err)
+			}
+
+			checks := make([]manifestCheck, 0, len(objs))
+			var invalid int
+			for _, o := range objs {
+				c := manifestCheck{Kind: o.GetKind(), Name: o.GetName()}
+				ns, err := dryRunApply(dial, o, params.Namespace)
+				c.Namespace = ns
+				if err != nil {
+					c.Error = err.Error()
+					invalid++
+				} else {
+					c.Valid = true
+				}
+				checks = append(checks, c)
+			}
+
+			return map[string]any{
+				"valid":   invalid == 0,
+				"summary": fmt.Sprintf("%d of %d resources passed a server-side dry-run", len(objs)-invalid, len(objs)),
+				"results": checks,
+			}, nil
+		},
+	)
+}
+
+// dryRunApply server-side applies an object in dry-run mode. Namespaced
+// objects without a namespace land in ns, or default. Returns the namespace
+// used, if any.
+func dryRunApply(dial dynamic.Interface, o *unstructured.Unstructured, ns string) (string, error) {
+	gvr, err := applyTarget(o, ns)
+	if err != nil {
+		return "", err
+	}
+	opts := metav1.ApplyOptions{DryRun: []string{metav1.DryRunAll}, FieldManager: aiFieldManager, Force: true}
+	_, err = resourceFor(dial, gvr, o).Apply(context.Background(), o.GetName(), o, opts)
+
+	return o.GetNamespace(), err
+}
+
+// applyTarget resolves the resource of a manifest object. Namespaced objects
+// without a namespace are set to ns, or default, cluster scoped ones lose
+// theirs.
+func applyTarget(o *unstructured.Unstructured, ns string) (*client.GVR, error) {
+	gv, err := schema.ParseGroupVersion(o.GetAPIVersion())
+	if err != nil {
+		return nil, err
+	}
+	gvr, namespaced, ok := dao.MetaAccess.GVK2GVR(gv, o.GetKind())
+	if !ok {
+		return nil, fmt.Errorf("unknown resource kind %s in %s", o.GetKind(), o.GetAPIVersion())
+	}
+	if namespaced {
+		o.SetNamespace(cmp.Or(o.GetNamespace(), ns, client.DefaultNamespace))
+	} else {
+		o.SetNamespace("")
+	}
+
+	return gvr, nil
+}
+
+// resourceFor returns the dynamic client of an object resolved by
+// applyTarget.
+func resourceFor(dial dynamic.Interface, gvr *client.GVR, o *unstructured.Unstructured) dynamic.ResourceInterface {
+	if ns := o.GetNamespace(); ns != "" {
+		return dial.Resource(gvr.GVR()).Namespace(ns)
+	}
+
+	return dial.Resource(gvr.GVR())
+}
+
+// decodeSingleManifest decodes a manifest holding exactly one resource.
+func decodeSingleManifest(raw string) (*unstructured.Unstructured, error) {
+	objs, err := decodeManifest(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	if len(objs) != 1 {
+		return nil, fmt.Errorf("manifest must hold a single resource, got %d", len(objs))
+	}
+
+	return objs[0], nil
+}
+
+// decodeManifest splits a YAML or JSON stream into objects, skipping empty
+// documents.
+func decodeManifest(raw string) ([]*unstructured.Unstructured, error) {
+	dec := yamlutil.NewYAMLOrJSONDecoder(bytes.NewReader([]byte(raw)), 4096)
+	var oo []*unstructured.Unstructured
+	for {
+		var m map[string]any
+		err := dec.Decode(&m)
+		if errors.Is(err, io.EOF) {
+			return oo, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(m) == 0 {
+			continue
+		}
+		o := &unstructured.Unstructured{Object: m}
+		if o.GetKind() == "" || o.GetAPIVersion() == "" {
+			return nil, fmt.Errorf("document %d is missing apiVersion or kind", len(oo)+1)
+		}
+		if o.GetName() == "" {
+			return nil, fmt.Errorf("%s document %d is missing metadata.name", o.GetKind(), len(oo)+1)
+		}
+		oo = append(oo, o)
+	}
+}
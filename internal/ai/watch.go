@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	copilot "github.com/github/copilot-sdk/go"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+const (
+	// defaultWatchSeconds is how long watch_events waits when the model
+	// doesn't say.
+	defaultWatchSeconds = 10
+
+	// maxWatchSeconds caps how long watch_events holds the turn.
+	maxWatchSeconds = 30
+
+	// maxWatchedEvents caps the events watch_events collects.
+	maxWatchedEvents = 100
+)
+
+// watchUnit is the unit of durationSeconds, shortened by tests.
+var watchUnit = time.Second
+
+// --- watch_events tool ---
+
+type watchEventsParams struct {
+	Namespace       string `json:"namespace,omitempty" jsonschema:"Namespace to watch (empty for all)"`
+	ResourceName    string `json:"resourceName,omitempty" jsonschema:"Only watch events of the involved object with this name, e.g. a pod"`
+	EventType       string `json:"eventType,omitempty" jsonschema:"Filter by event type: Normal or Warning"`
+	DurationSeconds int    `json:"durationSeconds,omitempty" jsonschema:"How long to watch, in seconds (default 10, max 30)"`
+}
+
+func (tf *ToolFactory) watchEventsTool() copilot.Tool {
+	return copilot.DefineTool(
+		"watch_events",
+		fmt.Sprintf("Watch Kubernetes events as they happen for up to %d seconds and return them in chronological order. "+
+			"Use it to catch what a flapping workload does next, e.g. \"watch this pod and tell me what happens when it restarts\". "+
+			"Past events are not included, use get_events for those. Always reads from the API server.", maxWatchSeconds),
+		func(params watchEventsParams, inv copilot.ToolInvocation) (any, error) {
+			ns, err := tf.listNamespace(params.Namespace)
+			if err != nil {
+				return nil, err
+			}
+			secs := params.DurationSeconds
+			if secs <= 0 {
+				secs = defaultWatchSeconds
+			}
+			secs = min(secs, maxWatchSeconds)
+
+			events, closed, err := tf.watchEvents(ns, params.ResourceName, time.Duration(secs)*watchUnit)
+			if err != nil {
+				return nil, fmt.Errorf("failed to watch events: %w", err)
+			}
+
+			var results []map[string]string
+			for _, ev := range events {
+				if params.EventType != "" && ev.Type != params.EventType {
+					continue
+				}
+				results = append(results, eventEntry(&ev))
+			}
+			res := map[string]any{
+				"watchedSeconds": secs,
+				"total":          len(results),
+				"events":         results,
+			}
+			if len(events) >= maxWatchedEvents {
+				res["truncated"] = true
+				res["note"] = fmt.Sprintf("Stopped after %d events.", maxWatchedEvents)
+			}
+			if closed {
+				res["note"] = "The API server closed the watch early."
+			}
+
+			return res, nil
+		},
+	)
+}
+
+// watchEvents collects the events created or updated in ns within d. The
+// watch starts at the current resource version so existing events are not
+// replayed, and is stopped before returning. closed reports a watch ended by
+// the server.
+func (tf *ToolFactory) watchEvents(ns, involved string, d time.Duration) (events []corev1.Event, closed bool, err error) {
+	dial, err := tf.conn.Dial()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to connect to cluster: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+
+	var opts metav1.ListOptions
+	if involved != "" {
+		opts.FieldSelector = "involvedObject.name=" + involved
+	}
+	ll, err := dial.CoreV1().Events(ns).List(ctx, metav1.ListOptions{FieldSelector: opts.FieldSelector, Limit: 1})
+	if err != nil {
+		return nil, false, err
+	}
+	opts.ResourceVersion = ll.ResourceVersion
+	w, err := dial.CoreV1().Events(ns).Watch(ctx, opts)
+	if err != nil {
+		return nil, false, err
+	}
+	defer w.Stop()
+
+	for len(events) < maxWatchedEvents {
+		select {
+		case <-ctx.Done():
+			return events, false, nil
+		case e, ok := <-w.ResultChan():
+			if !ok {
+				return events, true, nil
+			}
+			if e.Type != watch.Added && e.Type != watch.Modified {
+				continue
+			}
+			ev, ok := e.Object.(*corev1.Event)
+			if !ok || !tf.nsAllowed(ev.Namespace) || (involved != "" && ev.InvolvedObject.Name != involved) {
+				continue
+			}
+			events = append(events, *ev)
+		}
+	}
+
+	return events, false, nil
+}
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestWatchEventsTool(t *testing.T) {
+	defer func(d time.Duration) { watchUnit = d }(watchUnit)
+	watchUnit = 10 * time.Millisecond
+
+	crash := makeEvent("ns1", "e1", "p1", "Warning", "BackOff", toolNow)
+	pulled := makeEvent("ns1", "e2", "p1", "Normal", "Pulled", toolNow.Add(time.Second))
+	other := makeEvent("ns1", "e3", "p2", "Warning", "BackOff", toolNow)
+	denied := makeEvent("kube-system", "e4", "p1", "Warning", "BackOff", toolNow)
+
+	uu := map[string]struct {
+		params map[string]any
+		sends  []watch.Event
+		close  bool
+		e      []any
+		note   string
+	}{
+		"pod": {
+			params: map[string]any{"namespace": "ns1", "resourceName": "p1"},
+			sends: []watch.Event{
+				{Type: watch.Added, Object: crash},
+				{Type: watch.Added, Object: other},
+				{Type: watch.Deleted, Object: pulled},
+				{Type: watch.Modified, Object: pulled},
+			},
+			e: []any{eventEntryJSON(crash), eventEntryJSON(pulled)},
+		},
+		"warnings": {
+			params: map[string]any{"eventType": "Warning"},
+			sends: []watch.Event{
+				{Type: watch.Added, Object: crash},
+				{Type: watch.Added, Object: pulled},
+				{Type: watch.Added, Object: denied},
+			},
+			e: []any{eventEntryJSON(crash)},
+		},
+		"closed": {
+			params: map[string]any{"namespace": "ns1"},
+			sends:  []watch.Event{{Type: watch.Added, Object: other}},
+			close:  true,
+			e:      []any{eventEntryJSON(other)},
+			note:   "The API server closed the watch early.",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			conn := newTestConn()
+			w := watch.NewFakeWithChanSize(len(u.sends), false)
+			for _, e := range u.sends {
+				w.Action(e.Type, e.Object)
+			}
+			if u.close {
+				w.Stop()
+			}
+			conn.dial.PrependWatchReactor("events", func(k8stesting.Action) (bool, watch.Interface, error) {
+				return true, w, nil
+			})
+			tf := newTestToolFactory(newTestFactory(), conn)
+			tf.cfg.AllowedNamespaces = []string{"ns*"}
+
+			m := callToolJSON(t, tf, "watch_events", u.params)
+			assert.Equal(t, float64(defaultWatchSeconds), m["watchedSeconds"])
+			assert.Equal(t, u.e, m["events"])
+			if u.note != "" {
+				assert.Equal(t, u.note, m["note"])
+			}
+			assert.True(t, w.IsStopped())
+		})
+	}
+}
+
+func eventEntryJSON(o runtime.Object) map[string]any {
+	m := make(map[string]any)
+	for k, v := range eventEntry(o.(*corev1.Event)) {
+		m[k] = v
+	}
+
+	return m
+}
+
+func TestWatchEventsToolDuration(t *testing.T) {
+	defer func(d time.Duration) { watchUnit = d }(watchUnit)
+	watchUnit = time.Millisecond
+
+	tf := newTestToolFactory(newTestFactory(), newTestConn())
+	m := callToolJSON(t, tf, "watch_events", map[string]any{"durationSeconds": 300})
+	assert.Equal(t, float64(maxWatchSeconds), m["watchedSeconds"])
+	assert.Equal(t, float64(0), m["total"])
+
+	tf.cfg.AllowedNamespaces = []string{"ns1"}
+	_, err := callTool(t, tf, "watch_events", map[string]any{"namespace": "kube-system"})
+	require.Error(t, err)
+}
@@ -0,0 +1,321 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	copilot "github.com/github/copilot-sdk/go"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const maxWorkloadEvents = 10
+
+// --- get_workload_summary tool ---
+
+type getWorkloadSummaryParams struct {
+	Kind      string `json:"kind" jsonschema:"Workload kind: deployment, statefulset or daemonset"`
+	Name      string `json:"name" jsonschema:"Workload name"`
+	Namespace string `json:"namespace" jsonschema:"Workload namespace"`
+}
+
+// workloadPods tracks aggregate pod health for a workload.
+type workloadPods struct {
+	Total     int `json:"total"`
+	Ready     int `json:"ready"`
+	Running   int `json:"running"`
+	Crashing  int `json:"crashing"`
+	Pending   int `json:"pending"`
+	Failed    int `json:"failed"`
+	Succeeded int `json:"succeeded"`
+	Restarts  int `json:"restarts"`
+}
+
+func (tf *ToolFactory) getWorkloadSummaryTool() copilot.Tool {
+	return copilot.DefineTool(
+		"get_workload_summary",
+		"Summarize the health of a Deployment, StatefulSet or DaemonSet in one call: desired/ready/available replicas, rollout status, aggregate pod status (running/crashing/pending), total restarts, images and recent warning events. Use this first for 'how is my app doing' questions.",
+		func(params getWorkloadSummaryParams, inv copilot.ToolInvocation) (any, error) {
+			if err := tf.checkNamespace(params.Namespace); err != nil {
+				return nil, err
+			}
+			dial, err := tf.conn.Dial()
+			if err != nil {
+				return nil, fmt.Errorf("failed to connect to cluster: %w", err)
+			}
+			ctx := context.Background()
+			summary, pods, err := workloadSummary(ctx, dial, params.Kind, params.Name, params.Namespace)
+			if err != nil {
+				return nil, err
+			}
+
+			events, err := dial.CoreV1().Events(params.Namespace).List(ctx, metav1.ListOptions{
+				FieldSelector: "type=Warning",
+			})
+			if err == nil {
+				summary["warningEvents"] = workloadWarnings(events.Items, params.Name, podNames(pods))
+			}
+
+			return summary, nil
+		},
+	)
+}
+
+// workloadKind normalizes a workload kind or alias. It returns an empty
+// string for kinds that are not deployments, statefulsets or daemonsets.
+func workloadKind(kind string) string {
+	switch strings.ToLower(kind) {
+	case "deployment", "deployments", "deploy", "dp":
+		return "deployment"
+	case "statefulset", "statefulsets", "sts":
+		return "statefulset"
+	case "daemonset", "daemonsets", "ds":
+		return "daemonset"
+	default:
+		return ""
+	}
+}
+
+// workloadSummary describes a workload's rollout and pod health and returns
+// the pods it selects.
+func workloadSummary(ctx context.Context, dial kubernetes.Interface, kind, name, ns string) (map[string]any, []corev1.Pod, error) {
+	var (
+		apps     = dial.AppsV1()
+		summary  map[string]any
+		selector *metav1.LabelSelector
+		template corev1.PodTemplateSpec
+	)
+	switch workloadKind(kind) {
+	case "deployment":
+		dp, err := apps.Deployments(ns).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get deployment %s/%s: %w", ns, name, err)
+		}
+		summary, selector, template = deploymentSummary(dp), dp.Spec.Selector, dp.Spec.Template
+	case "statefulset":
+		sts, err := apps.StatefulSets(ns).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get statefulset %s/%s: %w", ns, name, err)
+		}
+		summary, selector, template = statefulSetSummary(sts), sts.Spec.Selector, sts.Spec.Template
+	case "daemonset":
+		ds, err := apps.DaemonSets(ns).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get daemonset %s/%s: %w", ns, name, err)
+		}
+		summary, selector, template = daemonSetSummary(ds), ds.Spec.Selector, ds.Spec.Template
+	default:
+		return nil, nil, fmt.Errorf("unsupported workload kind %q: use deployment, statefulset or daemonset", kind)
+	}
+
+	images := make(map[string]string, len(template.Spec.Containers))
+	for _, c := range template.Spec.Containers {
+		images[c.Name] = c.Image
+	}
+	summary["images"] = images
+
+	sel, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid selector on %s: %w", name, err)
+	}
+	pods, err := dial.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{
+		LabelSelector: sel.String(),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list pods for %s: %w", name, err)
+	}
+	health, unhealthy := podHealth(pods.Items)
+	summary["pods"] = health
+	if len(unhealthy) > 0 {
+		summary["unhealthyPods"] = unhealthy
+	}
+
+	return summary, pods.Items, nil
+}
+
+func podNames(pods []corev1.Pod) []string {
+	nn := make([]string, 0, len(pods))
+	for i := range pods {
+		nn = append(nn, pods[i].Name)
+	}
+
+	return nn
+}
+
+func deploymentSummary(dp *appsv1.Deployment) map[string]any {
+	desired := int32(1)
+	if dp.Spec.Replicas != nil {
+		desired = *dp.Spec.Replicas
+	}
+	rollout := "Unknown"
+	for _, c := range dp.Status.Conditions {
+		if c.Type != appsv1.DeploymentProgressing {
+			continue
+		}
+		rollout = c.Reason
+		if c.Message != "" {
+			rollout += ": " + c.Message
+		}
+	}
+
+	return map[string]any{
+		"kind":      "Deployment",
+		"name":      dp.Namespace + "/" + dp.Name,
+		"desired":   desired,
+		"updated":   dp.Status.UpdatedReplicas,
+		"ready":     dp.Status.ReadyReplicas,
+		"available": dp.Status.AvailableReplicas,
+		"paused":    dp.Spec.Paused,
+		"rollout":   rollout,
+	}
+}
+
+func statefulSetSummary(sts *appsv1.StatefulSet) map[string]any {
+	desired := int32(1)
+	if sts.Spec.Replicas != nil {
+		desired = *sts.Spec.Replicas
+	}
+	rollout := "Complete"
+	if sts.Status.UpdateRevision != "" && sts.Status.CurrentRevision != sts.Status.UpdateRevision {
+		rollout = fmt.Sprintf("In progress: %d/%d updated to revision %s",
+			sts.Status.UpdatedReplicas, desired, sts.Status.UpdateRevision)
+	}
+
+	return map[string]any{
+		"kind":      "StatefulSet",
+		"name":      sts.Namespace + "/" + sts.Name,
+		"desired":   desired,
+		"updated":   sts.Status.UpdatedReplicas,
+		"ready":     sts.Status.ReadyReplicas,
+		"available": sts.Status.AvailableReplicas,
+		"rollout":   rollout,
+	}
+}
+
+func daemonSetSummary(ds *appsv1.DaemonSet) map[string]any {
+	rollout := "Complete"
+	if ds.Status.UpdatedNumberScheduled < ds.Status.DesiredNumberScheduled {
+		rollout = fmt.Sprintf("In progress: %d/%d nodes updated",
+			ds.Status.UpdatedNumberScheduled, ds.Status.DesiredNumberScheduled)
+	}
+
+	return map[string]any{
+		"kind":         "DaemonSet",
+		"name":         ds.Namespace + "/" + ds.Name,
+		"desired":      ds.Status.DesiredNumberScheduled,
+		"updated":      ds.Status.UpdatedNumberScheduled,
+		"ready":        ds.Status.NumberReady,
+		"available":    ds.Status.NumberAvailable,
+		"misscheduled": ds.Status.NumberMisscheduled,
+		"rollout":      rollout,
+	}
+}
+
+// podHealth aggregates pod states and returns the reasons for unhealthy pods.
+func podHealth(pods []corev1.Pod) (workloadPods, map[string]string) {
+	var (
+		h         = workloadPods{Total: len(pods)}
+		unhealthy = make(map[string]string)
+	)
+	for i := range pods {
+		p := &pods[i]
+		reason := ""
+		for _, cs := range p.Status.ContainerStatuses {
+			h.Restarts += int(cs.RestartCount)
+			if w := cs.State.Waiting; w != nil && reason == "" {
+				reason = w.Reason
+			}
+		}
+		if isPodReady(p) {
+			h.Ready++
+		}
+
+		switch {
+		case p.Status.Phase == corev1.PodSucceeded:
+			h.Succeeded++
+		case p.Status.Phase == corev1.PodFailed:
+			h.Failed++
+			unhealthy[p.Name] = "Failed: " + p.Status.Reason
+		case isCrashReason(reason):
+			h.Crashing++
+			unhealthy[p.Name] = reason
+		case p.Status.Phase == corev1.PodPending:
+			h.Pending++
+			if reason == "" {
+				reason = "Pending"
+			}
+			unhealthy[p.Name] = reason
+		default:
+			h.Running++
+		}
+	}
+
+	return h, unhealthy
+}
+
+func isPodReady(p *corev1.Pod) bool {
+	for _, c := range p.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+func isCrashReason(reason string) bool {
+	switch reason {
+	case "CrashLoopBackOff", "Error", "CreateContainerConfigError", "RunContainerError":
+		return true
+	default:
+		return false
+	}
+}
+
+// workloadWarnings returns the most recent warning messages involving the
+// workload, its pods or its replica sets.
+func workloadWarnings(events []corev1.Event, name string, pods []string) []string {
+	rel := make([]corev1.Event, 0, len(events))
+	for _, ev := range events {
+		n := ev.InvolvedObject.Name
+		if n == name || slices.Contains(pods, n) || isReplicaSetOf(&ev.InvolvedObject, name) {
+			rel = append(rel, ev)
+		}
+	}
+	sort.SliceStable(rel, func(i, j int) bool {
+		return eventTime(&rel[i]).After(eventTime(&rel[j]))
+	})
+
+	out := make([]string, 0, min(len(rel), maxWorkloadEvents))
+	for i := range rel {
+		if i == maxWorkloadEvents {
+			break
+		}
+		ev := &rel[i]
+		out = append(out, fmt.Sprintf("%s %s/%s %s: %s",
+			toolTime(eventTime(ev)),
+			ev.InvolvedObject.Kind, ev.InvolvedObject.Name, ev.Reason, ev.Message))
+	}
+
+	return out
+}
+
+// isReplicaSetOf checks if ref names a replica set of the deployment, ie
+// <name>-<pod-template-hash>. The hash never holds a dash, so app's sets
+// don't pick up app-canary's.
+func isReplicaSetOf(ref *corev1.ObjectReference, name string) bool {
+	if ref.Kind != "ReplicaSet" {
+		return false
+	}
+	hash, ok := strings.CutPrefix(ref.Name, name+"-")
+
+	return ok && hash != "" && !strings.Contains(hash, "-")
+}
@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodHealth(t *testing.T) {
+	pods := []corev1.Pod{
+		makeWorkloadPod("p1", corev1.PodRunning, true, "", 0),
+		makeWorkloadPod("p2", corev1.PodRunning, false, "CrashLoopBackOff", 5),
+		makeWorkloadPod("p3", corev1.PodPending, false, "ImagePullBackOff", 0),
+		makeWorkloadPod("p4", corev1.PodPending, false, "", 0),
+		makeWorkloadPod("p5", corev1.PodSucceeded, false, "", 1),
+	}
+
+	h, unhealthy := podHealth(pods)
+
+	assert.Equal(t, workloadPods{
+		Total:     5,
+		Ready:     1,
+		Running:   1,
+		Crashing:  1,
+		Pending:   2,
+		Succeeded: 1,
+		Restarts:  6,
+	}, h)
+	assert.Equal(t, map[string]string{
+		"p2": "CrashLoopBackOff",
+		"p3": "ImagePullBackOff",
+		"p4": "Pending",
+	}, unhealthy)
+}
+
+func TestWorkloadWarnings(t *testing.T) {
+	orig := timeNow
+	defer func() { timeNow = orig }()
+	timeNow = func() time.Time { return toolNow.Add(time.Hour) }
+
+	now := toolNow
+	ev := func(kind, name, reason string, age time.Duration) corev1.Event {
+		return corev1.Event{
+			InvolvedObject: corev1.ObjectReference{Kind: kind, Name: name},
+			Reason:         reason,
+			Message:        "boom",
+			LastTimestamp:  metav1.NewTime(now.Add(-age)),
+		}
+	}
+	events := []corev1.Event{
+		ev("ReplicaSet", "fred-abc", "FailedCreate", 2*time.Minute),
+		ev("Pod", "blee", "Unrelated", time.Minute),
+		ev("Deployment", "fred", "ProgressDeadline", 3*time.Minute),
+		ev("Pod", "p1", "Unhealthy", 0),
+		ev("Deployment", "fred-canary", "ProgressDeadline", 4*time.Minute),
+		ev("ReplicaSet", "fred-canary-abc", "FailedCreate", 5*time.Minute),
+		ev("Pod", "fred-canary-abc-x1", "BackOff", 6*time.Minute),
+	}
+
+	assert.Equal(t, []string{
+		"2024-05-01T10:00:00Z (60m ago) Pod/p1 Unhealthy: boom",
+		"2024-05-01T09:58:00Z (62m ago) ReplicaSet/fred-abc FailedCreate: boom",
+		"2024-05-01T09:57:00Z (63m ago) Deployment/fred ProgressDeadline: boom",
+	}, workloadWarnings(events, "fred", []string{"p1"}))
+}
+
+func makeWorkloadPod(name string, phase corev1.PodPhase, ready bool, waiting string, restarts int32) corev1.Pod {
+	cs := corev1.ContainerStatus{Name: "c1", RestartCount: restarts}
+	if waiting != "" {
+		cs.State.Waiting = &corev1.ContainerStateWaiting{Reason: waiting}
+	}
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.PodStatus{
+			Phase:             phase,
+			Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+			ContainerStatuses: []corev1.ContainerStatus{cs},
+		},
+	}
+}
@@ -3,7 +3,170 @@
 
 package config
 
-import "os"
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/url"
+	"os"
+	"path"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/derailed/k9s/internal/slogs"
+)
+
+// ScaleToZeroAction is a confirm policy action matching scale_resource calls
+// that set replicas to 0.
+const ScaleToZeroAction = "scale_to_zero"
+
+// DefaultAIMaxToolCalls is the default per-turn tool call budget.
+const DefaultAIMaxToolCalls = 25
+
+// DefaultAIMaxRetries is the default number of times a turn is resent after
+// a transient provider error.
+const DefaultAIMaxRetries = 2
+
+// DefaultAIRequestTimeoutSeconds is the default time a chat turn may take.
+const DefaultAIRequestTimeoutSeconds = 300
+
+// MinAIRequestTimeoutSeconds is the shortest time a chat turn may take.
+const MinAIRequestTimeoutSeconds = 10
+
+// MaxAIPanelModels caps the models a panel asks, each answer costing the
+// tokens of a full turn.
+const MaxAIPanelModels = 4
+
+// DefaultAIMaxContextLines is the default number of log lines get_logs
+// returns when the model doesn't ask for a tail.
+const DefaultAIMaxContextLines = 500
+
+// DefaultAIClusterHealthMaxPods is the default number of pods
+// get_cluster_health scans before reporting partial counts.
+const DefaultAIClusterHealthMaxPods = 10_000
+
+// DefaultAIHealthRefresh is the default number of seconds between two health
+// badge scorings of a table.
+const DefaultAIHealthRefresh = 30
+
+const (
+	// DefaultAIDiagnosePrompt is the prompt used to diagnose a resource.
+	DefaultAIDiagnosePrompt = "Diagnose the {kind} '{name}' in namespace '{namespace}'. Check its status, recent events, logs if applicable, and suggest fixes for any issues."
+
+	// DefaultAIExplainPrompt is the prompt used to explain a resource.
+	DefaultAIExplainPrompt = "Explain the {kind} '{name}' in namespace '{namespace}'. Describe its current state, configuration, and how it relates to other resources. Highlight anything unusual."
+)
+
+const (
+	// AIPrefetchSummary prefetches the workload rollout and pod health summary.
+	AIPrefetchSummary = "summary"
+
+	// AIPrefetchPods prefetches the status of each pod.
+	AIPrefetchPods = "pods"
+
+	// AIPrefetchEvents prefetches recent warning events.
+	AIPrefetchEvents = "events"
+)
+
+// AIPrefetchParts lists the supported context bundle parts.
+var AIPrefetchParts = []string{AIPrefetchSummary, AIPrefetchPods, AIPrefetchEvents}
+
+const (
+	// AISendWait blocks on SendAndWait and takes the final assistant message
+	// as the answer. It holds up even when a provider doesn't stream, but the
+	// answer only lands once the final message arrives.
+	AISendWait = "wait"
+
+	// AISendStream fires the prompt and assembles the answer from streamed
+	// deltas until the session goes idle. The answer matches what the chat
+	// showed, including text written ahead of tool calls, but relies on the
+	// provider streaming deltas.
+	AISendStream = "stream"
+)
+
+// AISendModes lists the supported send modes.
+var AISendModes = []string{AISendWait, AISendStream}
+
+const (
+	// AIAudienceSRE gets terse, command-oriented answers.
+	AIAudienceSRE = "sre"
+
+	// AIAudienceBeginner gets detailed answers explaining concepts and
+	// commands along the way.
+	AIAudienceBeginner = "beginner"
+
+	// AIAudienceExec gets high-level summaries of impact and next steps.
+	AIAudienceExec = "exec"
+)
+
+// AILogFilterErrors filters logs down to errors, warnings and stack traces.
+const AILogFilterErrors = "errors"
+
+// DefaultAIExecAllowlist lists the command prefixes exec_command runs when
+// ai.execAllowlist is not set: read-only inspection commands.
+var DefaultAIExecAllowlist = []string{
+	"cat", "ls", "head", "tail", "df", "ps", "ss", "netstat", "nslookup", "getent",
+}
+
+// AIExecDeniedPaths lists the paths exec_command never touches, whatever the
+// allowlist: where the kubelet mounts service account tokens and secrets.
+var AIExecDeniedPaths = []string{"/var/run/secrets", "/run/secrets"}
+
+// AIAudiences lists the supported answer audiences, in toggle order.
+var AIAudiences = []string{AIAudienceSRE, AIAudienceBeginner, AIAudienceExec}
+
+// AICopilotLogLevels lists the log levels supported by the Copilot CLI server.
+var AICopilotLogLevels = []string{"none", "error", "warning", "info", "debug", "all"}
+
+// PromptPlaceholders lists the placeholders supported by prompt templates.
+var PromptPlaceholders = []string{"{kind}", "{name}", "{namespace}"}
+
+var promptPlaceholderRx = regexp.MustCompile(`\{[a-zA-Z]+\}`)
+
+// providerPresets tracks the defaults filled in by ai.provider.preset.
+// Explicit provider fields always take precedence.
+var providerPresets = map[string]AIProvider{
+	"ollama": {
+		Type:    "openai",
+		BaseURL: "http://localhost:11434/v1",
+		WireAPI: "completions",
+	},
+	"openai": {
+		Type:    "openai",
+		BaseURL: "https://api.openai.com/v1",
+		WireAPI: "completions",
+	},
+	// Azure endpoints are per resource so the base URL must be set explicitly.
+	"azure": {
+		Type:    "azure",
+		WireAPI: "completions",
+	},
+	"anthropic": {
+		Type:    "anthropic",
+		BaseURL: "https://api.anthropic.com",
+	},
+}
+
+var (
+	providerTypes    = []string{"openai", "azure", "anthropic"}
+	providerWireAPIs = []string{"completions", "responses"}
+)
+
+// providerWireAPIAliases maps the common spellings of the wire APIs to
+// their canonical names.
+var providerWireAPIAliases = map[string]string{
+	"chat":             "completions",
+	"chat-completions": "completions",
+	"chat_completions": "completions",
+	"chatcompletions":  "completions",
+	"completion":       "completions",
+	"response":         "responses",
+}
 
 // AI tracks AI/Copilot configuration options.
 type AI struct {
@@ -16,6 +179,391 @@ type AI struct {
 	ReasoningEffort string      `json:"reasoningEffort,omitempty" yaml:"reasoningEffort,omitempty"`
 	ActiveSkill     string      `json:"activeSkill,omitempty" yaml:"activeSkill,omitempty"`
 	GitHubToken     string      `json:"githubToken,omitempty" yaml:"githubToken,omitempty"`
+	MaxWidth        int         `json:"maxWidth,omitempty" yaml:"maxWidth,omitempty"`
+	// SummarizeToolOutput condenses oversized tool results before they reach the model.
+	SummarizeToolOutput bool `json:"summarizeToolOutput,omitempty" yaml:"summarizeToolOutput,omitempty"`
+	// ConfirmPolicy lists mutations that require typing the resource name to approve.
+	ConfirmPolicy []AIConfirmRule `json:"confirmPolicy,omitempty" yaml:"confirmPolicy,omitempty"`
+	// MaxToolCalls caps the number of tool calls the model may make per turn.
+	MaxToolCalls int `json:"maxToolCalls,omitempty" yaml:"maxToolCalls,omitempty"`
+	// MaxRetries is the number of times a turn is resent after a transient
+	// provider error, e.g. a 429 or a 5xx. Defaults to DefaultAIMaxRetries,
+	// a negative value disables retries.
+	MaxRetries int `json:"maxRetries,omitempty" yaml:"maxRetries,omitempty"`
+	// RequestTimeoutSeconds bounds a chat turn, retries and tool calls
+	// included. Defaults to DefaultAIRequestTimeoutSeconds.
+	RequestTimeoutSeconds int `json:"requestTimeoutSeconds,omitempty" yaml:"requestTimeoutSeconds,omitempty"`
+	// ClusterHealthMaxPods caps the pods get_cluster_health scans. Past it,
+	// pod counts come from a partial scan. Defaults to
+	// DefaultAIClusterHealthMaxPods.
+	ClusterHealthMaxPods int `json:"clusterHealthMaxPods,omitempty" yaml:"clusterHealthMaxPods,omitempty"`
+	// HistoryTokenBudget caps the estimated tokens of a chat session. Past
+	// it, the chat starts a new session carrying only the latest turns. Zero
+	// leaves context management to the server.
+	HistoryTokenBudget int `json:"historyTokenBudget,omitempty" yaml:"historyTokenBudget,omitempty"`
+	// AuditSink mirrors every chat turn to a JSONL file, or POSTs it as JSON
+	// when it is an http(s) URL.
+	AuditSink string `json:"auditSink,omitempty" yaml:"auditSink,omitempty"`
+	// LogFilter is the get_logs grep used when the model doesn't pass one:
+	// AILogFilterErrors or a regular expression.
+	LogFilter string `json:"logFilter,omitempty" yaml:"logFilter,omitempty"`
+	// ShowReasoning streams the model's reasoning into the chat as a separate block.
+	ShowReasoning bool `json:"showReasoning,omitempty" yaml:"showReasoning,omitempty"`
+	// ApprovePlans asks users to approve plans with mutating steps declared via
+	// report_intent before the model carries them out.
+	ApprovePlans bool `json:"approvePlans,omitempty" yaml:"approvePlans,omitempty"`
+	// RequireConsent asks users to acknowledge that cluster data is sent to the
+	// AI service before their first chat of the session.
+	RequireConsent bool `json:"requireConsent,omitempty" yaml:"requireConsent,omitempty"`
+	// Prewarm starts the Copilot CLI and creates the chat session in the
+	// background at startup so the first question is answered right away.
+	Prewarm bool `json:"prewarm,omitempty" yaml:"prewarm,omitempty"`
+	// PersistHistory saves chat histories to AppChatHistoryFile so they
+	// survive restarts. Off by default since chats may hold cluster data.
+	PersistHistory bool `json:"persistHistory,omitempty" yaml:"persistHistory,omitempty"`
+	// DiagnosePrompt overrides the diagnose quick-start prompt. See PromptPlaceholders.
+	DiagnosePrompt string `json:"diagnosePrompt,omitempty" yaml:"diagnosePrompt,omitempty"`
+	// ExplainPrompt overrides the explain quick-start prompt. See PromptPlaceholders.
+	ExplainPrompt string `json:"explainPrompt,omitempty" yaml:"explainPrompt,omitempty"`
+	// PrometheusURL is queried for usage metrics in place of metrics-server when set.
+	PrometheusURL string `json:"prometheusURL,omitempty" yaml:"prometheusURL,omitempty"`
+	// RunbooksDir is where chat runbooks are saved. Defaults to AppRunbooksDir.
+	RunbooksDir string `json:"runbooksDir,omitempty" yaml:"runbooksDir,omitempty"`
+	// Prefetch loads a context bundle when a resource scoped chat opens.
+	Prefetch *AIPrefetch `json:"prefetch,omitempty" yaml:"prefetch,omitempty"`
+	// Redaction tunes the masking of credentials in tool results.
+	Redaction *AIRedaction `json:"redaction,omitempty" yaml:"redaction,omitempty"`
+	// HealthBadges shows a health badge column in AI enabled resource tables.
+	HealthBadges *AIHealthBadges `json:"healthBadges,omitempty" yaml:"healthBadges,omitempty"`
+	// AllowedNamespaces confines AI tools to the namespaces matching these
+	// names or globs. Empty allows all namespaces.
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty" yaml:"allowedNamespaces,omitempty"`
+	// CopilotLogLevel sets the Copilot CLI server log level. Defaults to error.
+	CopilotLogLevel string `json:"copilotLogLevel,omitempty" yaml:"copilotLogLevel,omitempty"`
+	// NpmRegistry is the npm registry the Copilot CLI is downloaded from,
+	// e.g. an internal mirror. Defaults to NPM_CONFIG_REGISTRY, then the
+	// public registry.
+	NpmRegistry string `json:"npmRegistry,omitempty" yaml:"npmRegistry,omitempty"`
+	// SendMode picks how answers are collected, see AISendModes. Defaults
+	// to AISendWait.
+	SendMode string `json:"sendMode,omitempty" yaml:"sendMode,omitempty"`
+	// Audience tunes the answers for their reader, see AIAudiences.
+	// Defaults to AIAudienceSRE.
+	Audience string `json:"audience,omitempty" yaml:"audience,omitempty"`
+	// PanelModels lists the models the /panel chat command asks, up to
+	// MaxAIPanelModels.
+	PanelModels []string `json:"panelModels,omitempty" yaml:"panelModels,omitempty"`
+	// SkillByKind maps resource kinds to the skill picked for their scoped
+	// chats, overriding ActiveSkill.
+	SkillByKind map[string]string `json:"skillByKind,omitempty" yaml:"skillByKind,omitempty"`
+	// CustomSkills adds user-defined skills next to the built-in ones.
+	CustomSkills []AISkillConfig `json:"customSkills,omitempty" yaml:"customSkills,omitempty"`
+	// AllowExec registers the exec_command tool, which runs commands in
+	// containers. Off by default.
+	AllowExec bool `json:"allowExec,omitempty" yaml:"allowExec,omitempty"`
+	// ExecAllowlist lists the command prefixes exec_command may run, e.g.
+	// "cat /etc/nginx". Defaults to DefaultAIExecAllowlist. Commands touching
+	// AIExecDeniedPaths or the pod's secret mounts are rejected regardless.
+	ExecAllowlist []string `json:"execAllowlist,omitempty" yaml:"execAllowlist,omitempty"`
+	// Contexts overrides the AI settings per kube context name, e.g. to use
+	// a local BYOK endpoint on prod clusters. See ForContext.
+	Contexts map[string]AIContext `json:"contexts,omitempty" yaml:"contexts,omitempty"`
+}
+
+// AIContext overrides the AI settings for a kube context. Unset fields keep
+// the global values.
+type AIContext struct {
+	Enabled         *bool       `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	Model           string      `json:"model,omitempty" yaml:"model,omitempty"`
+	Provider        *AIProvider `json:"provider,omitempty" yaml:"provider,omitempty"`
+	ReasoningEffort string      `json:"reasoningEffort,omitempty" yaml:"reasoningEffort,omitempty"`
+}
+
+// AISkillConfig defines a custom skill: the tools the assistant may use and
+// the instructions it gets while the skill is active.
+type AISkillConfig struct {
+	Name        string   `json:"name" yaml:"name"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	ToolNames   []string `json:"toolNames" yaml:"toolNames"`
+	// SystemSuffix is appended to the system message of the skill's sessions.
+	SystemSuffix string `json:"systemSuffix,omitempty" yaml:"systemSuffix,omitempty"`
+	// ReasoningEffort overrides ai.reasoningEffort while the skill is active.
+	ReasoningEffort string `json:"reasoningEffort,omitempty" yaml:"reasoningEffort,omitempty"`
+}
+
+// AIRedaction configures how credentials are masked in tool results before
+// they are sent to the model. Built-in patterns are always on unless disabled.
+type AIRedaction struct {
+	// Disabled turns redaction off altogether.
+	Disabled bool `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+	// Patterns lists extra regexes to mask. When a pattern has a capture
+	// group, only the first group is masked.
+	Patterns []string `json:"patterns,omitempty" yaml:"patterns,omitempty"`
+	// Keys lists extra words flagging a key or env var name as sensitive.
+	Keys []string `json:"keys,omitempty" yaml:"keys,omitempty"`
+}
+
+// AIPrefetch configures the context bundle injected into scoped chats.
+type AIPrefetch struct {
+	// Enabled loads the bundle when a scoped chat opens.
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// Include lists the bundle parts. Defaults to all AIPrefetchParts.
+	Include []string `json:"include,omitempty" yaml:"include,omitempty"`
+}
+
+// AIHealthBadges configures the health badges shown in resource tables.
+// Rows are scored with local heuristics, the model is only asked about rows
+// the heuristics can't tell.
+type AIHealthBadges struct {
+	// Enabled adds the badge column to AI enabled tables.
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// RefreshSeconds is how often rows are rescored. Defaults to
+	// DefaultAIHealthRefresh.
+	RefreshSeconds int `json:"refreshSeconds,omitempty" yaml:"refreshSeconds,omitempty"`
+	// ModelAssist asks the model to grade the rows the heuristics can't,
+	// at most once per refresh.
+	ModelAssist bool `json:"modelAssist,omitempty" yaml:"modelAssist,omitempty"`
+}
+
+// AIConfirmRule requires a typed confirmation for matching mutations.
+// Empty fields match everything.
+type AIConfirmRule struct {
+	// Actions lists mutation tool names (e.g. delete_resource) or scale_to_zero.
+	Actions []string `json:"actions,omitempty" yaml:"actions,omitempty"`
+	// Namespace is a regex matched against the target namespace.
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	// Name is a regex matched against the target resource name.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+}
+
+// Matches returns true if the rule applies to the given action and target.
+func (r AIConfirmRule) Matches(action, ns, name string) bool {
+	if len(r.Actions) > 0 && !slices.Contains(r.Actions, action) {
+		return false
+	}
+
+	return matchRx(r.Namespace, ns) && matchRx(r.Name, name)
+}
+
+func matchRx(expr, s string) bool {
+	if expr == "" {
+		return true
+	}
+	rx, err := regexp.Compile("^(?:" + expr + ")$")
+
+	return err == nil && rx.MatchString(s)
+}
+
+// RequiresTypedConfirm returns true if any confirm policy rule matches the
+// mutation. Scaling to zero replicas also matches rules for scale_to_zero.
+func (a AI) RequiresTypedConfirm(action, ns, name string, replicas int) bool {
+	for _, r := range a.ConfirmPolicy {
+		if r.Matches(action, ns, name) {
+			return true
+		}
+		if action == "scale_resource" && replicas == 0 && r.Matches(ScaleToZeroAction, ns, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ToolCallBudget returns the per-turn tool call limit.
+func (a AI) ToolCallBudget() int {
+	if a.MaxToolCalls <= 0 {
+		return DefaultAIMaxToolCalls
+	}
+
+	return a.MaxToolCalls
+}
+
+// SendRetries returns the number of times a failed turn may be resent.
+func (a AI) SendRetries() int {
+	switch {
+	case a.MaxRetries < 0:
+		return 0
+	case a.MaxRetries == 0:
+		return DefaultAIMaxRetries
+	default:
+		return a.MaxRetries
+	}
+}
+
+// RequestTimeout returns the time a chat turn may take.
+func (a AI) RequestTimeout() time.Duration {
+	if a.RequestTimeoutSeconds <= 0 {
+		return DefaultAIRequestTimeoutSeconds * time.Second
+	}
+
+	return time.Duration(max(a.RequestTimeoutSeconds, MinAIRequestTimeoutSeconds)) * time.Second
+}
+
+// ExecAllowed checks a command starts with one of the allowed prefixes,
+// compared word by word so "cat" doesn't allow "catch".
+func (a AI) ExecAllowed(cmd []string) bool {
+	if !a.AllowExec || len(cmd) == 0 {
+		return false
+	}
+	allow := a.ExecAllowlist
+	if len(allow) == 0 {
+		allow = DefaultAIExecAllowlist
+	}
+
+	if _, ok := ExecTouches(cmd, AIExecDeniedPaths); ok {
+		return false
+	}
+
+	return slices.ContainsFunc(allow, func(prefix string) bool {
+		ff := strings.Fields(prefix)
+		return len(ff) > 0 && len(ff) <= len(cmd) && slices.Equal(ff, cmd[:len(ff)])
+	})
+}
+
+// ExecTouches returns the first command argument referring to a path under
+// one of dirs. Arguments are cleaned so "/etc/../run/secrets" and flag values
+// like --file=/run/secrets match too. Relative paths are resolved from /.
+func ExecTouches(cmd, dirs []string) (string, bool) {
+	for _, arg := range cmd {
+		pp := []string{arg}
+		if _, v, ok := strings.Cut(arg, "="); ok {
+			pp = append(pp, v)
+		}
+		for _, p := range pp {
+			p = path.Clean("/"+p) + "/"
+			for _, d := range dirs {
+				if strings.Contains(p, path.Clean("/"+d)+"/") {
+					return arg, true
+				}
+			}
+		}
+	}
+
+	return "", false
+}
+
+// ContextLines returns the number of log lines get_logs returns by default.
+func (a AI) ContextLines() int {
+	if a.MaxContextLines <= 0 {
+		return DefaultAIMaxContextLines
+	}
+
+	return a.MaxContextLines
+}
+
+// ClusterHealthPodCap returns the number of pods get_cluster_health scans.
+func (a AI) ClusterHealthPodCap() int {
+	if a.ClusterHealthMaxPods <= 0 {
+		return DefaultAIClusterHealthMaxPods
+	}
+
+	return a.ClusterHealthMaxPods
+}
+
+// PrefetchParts returns the context bundle parts to load, none when
+// prefetching is off.
+func (a AI) PrefetchParts() []string {
+	if a.Prefetch == nil || !a.Prefetch.Enabled {
+		return nil
+	}
+	if len(a.Prefetch.Include) == 0 {
+		return AIPrefetchParts
+	}
+
+	return a.Prefetch.Include
+}
+
+// HealthBadgesEnabled returns true if tables show health badges.
+func (a AI) HealthBadgesEnabled() bool {
+	return a.HealthBadges != nil && a.HealthBadges.Enabled
+}
+
+// HealthRefresh returns the delay between two health badge scorings.
+func (a AI) HealthRefresh() time.Duration {
+	if a.HealthBadges == nil || a.HealthBadges.RefreshSeconds <= 0 {
+		return DefaultAIHealthRefresh * time.Second
+	}
+
+	return time.Duration(a.HealthBadges.RefreshSeconds) * time.Second
+}
+
+// NamespaceAllowed reports whether AI tools may inspect the given namespace.
+func (a AI) NamespaceAllowed(ns string) bool {
+	if len(a.AllowedNamespaces) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(a.AllowedNamespaces, func(pattern string) bool {
+		ok, _ := path.Match(pattern, ns)
+		return ok
+	})
+}
+
+// SkillFor returns the skill mapped to a resource kind in skillByKind, if any.
+// Kinds match case-insensitively, in singular or plural form. When several
+// keys match, the first one in sorted order wins.
+func (a AI) SkillFor(kind string) string {
+	if kind == "" {
+		return ""
+	}
+	k := singularKind(kind)
+	for _, key := range slices.Sorted(maps.Keys(a.SkillByKind)) {
+		if singularKind(key) == k {
+			return a.SkillByKind[key]
+		}
+	}
+
+	return ""
+}
+
+// singularKind lowers a kind or resource name and strips its plural suffix.
+func singularKind(kind string) string {
+	k := strings.ToLower(kind)
+	switch {
+	case strings.HasSuffix(k, "ies"):
+		return strings.TrimSuffix(k, "ies") + "y"
+	case strings.HasSuffix(k, "sses"), strings.HasSuffix(k, "xes"):
+		return strings.TrimSuffix(k, "es")
+	case strings.HasSuffix(k, "s") && !strings.HasSuffix(k, "ss"):
+		return strings.TrimSuffix(k, "s")
+	default:
+		return k
+	}
+}
+
+// RunbooksPath returns the directory runbooks are saved to.
+func (a AI) RunbooksPath() string {
+	return cmp.Or(a.RunbooksDir, AppRunbooksDir)
+}
+
+// DiagnosePromptFor renders the diagnose prompt for the given resource.
+func (a AI) DiagnosePromptFor(kind, name, ns string) string {
+	return renderPrompt(cmp.Or(a.DiagnosePrompt, DefaultAIDiagnosePrompt), kind, name, ns)
+}
+
+// ExplainPromptFor renders the explain prompt for the given resource.
+func (a AI) ExplainPromptFor(kind, name, ns string) string {
+	return renderPrompt(cmp.Or(a.ExplainPrompt, DefaultAIExplainPrompt), kind, name, ns)
+}
+
+func renderPrompt(tpl, kind, name, ns string) string {
+	return strings.NewReplacer("{kind}", kind, "{name}", name, "{namespace}", ns).Replace(tpl)
+}
+
+// validatePrompt checks a prompt template only uses known placeholders.
+func validatePrompt(tpl string) error {
+	var unknown []string
+	for _, p := range promptPlaceholderRx.FindAllString(tpl, -1) {
+		if !slices.Contains(PromptPlaceholders, p) && !slices.Contains(unknown, p) {
+			unknown = append(unknown, p)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("unknown placeholder(s) %s, supported: %s",
+			strings.Join(unknown, ", "), strings.Join(PromptPlaceholders, ", "))
+	}
+
+	return nil
 }
 
 // IsEnabled returns true if AI is enabled (defaults to true when not explicitly set).
@@ -25,6 +573,8 @@ func (a AI) IsEnabled() bool {
 
 // AIProvider tracks BYOK (Bring Your Own Key) provider configuration.
 type AIProvider struct {
+	// Preset fills in defaults for a well known provider (ollama, openai, azure, anthropic).
+	Preset      string              `json:"preset,omitempty" yaml:"preset,omitempty"`
 	Type        string              `json:"type" yaml:"type"`
 	BaseURL     string              `json:"baseURL" yaml:"baseURL"`
 	APIKey      string              `json:"apiKey,omitempty" yaml:"apiKey,omitempty"`
@@ -38,6 +588,105 @@ type AzureProviderOpts struct {
 	APIVersion string `json:"apiVersion,omitempty" yaml:"apiVersion,omitempty"`
 }
 
+// applyPreset fills unset fields from the provider preset if any.
+func (p *AIProvider) applyPreset() error {
+	if p.Preset == "" {
+		return nil
+	}
+	pre, ok := providerPresets[strings.ToLower(p.Preset)]
+	if !ok {
+		return fmt.Errorf("unknown AI provider preset %q (valid: %s)",
+			p.Preset, strings.Join(slices.Sorted(maps.Keys(providerPresets)), ", "))
+	}
+	if p.Type == "" {
+		p.Type = pre.Type
+	}
+	if p.BaseURL == "" {
+		p.BaseURL = pre.BaseURL
+	}
+	if p.WireAPI == "" {
+		p.WireAPI = pre.WireAPI
+	}
+
+	return nil
+}
+
+// validate checks the provider settings are coherent and normalizes the
+// wire API. All problems are reported at once so they can be fixed in one go.
+func (p *AIProvider) validate() error {
+	var errs []string
+	if err := p.applyPreset(); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	p.Type = strings.ToLower(strings.TrimSpace(p.Type))
+	p.BaseURL = strings.TrimSpace(p.BaseURL)
+	p.WireAPI = strings.ToLower(strings.TrimSpace(p.WireAPI))
+	if w, ok := providerWireAPIAliases[p.WireAPI]; ok {
+		p.WireAPI = w
+	}
+	if p.WireAPI != "" && !slices.Contains(providerWireAPIs, p.WireAPI) {
+		errs = append(errs, fmt.Sprintf("ai.provider.wireApi %q is not supported (valid: %s)",
+			p.WireAPI, strings.Join(providerWireAPIs, ", ")))
+		p.WireAPI = ""
+	}
+	// Only OpenAI style APIs pick a wire API.
+	if p.Type == "anthropic" {
+		p.WireAPI = ""
+	}
+	// An Azure block without a version is as good as none.
+	if p.Azure != nil && strings.TrimSpace(p.Azure.APIVersion) == "" {
+		p.Azure = nil
+	}
+
+	switch {
+	case p.Type == "" && p.BaseURL != "":
+		errs = append(errs, fmt.Sprintf("ai.provider.type is required when baseURL is set (valid: %s)",
+			strings.Join(providerTypes, ", ")))
+	case p.Type != "" && !slices.Contains(providerTypes, p.Type):
+		errs = append(errs, fmt.Sprintf("ai.provider.type %q is not supported (valid: %s)",
+			p.Type, strings.Join(providerTypes, ", ")))
+	case p.Type != "" && p.BaseURL == "":
+		errs = append(errs, fmt.Sprintf("ai.provider.baseURL is required for provider type %q", p.Type))
+	}
+	if p.BaseURL != "" && !isLocalURL(p.BaseURL) && p.ResolveAPIKey() == "" && p.ResolveBearerToken() == "" {
+		errs = append(errs, "ai.provider.apiKey or bearerToken is required for remote providers (or set K9S_AI_API_KEY/K9S_AI_BEARER_TOKEN)")
+	}
+	if p.Type == "azure" && (p.Azure == nil || p.Azure.APIVersion == "") {
+		errs = append(errs, "ai.provider.azure.apiVersion is required for azure providers (e.g. 2024-10-21)")
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errors.New(strings.Join(errs, "; "))
+}
+
+// isLocalURL returns true when the URL points to the local machine, as for
+// Ollama, where no credentials are needed.
+func isLocalURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	switch u.Hostname() {
+	case "localhost", "127.0.0.1", "::1":
+		return true
+	default:
+		return false
+	}
+}
+
+// ProviderError returns the BYOK provider configuration problems if any.
+func (a AI) ProviderError() error {
+	if a.Provider == nil {
+		return nil
+	}
+	p := *a.Provider
+
+	return p.validate()
+}
+
 // ResolveAPIKey returns the API key from config or the K9S_AI_API_KEY env var.
 func (p *AIProvider) ResolveAPIKey() string {
 	if p.APIKey != "" {
@@ -54,6 +703,50 @@ func (p *AIProvider) ResolveBearerToken() string {
 	return os.Getenv("K9S_AI_BEARER_TOKEN")
 }
 
+// DataDestination describes the service chat prompts and tool results are sent to.
+func (a AI) DataDestination() string {
+	if !a.IsBYOK() {
+		return "GitHub Copilot"
+	}
+	if a.Provider.Type == "" {
+		return a.Provider.BaseURL
+	}
+
+	return fmt.Sprintf("%s provider at %s", a.Provider.Type, a.Provider.BaseURL)
+}
+
+// ForContext returns the AI settings in effect for the given kube context:
+// the global settings overlaid with the context overrides if any.
+func (a AI) ForContext(name string) AI {
+	o, ok := a.Contexts[name]
+	if !ok {
+		return a
+	}
+	if o.Enabled != nil {
+		a.Enabled = o.Enabled
+	}
+	if o.Model != "" {
+		a.Model = o.Model
+	}
+	if o.Provider != nil {
+		a.Provider = o.Provider
+	}
+	if o.ReasoningEffort != "" {
+		a.ReasoningEffort = o.ReasoningEffort
+	}
+
+	return a
+}
+
+// ClientChanged returns true when b differs from a in a setting the AI
+// client is built with, so the client must be restarted to pick it up.
+func (a AI) ClientChanged(b AI) bool {
+	return a.IsEnabled() != b.IsEnabled() ||
+		a.Model != b.Model ||
+		a.ReasoningEffort != b.ReasoningEffort ||
+		!reflect.DeepEqual(a.Provider, b.Provider)
+}
+
 // IsBYOK returns true when a BYOK (Bring Your Own Key) provider is configured.
 func (a AI) IsBYOK() bool {
 	return a.Provider != nil && a.Provider.BaseURL != ""
@@ -71,7 +764,7 @@ func NewAI() AI {
 		Enabled:         boolPtr(true),
 		Model:           "gpt-4.1",
 		Streaming:       true,
-		MaxContextLines: 500,
+		MaxContextLines: DefaultAIMaxContextLines,
 		AutoDiagnose:    false,
 	}
 }
@@ -85,21 +778,200 @@ func (a AI) Validate() AI {
 		a.Model = "gpt-4.1"
 	}
 	if a.MaxContextLines <= 0 {
-		a.MaxContextLines = 500
+		a.MaxContextLines = DefaultAIMaxContextLines
+	}
+	// A zero timeout falls back to DefaultAIRequestTimeoutSeconds.
+	switch {
+	case a.RequestTimeoutSeconds < 0:
+		a.RequestTimeoutSeconds = 0
+	case a.RequestTimeoutSeconds > 0 && a.RequestTimeoutSeconds < MinAIRequestTimeoutSeconds:
+		slog.Warn("Raising AI request timeout to the minimum",
+			"timeout", a.RequestTimeoutSeconds,
+			"min", MinAIRequestTimeoutSeconds,
+		)
+		a.RequestTimeoutSeconds = MinAIRequestTimeoutSeconds
+	}
+	// A zero width means responses use the full width of the chat view.
+	if a.MaxWidth < 0 {
+		a.MaxWidth = 0
+	}
+	// A zero budget falls back to DefaultAIMaxToolCalls.
+	if a.MaxToolCalls < 0 {
+		a.MaxToolCalls = 0
+	}
+	if a.ClusterHealthMaxPods < 0 {
+		a.ClusterHealthMaxPods = 0
+	}
+	if a.HistoryTokenBudget < 0 {
+		a.HistoryTokenBudget = 0
 	}
 	// Default streaming to true if config was not explicitly set.
 	if !a.Streaming {
 		a.Streaming = true
 	}
+	if a.Provider != nil {
+		p := *a.Provider
+		if err := p.validate(); err != nil {
+			slog.Warn("Invalid AI provider config", slogs.Error, err)
+		}
+		a.Provider = &p
+	}
+	// Drop confirm policy rules carrying invalid regexes.
+	if len(a.ConfirmPolicy) > 0 {
+		rules := make([]AIConfirmRule, 0, len(a.ConfirmPolicy))
+		for _, r := range a.ConfirmPolicy {
+			if err := r.validate(); err != nil {
+				slog.Warn("Dropping invalid AI confirm policy rule", slogs.Error, err)
+				continue
+			}
+			rules = append(rules, r)
+		}
+		a.ConfirmPolicy = rules
+	}
+	if a.NpmRegistry != "" {
+		if u, err := url.Parse(a.NpmRegistry); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			slog.Warn("Ignoring invalid AI npmRegistry", "url", a.NpmRegistry)
+			a.NpmRegistry = ""
+		}
+	}
+	if a.PrometheusURL != "" {
+		if u, err := url.Parse(a.PrometheusURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			slog.Warn("Ignoring invalid AI prometheusURL", "url", a.PrometheusURL)
+			a.PrometheusURL = ""
+		}
+	}
+	if a.Prefetch != nil && len(a.Prefetch.Include) > 0 {
+		p := *a.Prefetch
+		p.Include = slices.DeleteFunc(slices.Clone(p.Include), func(part string) bool {
+			if slices.Contains(AIPrefetchParts, part) {
+				return false
+			}
+			slog.Warn("Ignoring unknown AI prefetch part",
+				"part", part,
+				"valid", strings.Join(AIPrefetchParts, ", "),
+			)
+			return true
+		})
+		a.Prefetch = &p
+	}
+	if len(a.PanelModels) > MaxAIPanelModels {
+		slog.Warn("Ignoring AI panel models past the limit",
+			"limit", MaxAIPanelModels,
+			"ignored", strings.Join(a.PanelModels[MaxAIPanelModels:], ", "),
+		)
+		a.PanelModels = a.PanelModels[:MaxAIPanelModels]
+	}
+	if a.HealthBadges != nil && a.HealthBadges.RefreshSeconds < 0 {
+		h := *a.HealthBadges
+		h.RefreshSeconds = 0
+		a.HealthBadges = &h
+	}
+	if a.Redaction != nil && len(a.Redaction.Patterns) > 0 {
+		r := *a.Redaction
+		r.Patterns = slices.DeleteFunc(slices.Clone(r.Patterns), func(expr string) bool {
+			if _, err := regexp.Compile(expr); err != nil {
+				slog.Warn("Dropping invalid AI redaction pattern", slogs.Error, err)
+				return true
+			}
+			return false
+		})
+		a.Redaction = &r
+	}
+	// Custom prompts with unknown placeholders fall back to the defaults.
+	if err := validatePrompt(a.DiagnosePrompt); err != nil {
+		slog.Warn("Ignoring invalid AI diagnose prompt", slogs.Error, err)
+		a.DiagnosePrompt = ""
+	}
+	if err := validatePrompt(a.ExplainPrompt); err != nil {
+		slog.Warn("Ignoring invalid AI explain prompt", slogs.Error, err)
+		a.ExplainPrompt = ""
+	}
+
+	// Invalid patterns are kept but match no namespace: dropping them could
+	// empty the allowlist and open up every namespace.
+	for _, pattern := range a.AllowedNamespaces {
+		if _, err := path.Match(pattern, ""); err != nil {
+			slog.Warn("Invalid AI allowed namespace pattern matches no namespace", "pattern", pattern, slogs.Error, err)
+		}
+	}
+	if a.CopilotLogLevel != "" && !slices.Contains(AICopilotLogLevels, a.CopilotLogLevel) {
+		slog.Warn("Ignoring unknown AI copilot log level",
+			"level", a.CopilotLogLevel,
+			"valid", strings.Join(AICopilotLogLevels, ", "),
+		)
+		a.CopilotLogLevel = ""
+	}
+	if a.SendMode != "" && !slices.Contains(AISendModes, a.SendMode) {
+		slog.Warn("Ignoring unknown AI send mode",
+			"mode", a.SendMode,
+			"valid", strings.Join(AISendModes, ", "),
+		)
+		a.SendMode = ""
+	}
+	if a.LogFilter != "" && a.LogFilter != AILogFilterErrors {
+		if _, err := regexp.Compile(a.LogFilter); err != nil {
+			slog.Warn("Ignoring invalid AI log filter", "filter", a.LogFilter, slogs.Error, err)
+			a.LogFilter = ""
+		}
+	}
+	if a.Audience != "" && !slices.Contains(AIAudiences, a.Audience) {
+		slog.Warn("Ignoring unknown AI audience",
+			"audience", a.Audience,
+			"valid", strings.Join(AIAudiences, ", "),
+		)
+		a.Audience = ""
+	}
+
 	// Only keep reasoning effort when explicitly set to a supported value.
 	// Note: many models (e.g. gpt-4.1) don't support reasoning effort at all;
 	// the session-creation retry in client.go handles that gracefully.
-	switch a.ReasoningEffort {
-	case "low", "medium", "high", "xhigh":
-		// keep it — user explicitly chose this
-	default:
+	if !isReasoningEffort(a.ReasoningEffort) {
 		a.ReasoningEffort = ""
 	}
+	if len(a.Contexts) > 0 {
+		cc := make(map[string]AIContext, len(a.Contexts))
+		for n, c := range a.Contexts {
+			cc[n] = c.validate(n)
+		}
+		a.Contexts = cc
+	}
 
 	return a
 }
+
+func isReasoningEffort(s string) bool {
+	switch s {
+	case "low", "medium", "high", "xhigh":
+		return true
+	default:
+		return false
+	}
+}
+
+// validate normalizes the context overrides the way AI.Validate does the
+// global settings.
+func (c AIContext) validate(name string) AIContext {
+	if c.Provider != nil {
+		p := *c.Provider
+		if err := p.validate(); err != nil {
+			slog.Warn("Invalid AI provider config", slogs.Context, name, slogs.Error, err)
+		}
+		c.Provider = &p
+	}
+	if c.ReasoningEffort != "" && !isReasoningEffort(c.ReasoningEffort) {
+		slog.Warn("Ignoring unknown AI reasoning effort", slogs.Context, name, "effort", c.ReasoningEffort)
+		c.ReasoningEffort = ""
+	}
+
+	return c
+}
+
+func (r AIConfirmRule) validate() error {
+	for _, expr := range []string{r.Namespace, r.Name} {
+		if _, err := regexp.Compile(expr); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,711 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/derailed/k9s/internal/config"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/ptr"
+)
+
+func TestAIRequiresTypedConfirm(t *testing.T) {
+	a := config.AI{
+		ConfirmPolicy: []config.AIConfirmRule{
+			{Actions: []string{"delete_resource"}, Namespace: "prod-.*"},
+			{Actions: []string{config.ScaleToZeroAction}},
+			{Name: "payments"},
+		},
+	}
+
+	uu := map[string]struct {
+		action, ns, name string
+		replicas         int
+		e                bool
+	}{
+		"delete-prod": {
+			action: "delete_resource", ns: "prod-eu", name: "fred", replicas: -1, e: true,
+		},
+		"delete-dev": {
+			action: "delete_resource", ns: "dev", name: "fred", replicas: -1,
+		},
+		"anchored": {
+			action: "delete_resource", ns: "not-prod-eu", name: "fred", replicas: -1,
+		},
+		"scale-to-zero": {
+			action: "scale_resource", ns: "dev", name: "fred", e: true,
+		},
+		"scale-up": {
+			action: "scale_resource", ns: "dev", name: "fred", replicas: 3,
+		},
+		"any-action-by-name": {
+			action: "patch_resource", ns: "dev", name: "payments", replicas: -1, e: true,
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, a.RequiresTypedConfirm(u.action, u.ns, u.name, u.replicas))
+		})
+	}
+}
+
+func TestAIValidateConfirmPolicy(t *testing.T) {
+	a := config.AI{
+		ConfirmPolicy: []config.AIConfirmRule{
+			{Namespace: "prod"},
+			{Name: "("},
+		},
+	}
+	a = a.Validate()
+
+	assert.Equal(t, []config.AIConfirmRule{{Namespace: "prod"}}, a.ConfirmPolicy)
+}
+
+func TestAIProviderPreset(t *testing.T) {
+	uu := map[string]struct {
+		p *config.AIProvider
+		e *config.AIProvider
+	}{
+		"ollama": {
+			p: &config.AIProvider{Preset: "ollama"},
+			e: &config.AIProvider{
+				Preset:  "ollama",
+				Type:    "openai",
+				BaseURL: "http://localhost:11434/v1",
+				WireAPI: "completions",
+			},
+		},
+		"override": {
+			p: &config.AIProvider{Preset: "openai", BaseURL: "https://proxy.example.com/v1", WireAPI: "responses"},
+			e: &config.AIProvider{
+				Preset:  "openai",
+				Type:    "openai",
+				BaseURL: "https://proxy.example.com/v1",
+				WireAPI: "responses",
+			},
+		},
+		"anthropic": {
+			p: &config.AIProvider{Preset: "anthropic", APIKey: "k"},
+			e: &config.AIProvider{
+				Preset:  "anthropic",
+				Type:    "anthropic",
+				BaseURL: "https://api.anthropic.com",
+				APIKey:  "k",
+			},
+		},
+		"azure-missing-base": {
+			p: &config.AIProvider{Preset: "azure"},
+			e: &config.AIProvider{Preset: "azure", Type: "azure", WireAPI: "completions"},
+		},
+		"unknown": {
+			p: &config.AIProvider{Preset: "fred"},
+			e: &config.AIProvider{Preset: "fred"},
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			a := config.AI{Provider: u.p}.Validate()
+			assert.Equal(t, u.e, a.Provider)
+		})
+	}
+}
+
+func TestAIProviderError(t *testing.T) {
+	uu := map[string]struct {
+		p *config.AIProvider
+		e string
+	}{
+		"none": {},
+		"ok": {
+			p: &config.AIProvider{Type: "openai", BaseURL: "https://api.openai.com/v1", APIKey: "k"},
+		},
+		"local-no-key": {
+			p: &config.AIProvider{Preset: "ollama"},
+		},
+		"missing-type": {
+			p: &config.AIProvider{BaseURL: "https://api.example.com", APIKey: "k"},
+			e: "ai.provider.type is required when baseURL is set (valid: openai, azure, anthropic)",
+		},
+		"bad-type": {
+			p: &config.AIProvider{Type: "fred", BaseURL: "https://api.example.com", APIKey: "k"},
+			e: `ai.provider.type "fred" is not supported (valid: openai, azure, anthropic)`,
+		},
+		"missing-base": {
+			p: &config.AIProvider{Type: "anthropic", APIKey: "k"},
+			e: `ai.provider.baseURL is required for provider type "anthropic"`,
+		},
+		"missing-creds": {
+			p: &config.AIProvider{Type: "openai", BaseURL: "https://api.openai.com/v1"},
+			e: "ai.provider.apiKey or bearerToken is required for remote providers (or set K9S_AI_API_KEY/K9S_AI_BEARER_TOKEN)",
+		},
+		"azure-no-version": {
+			p: &config.AIProvider{Type: "azure", BaseURL: "https://fred.openai.azure.com", BearerToken: "t"},
+			e: "ai.provider.azure.apiVersion is required for azure providers (e.g. 2024-10-21)",
+		},
+		"bad-wire": {
+			p: &config.AIProvider{Type: "openai", BaseURL: "http://localhost:8080", WireAPI: "grpc"},
+			e: `ai.provider.wireApi "grpc" is not supported (valid: completions, responses)`,
+		},
+		"multi": {
+			p: &config.AIProvider{Preset: "azure"},
+			e: `ai.provider.baseURL is required for provider type "azure"; ai.provider.azure.apiVersion is required for azure providers (e.g. 2024-10-21)`,
+		},
+	}
+
+	t.Setenv("K9S_AI_API_KEY", "")
+	t.Setenv("K9S_AI_BEARER_TOKEN", "")
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			err := config.AI{Provider: u.p}.ProviderError()
+			if u.e == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, u.e)
+		})
+	}
+}
+
+func TestAIValidateWireAPI(t *testing.T) {
+	a := config.AI{
+		Provider: &config.AIProvider{Type: "openai", BaseURL: "http://localhost:8080", WireAPI: " Responses "},
+	}.Validate()
+
+	assert.Equal(t, "responses", a.Provider.WireAPI)
+}
+
+func TestAIValidateProviderNormalize(t *testing.T) {
+	uu := map[string]struct {
+		p, e *config.AIProvider
+	}{
+		"chat": {
+			p: &config.AIProvider{Type: " OpenAI ", BaseURL: "http://localhost:8080 ", WireAPI: "chat"},
+			e: &config.AIProvider{Type: "openai", BaseURL: "http://localhost:8080", WireAPI: "completions"},
+		},
+		"chat-completions": {
+			p: &config.AIProvider{Type: "openai", BaseURL: "http://localhost:8080", WireAPI: "Chat_Completions"},
+			e: &config.AIProvider{Type: "openai", BaseURL: "http://localhost:8080", WireAPI: "completions"},
+		},
+		"anthropic-wire": {
+			p: &config.AIProvider{Type: "anthropic", BaseURL: "https://api.anthropic.com", WireAPI: "responses"},
+			e: &config.AIProvider{Type: "anthropic", BaseURL: "https://api.anthropic.com"},
+		},
+		"azure-no-version": {
+			p: &config.AIProvider{Type: "azure", BaseURL: "https://fred.openai.azure.com", Azure: &config.AzureProviderOpts{}},
+			e: &config.AIProvider{Type: "azure", BaseURL: "https://fred.openai.azure.com"},
+		},
+		"azure": {
+			p: &config.AIProvider{Type: "azure", BaseURL: "https://fred.openai.azure.com", Azure: &config.AzureProviderOpts{APIVersion: "2024-10-21"}},
+			e: &config.AIProvider{Type: "azure", BaseURL: "https://fred.openai.azure.com", Azure: &config.AzureProviderOpts{APIVersion: "2024-10-21"}},
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			a := config.AI{Provider: u.p}.Validate()
+			assert.Equal(t, u.e, a.Provider)
+		})
+	}
+}
+
+func TestAIToolCallBudget(t *testing.T) {
+	uu := map[string]struct {
+		max, e int
+	}{
+		"default":  {e: config.DefaultAIMaxToolCalls},
+		"custom":   {max: 10, e: 10},
+		"negative": {max: -3, e: config.DefaultAIMaxToolCalls},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			a := config.AI{MaxToolCalls: u.max}.Validate()
+			assert.Equal(t, u.e, a.ToolCallBudget())
+			assert.GreaterOrEqual(t, a.MaxToolCalls, 0)
+		})
+	}
+}
+
+func TestAIClusterHealthPodCap(t *testing.T) {
+	uu := map[string]struct {
+		max, e int
+	}{
+		"default":  {e: config.DefaultAIClusterHealthMaxPods},
+		"custom":   {max: 500, e: 500},
+		"negative": {max: -1, e: config.DefaultAIClusterHealthMaxPods},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			a := config.AI{ClusterHealthMaxPods: u.max}.Validate()
+			assert.Equal(t, u.e, a.ClusterHealthPodCap())
+			assert.GreaterOrEqual(t, a.ClusterHealthMaxPods, 0)
+		})
+	}
+}
+
+func TestAIPromptTemplates(t *testing.T) {
+	uu := map[string]struct {
+		diagnose, explain string
+		ed, ee            string
+	}{
+		"defaults": {
+			ed: "Diagnose the Deployment 'fred' in namespace 'blee'. Check its status, recent events, logs if applicable, and suggest fixes for any issues.",
+			ee: "Explain the Deployment 'fred' in namespace 'blee'. Describe its current state, configuration, and how it relates to other resources. Highlight anything unusual.",
+		},
+		"custom": {
+			diagnose: `Rate severity of {kind} {namespace}/{name} as {"severity": "low|high"}`,
+			explain:  "Explain {name}",
+			ed:       `Rate severity of Deployment blee/fred as {"severity": "low|high"}`,
+			ee:       "Explain fred",
+		},
+		"unknown-placeholder": {
+			diagnose: "Diagnose {pod}",
+			explain:  "Explain {kind} {Name}",
+			ed:       "Diagnose the Deployment 'fred' in namespace 'blee'. Check its status, recent events, logs if applicable, and suggest fixes for any issues.",
+			ee:       "Explain the Deployment 'fred' in namespace 'blee'. Describe its current state, configuration, and how it relates to other resources. Highlight anything unusual.",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			a := config.AI{DiagnosePrompt: u.diagnose, ExplainPrompt: u.explain}.Validate()
+			assert.Equal(t, u.ed, a.DiagnosePromptFor("Deployment", "fred", "blee"))
+			assert.Equal(t, u.ee, a.ExplainPromptFor("Deployment", "fred", "blee"))
+		})
+	}
+}
+
+func TestAIPrefetchParts(t *testing.T) {
+	uu := map[string]struct {
+		p *config.AIPrefetch
+		e []string
+	}{
+		"unset": {},
+		"disabled": {
+			p: &config.AIPrefetch{Include: []string{config.AIPrefetchPods}},
+		},
+		"defaults": {
+			p: &config.AIPrefetch{Enabled: true},
+			e: config.AIPrefetchParts,
+		},
+		"include": {
+			p: &config.AIPrefetch{Enabled: true, Include: []string{config.AIPrefetchEvents, "bozo"}},
+			e: []string{config.AIPrefetchEvents},
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			a := config.AI{Prefetch: u.p}.Validate()
+			assert.Equal(t, u.e, a.PrefetchParts())
+		})
+	}
+}
+
+func TestAIDataDestination(t *testing.T) {
+	uu := map[string]struct {
+		p *config.AIProvider
+		e string
+	}{
+		"copilot": {
+			e: "GitHub Copilot",
+		},
+		"byok": {
+			p: &config.AIProvider{Type: "openai", BaseURL: "https://api.openai.com/v1"},
+			e: "openai provider at https://api.openai.com/v1",
+		},
+		"preset": {
+			p: &config.AIProvider{Preset: "ollama"},
+			e: "openai provider at http://localhost:11434/v1",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			a := config.AI{Provider: u.p}.Validate()
+			assert.Equal(t, u.e, a.DataDestination())
+		})
+	}
+}
+
+func TestAIValidateRedaction(t *testing.T) {
+	a := config.AI{
+		Redaction: &config.AIRedaction{Patterns: []string{`sk-[a-z0-9]+`, `(unclosed`}},
+	}.Validate()
+
+	assert.Equal(t, []string{`sk-[a-z0-9]+`}, a.Redaction.Patterns)
+}
+
+func TestAIValidateCopilotLogLevel(t *testing.T) {
+	assert.Equal(t, "debug", config.AI{CopilotLogLevel: "debug"}.Validate().CopilotLogLevel)
+	assert.Empty(t, config.AI{CopilotLogLevel: "verbose"}.Validate().CopilotLogLevel)
+}
+
+func TestAIValidateNpmRegistry(t *testing.T) {
+	assert.Equal(t, "https://npm.example.com", config.AI{NpmRegistry: "https://npm.example.com"}.Validate().NpmRegistry)
+	assert.Empty(t, config.AI{NpmRegistry: "npm.example.com"}.Validate().NpmRegistry)
+}
+
+func TestAIValidateSendMode(t *testing.T) {
+	assert.Equal(t, config.AISendStream, config.AI{SendMode: "stream"}.Validate().SendMode)
+	assert.Empty(t, config.AI{SendMode: "poll"}.Validate().SendMode)
+}
+
+func TestAIValidateAudience(t *testing.T) {
+	assert.Equal(t, config.AIAudienceExec, config.AI{Audience: "exec"}.Validate().Audience)
+	assert.Empty(t, config.AI{Audience: "ceo"}.Validate().Audience)
+}
+
+func TestAIValidateLogFilter(t *testing.T) {
+	assert.Equal(t, config.AILogFilterErrors, config.AI{LogFilter: "errors"}.Validate().LogFilter)
+	assert.Equal(t, "(?i)timeout", config.AI{LogFilter: "(?i)timeout"}.Validate().LogFilter)
+	assert.Empty(t, config.AI{LogFilter: "("}.Validate().LogFilter)
+}
+
+func TestAIValidateHistoryTokenBudget(t *testing.T) {
+	assert.Equal(t, 8000, config.AI{HistoryTokenBudget: 8000}.Validate().HistoryTokenBudget)
+	assert.Zero(t, config.AI{HistoryTokenBudget: -1}.Validate().HistoryTokenBudget)
+}
+
+func TestAIHealthRefresh(t *testing.T) {
+	uu := map[string]struct {
+		badges  *config.AIHealthBadges
+		enabled bool
+		e       time.Duration
+	}{
+		"unset":    {e: config.DefaultAIHealthRefresh * time.Second},
+		"disabled": {badges: &config.AIHealthBadges{RefreshSeconds: 5}, e: 5 * time.Second},
+		"enabled":  {badges: &config.AIHealthBadges{Enabled: true}, enabled: true, e: config.DefaultAIHealthRefresh * time.Second},
+		"negative": {badges: &config.AIHealthBadges{Enabled: true, RefreshSeconds: -1}, enabled: true, e: config.DefaultAIHealthRefresh * time.Second},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			a := config.AI{HealthBadges: u.badges}.Validate()
+			assert.Equal(t, u.enabled, a.HealthBadgesEnabled())
+			assert.Equal(t, u.e, a.HealthRefresh())
+		})
+	}
+}
+
+func TestAIValidatePanelModels(t *testing.T) {
+	mm := []string{"gpt-4.1", "claude-sonnet-4", "gpt-5", "o3", "gemini-2.5-pro"}
+	assert.Equal(t, mm[:config.MaxAIPanelModels], config.AI{PanelModels: mm}.Validate().PanelModels)
+	assert.Equal(t, mm[:2], config.AI{PanelModels: mm[:2]}.Validate().PanelModels)
+}
+
+func TestAISkillFor(t *testing.T) {
+	a := config.AI{SkillByKind: map[string]string{
+		"NetworkPolicy": "security",
+		"deployments":   "diagnostics",
+		"Ingress":       "security",
+	}}
+
+	uu := map[string]struct {
+		kind, e string
+	}{
+		"singular":   {kind: "networkpolicy", e: "security"},
+		"plural-ies": {kind: "networkpolicies", e: "security"},
+		"plural-es":  {kind: "ingresses", e: "security"},
+		"kind":       {kind: "Deployment", e: "diagnostics"},
+		"none":       {kind: "pods"},
+		"blank":      {},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, a.SkillFor(u.kind))
+		})
+	}
+}
+
+func TestAISkillForOverlappingKeys(t *testing.T) {
+	a := config.AI{SkillByKind: map[string]string{
+		"pods": "diagnostics",
+		"Pod":  "security",
+		"pod":  "performance",
+	}}
+
+	for range 20 {
+		assert.Equal(t, "security", a.SkillFor("pods"))
+	}
+}
+
+func TestAINamespaceAllowed(t *testing.T) {
+	a := config.AI{AllowedNamespaces: []string{"default", "team-*"}}
+
+	uu := map[string]struct {
+		ns string
+		e  bool
+	}{
+		"literal": {ns: "default", e: true},
+		"glob":    {ns: "team-a", e: true},
+		"outside": {ns: "kube-system"},
+		"prefix":  {ns: "team"},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, a.NamespaceAllowed(u.ns))
+		})
+	}
+	assert.True(t, config.AI{}.NamespaceAllowed("kube-system"))
+}
+
+func TestAIValidateAllowedNamespaces(t *testing.T) {
+	a := config.AI{AllowedNamespaces: []string{"default", "team-[", "ops-*"}}.Validate()
+	assert.Equal(t, []string{"default", "team-[", "ops-*"}, a.AllowedNamespaces)
+	assert.True(t, a.NamespaceAllowed("ops-1"))
+	assert.False(t, a.NamespaceAllowed("team-a"))
+
+	a = config.AI{AllowedNamespaces: []string{"team-["}}.Validate()
+	assert.False(t, a.NamespaceAllowed("team-a"))
+	assert.False(t, a.NamespaceAllowed("kube-system"))
+}
+
+func TestAIForContext(t *testing.T) {
+	local := &config.AIProvider{Type: "openai", BaseURL: "http://localhost:11434/v1"}
+	a := config.AI{
+		Model: "gpt-4.1",
+		Contexts: map[string]config.AIContext{
+			"prod": {Model: "llama3", Provider: local, ReasoningEffort: "high"},
+			"kind": {Enabled: ptr.To(false)},
+		},
+	}
+
+	uu := map[string]struct {
+		ctx      string
+		enabled  bool
+		model    string
+		provider *config.AIProvider
+		effort   string
+	}{
+		"global": {
+			ctx:     "dev",
+			enabled: true,
+			model:   "gpt-4.1",
+		},
+		"byok": {
+			ctx:      "prod",
+			enabled:  true,
+			model:    "llama3",
+			provider: local,
+			effort:   "high",
+		},
+		"disabled": {
+			ctx:   "kind",
+			model: "gpt-4.1",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			c := a.ForContext(u.ctx)
+			assert.Equal(t, u.enabled, c.IsEnabled())
+			assert.Equal(t, u.model, c.Model)
+			assert.Equal(t, u.provider, c.Provider)
+			assert.Equal(t, u.effort, c.ReasoningEffort)
+		})
+	}
+}
+
+func TestAIClientChanged(t *testing.T) {
+	a := config.AI{
+		Model:        "gpt-4.1",
+		MaxToolCalls: 10,
+		Contexts: map[string]config.AIContext{
+			"prod":    {Provider: &config.AIProvider{Type: "openai", BaseURL: "http://localhost:11434/v1"}},
+			"staging": {Provider: &config.AIProvider{Type: "openai", BaseURL: "http://localhost:11434/v1"}},
+			"kind":    {Enabled: ptr.To(false)},
+		},
+	}
+
+	assert.False(t, a.ClientChanged(a.ForContext("dev")))
+	assert.True(t, a.ClientChanged(a.ForContext("prod")))
+	assert.True(t, a.ClientChanged(a.ForContext("kind")))
+	assert.False(t, a.ForContext("prod").ClientChanged(a.ForContext("staging")))
+
+	b := a
+	b.MaxToolCalls = 20
+	assert.False(t, a.ClientChanged(b))
+	b.Model = "gpt-5"
+	assert.True(t, a.ClientChanged(b))
+}
+
+func TestAIValidateContexts(t *testing.T) {
+	a := config.AI{
+		Contexts: map[string]config.AIContext{
+			"prod": {
+				Provider:        &config.AIProvider{Preset: "ollama", WireAPI: "chat"},
+				ReasoningEffort: "bozo",
+			},
+		},
+	}.Validate()
+
+	assert.Equal(t, config.AIContext{
+		Provider: &config.AIProvider{Preset: "ollama", Type: "openai", BaseURL: "http://localhost:11434/v1", WireAPI: "completions"},
+	}, a.Contexts["prod"])
+}
+
+func TestAIExecAllowed(t *testing.T) {
+	uu := map[string]struct {
+		cfg config.AI
+		cmd []string
+		e   bool
+	}{
+		"disabled": {
+			cmd: []string{"ls"},
+		},
+		"default": {
+			cfg: config.AI{AllowExec: true},
+			cmd: []string{"cat", "/etc/hosts"},
+			e:   true,
+		},
+		"default-denied": {
+			cfg: config.AI{AllowExec: true},
+			cmd: []string{"sh", "-c", "ls"},
+		},
+		"prefix": {
+			cfg: config.AI{AllowExec: true, ExecAllowlist: []string{"cat /etc/nginx"}},
+			cmd: []string{"cat", "/etc/nginx", "nginx.conf"},
+			e:   true,
+		},
+		"prefix-partial-word": {
+			cfg: config.AI{AllowExec: true, ExecAllowlist: []string{"cat /etc/nginx"}},
+			cmd: []string{"cat", "/etc/nginx-secrets"},
+		},
+		"prefix-too-short": {
+			cfg: config.AI{AllowExec: true, ExecAllowlist: []string{"cat /etc/nginx"}},
+			cmd: []string{"cat"},
+		},
+		"empty": {
+			cfg: config.AI{AllowExec: true},
+		},
+		"service-account-token": {
+			cfg: config.AI{AllowExec: true},
+			cmd: []string{"cat", "/var/run/secrets/kubernetes.io/serviceaccount/token"},
+		},
+		"denied-despite-allowlist": {
+			cfg: config.AI{AllowExec: true, ExecAllowlist: []string{"cat /run"}},
+			cmd: []string{"cat", "/run/secrets/db/password"},
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, u.cfg.ExecAllowed(u.cmd))
+		})
+	}
+}
+
+func TestExecTouches(t *testing.T) {
+	dirs := []string{"/var/run/secrets", "/etc/creds/"}
+
+	uu := map[string]struct {
+		cmd []string
+		arg string
+		ok  bool
+	}{
+		"unrelated": {
+			cmd: []string{"cat", "/etc/hosts"},
+		},
+		"dir": {
+			cmd: []string{"ls", "/var/run/secrets"},
+			arg: "/var/run/secrets",
+			ok:  true,
+		},
+		"file": {
+			cmd: []string{"cat", "/etc/creds/password"},
+			arg: "/etc/creds/password",
+			ok:  true,
+		},
+		"sibling": {
+			cmd: []string{"cat", "/etc/credsx/password"},
+		},
+		"traversal": {
+			cmd: []string{"cat", "/etc/nginx/../../var/run/secrets/token"},
+			arg: "/etc/nginx/../../var/run/secrets/token",
+			ok:  true,
+		},
+		"relative": {
+			cmd: []string{"cat", "var/run/secrets/token"},
+			arg: "var/run/secrets/token",
+			ok:  true,
+		},
+		"proc-root": {
+			cmd: []string{"cat", "/proc/1/root/etc/creds/password"},
+			arg: "/proc/1/root/etc/creds/password",
+			ok:  true,
+		},
+		"flag-value": {
+			cmd: []string{"tail", "--lines=5", "--follow=/etc/creds/password"},
+			arg: "--follow=/etc/creds/password",
+			ok:  true,
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			arg, ok := config.ExecTouches(u.cmd, dirs)
+			assert.Equal(t, u.ok, ok)
+			assert.Equal(t, u.arg, arg)
+		})
+	}
+}
+
+func TestAIRequestTimeout(t *testing.T) {
+	uu := map[string]struct {
+		secs, validated int
+		e               time.Duration
+	}{
+		"default": {
+			e: 300 * time.Second,
+		},
+		"negative": {
+			secs: -5,
+			e:    300 * time.Second,
+		},
+		"floored": {
+			secs:      3,
+			validated: 10,
+			e:         10 * time.Second,
+		},
+		"custom": {
+			secs:      900,
+			validated: 900,
+			e:         15 * time.Minute,
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			a := config.AI{RequestTimeoutSeconds: u.secs}
+			assert.Equal(t, u.e, a.RequestTimeout())
+			a = a.Validate()
+			assert.Equal(t, u.validated, a.RequestTimeoutSeconds)
+			assert.Equal(t, u.e, a.RequestTimeout())
+		})
+	}
+}
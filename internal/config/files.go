@@ -60,6 +60,15 @@ var (
 	// AppDumpsDir tracks screen dumps data directory.
 	AppDumpsDir string
 
+	// AppRunbooksDir tracks the default AI runbooks directory.
+	AppRunbooksDir string
+
+	// AppConversationsDir tracks the saved AI conversations directory.
+	AppConversationsDir string
+
+	// AppChatHistoryFile tracks the persisted AI chat history file.
+	AppChatHistoryFile string
+
 	// AppContextsDir tracks contexts data directory.
 	AppContextsDir string
 
@@ -128,6 +137,9 @@ func initK9sEnvLocs() error {
 	if err := data.EnsureFullPath(AppDumpsDir, data.DefaultDirMod); err != nil {
 		slog.Warn("Unable to create screen-dumps dir", slogs.Dir, AppDumpsDir, slogs.Error, err)
 	}
+	AppRunbooksDir = filepath.Join(AppConfigDir, "runbooks")
+	AppConversationsDir = filepath.Join(AppConfigDir, "conversations")
+	AppChatHistoryFile = filepath.Join(AppConfigDir, "chat-history.json")
 	AppBenchmarksDir = filepath.Join(AppConfigDir, "benchmarks")
 	if err := data.EnsureFullPath(AppBenchmarksDir, data.DefaultDirMod); err != nil {
 		slog.Warn("Unable to create benchmarks dir",
@@ -199,6 +211,9 @@ func initXDGLocs() error {
 	if err != nil {
 		return err
 	}
+	AppRunbooksDir = filepath.Join(dataDir, "runbooks")
+	AppConversationsDir = filepath.Join(dataDir, "conversations")
+	AppChatHistoryFile = filepath.Join(dataDir, "chat-history.json")
 	AppContextsDir = filepath.Join(dataDir, "clusters")
 	if err := data.EnsureFullPath(AppContextsDir, data.DefaultDirMod); err != nil {
 		slog.Warn("No context dir detected",
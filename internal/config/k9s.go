@@ -420,6 +420,11 @@ func (k *K9s) IsReadOnly() bool {
 	return ro
 }
 
+// ActiveAI returns the AI settings in effect for the active context.
+func (k *K9s) ActiveAI() AI {
+	return k.AI.ForContext(k.getActiveContextName())
+}
+
 // Validate the current configuration.
 func (k *K9s) Validate(c client.Connection, contextName, clusterName string) {
 	if k.RefreshRate <= 0 {
@@ -129,6 +129,20 @@ type (
 		Yaml   Yaml   `json:"yaml" yaml:"yaml"`
 		Picker Picker `json:"picker" yaml:"picker"`
 		Log    Log    `json:"logs" yaml:"logs"`
+		AI     AIChat `json:"ai" yaml:"ai"`
+	}
+
+	// AIChat tracks AI chat styles. Blank colors and glyphs fall back to the
+	// ones derived from the frame styles.
+	AIChat struct {
+		UserColor      Color  `json:"userColor" yaml:"userColor"`
+		AssistantColor Color  `json:"assistantColor" yaml:"assistantColor"`
+		ActivityColor  Color  `json:"activityColor" yaml:"activityColor"`
+		ErrorColor     Color  `json:"errorColor" yaml:"errorColor"`
+		UserGlyph      string `json:"userGlyph" yaml:"userGlyph"`
+		AssistantGlyph string `json:"assistantGlyph" yaml:"assistantGlyph"`
+		ActivityGlyph  string `json:"activityGlyph" yaml:"activityGlyph"`
+		ErrorGlyph     string `json:"errorGlyph" yaml:"errorGlyph"`
 	}
 
 	// Status tracks resource status styles.
@@ -698,6 +712,7 @@ func (v *Views) Invert() {
 	v.Yaml.Invert()
 	v.Picker.Invert()
 	v.Log.Invert()
+	v.AI.Invert()
 }
 
 // Invert inverts all colors in Table.
@@ -762,6 +777,14 @@ func (l *Log) Invert() {
 	l.Indicator.Invert()
 }
 
+// Invert inverts all colors in AIChat.
+func (a *AIChat) Invert() {
+	a.UserColor = a.UserColor.InvertColor()
+	a.AssistantColor = a.AssistantColor.InvertColor()
+	a.ActivityColor = a.ActivityColor.InvertColor()
+	a.ErrorColor = a.ErrorColor.InvertColor()
+}
+
 // Invert inverts all colors in LogIndicator.
 func (l *LogIndicator) Invert() {
 	l.FgColor = l.FgColor.InvertColor()
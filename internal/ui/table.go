@@ -410,6 +410,11 @@ func (t *Table) SetDecorateFn(f DecorateFunc) {
 	t.decorateFn = f
 }
 
+// DecorateFn returns the current row decorator, if any.
+func (t *Table) DecorateFn() DecorateFunc {
+	return t.decorateFn
+}
+
 // SetColorerFn specifies the default colorer.
 func (t *Table) SetColorerFn(f model1.ColorerFunc) {
 	t.colorerFn = f
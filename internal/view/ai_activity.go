@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"fmt"
+	"strings"
+)
+
+// recordActivity records a tool activity line, folding it into the previous
+// message when that one is the same activity. Mutations are never folded so
+// each one stays on record. It returns how many times the activity ran in a
+// row.
+func (v *AIChatView) recordActivity(msg chatMessage) int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if n := len(v.history); n > 0 && repeatsActivity(v.history[n-1], msg) {
+		v.history[n-1].repeats++
+		scope := v.scopeKey()
+		globalChatMu.Lock()
+		if mm := globalChatHistories[scope]; len(mm) > 0 && repeatsActivity(mm[len(mm)-1], msg) {
+			mm[len(mm)-1].repeats++
+		}
+		globalChatMu.Unlock()
+
+		return v.history[n-1].repeats + 1
+	}
+	v.history = append(v.history, msg)
+	scope := v.scopeKey()
+	globalChatMu.Lock()
+	globalChatHistories[scope] = append(globalChatHistories[scope], msg)
+	globalChatMu.Unlock()
+
+	return 1
+}
+
+// repeatsActivity returns true if msg is the same non mutating activity as
+// prev.
+func repeatsActivity(prev, msg chatMessage) bool {
+	return prev.role == "activity" && msg.role == "activity" &&
+		!prev.mutation && !msg.mutation && prev.content == msg.content
+}
+
+// repeatSuffix marks an activity line that ran count times in a row.
+func repeatSuffix(count int) string {
+	if count < 2 {
+		return ""
+	}
+
+	return fmt.Sprintf(" ×%d", count)
+}
+
+// foldActivityLine replaces the last output line with line when it reads
+// prev, so a repeated activity updates its count in place. It returns false
+// when something else was written since.
+func (v *AIChatView) foldActivityLine(prev, line string) bool {
+	text := strings.TrimRight(v.output.GetText(false), "\n")
+	head, ok := strings.CutSuffix(text, prev)
+	if !ok {
+		return false
+	}
+	v.output.Clear()
+	fmt.Fprint(v.output, head+line+"\n")
+
+	return true
+}
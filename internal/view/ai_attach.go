@@ -0,0 +1,48 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"fmt"
+
+	"github.com/derailed/k9s/internal/ai"
+	"github.com/derailed/tview"
+)
+
+// attachCmd attaches a local manifest to the next question.
+// Usage: /attach path. With no path a pending attachment is dropped.
+func (v *AIChatView) attachCmd(path string) {
+	if path == "" {
+		if v.swapAttachment(nil) == nil {
+			v.app.Flash().Errf("Missing file. Use /attach <path>")
+			return
+		}
+		v.app.Flash().Info("Attachment dropped")
+		return
+	}
+
+	a, err := ai.LoadAttachment(path, ai.NewRedactor(v.app.Config.K9s.ActiveAI().Redaction))
+	if err != nil {
+		v.app.Flash().Errf("Unable to attach file: %s", err)
+		return
+	}
+	v.swapAttachment(a)
+
+	msg := fmt.Sprintf("📎 Attached %s (%d document(s), %d bytes) — sent with your next question",
+		tview.Escape(a.Path), a.Docs, a.Bytes)
+	v.recordMessage(chatMessage{role: "system", content: msg, activity: true})
+	v.renderMessage("system", msg)
+	v.scrollToEnd()
+}
+
+// swapAttachment replaces the pending attachment and returns the old one.
+func (v *AIChatView) swapAttachment(a *ai.Attachment) *ai.Attachment {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	old := v.attachment
+	v.attachment = a
+
+	return old
+}
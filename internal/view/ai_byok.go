@@ -25,6 +25,8 @@ const (
 )
 
 // BYOKView provides an interactive form for configuring BYOK providers.
+// It edits the global AI settings, the default every context inherits, and
+// not the active context's ai.contexts override.
 type BYOKView struct {
 	*tview.Flex
 
@@ -202,7 +204,7 @@ func (v *BYOKView) saveConfig() {
 		return
 	}
 
-	// Update config in memory.
+	// Update config in memory, on the global default not ActiveAI().
 	v.app.Config.K9s.AI.Provider = &config.AIProvider{
 		Type:    providerType,
 		BaseURL: baseURL,
@@ -227,6 +229,7 @@ func (v *BYOKView) saveConfig() {
 }
 
 func (v *BYOKView) removeBYOK() {
+	// Like saveConfig, only the global default is changed.
 	v.app.Config.K9s.AI.Provider = nil
 
 	if err := v.app.Config.SaveFile(config.AppConfigFile); err != nil {
@@ -258,7 +261,7 @@ func (v *BYOKView) reinitAI() {
 	}
 
 	// Create new client with updated config.
-	aiClient := ai.NewAIClient(v.app.Config.K9s.AI, slog.Default())
+	aiClient := ai.NewAIClient(v.app.Config.K9s.ActiveAI(), slog.Default())
 	ai.Client = aiClient
 
 	if err := aiClient.Init(context.Background()); err != nil {
@@ -267,13 +270,7 @@ func (v *BYOKView) reinitAI() {
 		return
 	}
 
-	// Re-wire tools.
-	if v.app.Conn() != nil && v.app.Conn().ConnectionOK() {
-		if factory := v.app.factory; factory != nil {
-			tf := ai.NewToolFactory(factory, v.app.Conn(), slog.Default())
-			aiClient.SetTools(tf.BuildTools())
-		}
-	}
+	v.app.wireAITools(aiClient)
 }
 
 func (v *BYOKView) cancel() {
@@ -6,21 +6,27 @@ package view
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"regexp"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/derailed/k9s/internal/ai"
+	"github.com/derailed/k9s/internal/client"
 	"github.com/derailed/k9s/internal/config"
+	"github.com/derailed/k9s/internal/dao"
 	"github.com/derailed/k9s/internal/model"
 	"github.com/derailed/k9s/internal/slogs"
 	"github.com/derailed/k9s/internal/ui"
 	"github.com/derailed/k9s/internal/view/cmd"
 	"github.com/derailed/tcell/v2"
 	"github.com/derailed/tview"
+	"github.com/mattn/go-runewidth"
 	"k8s.io/apimachinery/pkg/labels"
 )
 
@@ -37,26 +43,51 @@ const (
 	chatSeparator     = "─────────────────────────────────────────────"
 	thinSeparator     = "─ ─ ─ ─ ─ ─ ─ ─ ─ ─ ─ ─ ─ ─ ─ ─ ─ ─ ─ ─ ─"
 	approvalDialogKey = "ai-approval"
+
+	defaultCodeBlockWidth = 27
+
+	// maxContextPreviewLines caps how much of a prefetched bundle is shown.
+	maxContextPreviewLines = 12
+
+	// globalPrefix sends a scoped chat message without the resource context.
+	globalPrefix = "!global"
 )
 
 // AIChatView represents the AI chat interface.
 type AIChatView struct {
 	*tview.Flex
 
-	app            *App
-	output         *tview.TextView
-	input          *tview.InputField
-	statusBar      *tview.TextView
-	actions        *ui.KeyActions
-	history        []chatMessage
-	streaming      bool
+	app             *App
+	output          *tview.TextView
+	input           *tview.InputField
+	statusBar       *tview.TextView
+	actions         *ui.KeyActions
+	history         []chatMessage
+	streaming       bool
 	streamingHeader bool // true if we've printed the Copilot header for current stream
-	thinkingShown  bool // true if the inline thinking indicator is displayed
-	fullScreen     bool
-	resKind        string
-	resName        string
-	resNamespace   string
-	mu             sync.Mutex
+	paneling        bool // true while a /panel question is out, which can't be nudged
+	thinkingShown   bool // true if the inline thinking indicator is displayed
+	fullScreen      bool
+	resKind         string
+	resName         string
+	resNamespace    string
+	viewWidth       int                     // inner width of the output at last draw
+	follow          bool                    // auto-scroll to the end as new content arrives
+	scrollCheck     int                     // requested offset of the last downward scroll, -1 if none
+	showReasoning   bool                    // render reasoning blocks in full rather than collapsed
+	prefetched      string                  // context bundle to send along with the next question
+	attachment      *ai.Attachment          // local manifest to send along with the next question
+	recap           string                  // transcript of a loaded conversation to send along with the next question
+	sessionTokens   int                     // estimated tokens sent and received in the current AI session, absent reported usage
+	turns           []chatTurn              // where each rendered turn starts (UI goroutine only)
+	correction      *string                 // correction to send once an interrupted answer stops
+	cancel          context.CancelCauseFunc // cancels the request in flight, if any
+	// closed is set while the view is off screen. Draws queued from other
+	// goroutines are dropped until the view starts again.
+	closed atomic.Bool
+	// mu guards history, the resource context and the streaming flags, which
+	// are shared by the UI, send and AI listener goroutines.
+	mu sync.Mutex
 }
 
 type chatMessage struct {
@@ -64,6 +95,23 @@ type chatMessage struct {
 	content string
 	// activity is true for tool activity lines (not sent to AI, display-only).
 	activity bool
+	// mutation is true for activity lines of tools that changed the cluster.
+	mutation bool
+	// sources lists the tool results recorded while producing an answer.
+	sources []ai.Source
+	// truncated is true for answers cut off by the output limit.
+	truncated bool
+	// intent holds the plan of a plan block declared via report_intent.
+	intent *ai.Intent
+	// repeats counts the identical activity lines folded into this one.
+	repeats int
+}
+
+// chatTurn marks the output line holding the separator of a user or
+// assistant turn.
+type chatTurn struct {
+	line   int
+	answer bool
 }
 
 // Package-level chat history that persists across view recreations.
@@ -82,10 +130,12 @@ var _ model.Component = (*AIChatView)(nil)
 // NewAIChatView returns a new AI chat view.
 func NewAIChatView() *AIChatView {
 	return &AIChatView{
-		Flex:    tview.NewFlex().SetDirection(tview.FlexRow),
-		output:  tview.NewTextView(),
-		input:   tview.NewInputField(),
-		actions: ui.NewKeyActions(),
+		Flex:        tview.NewFlex().SetDirection(tview.FlexRow),
+		output:      tview.NewTextView(),
+		input:       tview.NewInputField(),
+		actions:     ui.NewKeyActions(),
+		follow:      true,
+		scrollCheck: -1,
 	}
 }
 
@@ -108,6 +158,7 @@ func (v *AIChatView) Init(ctx context.Context) error {
 	v.output.SetScrollable(true)
 	v.output.SetWrap(true)
 	v.output.SetWordWrap(true)
+	v.output.SetMouseCapture(v.mouse)
 
 	// Status bar between output and input.
 	v.statusBar = tview.NewTextView()
@@ -128,13 +179,17 @@ func (v *AIChatView) Init(ctx context.Context) error {
 	// tview docs: "SetInputCapture will not have an effect on composing
 	// primitives such as Flex" — only the focused primitive gets events.
 	v.input.SetInputCapture(v.keyboard)
+	v.showReasoning = v.app.Config.K9s.ActiveAI().ShowReasoning
 	v.StylesChanged(v.app.Styles)
+	v.selectSkill()
 	v.updateTitle()
 	v.setStatusReady()
 
 	// Restore previous chat history if available; otherwise show welcome.
+	v.loadPersistedHistory()
 	if !v.restoreHistory() {
 		v.printWelcome()
+		v.prefetchContext()
 	}
 
 	return nil
@@ -154,6 +209,17 @@ func (v *AIChatView) StylesChanged(s *config.Styles) {
 	v.input.SetPlaceholderTextColor(s.Frame().Menu.FgColor.Color())
 }
 
+// selectSkill picks the skill configured for the scoped resource kind.
+func (v *AIChatView) selectSkill() {
+	if ai.Client == nil {
+		return
+	}
+	v.mu.Lock()
+	kind := v.resKind
+	v.mu.Unlock()
+	ai.Client.SelectSkillFor(kind)
+}
+
 func (v *AIChatView) updateTitle() {
 	styles := v.app.Styles.Frame()
 	modelName := "copilot"
@@ -165,11 +231,71 @@ func (v *AIChatView) updateTitle() {
 		if skill := ai.Client.ActiveSkill(); skill != "" {
 			skillInfo = fmt.Sprintf(" | skill:%s", skill)
 		}
+		if audience := ai.Client.Audience(); audience != config.AIAudienceSRE {
+			skillInfo += fmt.Sprintf(" | audience:%s", audience)
+		}
 	}
 	title := ui.SkinTitle(fmt.Sprintf(aiChatTitleFmt, modelName+skillInfo), &styles)
 	v.SetTitle(title)
 }
 
+// Draw records the output width so rendered content can be reflowed to it.
+func (v *AIChatView) Draw(screen tcell.Screen) {
+	v.Flex.Draw(screen)
+	// The output clamps a scroll past the last line during draw. When that
+	// happens the user reached the bottom, so resume following new content.
+	if v.scrollCheck >= 0 {
+		if row, _ := v.output.GetScrollOffset(); row < v.scrollCheck {
+			v.follow = true
+		}
+		v.scrollCheck = -1
+	}
+	_, _, w, _ := v.output.GetInnerRect()
+	v.mu.Lock()
+	v.viewWidth = w
+	v.mu.Unlock()
+}
+
+// contentWidth returns the column budget for rendered content, honoring
+// the configured ai.maxWidth. Zero means unconstrained.
+func (v *AIChatView) contentWidth() int {
+	v.mu.Lock()
+	w := v.viewWidth
+	v.mu.Unlock()
+
+	if mw := v.app.Config.K9s.ActiveAI().MaxWidth; mw > 0 && (w <= 0 || mw < w) {
+		return mw
+	}
+	return w
+}
+
+// codeBlockWidth returns the width of code block borders: the content width,
+// or a default until the view is drawn.
+func (v *AIChatView) codeBlockWidth() int {
+	w := v.contentWidth() - 4
+	if w <= 0 {
+		return defaultCodeBlockWidth
+	}
+	return maxInt(w, 8)
+}
+
+// writeWrapped writes text reflowed to the content width. The first line is
+// prefixed with prefix and continuation lines with indent.
+func (v *AIChatView) writeWrapped(prefix, indent, text string) {
+	width := v.contentWidth() - tview.TaggedStringWidth(prefix)
+	if width <= 0 {
+		fmt.Fprintf(v.output, "%s%s\n", prefix, text)
+		return
+	}
+	for i, line := range tview.WordWrap(text, width) {
+		if i == 0 {
+			fmt.Fprintf(v.output, "%s%s\n", prefix, line)
+			continue
+		}
+		fmt.Fprintf(v.output, "%s%s\n", indent, strings.TrimLeft(line, " "))
+	}
+}
+
 // InCmdMode checks if prompt is active.
 func (*AIChatView) InCmdMode() bool {
 	return false
@@ -180,19 +306,27 @@ func (*AIChatView) Name() string { return aiChatTitle }
 
 // Start starts the chat view.
 func (v *AIChatView) Start() {
+	if v.closed.Swap(false) {
+		v.resync()
+	}
 	v.app.Styles.AddListener(v)
 	v.updateTitle()
 	v.app.SetFocus(v.input)
+	if v.needsConsent() {
+		v.showConsentDialog()
+	}
 
 	// Wire approval and activity callbacks into the AI client.
 	if ai.Client != nil {
 		ai.Client.SetApprovalFunc(v.approvalCallback)
 		ai.Client.SetToolActivityFunc(v.toolActivityCallback)
+		ai.Client.SetIntentFunc(v.intentCallback)
 	}
 }
 
 // Stop stops the chat view.
 func (v *AIChatView) Stop() {
+	v.closed.Store(true)
 	v.app.Styles.RemoveListener(v)
 	if ai.Client != nil {
 		ai.Client.ResetCallbacks()
@@ -216,14 +350,22 @@ func (v *AIChatView) Actions() *ui.KeyActions {
 
 func (v *AIChatView) bindKeys() {
 	v.actions.Bulk(ui.KeyMap{
-		tcell.KeyEscape: ui.NewKeyAction("Back", v.backCmd, false),
-		tcell.KeyCtrlC:  ui.NewKeyAction("Clear", v.clearCmd, false),
-		tcell.KeyCtrlR:  ui.NewKeyAction("Reset", v.resetCmd, false),
-		tcell.KeyCtrlS:  ui.NewKeyAction("Save", v.saveCmd, false),
-		tcell.KeyCtrlF:  ui.NewKeyAction("FullScreen", v.toggleFullScreenCmd, false),
-		tcell.KeyCtrlN:  ui.NewKeyAction("Models", v.modelsCmd, false),
-		tcell.KeyPgUp:   ui.NewKeyAction("PgUp", nil, false),
-		tcell.KeyPgDn:   ui.NewKeyAction("PgDn", nil, false),
+		tcell.KeyEscape:    ui.NewKeyAction("Back", v.backCmd, false),
+		tcell.KeyCtrlC:     ui.NewKeyAction("Clear", v.clearCmd, false),
+		tcell.KeyCtrlR:     ui.NewKeyAction("Reset", v.resetCmd, false),
+		tcell.KeyCtrlS:     ui.NewKeyAction("Save", v.saveCmd, false),
+		tcell.KeyCtrlF:     ui.NewKeyAction("FullScreen", v.toggleFullScreenCmd, false),
+		tcell.KeyCtrlN:     ui.NewKeyAction("Models", v.modelsCmd, false),
+		tcell.KeyCtrlK:     ui.NewKeyAction("Skills", v.skillsCmd, false),
+		tcell.KeyCtrlT:     ui.NewKeyAction("Reasoning", v.toggleReasoningCmd, false),
+		tcell.KeyCtrlO:     ui.NewKeyAction("Audience", v.audienceCmd, false),
+		tcell.KeyCtrlL:     ui.NewKeyAction("Latest Answer", v.latestAnswerCmd, false),
+		tcell.KeyCtrlY:     ui.NewKeyAction("Copy Answer", v.copyAnswerCmd, false),
+		tcell.KeyCtrlSpace: ui.NewKeyAction("Continue", v.continueCmd, false),
+		tcell.KeyCtrlB:     ui.NewKeyAction("Rerun", v.rerunCmd, false),
+		tcell.KeyCtrlX:     ui.NewKeyAction("Interrupt", v.interruptCmd, false),
+		tcell.KeyPgUp:      ui.NewKeyAction("PgUp", nil, false),
+		tcell.KeyPgDn:      ui.NewKeyAction("PgDn", nil, false),
 	})
 }
 
@@ -231,20 +373,24 @@ func (v *AIChatView) keyboard(evt *tcell.EventKey) *tcell.EventKey {
 	// Scroll output while input retains focus.
 	switch evt.Key() {
 	case tcell.KeyPgUp:
-		row, col := v.output.GetScrollOffset()
-		v.output.ScrollTo(row-10, col)
+		v.scrollBy(-10)
 		return nil
 	case tcell.KeyPgDn:
-		row, col := v.output.GetScrollOffset()
-		v.output.ScrollTo(row+10, col)
+		v.scrollBy(10)
 		return nil
 	case tcell.KeyUp:
-		row, col := v.output.GetScrollOffset()
-		v.output.ScrollTo(row-1, col)
+		if evt.Modifiers()&tcell.ModShift != 0 {
+			v.jumpTurn(-1)
+			return nil
+		}
+		v.scrollBy(-1)
 		return nil
 	case tcell.KeyDown:
-		row, col := v.output.GetScrollOffset()
-		v.output.ScrollTo(row+1, col)
+		if evt.Modifiers()&tcell.ModShift != 0 {
+			v.jumpTurn(1)
+			return nil
+		}
+		v.scrollBy(1)
 		return nil
 	}
 
@@ -254,18 +400,141 @@ func (v *AIChatView) keyboard(evt *tcell.EventKey) *tcell.EventKey {
 	return evt
 }
 
+// scrollBy scrolls the output by delta lines. Scrolling up stops following
+// new content; scrolling down past the end resumes it (see Draw).
+func (v *AIChatView) scrollBy(delta int) {
+	row, col := v.output.GetScrollOffset()
+	v.output.ScrollTo(row+delta, col)
+	if delta < 0 {
+		v.follow = false
+		return
+	}
+	v.scrollCheck = row + delta
+}
+
+// mouse tracks wheel scrolling so follow mode matches the keyboard.
+func (v *AIChatView) mouse(action tview.MouseAction, evt *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+	switch action {
+	case tview.MouseScrollUp:
+		v.follow = false
+	case tview.MouseScrollDown:
+		row, _ := v.output.GetScrollOffset()
+		v.scrollCheck = row + 1
+	}
+
+	return action, evt
+}
+
+// markTurn records the start of a turn. Must be called right before the
+// turn separator is written.
+func (v *AIChatView) markTurn(answer bool) {
+	// Unstripped text carries an extra trailing line for pending bytes.
+	line := strings.Count(v.output.GetText(true), "\n") + 1
+	v.turns = append(v.turns, chatTurn{line: line, answer: answer})
+}
+
+// resetOutput clears the output along with the recorded turns.
+func (v *AIChatView) resetOutput() {
+	v.output.Clear()
+	v.turns = nil
+}
+
+// turnRow converts an output line to a display row, accounting for lines
+// the output wraps at its current width.
+func (v *AIChatView) turnRow(line int) int {
+	_, _, w, _ := v.output.GetInnerRect()
+	if w <= 0 {
+		return line
+	}
+	lines := strings.Split(v.output.GetText(true), "\n")
+	var row int
+	for _, l := range lines[:min(line, len(lines))] {
+		row += max(1, len(tview.WordWrap(l, w)))
+	}
+
+	return row
+}
+
+// jumpTurn scrolls to the previous (dir < 0) or next (dir > 0) turn
+// relative to the current scroll position.
+func (v *AIChatView) jumpTurn(dir int) {
+	current, _ := v.output.GetScrollOffset()
+	if dir < 0 {
+		for i := len(v.turns) - 1; i >= 0; i-- {
+			if row := v.turnRow(v.turns[i].line); row < current {
+				v.jumpTo(row)
+				return
+			}
+		}
+		v.jumpTo(0)
+		return
+	}
+	for _, t := range v.turns {
+		if row := v.turnRow(t.line); row > current {
+			v.jumpTo(row)
+			return
+		}
+	}
+	v.follow = true
+	v.output.ScrollToEnd()
+}
+
+// jumpTo scrolls the output so row is at the top. Like scrolling down, a
+// jump clamped at the bottom resumes following new content (see Draw).
+func (v *AIChatView) jumpTo(row int) {
+	v.output.ScrollTo(row, 0)
+	v.follow = false
+	v.scrollCheck = row
+}
+
+func (v *AIChatView) latestAnswerCmd(*tcell.EventKey) *tcell.EventKey {
+	for i := len(v.turns) - 1; i >= 0; i-- {
+		if v.turns[i].answer {
+			v.jumpTo(v.turnRow(v.turns[i].line))
+			return nil
+		}
+	}
+	v.app.Flash().Info("No answer yet")
+	return nil
+}
+
+// copyAnswerCmd copies the latest answer to the clipboard, without its
+// citation markers.
+func (v *AIChatView) copyAnswerCmd(*tcell.EventKey) *tcell.EventKey {
+	mm := v.messages()
+	i := lastAnswer(mm)
+	if i < 0 {
+		v.app.Flash().Warn("No answer to copy yet")
+		return nil
+	}
+	if err := clipboardWrite(strings.TrimSpace(ai.CitationRX.ReplaceAllString(mm[i].content, ""))); err != nil {
+		v.app.Flash().Err(err)
+		return nil
+	}
+	v.app.Flash().Info("Latest answer copied to clipboard...")
+
+	return nil
+}
+
+// scrollToEnd keeps the output pinned to the latest content unless the
+// user scrolled away from the bottom.
+func (v *AIChatView) scrollToEnd() {
+	if v.follow {
+		v.output.ScrollToEnd()
+	}
+}
+
 func (v *AIChatView) backCmd(*tcell.EventKey) *tcell.EventKey {
+	v.cancelRequest()
 	v.app.Content.Pop()
 	return nil
 }
 
 func (v *AIChatView) clearCmd(*tcell.EventKey) *tcell.EventKey {
-	v.output.Clear()
-	v.history = nil
-	scope := v.chatScope()
-	globalChatMu.Lock()
-	delete(globalChatHistories, scope)
-	globalChatMu.Unlock()
+	v.follow = true
+	v.resetOutput()
+	v.clearHistory()
+	v.persistHistory()
 	v.printWelcome()
 	return nil
 }
@@ -274,13 +543,13 @@ func (v *AIChatView) resetCmd(*tcell.EventKey) *tcell.EventKey {
 	if ai.Client != nil {
 		ai.Client.ResetSession()
 	}
-	v.output.Clear()
-	v.history = nil
-	scope := v.chatScope()
-	globalChatMu.Lock()
-	delete(globalChatHistories, scope)
-	globalChatMu.Unlock()
+	v.resetSessionTokens()
+	v.follow = true
+	v.resetOutput()
+	v.clearHistory()
+	v.persistHistory()
 	v.printWelcome()
+	v.prefetchContext()
 	v.app.Flash().Info("AI session reset")
 	return nil
 }
@@ -302,6 +571,53 @@ func (v *AIChatView) toggleFullScreenCmd(*tcell.EventKey) *tcell.EventKey {
 	return nil
 }
 
+func (v *AIChatView) toggleReasoningCmd(*tcell.EventKey) *tcell.EventKey {
+	v.mu.Lock()
+	v.showReasoning = !v.showReasoning
+	show, streaming := v.showReasoning, v.streaming
+	v.mu.Unlock()
+	// Re-rendering mid-stream would drop the partial answer; apply on completion.
+	if !streaming {
+		v.reRenderChat()
+	}
+	if show {
+		v.app.Flash().Info("Reasoning trace on")
+	} else {
+		v.app.Flash().Info("Reasoning trace collapsed")
+	}
+	return nil
+}
+
+// audienceCmd switches to the next audience. The new system message takes a
+// new session, so the conversation starts over.
+func (v *AIChatView) audienceCmd(*tcell.EventKey) *tcell.EventKey {
+	if ai.Client == nil {
+		v.app.Flash().Errf("AI client not available")
+		return nil
+	}
+	audience := nextAudience(ai.Client.Audience())
+	ai.Client.SetAudience(audience)
+	v.resetSessionTokens()
+	v.updateTitle()
+	v.app.Flash().Infof("Answers now target %s readers, new session started", audience)
+
+	return nil
+}
+
+// nextAudience returns the audience following the given one.
+func nextAudience(audience string) string {
+	i := slices.Index(config.AIAudiences, audience)
+
+	return config.AIAudiences[(i+1)%len(config.AIAudiences)]
+}
+
+func (v *AIChatView) reasoningShown() bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return v.showReasoning
+}
+
 func (v *AIChatView) modelsCmd(*tcell.EventKey) *tcell.EventKey {
 	modelsView := NewAIModelsView()
 	if err := v.app.inject(modelsView, false); err != nil {
@@ -310,12 +626,27 @@ func (v *AIChatView) modelsCmd(*tcell.EventKey) *tcell.EventKey {
 	return nil
 }
 
+// skillsCmd opens the skill picker. The chat title shows the new skill once
+// the picker closes and the chat starts again.
+func (v *AIChatView) skillsCmd(*tcell.EventKey) *tcell.EventKey {
+	if err := v.app.inject(NewAISkillsView(), false); err != nil {
+		v.app.Flash().Err(err)
+	}
+	return nil
+}
+
 // --------------------------------------------------------------------------
 // Status bar helpers
 
 func (v *AIChatView) setStatusReady() {
 	v.statusBar.Clear()
 	fmt.Fprintf(v.statusBar, " [green::b]● Ready[-::-]")
+	if ai.Client == nil {
+		return
+	}
+	if u := ai.Client.Usage(); u.Requests > 0 {
+		fmt.Fprintf(v.statusBar, "  [gray::-]%d in / %d out tokens · %d requests[-::-]", u.Input, u.Output, u.Requests)
+	}
 }
 
 func (v *AIChatView) setStatusThinking() {
@@ -333,6 +664,19 @@ func (v *AIChatView) setStatusStreaming() {
 	fmt.Fprintf(v.statusBar, " [cyan::b]● Receiving response...[-::-]")
 }
 
+// spinnerFrames animate the status bar while waiting on a full response.
+var spinnerFrames = []rune("⠋⠙⠹⠸⠼⠴⠦⠧⠇⠏")
+
+func (v *AIChatView) setStatusWaiting(frame rune) {
+	v.statusBar.Clear()
+	fmt.Fprintf(v.statusBar, " [cyan::b]%c Waiting for the full response...[-::-]  [gray::-]provider doesn't stream[-::-]", frame)
+}
+
+func (v *AIChatView) setStatusRetrying(attempt, max int) {
+	v.statusBar.Clear()
+	fmt.Fprintf(v.statusBar, " [yellow::b]↻ Retrying...[-::-]  [gray::-]attempt %d of %d, the provider is busy[-::-]", attempt, max)
+}
+
 func (v *AIChatView) setStatusTool(toolName string) {
 	v.statusBar.Clear()
 	label := toolDisplayName(toolName)
@@ -350,7 +694,7 @@ func (v *AIChatView) showThinkingIndicator() {
 	dimColor := s.Frame().Menu.FgColor
 	fmt.Fprintf(v.output, "\n  [%s::d]%s[-::-]\n", dimColor, chatSeparator)
 	fmt.Fprintf(v.output, "  [yellow::d]● Thinking...[-::-]\n")
-	v.output.ScrollToEnd()
+	v.scrollToEnd()
 }
 
 func (v *AIChatView) clearThinkingIndicator() {
@@ -383,9 +727,22 @@ func (v *AIChatView) handleInput(key tcell.Key) {
 		return
 	}
 	v.mu.Lock()
-	busy := v.streaming
+	busy, paneling := v.streaming, v.paneling
 	v.mu.Unlock()
+	if paneling {
+		v.app.Flash().Warn("Waiting on the panel answers")
+		return
+	}
 	if busy {
+		// Input typed while an answer streams steers it.
+		if text := strings.TrimSpace(v.input.GetText()); text != "" {
+			v.input.SetText("")
+			v.nudge(text)
+		}
+		return
+	}
+	if v.needsConsent() {
+		v.showConsentDialog()
 		return
 	}
 
@@ -395,14 +752,187 @@ func (v *AIChatView) handleInput(key tcell.Key) {
 	}
 	v.input.SetText("")
 
+	if v.runChatCommand(text) {
+		return
+	}
+	v.submit(text)
+}
+
+// submit sends a question typed by the user.
+func (v *AIChatView) submit(text string) {
+	question, global := stripGlobalPrefix(text)
+	if question == "" {
+		v.app.Flash().Errf("Missing question. Use %s <question>", globalPrefix)
+		return
+	}
 	// Expand quick-start shortcuts for resource-scoped chats.
-	if expanded := v.expandQuickStart(text); expanded != "" {
-		text = expanded
+	if expanded := v.expandQuickStart(question); expanded != "" && !global {
+		text, question = expanded, expanded
 	}
 
+	// Sending a new message jumps back to the live conversation.
+	v.follow = true
 	v.appendMessage("user", text)
+	v.suggestRunbooks(question)
 	v.showThinkingIndicator()
-	go v.sendMessage(text)
+	go v.sendMessage(question, global)
+}
+
+// ask sends a canned prompt on the user's behalf, showing display in its
+// place in the transcript.
+func (v *AIChatView) ask(display, prompt string) {
+	v.mu.Lock()
+	busy := v.streaming
+	v.mu.Unlock()
+	if busy {
+		v.app.Flash().Warn("AI chat is busy, wait for the current answer first")
+		return
+	}
+	if v.needsConsent() {
+		v.app.Flash().Warn("Acknowledge the AI data notice, then try again")
+		return
+	}
+
+	v.follow = true
+	v.appendMessage("user", display)
+	v.showThinkingIndicator()
+	go v.sendMessage(prompt, true)
+}
+
+// stripGlobalPrefix removes a leading !global directive. Returns the
+// remaining question and whether the directive was present.
+func stripGlobalPrefix(text string) (string, bool) {
+	rest, ok := strings.CutPrefix(text, globalPrefix)
+	if !ok || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+		return text, false
+	}
+
+	return strings.TrimSpace(rest), true
+}
+
+// runChatCommand handles slash commands typed in the chat input.
+// Returns true when the input was consumed as a command.
+func (v *AIChatView) runChatCommand(text string) bool {
+	args := strings.Fields(text)
+	switch args[0] {
+	case "/scope":
+		v.scopeCmd(args[1:])
+	case "/runbook":
+		v.runbookCmd(strings.Join(args[1:], " "))
+	case "/attach":
+		v.attachCmd(strings.Join(args[1:], " "))
+	case "/save-as":
+		v.saveAsCmd(strings.Join(args[1:], " "))
+	case "/load":
+		v.loadCmd(strings.Join(args[1:], " "))
+	case "/context":
+		v.contextCmd(strings.Join(args[1:], " "))
+	case "/panel":
+		v.panelCmd(args[1:])
+	default:
+		return false
+	}
+
+	return true
+}
+
+// scopeCmd re-scopes the chat to another resource.
+// Usage: /scope kind/name [-n namespace] [--new]. With no target the chat
+// goes back to cluster scope.
+func (v *AIChatView) scopeCmd(args []string) {
+	kind, name, ns, fresh, err := parseScopeArgs(args)
+	if err != nil {
+		v.app.Flash().Err(err)
+		return
+	}
+	if kind == "" {
+		v.switchScope("", "", "", fresh)
+		v.app.Flash().Info("AI chat scoped to cluster")
+		return
+	}
+
+	if v.app.command == nil || v.app.command.alias == nil || v.app.factory == nil {
+		v.app.Flash().Errf("No cluster connection available")
+		return
+	}
+	gvr, ok := v.app.command.alias.Resolve(cmd.NewInterpreter(kind))
+	if !ok {
+		v.app.Flash().Errf("Unknown resource kind %q", kind)
+		return
+	}
+
+	if namespaced, err := dao.MetaAccess.IsNamespaced(gvr); err == nil && !namespaced {
+		ns = ""
+	} else if ns == "" {
+		ns = v.resNamespace
+		if ns == "" {
+			ns = client.CleanseNamespace(v.app.Config.ActiveNamespace())
+		}
+	}
+
+	go func() {
+		if _, err := v.app.factory.Get(gvr, client.FQN(ns, name), true, labels.Everything()); err != nil {
+			v.queueDraw(func() {
+				v.app.Flash().Errf("Unable to scope chat to %s %s: %s", gvr.R(), client.FQN(ns, name), err)
+			})
+			return
+		}
+		v.queueDraw(func() {
+			v.switchScope(gvr.R(), name, ns, fresh)
+			v.app.Flash().Infof("AI chat scoped to %s %s", gvr.R(), client.FQN(ns, name))
+		})
+	}()
+}
+
+// parseScopeArgs parses the /scope arguments. The kind and name are empty
+// when no target is given.
+func parseScopeArgs(args []string) (kind, name, ns string, fresh bool, err error) {
+	var target string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "-n", "--namespace":
+			if i+1 < len(args) {
+				i++
+				ns = args[i]
+			}
+		case "--new":
+			fresh = true
+		default:
+			target = args[i]
+		}
+	}
+	if target == "" {
+		return "", "", ns, fresh, nil
+	}
+
+	kind, name, ok := strings.Cut(target, "/")
+	if !ok || kind == "" || name == "" {
+		return "", "", "", false, fmt.Errorf("invalid scope %q. Use /scope kind/name [-n namespace] [--new]", target)
+	}
+
+	return kind, name, ns, fresh, nil
+}
+
+// switchScope points the chat at a new resource context and replays that
+// context's history. When fresh is set, the target's history is discarded.
+func (v *AIChatView) switchScope(kind, name, ns string, fresh bool) {
+	v.SetResourceContext(kind, name, ns)
+	v.selectSkill()
+	v.updateTitle()
+	if fresh {
+		globalChatMu.Lock()
+		delete(globalChatHistories, v.chatScope())
+		globalChatMu.Unlock()
+	}
+
+	v.resetOutput()
+	v.setHistory(nil)
+	v.swapPrefetched("")
+	if !v.restoreHistory() {
+		v.printWelcome()
+		v.prefetchContext()
+	}
+	v.restorePlaceholder()
 }
 
 // expandQuickStart converts shortcut numbers to full prompts for resource chats.
@@ -414,11 +944,18 @@ func (v *AIChatView) expandQuickStart(text string) string {
 	if ns == "" {
 		ns = "default"
 	}
+	cfg := v.app.Config.K9s.ActiveAI()
 	switch text {
 	case "1":
-		return fmt.Sprintf("Diagnose the %s '%s' in namespace '%s'. Check its status, recent events, logs if applicable, and suggest fixes for any issues.", v.resKind, v.resName, ns)
+		if cfg.DiagnosePrompt != "" {
+			slog.Info("Using custom AI diagnose prompt", slogs.Subsys, "ai")
+		}
+		return cfg.DiagnosePromptFor(v.resKind, v.resName, ns)
 	case "2":
-		return fmt.Sprintf("Explain the %s '%s' in namespace '%s'. Describe its current state, configuration, and how it relates to other resources. Highlight anything unusual.", v.resKind, v.resName, ns)
+		if cfg.ExplainPrompt != "" {
+			slog.Info("Using custom AI explain prompt", slogs.Subsys, "ai")
+		}
+		return cfg.ExplainPromptFor(v.resKind, v.resName, ns)
 	case "3":
 		return fmt.Sprintf("Show all resources related to the %s '%s' in namespace '%s' — services, configmaps, secrets, ingress, PVCs, network policies, and any other connected resources.", v.resKind, v.resName, ns)
 	case "4":
@@ -427,20 +964,153 @@ func (v *AIChatView) expandQuickStart(text string) string {
 	return ""
 }
 
-func (v *AIChatView) sendMessage(text string) {
+// continueCmd asks the model to resume the latest answer when it was cut off.
+func (v *AIChatView) continueCmd(*tcell.EventKey) *tcell.EventKey {
+	v.mu.Lock()
+	busy := v.streaming
+	v.mu.Unlock()
+	if busy {
+		return nil
+	}
+	mm := v.messages()
+	if i := lastAnswer(mm); i < 0 || !mm[i].truncated {
+		v.app.Flash().Info("No truncated answer to continue")
+		return nil
+	}
+
+	// Drop the truncated marker so the continuation streams right after the answer.
+	v.extendAnswer("", false, nil)
+	v.follow = true
+	v.reRenderChat()
+	fmt.Fprint(v.output, "    ")
+	go v.send(ai.ContinuePrompt, true)
+
+	return nil
+}
+
+// rerunCmd resends the latest question, e.g. after switching models.
+func (v *AIChatView) rerunCmd(*tcell.EventKey) *tcell.EventKey {
+	v.mu.Lock()
+	busy, paneling := v.streaming, v.paneling
+	v.mu.Unlock()
+	if busy || paneling {
+		return nil
+	}
+	text := lastQuestion(v.messages())
+	if text == "" {
+		v.app.Flash().Info("No question to rerun")
+		return nil
+	}
+	if v.needsConsent() {
+		v.showConsentDialog()
+		return nil
+	}
+	v.submit(text)
+
+	return nil
+}
+
+// sendMessage sends the question to the AI. Unless global is set, scoped
+// chats wrap the question with the resource context.
+func (v *AIChatView) sendMessage(text string, global bool) {
+	// Scope the prompt to the workload context if applicable.
+	prompt := text
+	if !global {
+		prompt = v.buildContextualPrompt(text)
+		if bundle := v.swapPrefetched(""); bundle != "" {
+			prompt = bundle + "\n" + prompt
+		}
+	}
+	if a := v.swapAttachment(nil); a != nil {
+		prompt = a.Prompt() + "\n" + prompt
+	}
+	v.trimContext(prompt)
+	if recap := v.swapRecap(""); recap != "" {
+		prompt = recap + "\n" + prompt
+	}
+	v.send(prompt, false)
+}
+
+// errChatClosed cancels the request in flight when the user leaves the chat.
+var errChatClosed = errors.New("AI chat closed")
+
+// trackRequest returns the context of a new request, canceled by
+// cancelRequest. Call done once the request completes.
+func (v *AIChatView) trackRequest() (ctx context.Context, done func()) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	v.mu.Lock()
+	v.cancel = cancel
+	v.mu.Unlock()
+
+	return ctx, func() {
+		cancel(nil)
+		v.mu.Lock()
+		v.cancel = nil
+		v.mu.Unlock()
+	}
+}
+
+// cancelRequest cancels the request in flight, if any.
+func (v *AIChatView) cancelRequest() {
+	v.mu.Lock()
+	cancel := v.cancel
+	v.mu.Unlock()
+	if cancel != nil {
+		cancel(errChatClosed)
+	}
+}
+
+// queueDraw runs f on the UI goroutine unless the view is off screen, so
+// answers streaming in after the user left don't draw into a detached view.
+func (v *AIChatView) queueDraw(f func()) {
+	if v.closed.Load() {
+		return
+	}
+	v.app.QueueUpdateDraw(func() {
+		if v.closed.Load() {
+			return
+		}
+		f()
+	})
+}
+
+// resync redraws the chat from its history, catching up on the draws
+// dropped while the view was off screen.
+func (v *AIChatView) resync() {
+	v.mu.Lock()
+	v.thinkingShown, v.streamingHeader = false, false
+	busy := v.streaming
+	v.mu.Unlock()
+
+	v.reRenderChat()
+	if busy {
+		v.showThinkingIndicator()
+		v.setStatusThinking()
+		return
+	}
+	v.restorePlaceholder()
+	v.setStatusReady()
+}
+
+// emptyResponseNotice stands in for answers with no text, e.g. when the
+// model only ran tools.
+const emptyResponseNotice = "(no text response — the assistant only ran tools; ask a follow-up)"
+
+// send streams the prompt response to the output. When resume is set the
+// response continues the latest answer rather than starting a new one.
+func (v *AIChatView) send(prompt string, resume bool) {
 	v.mu.Lock()
 	if v.streaming {
 		v.mu.Unlock()
 		return
 	}
 	v.streaming = true
-	v.streamingHeader = false
+	v.streamingHeader = resume
 	v.mu.Unlock()
 
-	// Disable input while processing.
-	v.app.QueueUpdateDraw(func() {
-		v.input.SetAcceptanceFunc(func(string, rune) bool { return false })
-		v.input.SetPlaceholder("Waiting for response...")
+	// Input typed while processing nudges the answer in flight.
+	v.queueDraw(func() {
+		v.input.SetPlaceholder("Type to nudge the answer, Ctrl+X to interrupt and correct...")
 		v.setStatusThinking()
 	})
 
@@ -448,12 +1118,14 @@ func (v *AIChatView) sendMessage(text string) {
 		v.mu.Lock()
 		v.streaming = false
 		v.mu.Unlock()
-		v.app.QueueUpdateDraw(func() {
-			v.input.SetAcceptanceFunc(nil)
+		v.queueDraw(func() {
 			v.restorePlaceholder()
 			v.setStatusReady()
 			v.app.SetFocus(v.input)
 		})
+		if correction, ok := v.takeCorrection(); ok {
+			go v.correct(correction)
+		}
 	}()
 
 	if ai.Client == nil {
@@ -461,17 +1133,28 @@ func (v *AIChatView) sendMessage(text string) {
 		return
 	}
 
-	// Scope the prompt to the workload context if applicable.
-	prompt := v.buildContextualPrompt(text)
-
 	var streamedContent strings.Builder
 	var streamMu sync.Mutex
-	err := ai.Client.Send(context.Background(), prompt, &chatListener{
+	l := chatListener{
 		view:            v,
 		streamedContent: &streamedContent,
 		mu:              &streamMu,
-	})
-
+	}
+	// Failed or canceled turns never complete, stop the delta flusher anyway.
+	defer l.stopFlush()
+	defer l.stopSpinner()
+	ctx, done := v.trackRequest()
+	defer done()
+	v.addSessionTokens(estimateTokens(prompt))
+	err := ai.Client.Send(ctx, prompt, &l)
+
+	if errors.Is(err, ai.ErrInterrupted) || errors.Is(context.Cause(ctx), errChatClosed) {
+		streamMu.Lock()
+		partial := streamedContent.String()
+		streamMu.Unlock()
+		v.recordInterrupted(partial, resume)
+		return
+	}
 	if err != nil {
 		slog.Error("AI request failed", slogs.Error, err)
 		v.appendError(err.Error())
@@ -480,23 +1163,27 @@ func (v *AIChatView) sendMessage(text string) {
 
 	// Save the final response to history for persistence.
 	streamMu.Lock()
-	finalContent := streamedContent.String()
+	finalContent, truncated := streamedContent.String(), l.truncated
 	streamMu.Unlock()
+	v.addSessionTokens(estimateTokens(finalContent))
 
-	if finalContent != "" {
-		// Don't re-render — already streamed to output. Just persist.
-		msg := chatMessage{role: "assistant", content: finalContent}
-		v.history = append(v.history, msg)
-		scope := v.chatScope()
-		globalChatMu.Lock()
-		globalChatHistories[scope] = append(globalChatHistories[scope], msg)
-		globalChatMu.Unlock()
+	if strings.TrimSpace(finalContent) == "" {
+		v.appendMessage("system", emptyResponseNotice)
+		return
+	}
 
-		// Re-render with proper markdown formatting (streaming was raw text).
-		v.app.QueueUpdateDraw(func() {
-			v.reRenderChat()
-		})
+	// Don't re-render — already streamed to output. Just persist.
+	if resume {
+		v.extendAnswer(finalContent, truncated, ai.Client.TurnSources())
+	} else {
+		v.recordMessage(chatMessage{role: "assistant", content: finalContent, sources: ai.Client.TurnSources(), truncated: truncated})
 	}
+	v.persistHistory()
+
+	// Re-render with proper markdown formatting (streaming was raw text).
+	v.queueDraw(func() {
+		v.reRenderChat()
+	})
 }
 
 // --------------------------------------------------------------------------
@@ -504,6 +1191,14 @@ func (v *AIChatView) sendMessage(text string) {
 
 // chatScope returns the history scope key for this chat view.
 func (v *AIChatView) chatScope() string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return v.scopeKey()
+}
+
+// scopeKey returns the history scope key. Callers must hold v.mu.
+func (v *AIChatView) scopeKey() string {
 	if v.resKind == "" || v.resName == "" {
 		return "_global_"
 	}
@@ -516,11 +1211,13 @@ func (v *AIChatView) chatScope() string {
 // buildContextualPrompt wraps the user's question with workload context
 // so the AI focuses on the specific resource, not the whole cluster.
 func (v *AIChatView) buildContextualPrompt(text string) string {
-	if v.resKind == "" || v.resName == "" {
+	v.mu.Lock()
+	kind, name, ns := v.resKind, v.resName, v.resNamespace
+	v.mu.Unlock()
+	if kind == "" || name == "" {
 		return text
 	}
 
-	ns := v.resNamespace
 	if ns == "" {
 		ns = "(cluster-scoped)"
 	}
@@ -539,63 +1236,234 @@ Do NOT analyze unrelated cluster-wide resources unless the user explicitly asks.
 When using diagnostic tools, scope queries to this resource and its namespace.
 
 [USER QUESTION]
-%s`, v.resKind, v.resName, ns, v.resKind, text)
+%s`, kind, name, ns, kind, text)
 }
 
 // reRenderChat clears and re-renders the full chat with proper formatting.
+// The scroll position is kept when the user is reading earlier content.
 func (v *AIChatView) reRenderChat() {
-	v.output.Clear()
+	row, col := v.output.GetScrollOffset()
+	v.resetOutput()
 	v.printWelcome()
-	for _, msg := range v.history {
-		v.renderMessage(msg.role, msg.content)
+	v.renderHistory(v.messages())
+	if v.follow {
+		v.output.ScrollToEnd()
+		return
 	}
-	v.output.ScrollToEnd()
+	v.output.ScrollTo(row, col)
 }
 
-func (v *AIChatView) appendMessage(role, content string) {
-	msg := chatMessage{role: role, content: content}
-	v.history = append(v.history, msg)
+// recordMessage appends a message to the history and its persisted scope.
+// History is appended from the UI goroutine, the send goroutine and the AI
+// listener callbacks, so all access goes through v.mu. Messages are recorded
+// when the event happens rather than when its rendering is drawn, keeping
+// history in event order.
+func (v *AIChatView) recordMessage(msg chatMessage) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
 
-	// Persist to scoped global store.
-	scope := v.chatScope()
+	v.history = append(v.history, msg)
+	scope := v.scopeKey()
 	globalChatMu.Lock()
 	globalChatHistories[scope] = append(globalChatHistories[scope], msg)
 	globalChatMu.Unlock()
+}
+
+// extendAnswer appends a continuation to the latest answer and its persisted
+// copy, updating whether the answer is still truncated.
+func (v *AIChatView) extendAnswer(content string, truncated bool, sources []ai.Source) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	extend := func(mm []chatMessage) {
+		if i := lastAnswer(mm); i >= 0 {
+			mm[i].content += content
+			mm[i].truncated = truncated
+			mm[i].sources = append(mm[i].sources, sources...)
+		}
+	}
+	extend(v.history)
+	globalChatMu.Lock()
+	extend(globalChatHistories[v.scopeKey()])
+	globalChatMu.Unlock()
+}
 
-	v.app.QueueUpdateDraw(func() {
+// lastAnswer returns the index of the latest assistant message or -1.
+func lastAnswer(mm []chatMessage) int {
+	for i := len(mm) - 1; i >= 0; i-- {
+		if mm[i].role == "assistant" {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// lastQuestion returns the latest question asked by the user or "".
+func lastQuestion(mm []chatMessage) string {
+	for i := len(mm) - 1; i >= 0; i-- {
+		if mm[i].role == "user" {
+			return mm[i].content
+		}
+	}
+
+	return ""
+}
+
+// setHistory replaces the view history without touching the persisted scope.
+func (v *AIChatView) setHistory(mm []chatMessage) {
+	v.mu.Lock()
+	v.history = mm
+	v.mu.Unlock()
+}
+
+// clearHistory drops the view history along with its persisted scope.
+func (v *AIChatView) clearHistory() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.history = nil
+	globalChatMu.Lock()
+	delete(globalChatHistories, v.scopeKey())
+	globalChatMu.Unlock()
+}
+
+// messages returns a snapshot of the chat history.
+func (v *AIChatView) messages() []chatMessage {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return slices.Clone(v.history)
+}
+
+// swapPrefetched replaces the pending context bundle and returns the old one.
+func (v *AIChatView) swapPrefetched(bundle string) string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	old := v.prefetched
+	v.prefetched = bundle
+
+	return old
+}
+
+// prefetchContext loads the configured context bundle for a scoped chat in
+// the background. The bundle is shown in the chat and sent along with the
+// next question.
+func (v *AIChatView) prefetchContext() {
+	parts := v.app.Config.K9s.ActiveAI().PrefetchParts()
+	if len(parts) == 0 || v.app.factory == nil || v.app.Conn() == nil || !v.app.Conn().ConnectionOK() {
+		return
+	}
+	v.mu.Lock()
+	kind, name, ns, scope := v.resKind, v.resName, v.resNamespace, v.scopeKey()
+	v.mu.Unlock()
+	if kind == "" || name == "" {
+		return
+	}
+
+	tf := ai.NewToolFactory(v.app.factory, v.app.Conn(), v.app.Config.K9s.ActiveAI(), slog.Default())
+	go func() {
+		bundle, err := tf.ContextBundle(context.Background(), kind, name, ns, parts)
+		if err != nil {
+			slog.Warn("AI context prefetch failed", slogs.Error, err)
+			return
+		}
+		if bundle == "" || v.chatScope() != scope {
+			return
+		}
+		v.swapPrefetched(bundle)
+		v.recordMessage(chatMessage{role: "context", content: bundle, activity: true})
+		v.queueDraw(func() {
+			v.renderMessage("context", bundle)
+			v.scrollToEnd()
+		})
+	}()
+}
+
+func (v *AIChatView) appendMessage(role, content string) {
+	v.recordMessage(chatMessage{role: role, content: content})
+	v.persistHistory()
+
+	v.queueDraw(func() {
 		v.renderMessage(role, content)
-		v.output.ScrollToEnd()
+		v.scrollToEnd()
 	})
 }
 
+// renderHistory writes the given messages to the output, adding source
+// footnotes to answers that cite tool results.
+func (v *AIChatView) renderHistory(mm []chatMessage) {
+	srcs := sourceIndex(mm)
+	for _, msg := range mm {
+		if msg.intent != nil {
+			v.renderPlan(*msg.intent)
+			continue
+		}
+		v.renderMessage(msg.role, msg.content+repeatSuffix(msg.repeats+1))
+		if msg.role != "assistant" {
+			continue
+		}
+		v.renderCitations(msg.content, srcs)
+		if msg.truncated {
+			fmt.Fprintf(v.output, "    [yellow::d](response truncated — press Ctrl+Space to continue)[-::-]\n")
+		}
+	}
+}
+
 // renderMessage writes a formatted message directly to the output.
 // Must be called from the UI goroutine or during Init (before display).
 func (v *AIChatView) renderMessage(role, content string) {
 	s := v.app.Styles
-	hlColor := s.Frame().Title.HighlightColor
+	th := newChatTheme(s)
 	dimColor := s.Frame().Menu.FgColor
 
 	switch role {
 	case "user":
+		v.markTurn(false)
 		fmt.Fprintf(v.output, "\n  [%s::d]%s[-::-]\n", dimColor, chatSeparator)
-		fmt.Fprintf(v.output, "  [%s::b]▶ You[-::-]\n", hlColor)
+		fmt.Fprintf(v.output, "  [%s::b]%s You[-::-]\n", th.userColor, th.userGlyph)
 		for _, line := range strings.Split(content, "\n") {
-			fmt.Fprintf(v.output, "    %s\n", line)
+			v.writeWrapped("    ", "    ", line)
 		}
 
 	case "assistant":
+		v.markTurn(true)
 		fmt.Fprintf(v.output, "\n  [%s::d]%s[-::-]\n", dimColor, chatSeparator)
-		fmt.Fprintf(v.output, "  [%s::b]✦ Copilot[-::-]\n", s.Frame().Status.AddColor)
+		fmt.Fprintf(v.output, "  [%s::b]%s Copilot[-::-]\n", th.assistantColor, th.assistantGlyph)
 		v.renderFormattedContent(content)
 
 	case "system":
 		fmt.Fprintf(v.output, "\n    [gray::d]%s[-::-]\n", content)
 
 	case "reasoning":
-		fmt.Fprintf(v.output, "    [%s::d]○ %s[-::-]\n", dimColor, content)
+		if !v.reasoningShown() {
+			fmt.Fprintf(v.output, "    [%s::d]○ Reasoning (%d lines, Ctrl-T to expand)[-::-]\n",
+				dimColor, strings.Count(strings.TrimSpace(content), "\n")+1)
+			break
+		}
+		// The dim style stays on across the wrapped lines until the reset.
+		fmt.Fprintf(v.output, "    [%s::d]○ Reasoning\n", dimColor)
+		for _, line := range strings.Split(strings.TrimSpace(content), "\n") {
+			v.writeWrapped("    ┆ ", "    ┆ ", tview.Escape(line))
+		}
+		fmt.Fprint(v.output, "[-::-]")
 
 	case "activity":
-		fmt.Fprintf(v.output, "    [%s::d]⚡ %s[-::-]\n", dimColor, content)
+		fmt.Fprintf(v.output, "    [%s::d]%s %s[-::-]\n", th.activityColor, th.activityGlyph, content)
+
+	case "context":
+		header, body, _ := strings.Cut(strings.TrimSpace(content), "\n")
+		fmt.Fprintf(v.output, "\n    [%s::d]⧉ Prefetched context · %s[-::-]\n",
+			dimColor, tview.Escape(strings.TrimPrefix(header, ai.PrefetchHeader+" ")))
+		lines := strings.Split(body, "\n")
+		for i, line := range lines {
+			if i == maxContextPreviewLines {
+				fmt.Fprintf(v.output, "    [%s::d]┆ … %d more lines[-::-]\n", dimColor, len(lines)-i)
+				break
+			}
+			fmt.Fprintf(v.output, "    [%s::d]┆ %s[-::-]\n", dimColor, tview.Escape(line))
+		}
 	}
 }
 
@@ -613,19 +1481,18 @@ func (v *AIChatView) restoreHistory() bool {
 		return false
 	}
 
-	v.history = msgs
-	for _, msg := range msgs {
-		v.renderMessage(msg.role, msg.content)
-	}
-	v.output.ScrollToEnd()
+	v.setHistory(msgs)
+	v.renderHistory(msgs)
+	v.scrollToEnd()
 
 	return true
 }
 
 func (v *AIChatView) appendError(msg string) {
-	v.app.QueueUpdateDraw(func() {
-		fmt.Fprintf(v.output, "\n    [red::b]✖ Error:[-::-] [red::-]%s[-::-]\n", msg)
-		v.output.ScrollToEnd()
+	v.queueDraw(func() {
+		th := newChatTheme(v.app.Styles)
+		fmt.Fprintf(v.output, "\n    [%s::b]%s Error:[-::-] [%s::-]%s[-::-]\n", th.errorColor, th.errorGlyph, th.errorColor, msg)
+		v.scrollToEnd()
 	})
 }
 
@@ -638,6 +1505,13 @@ func (v *AIChatView) renderFormattedContent(content string) {
 	s := v.app.Styles
 	codeColor := s.Frame().Menu.FgColor
 	highlightColor := s.Frame().Title.HighlightColor
+	palette := newCodePalette(s)
+	lang := ""
+
+	// Code lines are hard-wrapped so long lines don't spill past the block
+	// borders.
+	blockWidth := v.codeBlockWidth()
+	codeWidth := blockWidth - 2
 
 	flushTable := func() {
 		if len(tableRows) == 0 {
@@ -655,21 +1529,24 @@ func (v *AIChatView) renderFormattedContent(content string) {
 			flushTable()
 			inCodeBlock = !inCodeBlock
 			if inCodeBlock {
-				lang := strings.TrimPrefix(trimmed, "```")
+				lang = strings.TrimPrefix(trimmed, "```")
 				lang = strings.TrimSpace(lang)
 				if lang != "" {
-					fmt.Fprintf(v.output, "\n    [%s::d]┌─ %s ─────────────────────[-::-]\n", codeColor, lang)
+					rule := strings.Repeat("─", maxInt(3, blockWidth-runewidth.StringWidth(lang)-4))
+					fmt.Fprintf(v.output, "\n    [%s::d]┌─ %s %s[-::-]\n", codeColor, lang, rule)
 				} else {
-					fmt.Fprintf(v.output, "\n    [%s::d]┌──────────────────────────[-::-]\n", codeColor)
+					fmt.Fprintf(v.output, "\n    [%s::d]┌%s[-::-]\n", codeColor, strings.Repeat("─", blockWidth-1))
 				}
 			} else {
-				fmt.Fprintf(v.output, "    [%s::d]└──────────────────────────[-::-]\n\n", codeColor)
+				fmt.Fprintf(v.output, "    [%s::d]└%s[-::-]\n\n", codeColor, strings.Repeat("─", blockWidth-1))
 			}
 			continue
 		}
 
 		if inCodeBlock {
-			fmt.Fprintf(v.output, "    [%s::d]│[-::-] [%s::-]%s[-::-]\n", codeColor, highlightColor, line)
+			for _, chunk := range chunkString(line, codeWidth) {
+				fmt.Fprintf(v.output, "    [%s::d]│[-::-] %s\n", codeColor, highlightCode(lang, chunk, palette))
+			}
 			continue
 		}
 
@@ -703,14 +1580,14 @@ func (v *AIChatView) renderFormattedContent(content string) {
 		if strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* ") {
 			rest := trimmed[2:]
 			rest = renderInlineFormatting(rest)
-			fmt.Fprintf(v.output, "    [%s::-]•[-::-] %s\n", highlightColor, rest)
+			v.writeWrapped(fmt.Sprintf("    [%s::-]•[-::-] ", highlightColor), "      ", rest)
 			continue
 		}
 
 		// Numbered lists.
 		if numberedListRe.MatchString(trimmed) {
 			formatted := renderInlineFormatting(trimmed)
-			fmt.Fprintf(v.output, "    %s\n", formatted)
+			v.writeWrapped("    ", "       ", formatted)
 			continue
 		}
 
@@ -722,7 +1599,7 @@ func (v *AIChatView) renderFormattedContent(content string) {
 
 		// Regular text.
 		formatted := renderInlineFormatting(trimmed)
-		fmt.Fprintf(v.output, "    %s\n", formatted)
+		v.writeWrapped("    ", "    ", formatted)
 	}
 	flushTable()
 }
@@ -830,6 +1707,21 @@ func stripFormatting(s string) string {
 	return s
 }
 
+// chunkString splits s into pieces of at most n runes. A non-positive n
+// returns s unsplit.
+func chunkString(s string, n int) []string {
+	rr := []rune(s)
+	if n <= 0 || len(rr) <= n {
+		return []string{s}
+	}
+	chunks := make([]string, 0, len(rr)/n+1)
+	for len(rr) > n {
+		chunks = append(chunks, string(rr[:n]))
+		rr = rr[n:]
+	}
+	return append(chunks, string(rr))
+}
+
 func maxInt(a, b int) int {
 	if a > b {
 		return a
@@ -841,6 +1733,9 @@ func maxInt(a, b int) int {
 func renderInlineFormatting(text string) string {
 	text = boldRe.ReplaceAllString(text, "[::b]$1[-::-]")
 	text = codeRe.ReplaceAllString(text, "[aqua::-]$1[-::-]")
+	text = ai.CitationRX.ReplaceAllStringFunc(text, func(m string) string {
+		return m[:1] + "[::d]" + superscript(strings.Trim(m[1:], "[]")) + "[::-]"
+	})
 	return text
 }
 
@@ -868,7 +1763,7 @@ func (v *AIChatView) printWelcome() {
 				"    [%s::b]2[-::-]  Explain this %s — describe config and relationships\n"+
 				"    [%s::b]3[-::-]  Show related resources — services, configmaps, ingress\n"+
 				"    [%s::b]4[-::-]  Check events — recent warnings and errors\n\n"+
-				"  [%s::d]PgUp/PgDn scroll  ·  ↑↓ scroll  ·  Ctrl+R reset  [-::-]\n",
+				"  [%s::d]PgUp/PgDn scroll  ·  ↑↓ scroll  ·  Shift+↑↓ turns  ·  Ctrl+L latest answer  ·  Ctrl+Space continue  ·  Ctrl+X interrupt  ·  Ctrl+R reset  ·  /scope kind/name switch  ·  /runbook save  ·  /save-as name  ·  /load  ·  /attach file  ·  !global ask cluster-wide[-::-]\n",
 			addColor, dimColor, label,
 			dimColor, label, dimColor, v.resKind,
 			dimColor,
@@ -888,7 +1783,7 @@ func (v *AIChatView) printWelcome() {
 				"    [%s::-]•[-::-] Diagnose pod crashes, OOM kills, image pull errors\n"+
 				"    [%s::-]•[-::-] Fix deployments by patching, scaling, or restarting\n"+
 				"    [%s::-]•[-::-] Analyze events, logs, RBAC, and cluster health\n\n"+
-				"  [%s::d]PgUp/PgDn scroll  ·  ↑↓ scroll  ·  Ctrl+R reset [-::-]\n",
+				"  [%s::d]PgUp/PgDn scroll  ·  ↑↓ scroll  ·  Shift+↑↓ turns  ·  Ctrl+L latest answer  ·  Ctrl+X interrupt  ·  Ctrl+R reset  ·  /scope kind/name focus  ·  /save-as name  ·  /load  ·  /attach file [-::-]\n",
 			addColor,
 			dimColor,
 			dimColor,
@@ -899,8 +1794,44 @@ func (v *AIChatView) printWelcome() {
 	}
 }
 
+// openAIChat brings an open chat back to the top of the view stack, re-scoping
+// it to the given resource, so repeated requests don't pile up chat views.
+// A new chat is created when none is open.
+func (a *App) openAIChat(kind, name, ns string) error {
+	stack := a.Content.Peek()
+	for i := len(stack) - 1; i >= 0; i-- {
+		chat, ok := stack[i].(*AIChatView)
+		if !ok {
+			continue
+		}
+		for range len(stack) - 1 - i {
+			a.Content.Pop()
+		}
+		if chat.resKind == kind && chat.resName == name && chat.resNamespace == ns {
+			return nil
+		}
+		chat.mu.Lock()
+		busy := chat.streaming
+		chat.mu.Unlock()
+		if busy {
+			a.Flash().Warn("AI chat is busy, wait for the current answer before switching resources")
+			return nil
+		}
+		chat.switchScope(kind, name, ns, false)
+		return nil
+	}
+
+	chat := NewAIChatView()
+	chat.SetResourceContext(kind, name, ns)
+
+	return a.inject(chat, false)
+}
+
 // SetResourceContext sets the resource context for the chat view.
 func (v *AIChatView) SetResourceContext(kind, name, ns string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
 	v.resKind = kind
 	v.resName = name
 	v.resNamespace = ns
@@ -931,14 +1862,23 @@ type chatListener struct {
 	streamedContent *strings.Builder
 	mu              *sync.Mutex
 	// Streaming delta throttle buffer.
-	deltaBuf     strings.Builder
-	deltaBufMu   sync.Mutex
-	flushTicker  *time.Ticker
-	flushStop    chan struct{}
+	deltaBuf    strings.Builder
+	deltaBufMu  sync.Mutex
+	flushTicker *time.Ticker
+	flushStop   chan struct{}
+	// reasoningOpen is set while a reasoning block is streaming (UI goroutine only).
+	reasoningOpen bool
+	// truncated is set when the response was cut off, guarded by mu.
+	truncated bool
+	// Spinner shown while waiting on a provider that doesn't stream. It
+	// yields the status bar to running tools, guarded by spinMu.
+	spinMu   sync.Mutex
+	spinStop chan struct{}
+	tools    int
 }
 
 func (l *chatListener) AIResponseStart() {
-	l.view.app.QueueUpdateDraw(func() {
+	l.view.queueDraw(func() {
 		l.view.setStatusStreaming()
 	})
 }
@@ -954,18 +1894,19 @@ func (l *chatListener) AIResponseDelta(delta string) {
 	if l.flushTicker == nil {
 		l.flushStop = make(chan struct{})
 		l.flushTicker = time.NewTicker(33 * time.Millisecond)
-		go l.flushLoop()
+		go l.flushLoop(l.flushTicker, l.flushStop)
 	}
 	l.deltaBufMu.Unlock()
 }
 
-// flushLoop drains the delta buffer to the UI at a throttled rate.
-func (l *chatListener) flushLoop() {
+// flushLoop drains the delta buffer to the UI at a throttled rate. The ticker
+// and stop channel are passed in since stopFlush resets the listener fields.
+func (l *chatListener) flushLoop(ticker *time.Ticker, stop <-chan struct{}) {
 	for {
 		select {
-		case <-l.flushTicker.C:
+		case <-ticker.C:
 			l.flushDeltaBuffer()
-		case <-l.flushStop:
+		case <-stop:
 			l.flushDeltaBuffer()
 			return
 		}
@@ -981,8 +1922,9 @@ func (l *chatListener) flushDeltaBuffer() {
 	chunk := l.deltaBuf.String()
 	l.deltaBuf.Reset()
 	l.deltaBufMu.Unlock()
+	l.stopSpinner()
 
-	l.view.app.QueueUpdateDraw(func() {
+	l.view.queueDraw(func() {
 		// Clear thinking indicator on first real content.
 		l.view.clearThinkingIndicator()
 
@@ -993,13 +1935,15 @@ func (l *chatListener) flushDeltaBuffer() {
 			l.view.mu.Unlock()
 			s := l.view.app.Styles
 			dimColor := s.Frame().Menu.FgColor
+			l.view.markTurn(true)
 			fmt.Fprintf(l.view.output, "\n  [%s::d]%s[-::-]\n", dimColor, chatSeparator)
-			fmt.Fprintf(l.view.output, "  [%s::b]✦ Copilot[-::-]\n    ", s.Frame().Status.AddColor)
+			th := newChatTheme(s)
+			fmt.Fprintf(l.view.output, "  [%s::b]%s Copilot[-::-]\n    ", th.assistantColor, th.assistantGlyph)
 		} else {
 			l.view.mu.Unlock()
 		}
 		fmt.Fprint(l.view.output, chunk)
-		l.view.output.ScrollToEnd()
+		l.view.scrollToEnd()
 		l.view.setStatusStreaming()
 	})
 }
@@ -1017,6 +1961,7 @@ func (l *chatListener) stopFlush() {
 func (l *chatListener) AIResponseComplete(text string) {
 	// Stop the throttle ticker and flush any remaining buffered deltas.
 	l.stopFlush()
+	l.stopSpinner()
 
 	if text != "" {
 		l.mu.Lock()
@@ -1025,63 +1970,164 @@ func (l *chatListener) AIResponseComplete(text string) {
 		l.mu.Unlock()
 	}
 	// Add trailing newline after streamed content.
-	l.view.app.QueueUpdateDraw(func() {
+	l.view.queueDraw(func() {
 		fmt.Fprint(l.view.output, "\n")
-		l.view.output.ScrollToEnd()
+		l.view.scrollToEnd()
+	})
+}
+
+func (l *chatListener) AIResponseTruncated() {
+	l.mu.Lock()
+	l.truncated = true
+	l.mu.Unlock()
+}
+
+// AIRetrying shows the resend of a turn that hit a transient error.
+func (l *chatListener) AIRetrying(attempt, max int, _ error) {
+	l.view.queueDraw(func() {
+		l.view.setStatusRetrying(attempt, max)
 	})
 }
 
+// AIStreamUnavailable spins the status bar until the response arrives in one
+// go, so a provider that doesn't stream doesn't look frozen.
+func (l *chatListener) AIStreamUnavailable() {
+	l.spinMu.Lock()
+	defer l.spinMu.Unlock()
+
+	if l.spinStop != nil {
+		return
+	}
+	l.spinStop = make(chan struct{})
+	go l.spin(l.spinStop)
+}
+
+func (l *chatListener) spin(stop <-chan struct{}) {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for i := 0; ; i++ {
+		frame := spinnerFrames[i%len(spinnerFrames)]
+		l.view.queueDraw(func() {
+			if !l.toolRunning() {
+				l.view.setStatusWaiting(frame)
+			}
+		})
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// toolRunning checks if a tool call is in flight, its status line then
+// shows instead of the spinner.
+func (l *chatListener) toolRunning() bool {
+	l.spinMu.Lock()
+	defer l.spinMu.Unlock()
+
+	return l.tools > 0
+}
+
+func (l *chatListener) stopSpinner() {
+	l.spinMu.Lock()
+	defer l.spinMu.Unlock()
+
+	if l.spinStop != nil {
+		close(l.spinStop)
+		l.spinStop = nil
+	}
+}
+
 func (l *chatListener) AIResponseFailed(err error) {
 	slog.Error("AI streaming failed", slogs.Error, err)
 	l.view.appendError(err.Error())
 }
 
 func (l *chatListener) AIReasoningDelta(content string) {
-	l.view.app.QueueUpdateDraw(func() {
+	l.view.queueDraw(func() {
 		l.view.setStatusReasoning()
+		if !l.view.reasoningShown() {
+			return
+		}
+		dimColor := l.view.app.Styles.Frame().Menu.FgColor
+		if !l.reasoningOpen {
+			l.reasoningOpen = true
+			l.view.clearThinkingIndicator()
+			fmt.Fprintf(l.view.output, "\n    [%s::d]○ Reasoning[-::-]\n    [%s::d]┆ ", dimColor, dimColor)
+		}
+		fmt.Fprintf(l.view.output, "[%s::d]%s[-::-]", dimColor,
+			strings.ReplaceAll(tview.Escape(content), "\n", "\n    ┆ "))
+		l.view.scrollToEnd()
 	})
 }
 
 func (l *chatListener) AIReasoningComplete(content string) {
-	l.view.app.QueueUpdateDraw(func() {
-		s := l.view.app.Styles
-		dimColor := s.Frame().Menu.FgColor
-		fmt.Fprintf(l.view.output, "    [%s::d]○ %s[-::-]\n", dimColor, content)
-		l.view.output.ScrollToEnd()
+	v := l.view
+	// Persist to history whatever the toggle, so a re-render shows what was
+	// shown live. Reasoning is display-only and never replayed to the model.
+	v.recordMessage(chatMessage{role: "reasoning", content: content, activity: true})
+	v.queueDraw(func() {
+		if l.reasoningOpen {
+			l.reasoningOpen = false
+			fmt.Fprint(v.output, "\n")
+		} else {
+			// Nothing was streamed, render it as a re-render would.
+			v.renderMessage("reasoning", content)
+		}
+		v.scrollToEnd()
 	})
 }
 
 func (l *chatListener) AIToolStart(toolName string) {
+	l.spinMu.Lock()
+	l.tools++
+	l.spinMu.Unlock()
 	// Tool activity display is now handled by toolActivityCallback
 	// which has richer descriptions. Just update status bar here.
-	l.view.app.QueueUpdateDraw(func() {
+	l.view.queueDraw(func() {
 		l.view.setStatusTool(toolName)
 	})
 }
 
 func (l *chatListener) AIToolComplete(toolName string) {
-	l.view.app.QueueUpdateDraw(func() {
+	l.spinMu.Lock()
+	l.tools = max(l.tools-1, 0)
+	l.spinMu.Unlock()
+	l.view.queueDraw(func() {
 		l.view.setStatusThinking()
 	})
 }
 
+func (l *chatListener) AIToolBudgetExceeded(limit int) {
+	l.view.queueDraw(func() {
+		fmt.Fprintf(l.view.output, "    [yellow::d]⚠ Tool call budget (%d) reached — asking the model to answer with what it has[-::-]\n", limit)
+		l.view.scrollToEnd()
+	})
+}
+
 // --------------------------------------------------------------------------
 // AI approval and activity callbacks
 
 // approvalCallback is called from the AI client's OnPreToolUse hook for mutation
 // tools. It blocks until the user approves or denies via a modal dialog.
-func (v *AIChatView) approvalCallback(toolName, description string, args map[string]any) bool {
+func (v *AIChatView) approvalCallback(toolName, description string, args map[string]any, confirm string) bool {
+	if v.closed.Load() {
+		// Nobody is left to approve.
+		return false
+	}
 	result := make(chan bool, 1)
 
 	v.app.QueueUpdateDraw(func() {
-		v.showApprovalDialog(toolName, description, args, result)
+		v.showApprovalDialog(toolName, description, args, confirm, result)
 	})
 
 	return <-result
 }
 
 // showApprovalDialog pops a confirmation dialog for a mutation tool call.
-func (v *AIChatView) showApprovalDialog(toolName, description string, args map[string]any, result chan<- bool) {
+// When confirm is set, the user must type it before Approve is accepted.
+func (v *AIChatView) showApprovalDialog(toolName, description string, args map[string]any, confirm string, result chan<- bool) {
 	styles := v.app.Styles.Dialog()
 
 	getStr := func(key string) string {
@@ -1105,12 +2151,18 @@ func (v *AIChatView) showApprovalDialog(toolName, description string, args map[s
 	switch toolName {
 	case "patch_resource":
 		title = "Patch " + gvr
+	case "apply_manifest":
+		title = "Apply manifest"
 	case "scale_resource":
 		title = "Scale " + gvr
 	case "restart_resource":
 		title = "Restart " + gvr
 	case "delete_resource":
 		title = "Delete " + gvr
+	case "exec_command":
+		title = "Exec in pod"
+	case "report_intent":
+		title = "Approve plan"
 	default:
 		title = toolName
 	}
@@ -1143,6 +2195,15 @@ func (v *AIChatView) showApprovalDialog(toolName, description string, args map[s
 	if toolName == "restart_resource" {
 		msg = "This will perform a rolling restart."
 	}
+	if toolName == "exec_command" {
+		msg = "[::b]Command:[::-] " + tview.Escape(description)
+	}
+	if manifest := getStr("manifest"); manifest != "" {
+		msg = "[::b]Manifest:[::-]\n  " + strings.ReplaceAll(tview.Escape(strings.TrimSpace(manifest)), "\n", "\n  ")
+	}
+	if toolName == "report_intent" {
+		msg = tview.Escape(description)
+	}
 
 	dismissed := false
 	dismiss := func() {
@@ -1162,11 +2223,23 @@ func (v *AIChatView) showApprovalDialog(toolName, description string, args map[s
 		SetLabelColor(styles.LabelFgColor.Color()).
 		SetFieldTextColor(styles.FieldFgColor.Color())
 
+	var typed *tview.InputField
+	if confirm != "" {
+		msg += fmt.Sprintf("\n\n[orange::b]Policy requires confirmation. Type %q to approve.[::-]", confirm)
+		typed = tview.NewInputField().SetLabel("Confirm: ").SetFieldWidth(30)
+		typed.SetFieldBackgroundColor(styles.ButtonBgColor.Color())
+		f.AddFormItem(typed)
+	}
+
 	f.AddButton("Deny", func() {
 		dismiss()
 		result <- false
 	})
 	f.AddButton("Approve", func() {
+		if typed != nil && strings.TrimSpace(typed.GetText()) != confirm {
+			v.app.Flash().Errf("Confirmation text does not match %q", confirm)
+			return
+		}
 		dismiss()
 		result <- true
 	})
@@ -1177,7 +2250,7 @@ func (v *AIChatView) showApprovalDialog(toolName, description string, args map[s
 			b.SetLabelColorActivated(styles.ButtonFocusFgColor.Color())
 		}
 	}
-	f.SetFocus(0) // Focus on Deny by default — safe side.
+	f.SetFocus(f.GetFormItemCount()) // Focus on Deny by default — safe side.
 
 	modal := tview.NewModalForm("<"+title+">", f)
 	modal.SetText(msg)
@@ -1194,32 +2267,67 @@ func (v *AIChatView) showApprovalDialog(toolName, description string, args map[s
 // toolActivityCallback is called when any tool starts executing — updates
 // the chat output with a rich description of what the AI is doing.
 func (v *AIChatView) toolActivityCallback(toolName, description string, isMutation bool) {
-	v.app.QueueUpdateDraw(func() {
+	count := v.recordActivity(chatMessage{role: "activity", content: description, activity: true, mutation: isMutation})
+
+	v.queueDraw(func() {
 		// Clear thinking indicator on first tool activity.
 		v.clearThinkingIndicator()
 
-		s := v.app.Styles
-		dimColor := s.Frame().Menu.FgColor
-
-		icon := "⚡"
-		color := string(dimColor)
+		th := newChatTheme(v.app.Styles)
+		icon, color := th.activityGlyph, th.activityColor.String()
 		if isMutation {
 			icon = "⚠"
 			color = "orange"
 		}
 
-		fmt.Fprintf(v.output, "    [%s::d]%s %s[-::-]\n", color, icon, description)
-		v.output.ScrollToEnd()
+		line := fmt.Sprintf("    [%s::d]%s %s", color, icon, description)
+		if count == 1 || !v.foldActivityLine(line+repeatSuffix(count-1)+"[-::-]", line+repeatSuffix(count)+"[-::-]") {
+			fmt.Fprintf(v.output, "%s%s[-::-]\n", line, repeatSuffix(count))
+		}
+		v.scrollToEnd()
 		v.setStatusTool(toolName)
+	})
+}
 
-		// Persist to history.
-		msg := chatMessage{role: "activity", content: description, activity: true}
-		v.history = append(v.history, msg)
-		scope := v.chatScope()
-		globalChatMu.Lock()
-		globalChatHistories[scope] = append(globalChatHistories[scope], msg)
-		globalChatMu.Unlock()
+// intentCallback is called when the model declares a plan via report_intent.
+// The plan renders as its own block. When approve is set, it blocks until the
+// user approves or rejects the whole plan.
+func (v *AIChatView) intentCallback(intent ai.Intent, approve bool) bool {
+	v.recordMessage(chatMessage{role: "plan", content: intent.String(), activity: true, intent: &intent})
+
+	v.queueDraw(func() {
+		v.clearThinkingIndicator()
+		v.renderPlan(intent)
+		v.scrollToEnd()
+		v.setStatusTool("report_intent")
 	})
+	if !approve {
+		return true
+	}
+
+	return v.approvalCallback("report_intent", intent.String(), nil, "")
+}
+
+// renderPlan writes a plan block, flagging the steps that change the cluster.
+func (v *AIChatView) renderPlan(intent ai.Intent) {
+	dimColor := v.app.Styles.Frame().Menu.FgColor
+
+	fmt.Fprintf(v.output, "\n    [%s::b]☰ Plan[-::-] [%s::d]· %s[-::-]\n",
+		v.app.Styles.Frame().Title.HighlightColor, dimColor, tview.Escape(intent.Goal))
+	for i, s := range intent.Steps {
+		step := tview.Escape(s.Action)
+		if s.Tool != "" {
+			step += fmt.Sprintf(" [%s::d](%s)[-::-]", dimColor, tview.Escape(s.Tool))
+		}
+		if t := s.Target(); t != "" {
+			step += fmt.Sprintf(" [%s::d]on %s[-::-]", dimColor, tview.Escape(t))
+		}
+		if s.Mutating {
+			fmt.Fprintf(v.output, "    [%s::d]┆[-::-] %d. [orange::]⚠ %s[-::-]\n", dimColor, i+1, step)
+			continue
+		}
+		fmt.Fprintf(v.output, "    [%s::d]┆[-::-] %d. %s\n", dimColor, i+1, step)
+	}
 }
 
 // toolDisplayName maps internal tool names to user-friendly descriptions.
@@ -1227,6 +2335,12 @@ func toolDisplayName(name string) string {
 	switch name {
 	case "get_resource":
 		return "Fetching resource..."
+	case "get_secret":
+		return "Fetching secret..."
+	case "get_configmap":
+		return "Fetching configmap..."
+	case "get_helm_release":
+		return "Reading helm releases..."
 	case "list_resources":
 		return "Listing resources..."
 	case "describe_resource":
@@ -1235,20 +2349,50 @@ func toolDisplayName(name string) string {
 		return "Fetching logs..."
 	case "get_events":
 		return "Checking events..."
+	case "watch_events":
+		return "Watching events..."
 	case "get_cluster_health":
 		return "Checking cluster health..."
+	case "top_pods":
+		return "Finding top pods..."
+	case "top_nodes":
+		return "Finding top nodes..."
 	case "get_pod_diagnostics":
 		return "Running pod diagnostics..."
+	case "get_node_diagnostics":
+		return "Running node diagnostics..."
+	case "get_workload_summary":
+		return "Summarizing workload health..."
+	case "diagnose_scheduling":
+		return "Diagnosing scheduling..."
+	case "get_pdb_status":
+		return "Checking disruption budgets..."
+	case "get_admission_context":
+		return "Checking admission webhooks..."
+	case "validate_manifest":
+		return "Validating manifest..."
+	case "get_incident_timeline":
+		return "Building incident timeline..."
+	case "find_references":
+		return "Finding references..."
+	case "compare_resources":
+		return "Comparing resources..."
 	case "check_rbac":
 		return "Checking RBAC permissions..."
+	case "run_kubectl":
+		return "Running kubectl..."
 	case "patch_resource":
 		return "Patching resource..."
+	case "apply_manifest":
+		return "Applying manifest..."
 	case "scale_resource":
 		return "Scaling resource..."
 	case "restart_resource":
 		return "Restarting resource..."
 	case "delete_resource":
 		return "Deleting resource..."
+	case "exec_command":
+		return "Running command in pod..."
 	case "report_intent":
 		return "Planning action..."
 	default:
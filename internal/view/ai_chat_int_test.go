@@ -0,0 +1,811 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/derailed/k9s/internal/ai"
+	"github.com/derailed/k9s/internal/config"
+	"github.com/derailed/k9s/internal/config/mock"
+	"github.com/derailed/tcell/v2"
+	"github.com/derailed/tview"
+	"github.com/mattn/go-runewidth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChunkString(t *testing.T) {
+	uu := map[string]struct {
+		s string
+		n int
+		e []string
+	}{
+		"empty": {
+			e: []string{""},
+		},
+		"unbounded": {
+			s: "hello world",
+			e: []string{"hello world"},
+		},
+		"fits": {
+			s: "hello",
+			n: 5,
+			e: []string{"hello"},
+		},
+		"split": {
+			s: "helloworld!",
+			n: 5,
+			e: []string{"hello", "world", "!"},
+		},
+		"runes": {
+			s: "├──┤",
+			n: 2,
+			e: []string{"├─", "─┤"},
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, chunkString(u.s, u.n))
+		})
+	}
+}
+
+func TestDoctorReport(t *testing.T) {
+	uu := map[string]struct {
+		rr []ai.CheckResult
+		e  []string
+	}{
+		"all-pass": {
+			rr: []ai.CheckResult{
+				{Name: "Config", State: ai.CheckPass, Detail: "enabled"},
+			},
+			e: []string{"PASS", "Config", "All checks passed."},
+		},
+		"failures": {
+			rr: []ai.CheckResult{
+				{Name: "CLI", State: ai.CheckFail, Detail: "not found", Hint: "set COPILOT_CLI_PATH"},
+				{Name: "Auth", State: ai.CheckSkip, Detail: "CLI not running"},
+			},
+			e: []string{"FAIL", "↳ set COPILOT_CLI_PATH", "SKIP", "1 check(s) failed."},
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			out := doctorReport(u.rr)
+			for _, e := range u.e {
+				assert.Contains(t, out, e)
+			}
+		})
+	}
+}
+
+func TestChatFollowMode(t *testing.T) {
+	v := NewAIChatView()
+	v.output.SetScrollable(true)
+	v.AddItem(v.output, 0, 1, false)
+	v.SetRect(0, 0, 40, 10)
+	for i := range 50 {
+		fmt.Fprintf(v.output, "line %d\n", i)
+	}
+	v.scrollToEnd()
+
+	scr := tcell.NewSimulationScreen("UTF-8")
+	require.NoError(t, scr.Init())
+	scr.SetSize(40, 10)
+	v.Draw(scr)
+	assert.True(t, v.follow)
+
+	v.scrollBy(-5)
+	v.Draw(scr)
+	assert.False(t, v.follow)
+
+	v.scrollBy(1)
+	v.Draw(scr)
+	assert.False(t, v.follow)
+
+	v.scrollBy(10)
+	v.Draw(scr)
+	assert.True(t, v.follow)
+}
+
+func TestStripGlobalPrefix(t *testing.T) {
+	uu := map[string]struct {
+		text, e string
+		global  bool
+	}{
+		"plain": {
+			text: "why is my pod crashing?",
+			e:    "why is my pod crashing?",
+		},
+		"global": {
+			text:   "!global which nodes are not ready?",
+			e:      "which nodes are not ready?",
+			global: true,
+		},
+		"bare": {
+			text:   "!global",
+			global: true,
+		},
+		"not-a-directive": {
+			text: "!globally broken",
+			e:    "!globally broken",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			q, global := stripGlobalPrefix(u.text)
+			assert.Equal(t, u.e, q)
+			assert.Equal(t, u.global, global)
+		})
+	}
+}
+
+func TestChatReasoningToggle(t *testing.T) {
+	v := NewAIChatView()
+	v.app = NewApp(mock.NewMockConfig(t))
+	v.output.SetDynamicColors(true)
+
+	v.renderMessage("reasoning", "check pods\ncheck events")
+	assert.Contains(t, v.output.GetText(true), "Reasoning (2 lines, Ctrl-T to expand)")
+	assert.NotContains(t, v.output.GetText(true), "check events")
+
+	v.output.Clear()
+	v.showReasoning = true
+	v.renderMessage("reasoning", "check pods\ncheck events")
+	txt := v.output.GetText(true)
+	assert.Contains(t, txt, "┆ check pods")
+	assert.Contains(t, txt, "┆ check events")
+}
+
+func TestChatReasoningCompleteRerenders(t *testing.T) {
+	uu := map[string]struct {
+		shown bool
+	}{
+		"collapsed": {},
+		"expanded":  {shown: true},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			v := NewAIChatView()
+			v.app = NewApp(mock.NewMockConfig(t))
+			v.statusBar = tview.NewTextView()
+			v.output.SetDynamicColors(true)
+			v.showReasoning = u.shown
+			t.Cleanup(v.clearHistory)
+
+			scr := tcell.NewSimulationScreen("")
+			require.NoError(t, scr.Init())
+			v.app.SetScreen(scr)
+			v.app.SetRoot(v.output, true)
+			go func() { _ = v.app.Application.Run() }()
+			t.Cleanup(v.app.Application.Stop)
+
+			var (
+				content  strings.Builder
+				streamMu sync.Mutex
+			)
+			l := chatListener{view: v, streamedContent: &content, mu: &streamMu}
+			l.AIReasoningComplete("check pods\ncheck events")
+
+			var live string
+			require.Eventually(t, func() bool {
+				v.app.Application.QueueUpdate(func() { live = v.output.GetText(true) })
+				return live != ""
+			}, time.Second, 10*time.Millisecond)
+			require.Len(t, v.messages(), 1)
+			assert.Equal(t, "reasoning", v.messages()[0].role)
+
+			v.output.Clear()
+			v.renderHistory(v.messages())
+			assert.Equal(t, live, v.output.GetText(true))
+		})
+	}
+}
+
+func TestChatSpinnerYieldsToTools(t *testing.T) {
+	v := NewAIChatView()
+	v.app = NewApp(mock.NewMockConfig(t))
+	v.statusBar = tview.NewTextView()
+	v.statusBar.SetDynamicColors(true)
+	t.Cleanup(v.clearHistory)
+
+	scr := tcell.NewSimulationScreen("")
+	require.NoError(t, scr.Init())
+	v.app.SetScreen(scr)
+	v.app.SetRoot(v.output, true)
+	go func() { _ = v.app.Application.Run() }()
+	t.Cleanup(v.app.Application.Stop)
+
+	var (
+		content  strings.Builder
+		streamMu sync.Mutex
+	)
+	l := chatListener{view: v, streamedContent: &content, mu: &streamMu}
+	t.Cleanup(l.stopSpinner)
+	status := func() string {
+		var s string
+		v.app.Application.QueueUpdate(func() { s = v.statusBar.GetText(true) })
+		return s
+	}
+
+	l.AIStreamUnavailable()
+	require.Eventually(t, func() bool {
+		return strings.Contains(status(), "Waiting for the full response")
+	}, time.Second, 10*time.Millisecond)
+
+	l.AIToolStart("get_resource")
+	require.Eventually(t, func() bool {
+		return strings.Contains(status(), "Fetching resource...")
+	}, time.Second, 10*time.Millisecond)
+	// The spinner keeps ticking but leaves the tool status alone.
+	time.Sleep(300 * time.Millisecond)
+	assert.Contains(t, status(), "Fetching resource...")
+
+	l.AIToolComplete("get_resource")
+	require.Eventually(t, func() bool {
+		return strings.Contains(status(), "Waiting for the full response")
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestParseScopeArgs(t *testing.T) {
+	uu := map[string]struct {
+		args           []string
+		kind, name, ns string
+		fresh          bool
+		err            string
+	}{
+		"cluster": {},
+		"cluster-new": {
+			args:  []string{"--new"},
+			fresh: true,
+		},
+		"target": {
+			args: []string{"deploy/api"},
+			kind: "deploy",
+			name: "api",
+		},
+		"namespace": {
+			args: []string{"-n", "prod", "po/api-1", "--new"},
+			kind: "po", name: "api-1", ns: "prod",
+			fresh: true,
+		},
+		"long-namespace": {
+			args: []string{"svc/api", "--namespace", "prod"},
+			kind: "svc", name: "api", ns: "prod",
+		},
+		"dangling-namespace": {
+			args: []string{"svc/api", "-n"},
+			kind: "svc", name: "api",
+		},
+		"no-name": {
+			args: []string{"deploy"},
+			err:  `invalid scope "deploy". Use /scope kind/name [-n namespace] [--new]`,
+		},
+		"no-kind": {
+			args: []string{"/api"},
+			err:  `invalid scope "/api". Use /scope kind/name [-n namespace] [--new]`,
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			kind, name, ns, fresh, err := parseScopeArgs(u.args)
+			if u.err != "" {
+				assert.EqualError(t, err, u.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, u.kind, kind)
+			assert.Equal(t, u.name, name)
+			assert.Equal(t, u.ns, ns)
+			assert.Equal(t, u.fresh, fresh)
+		})
+	}
+}
+
+func TestSwitchScope(t *testing.T) {
+	uu := map[string]struct {
+		fresh bool
+		e     []chatMessage
+	}{
+		"restore": {
+			e: []chatMessage{{role: "user", content: "why is api down?"}},
+		},
+		"fresh": {
+			fresh: true,
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			v := NewAIChatView()
+			v.app = NewApp(mock.NewMockConfig(t))
+			v.statusBar = tview.NewTextView()
+			t.Cleanup(v.clearHistory)
+
+			globalChatMu.Lock()
+			globalChatHistories["Deployment/scope/api"] = []chatMessage{{role: "user", content: "why is api down?"}}
+			globalChatMu.Unlock()
+			t.Cleanup(func() {
+				globalChatMu.Lock()
+				delete(globalChatHistories, "Deployment/scope/api")
+				globalChatMu.Unlock()
+			})
+
+			v.switchScope("Deployment", "api", "scope", u.fresh)
+			assert.Equal(t, "Deployment/scope/api", v.chatScope())
+			assert.Equal(t, u.e, v.messages())
+		})
+	}
+}
+
+func TestRenderCodeBlockWidth(t *testing.T) {
+	uu := map[string]struct {
+		width   int
+		content string
+	}{
+		"narrow": {
+			width:   30,
+			content: "```yaml\n" + strings.Repeat("x", 80) + "\n```",
+		},
+		"wide-lang": {
+			width:   30,
+			content: "```日本語\nkubectl get pods\n```",
+		},
+		"wide": {
+			width:   120,
+			content: "```\n" + strings.Repeat("x", 200) + "\n```",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			v := NewAIChatView()
+			v.app = NewApp(mock.NewMockConfig(t))
+			v.output.SetDynamicColors(true)
+			v.viewWidth = u.width
+
+			v.renderFormattedContent(u.content)
+			ll := strings.Split(strings.Trim(v.output.GetText(true), "\n"), "\n")
+			for _, l := range ll {
+				assert.LessOrEqual(t, runewidth.StringWidth(l), u.width, l)
+			}
+			// The top border, language included, lines up with the bottom one.
+			assert.Equal(t, runewidth.StringWidth(ll[len(ll)-1]), runewidth.StringWidth(ll[0]))
+		})
+	}
+}
+
+func TestNextAudience(t *testing.T) {
+	assert.Equal(t, config.AIAudienceBeginner, nextAudience(config.AIAudienceSRE))
+	assert.Equal(t, config.AIAudienceExec, nextAudience(config.AIAudienceBeginner))
+	assert.Equal(t, config.AIAudienceSRE, nextAudience(config.AIAudienceExec))
+}
+
+func TestChatTheme(t *testing.T) {
+	v := NewAIChatView()
+	v.app = NewApp(mock.NewMockConfig(t))
+	v.output.SetDynamicColors(true)
+
+	v.renderMessage("user", "hi")
+	v.renderMessage("assistant", "hello")
+	txt := v.output.GetText(true)
+	assert.Contains(t, txt, "▶ You")
+	assert.Contains(t, txt, "✦ Copilot")
+
+	v.output.Clear()
+	v.app.Styles.K9s.Views.AI = config.AIChat{UserGlyph: "»", AssistantGlyph: "🤖", AssistantColor: "yellow"}
+	th := newChatTheme(v.app.Styles)
+	assert.Equal(t, config.Color("yellow"), th.assistantColor)
+	assert.Equal(t, v.app.Styles.Frame().Title.HighlightColor, th.userColor)
+	v.renderMessage("user", "hi")
+	v.renderMessage("assistant", "hello")
+	txt = v.output.GetText(true)
+	assert.Contains(t, txt, "» You")
+	assert.Contains(t, txt, "🤖 Copilot")
+}
+
+func TestHighlightCode(t *testing.T) {
+	p := codePalette{
+		text: "white", key: "blue", str: "green", num: "orange",
+		keyword: "purple", comment: "gray", punct: "red",
+	}
+
+	uu := map[string]struct {
+		lang, line string
+		e          string
+	}{
+		"unknown": {
+			lang: "rust",
+			line: "let x = [1];",
+			e:    "[white::-]let x = [1[];[-::-]",
+		},
+		"yaml-key": {
+			lang: "yaml",
+			line: "  replicas: 3 # scale",
+			e:    "[red::-]  [-::-][blue::-]replicas[-::-][red::-]:[-::-][white::-] [-::-][orange::-]3[-::-][white::-] [-::-][gray::d]# scale[-::-]",
+		},
+		"yaml-list": {
+			lang: "yml",
+			line: "- name: nginx",
+			e:    "[red::-]- [-::-][blue::-]name[-::-][red::-]:[-::-][white::-] nginx[-::-]",
+		},
+		"json": {
+			lang: "json",
+			line: `{"ready": true, "n": 2}`,
+			e:    `[red::-]{[-::-][blue::-]"ready"[-::-][red::-]:[-::-][white::-] [-::-][purple::-]true[-::-][red::-],[-::-][white::-] [-::-][blue::-]"n"[-::-][red::-]:[-::-][white::-] [-::-][orange::-]2[-::-][red::-]}[-::-]`,
+		},
+		"bash": {
+			lang: "bash",
+			line: `kubectl get po -n $NS | grep "Crash"`,
+			e:    `[white::-]kubectl get po [-::-][blue::-]-n[-::-][white::-] [-::-][blue::-]$NS[-::-][white::-] [-::-][red::-]|[-::-][white::-] grep [-::-][green::-]"Crash"[-::-]`,
+		},
+		"go": {
+			lang: "go",
+			line: `return fmt.Errorf("boom") // oops`,
+			e:    `[purple::-]return[-::-][white::-] fmt.Errorf[-::-][red::-]([-::-][green::-]"boom"[-::-][red::-])[-::-][white::-] [-::-][gray::d]// oops[-::-]`,
+		},
+	}
+
+	// Skin colors render as hex tags.
+	hex := strings.NewReplacer(
+		"[white:", "[#ffffff:", "[blue:", "[#0000ff:", "[green:", "[#008000:", "[orange:", "[#ffa500:",
+		"[purple:", "[#800080:", "[gray:", "[#808080:", "[red:", "[#ff0000:",
+	)
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, hex.Replace(u.e), highlightCode(u.lang, u.line, p))
+		})
+	}
+}
+
+func TestOpenAIChatReusesChat(t *testing.T) {
+	a := NewApp(mock.NewMockConfig(t))
+	require.NoError(t, a.openAIChat("Pod", "p1", "ns1"))
+	require.Len(t, a.Content.Peek(), 1)
+	chat, ok := a.Content.Top().(*AIChatView)
+	require.True(t, ok)
+
+	require.NoError(t, a.inject(NewAIModelsView(), false))
+	require.NoError(t, a.openAIChat("Deployment", "dp1", "ns2"))
+
+	assert.Len(t, a.Content.Peek(), 1)
+	assert.Same(t, chat, a.Content.Top())
+	assert.Equal(t, "Deployment/ns2/dp1", chat.chatScope())
+}
+
+func TestTriageNamespaceScopesChat(t *testing.T) {
+	a := NewApp(mock.NewMockConfig(t))
+	require.NoError(t, a.triageNamespace("shop"))
+
+	chat, ok := a.Content.Top().(*AIChatView)
+	require.True(t, ok)
+	assert.Equal(t, "namespaces/shop/shop", chat.chatScope())
+}
+
+func TestChatHistoryConcurrentStreaming(t *testing.T) {
+	v := NewAIChatView()
+	v.app = NewApp(mock.NewMockConfig(t))
+	v.statusBar = tview.NewTextView()
+	v.output.SetDynamicColors(true)
+	v.SetResourceContext("Pod", "p1", "race")
+	t.Cleanup(v.clearHistory)
+
+	scr := tcell.NewSimulationScreen("")
+	require.NoError(t, scr.Init())
+	v.app.SetScreen(scr)
+	v.app.SetRoot(v.output, true)
+	go func() { _ = v.app.Application.Run() }()
+	t.Cleanup(v.app.Application.Stop)
+
+	const count = 50
+	var (
+		content  strings.Builder
+		streamMu sync.Mutex
+		wg       sync.WaitGroup
+	)
+	l := chatListener{view: v, streamedContent: &content, mu: &streamMu}
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		v.sendMessage("", false)
+		for range count {
+			l.AIResponseDelta("x")
+		}
+		l.AIResponseComplete("")
+	}()
+	go func() {
+		defer wg.Done()
+		for i := range count {
+			l.AIToolStart("get_pods")
+			v.toolActivityCallback("get_pods", fmt.Sprintf("tool %d", i), i%10 == 0)
+			l.AIToolComplete("get_pods")
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := range count {
+			v.appendMessage("user", fmt.Sprintf("question %d", i))
+			_ = v.messages()
+			_ = v.chatScope()
+		}
+	}()
+	wg.Wait()
+
+	mm := v.messages()
+	assert.Len(t, mm, 2*count)
+	globalChatMu.Lock()
+	assert.Equal(t, mm, globalChatHistories["Pod/race/p1"])
+	globalChatMu.Unlock()
+
+	var tools []string
+	for _, m := range mm {
+		if m.role == "activity" {
+			tools = append(tools, m.content)
+		}
+	}
+	require.Len(t, tools, count)
+	assert.Equal(t, "tool 0", tools[0])
+	assert.Equal(t, fmt.Sprintf("tool %d", count-1), tools[count-1])
+}
+
+func TestChatLeaveMidStream(t *testing.T) {
+	a := NewApp(mock.NewMockConfig(t))
+	require.NoError(t, a.openAIChat("", "", ""))
+	v, ok := a.Content.Top().(*AIChatView)
+	require.True(t, ok)
+	t.Cleanup(v.clearHistory)
+
+	scr := tcell.NewSimulationScreen("")
+	require.NoError(t, scr.Init())
+	a.SetScreen(scr)
+	a.SetRoot(v, true)
+	go func() { _ = a.Application.Run() }()
+	t.Cleanup(a.Application.Stop)
+
+	ctx, done := v.trackRequest()
+	defer done()
+
+	var (
+		content  strings.Builder
+		streamMu sync.Mutex
+		wg       sync.WaitGroup
+	)
+	l := chatListener{view: v, streamedContent: &content, mu: &streamMu}
+	stream := func(n int) {
+		for i := range n {
+			l.AIResponseDelta("x")
+			l.AIToolStart("get_pods")
+			v.toolActivityCallback("get_pods", fmt.Sprintf("tool %d", i), false)
+			l.AIToolComplete("get_pods")
+		}
+	}
+	stream(10)
+
+	left := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		stream(50)
+		<-left
+		stream(10)
+		l.AIResponseComplete("")
+		v.appendError("late failure")
+	}()
+	a.Application.QueueUpdateDraw(func() { v.backCmd(nil) })
+	close(left)
+	assert.ErrorIs(t, context.Cause(ctx), errChatClosed)
+
+	var before string
+	a.Application.QueueUpdate(func() { before = v.output.GetText(true) })
+	wg.Wait()
+	var after string
+	a.Application.QueueUpdate(func() { after = v.output.GetText(true) })
+	assert.Equal(t, before, after)
+	assert.NotContains(t, after, "late failure")
+	assert.Empty(t, a.Content.Peek())
+}
+
+func TestRenderCitations(t *testing.T) {
+	v := NewAIChatView()
+	v.app = NewApp(mock.NewMockConfig(t))
+	v.output.SetDynamicColors(true)
+
+	mm := []chatMessage{
+		{role: "user", content: "why?"},
+		{
+			role:    "assistant",
+			content: "Pod is OOMKilled [1]. Node is full [7].",
+			sources: []ai.Source{{ID: 1, Tool: "get_pod_diagnostics", Description: `Running diagnostics on pod "p1"`}},
+		},
+	}
+	v.renderHistory(mm)
+
+	txt := v.output.GetText(true)
+	assert.Contains(t, txt, "Pod is OOMKilled ¹. Node is full ⁷.")
+	assert.Contains(t, txt, `¹ get_pod_diagnostics · Running diagnostics on pod "p1"`)
+	assert.Contains(t, txt, "⁷ unknown source, not backed by a tool result")
+}
+
+func TestRenderPrefetchedContext(t *testing.T) {
+	v := NewAIChatView()
+	v.app = NewApp(mock.NewMockConfig(t))
+	v.output.SetDynamicColors(true)
+
+	var b strings.Builder
+	b.WriteString(ai.PrefetchHeader + " deployments default/fred, fetched 2026-01-01T00:00:00Z\n")
+	for i := range maxContextPreviewLines + 3 {
+		fmt.Fprintf(&b, "line %d\n", i)
+	}
+	v.renderHistory([]chatMessage{{role: "context", content: b.String(), activity: true}})
+
+	txt := v.output.GetText(true)
+	assert.Contains(t, txt, "⧉ Prefetched context · deployments default/fred")
+	assert.NotContains(t, txt, ai.PrefetchHeader)
+	assert.Contains(t, txt, "┆ line 0")
+	assert.NotContains(t, txt, fmt.Sprintf("line %d", maxContextPreviewLines))
+	assert.Contains(t, txt, "┆ … 3 more lines")
+}
+
+func TestChatTurnNavigation(t *testing.T) {
+	v := NewAIChatView()
+	v.app = NewApp(mock.NewMockConfig(t))
+	v.output.SetDynamicColors(true)
+	v.output.SetScrollable(true)
+	v.AddItem(v.output, 0, 1, false)
+	v.SetRect(0, 0, 60, 5)
+
+	var mm []chatMessage
+	for i := range 3 {
+		mm = append(mm,
+			chatMessage{role: "user", content: fmt.Sprintf("question %d", i)},
+			chatMessage{role: "assistant", content: strings.Repeat("answer\n", 5)},
+		)
+	}
+	v.renderHistory(mm)
+	require.Len(t, v.turns, 6)
+	assert.False(t, v.turns[0].answer)
+	assert.True(t, v.turns[5].answer)
+
+	scr := tcell.NewSimulationScreen("UTF-8")
+	require.NoError(t, scr.Init())
+	scr.SetSize(60, 5)
+	v.output.ScrollToEnd()
+	v.Draw(scr)
+
+	row := func() int {
+		r, _ := v.output.GetScrollOffset()
+		return r
+	}
+	lines := strings.Split(v.output.GetText(true), "\n")
+
+	v.latestAnswerCmd(nil)
+	v.Draw(scr)
+	assert.Contains(t, lines[row()], chatSeparator)
+	assert.Contains(t, lines[row()+1], "Copilot")
+	assert.False(t, v.follow)
+
+	v.jumpTurn(-1)
+	v.Draw(scr)
+	assert.Contains(t, lines[row()+2], "question 2")
+
+	v.jumpTurn(-1)
+	v.jumpTurn(-1)
+	v.Draw(scr)
+	assert.Contains(t, lines[row()+2], "question 1")
+
+	v.jumpTurn(1)
+	v.Draw(scr)
+	assert.Contains(t, lines[row()+1], "Copilot")
+
+	v.resetOutput()
+	assert.Empty(t, v.turns)
+}
+
+func TestChatExtendTruncatedAnswer(t *testing.T) {
+	v := NewAIChatView()
+	v.app = NewApp(mock.NewMockConfig(t))
+	v.output.SetDynamicColors(true)
+	v.SetResourceContext("Pod", "p1", "cont")
+	t.Cleanup(v.clearHistory)
+
+	v.recordMessage(chatMessage{role: "user", content: "why?"})
+	v.recordMessage(chatMessage{role: "assistant", content: "The pod is Crash", truncated: true})
+	v.recordMessage(chatMessage{role: "activity", content: "tool", activity: true})
+	v.renderHistory(v.messages())
+	assert.Contains(t, v.output.GetText(true), "(response truncated — press Ctrl+Space to continue)")
+
+	v.extendAnswer("LoopBackOff.", false, []ai.Source{{ID: 1, Tool: "get_pods"}})
+	mm := v.messages()
+	require.Len(t, mm, 3)
+	assert.Equal(t, "The pod is CrashLoopBackOff.", mm[1].content)
+	assert.False(t, mm[1].truncated)
+	assert.Len(t, mm[1].sources, 1)
+	globalChatMu.Lock()
+	assert.Equal(t, mm, globalChatHistories["Pod/cont/p1"])
+	globalChatMu.Unlock()
+
+	v.resetOutput()
+	v.renderHistory(mm)
+	assert.NotContains(t, v.output.GetText(true), "response truncated")
+	assert.Equal(t, -1, lastAnswer(mm[:1]))
+}
+
+func TestTriagePrompt(t *testing.T) {
+	p := triagePrompt("shop")
+
+	assert.Contains(t, p, `Triage the namespace "shop"`)
+	assert.Contains(t, p, "use at most 10 tool calls")
+	assert.Contains(t, p, `get_cluster_health with namespace "shop" and groupByNamespace true`)
+	assert.Contains(t, p, "ranked from most to least severe")
+}
+
+func TestChatFoldRepeatedActivity(t *testing.T) {
+	v := NewAIChatView()
+	v.app = NewApp(mock.NewMockConfig(t))
+	v.output.SetDynamicColors(true)
+	v.SetResourceContext("Pod", "p1", "fold")
+	t.Cleanup(v.clearHistory)
+
+	fetch := chatMessage{role: "activity", content: "Fetching resource…", activity: true}
+	del := chatMessage{role: "activity", content: "Deleting pod", activity: true, mutation: true}
+	for range 3 {
+		v.recordActivity(fetch)
+	}
+	assert.Equal(t, 1, v.recordActivity(del))
+	assert.Equal(t, 1, v.recordActivity(del))
+	assert.Equal(t, 1, v.recordActivity(fetch))
+
+	mm := v.messages()
+	require.Len(t, mm, 4)
+	assert.Equal(t, 2, mm[0].repeats)
+	assert.Zero(t, mm[1].repeats)
+	globalChatMu.Lock()
+	assert.Equal(t, mm, globalChatHistories["Pod/fold/p1"])
+	globalChatMu.Unlock()
+
+	v.renderHistory(mm)
+	out := v.output.GetText(true)
+	assert.Contains(t, out, "Fetching resource… ×3\n")
+	assert.Equal(t, 2, strings.Count(out, "Deleting pod\n"))
+
+	v.resetOutput()
+	fmt.Fprint(v.output, "    [gray::d]⚡ Fetching[-::-]\n")
+	assert.True(t, v.foldActivityLine("    [gray::d]⚡ Fetching[-::-]", "    [gray::d]⚡ Fetching ×2[-::-]"))
+	assert.Equal(t, "    ⚡ Fetching ×2\n", v.output.GetText(true))
+	fmt.Fprint(v.output, "streamed text\n")
+	assert.False(t, v.foldActivityLine("    [gray::d]⚡ Fetching ×2[-::-]", "    [gray::d]⚡ Fetching ×3[-::-]"))
+}
+
+func TestLastQuestion(t *testing.T) {
+	mm := []chatMessage{
+		{role: "user", content: "why?"},
+		{role: "assistant", content: "The pod is crashing"},
+		{role: "user", content: "!global how many nodes?"},
+		{role: "activity", content: "tool", activity: true},
+		{role: "assistant", content: "3"},
+	}
+
+	assert.Equal(t, "!global how many nodes?", lastQuestion(mm))
+	assert.Equal(t, "why?", lastQuestion(mm[:2]))
+	assert.Empty(t, lastQuestion(nil))
+}
@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/derailed/k9s/internal/ai"
+	"github.com/derailed/tview"
+)
+
+var superscriptReplacer = strings.NewReplacer(
+	"0", "⁰", "1", "¹", "2", "²", "3", "³", "4", "⁴",
+	"5", "⁵", "6", "⁶", "7", "⁷", "8", "⁸", "9", "⁹",
+)
+
+// superscript renders the digits of a citation marker as footnote markers.
+func superscript(digits string) string {
+	return superscriptReplacer.Replace(digits)
+}
+
+// sourceIndex maps source ids to the tool results recorded in a chat.
+func sourceIndex(mm []chatMessage) map[int]ai.Source {
+	srcs := make(map[int]ai.Source)
+	for _, m := range mm {
+		for _, s := range m.sources {
+			srcs[s.ID] = s
+		}
+	}
+
+	return srcs
+}
+
+// renderCitations lists the sources cited by an answer as footnotes. Cited
+// ids with no recorded tool result are flagged so they can be discounted.
+func (v *AIChatView) renderCitations(content string, srcs map[int]ai.Source) {
+	ids := ai.CitedIDs(content)
+	if len(ids) == 0 {
+		return
+	}
+
+	dimColor := v.app.Styles.Frame().Menu.FgColor
+	for _, id := range ids {
+		marker := superscript(strconv.Itoa(id))
+		s, ok := srcs[id]
+		if !ok {
+			fmt.Fprintf(v.output, "    [%s::d]%s[-::-] [orange::d]unknown source, not backed by a tool result[-::-]\n", dimColor, marker)
+			continue
+		}
+		fmt.Fprintf(v.output, "    [%s::d]%s %s · %s[-::-]\n", dimColor, marker, s.Tool, tview.Escape(s.Description))
+	}
+}
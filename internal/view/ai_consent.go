@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/derailed/k9s/internal/config"
+	"github.com/derailed/tview"
+)
+
+const consentDialogKey = "ai-consent"
+
+// aiConsent records whether the AI data notice was acknowledged this session.
+var aiConsent struct {
+	sync.Mutex
+	given bool
+}
+
+// needsConsent returns true when ai.requireConsent is set and the data
+// notice has not been acknowledged yet.
+func (v *AIChatView) needsConsent() bool {
+	return consentPending(v.app.Config.K9s.ActiveAI())
+}
+
+// consentPending returns true when the config requires consent and the data
+// notice has not been acknowledged yet.
+func consentPending(cfg config.AI) bool {
+	if !cfg.RequireConsent {
+		return false
+	}
+	aiConsent.Lock()
+	defer aiConsent.Unlock()
+
+	return !aiConsent.given
+}
+
+// consentNotice explains what leaves the cluster once chatting starts.
+func consentNotice(destination string) string {
+	return fmt.Sprintf("Questions you ask and cluster data fetched by AI tools are sent to %s.\n\n"+
+		"This includes resource manifests, events, container logs and describe output "+
+		"for the resources the assistant inspects, Secrets included if it fetches them.\n\n"+
+		"Acknowledge to enable the chat for this session.", tview.Escape(destination))
+}
+
+// showConsentDialog locks the chat input until the user acknowledges the data
+// notice. Declining closes the chat.
+func (v *AIChatView) showConsentDialog() {
+	v.input.SetAcceptanceFunc(func(string, rune) bool { return false })
+	v.input.SetPlaceholder("Acknowledge the AI data notice to start chatting...")
+
+	styles := v.app.Styles.Dialog()
+	f := tview.NewForm()
+	f.SetItemPadding(0)
+	f.SetButtonsAlign(tview.AlignCenter).
+		SetButtonBackgroundColor(styles.ButtonBgColor.Color()).
+		SetButtonTextColor(styles.ButtonFgColor.Color()).
+		SetLabelColor(styles.LabelFgColor.Color()).
+		SetFieldTextColor(styles.FieldFgColor.Color())
+
+	decline := func() {
+		v.app.Content.RemovePage(consentDialogKey)
+		v.app.Content.Pop()
+	}
+	f.AddButton("Decline", decline)
+	f.AddButton("Acknowledge", func() {
+		aiConsent.Lock()
+		aiConsent.given = true
+		aiConsent.Unlock()
+
+		v.app.Content.RemovePage(consentDialogKey)
+		v.input.SetAcceptanceFunc(nil)
+		v.restorePlaceholder()
+		v.app.SetFocus(v.input)
+	})
+	for i := range 2 {
+		if b := f.GetButton(i); b != nil {
+			b.SetBackgroundColorActivated(styles.ButtonFocusBgColor.Color())
+			b.SetLabelColorActivated(styles.ButtonFocusFgColor.Color())
+		}
+	}
+	f.SetFocus(0)
+
+	modal := tview.NewModalForm("<AI Data Notice>", f)
+	modal.SetText(consentNotice(v.app.Config.K9s.ActiveAI().DataDestination()))
+	modal.SetTextColor(styles.FgColor.Color())
+	modal.SetDoneFunc(func(int, string) { decline() })
+
+	v.app.Content.AddPage(consentDialogKey, modal, false, false)
+	v.app.Content.ShowPage(consentDialogKey)
+}
@@ -0,0 +1,40 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"testing"
+
+	"github.com/derailed/k9s/internal/config/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChatNeedsConsent(t *testing.T) {
+	v := NewAIChatView()
+	v.app = NewApp(mock.NewMockConfig(t))
+	t.Cleanup(func() {
+		aiConsent.Lock()
+		aiConsent.given = false
+		aiConsent.Unlock()
+	})
+
+	assert.False(t, v.needsConsent())
+
+	v.app.Config.K9s.AI.RequireConsent = true
+	assert.True(t, v.needsConsent())
+
+	v.showConsentDialog()
+	assert.True(t, v.app.Content.HasPage(consentDialogKey))
+
+	aiConsent.Lock()
+	aiConsent.given = true
+	aiConsent.Unlock()
+	assert.False(t, v.needsConsent())
+}
+
+func TestConsentNotice(t *testing.T) {
+	n := consentNotice("openai provider at https://api.openai.com/v1")
+	assert.Contains(t, n, "sent to openai provider at https://api.openai.com/v1.")
+	assert.Contains(t, n, "container logs")
+}
@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/derailed/k9s/internal/ai"
+	"github.com/derailed/k9s/internal/slogs"
+	"github.com/derailed/tview"
+)
+
+// contextCmd switches K9s to another kube-context without leaving the chat.
+// The AI tools are rewired to the new cluster and the session starts over,
+// so the system message describes the new cluster.
+// Usage: /context name.
+func (v *AIChatView) contextCmd(name string) {
+	if name == "" {
+		v.app.Flash().Errf("Missing context. Use /context <name>")
+		return
+	}
+	v.mu.Lock()
+	busy := v.streaming
+	v.mu.Unlock()
+	if busy {
+		v.app.Flash().Warn("AI chat is busy, wait for the current answer before switching contexts")
+		return
+	}
+	if name == v.app.Config.ActiveContextName() {
+		v.app.Flash().Infof("Already on context %q", name)
+		return
+	}
+	if v.app.factory == nil {
+		v.app.Flash().Errf("No cluster connection available")
+		return
+	}
+
+	// Switching contexts resets the view stack, the chat goes back on top
+	// once done.
+	if err := useContext(v.app, name); err != nil {
+		slog.Error("AI chat context switch failed", slogs.Context, name, slogs.Error, err)
+		v.Start()
+		v.app.Flash().Errf("Unable to switch to context %q: %s", name, err)
+		return
+	}
+	v.app.clearHistory()
+	// The new context may turn AI off, see ai.contexts.
+	if ai.Client == nil {
+		v.app.Flash().Warnf("AI is disabled on context %q", name)
+		return
+	}
+	// The switch already moved the client to the new cluster and session.
+	v.resetSessionTokens()
+	v.app.Content.Push(v)
+
+	// The resource in focus belongs to the previous cluster.
+	v.switchScope("", "", "", false)
+	msg := fmt.Sprintf("🔀 Switched to context %s, the assistant now works on this cluster", tview.Escape(name))
+	v.recordMessage(chatMessage{role: "system", content: msg, activity: true})
+	v.renderMessage("system", msg)
+	v.scrollToEnd()
+	v.app.Flash().Infof("AI chat switched to context %q", name)
+}
@@ -0,0 +1,345 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/derailed/k9s/internal/ai"
+	"github.com/derailed/k9s/internal/config"
+	"github.com/derailed/k9s/internal/config/data"
+	"github.com/derailed/k9s/internal/slogs"
+	"github.com/derailed/k9s/internal/ui/dialog"
+	"github.com/derailed/tview"
+)
+
+const (
+	conversationExt = ".json"
+
+	// recapHeader marks the transcript of a loaded conversation.
+	recapHeader = "[RESUMED CONVERSATION]"
+
+	// maxRecapBytes caps the transcript sent along with the first question
+	// after a conversation is loaded. Older turns are dropped first.
+	maxRecapBytes = 16 * 1024
+)
+
+// savedConversation is a conversation saved under a name via /save-as.
+type savedConversation struct {
+	Name     string         `json:"name"`
+	Created  time.Time      `json:"created"`
+	Updated  time.Time      `json:"updated"`
+	Model    string         `json:"model,omitempty"`
+	Context  string         `json:"context,omitempty"`
+	Scope    savedScope     `json:"scope"`
+	Messages []savedMessage `json:"messages"`
+}
+
+// savedScope is the resource a saved conversation is focused on. It is
+// empty for cluster-wide chats.
+type savedScope struct {
+	Kind      string `json:"kind,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+func (s savedScope) String() string {
+	if s.Kind == "" || s.Name == "" {
+		return "cluster"
+	}
+
+	return s.Kind + " " + strings.TrimPrefix(s.Namespace+"/"+s.Name, "/")
+}
+
+// savedMessage is the on-disk form of a chatMessage.
+type savedMessage struct {
+	Role      string      `json:"role"`
+	Content   string      `json:"content"`
+	Activity  bool        `json:"activity,omitempty"`
+	Mutation  bool        `json:"mutation,omitempty"`
+	Sources   []ai.Source `json:"sources,omitempty"`
+	Truncated bool        `json:"truncated,omitempty"`
+	Intent    *ai.Intent  `json:"intent,omitempty"`
+	Repeats   int         `json:"repeats,omitempty"`
+}
+
+func newSavedMessages(mm []chatMessage) []savedMessage {
+	ss := make([]savedMessage, 0, len(mm))
+	for _, m := range mm {
+		ss = append(ss, savedMessage{
+			Role:      m.role,
+			Content:   m.content,
+			Activity:  m.activity,
+			Mutation:  m.mutation,
+			Sources:   m.sources,
+			Truncated: m.truncated,
+			Intent:    m.intent,
+			Repeats:   m.repeats,
+		})
+	}
+
+	return ss
+}
+
+func (c *savedConversation) chatMessages() []chatMessage {
+	mm := make([]chatMessage, 0, len(c.Messages))
+	for _, m := range c.Messages {
+		mm = append(mm, chatMessage{
+			role:      m.Role,
+			content:   m.Content,
+			activity:  m.Activity,
+			mutation:  m.Mutation,
+			sources:   m.Sources,
+			truncated: m.Truncated,
+			intent:    m.Intent,
+			repeats:   m.Repeats,
+		})
+	}
+
+	return mm
+}
+
+// saveAsCmd saves the conversation under a name, replacing an earlier save
+// with the same name.
+// Usage: /save-as name.
+func (v *AIChatView) saveAsCmd(name string) {
+	if name == "" {
+		v.app.Flash().Errf("Missing name. Use /save-as <name>")
+		return
+	}
+	mm := v.messages()
+	if !slices.ContainsFunc(mm, func(m chatMessage) bool { return m.role == "assistant" }) {
+		v.app.Flash().Errf("Nothing to save yet. Ask a question first")
+		return
+	}
+
+	v.mu.Lock()
+	c := savedConversation{
+		Name:     name,
+		Context:  v.app.Config.ActiveContextName(),
+		Scope:    savedScope{Kind: v.resKind, Name: v.resName, Namespace: v.resNamespace},
+		Messages: newSavedMessages(mm),
+	}
+	v.mu.Unlock()
+	if ai.Client != nil {
+		c.Model = ai.Client.ActiveModel()
+	}
+
+	path, err := saveConversation(config.AppConversationsDir, c, time.Now())
+	if err != nil {
+		slog.Error("Unable to save AI conversation", slogs.Error, err)
+		v.app.Flash().Err(err)
+		return
+	}
+	v.app.Flash().Infof("Conversation saved to %s", path)
+}
+
+// loadCmd reopens a saved conversation. With no name, a picker lists the
+// saved conversations, most recently saved first.
+// Usage: /load [name].
+func (v *AIChatView) loadCmd(name string) {
+	dir := config.AppConversationsDir
+	if name != "" {
+		c, err := loadConversation(dir, name)
+		if err != nil {
+			v.app.Flash().Errf("Unable to load conversation %q: %s", name, err)
+			return
+		}
+		v.restoreConversation(c)
+		return
+	}
+
+	cc := listConversations(dir)
+	if len(cc) == 0 {
+		v.app.Flash().Errf("No saved conversations. Use /save-as <name> first")
+		return
+	}
+	options := make([]string, 0, len(cc))
+	for _, c := range cc {
+		options = append(options, fmt.Sprintf("%s  (%s, %s, saved %s)",
+			c.Name, c.Scope, cmp.Or(c.Model, "default model"), c.Updated.Local().Format(time.DateTime)))
+	}
+	d := v.app.Styles.Dialog()
+	dialog.ShowSelection(&d, v.app.Content.Pages, "Conversations", options, func(index int) {
+		v.app.SetFocus(v.input)
+		if index < 0 {
+			return
+		}
+		v.restoreConversation(&cc[index])
+	})
+}
+
+// restoreConversation refocuses the chat on the scope of a saved
+// conversation and replays its history. The AI session starts over, so the
+// transcript goes along with the next question.
+func (v *AIChatView) restoreConversation(c *savedConversation) {
+	v.mu.Lock()
+	busy := v.streaming
+	v.mu.Unlock()
+	if busy {
+		v.app.Flash().Warn("AI chat is busy, wait for the current answer first")
+		return
+	}
+
+	v.SetResourceContext(c.Scope.Kind, c.Scope.Name, c.Scope.Namespace)
+	v.selectSkill()
+	if ai.Client != nil {
+		ai.Client.ResetSession()
+	}
+	v.resetSessionTokens()
+	v.updateTitle()
+
+	mm := c.chatMessages()
+	scope := v.chatScope()
+	globalChatMu.Lock()
+	globalChatHistories[scope] = slices.Clone(mm)
+	globalChatMu.Unlock()
+	v.setHistory(mm)
+	v.swapPrefetched("")
+	v.swapRecap(conversationRecap(mm, maxRecapBytes))
+	notice := contextNotice(c.Context, v.app.Config.ActiveContextName())
+	if notice != "" {
+		v.recordMessage(chatMessage{role: "system", content: notice, activity: true})
+	}
+
+	v.follow = true
+	v.reRenderChat()
+	v.restorePlaceholder()
+	if notice != "" {
+		v.app.Flash().Warnf("Conversation %q was saved on context %q", c.Name, c.Context)
+		return
+	}
+	v.app.Flash().Infof("Conversation %q loaded, the assistant will get its transcript with your next question", c.Name)
+}
+
+// contextNotice warns that a conversation was saved on another context, so
+// the resources it talks about may not exist on this cluster. It is empty
+// when the contexts match or the save did not record one.
+func contextNotice(saved, active string) string {
+	if saved == "" || saved == active {
+		return ""
+	}
+
+	return fmt.Sprintf("⚠ This conversation was saved on context %s, you are now on %s: the resources it mentions may differ or not exist here",
+		tview.Escape(saved), tview.Escape(active))
+}
+
+// swapRecap replaces the pending conversation transcript and returns the
+// old one.
+func (v *AIChatView) swapRecap(recap string) string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	old := v.recap
+	v.recap = recap
+
+	return old
+}
+
+// conversationRecap renders the questions and answers of a conversation for
+// a new AI session, keeping the latest turns within maxBytes.
+func conversationRecap(mm []chatMessage, maxBytes int) string {
+	var turns []string
+	for _, m := range mm {
+		switch m.role {
+		case "user":
+			turns = append(turns, "User: "+m.content)
+		case "assistant":
+			turns = append(turns, "Assistant: "+m.content)
+		}
+	}
+	size, first := 0, len(turns)
+	for first > 0 && size+len(turns[first-1]) <= maxBytes {
+		first--
+		size += len(turns[first])
+	}
+	if first == len(turns) {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(recapHeader + "\n")
+	if first > 0 {
+		b.WriteString(trimmedMarker + "\n")
+	}
+	b.WriteString(strings.Join(turns[first:], "\n\n"))
+	b.WriteString("\n[END RESUMED CONVERSATION]\n")
+
+	return b.String()
+}
+
+func conversationPath(dir, name string) string {
+	return filepath.Join(dir, data.SanitizeFileName(name)+conversationExt)
+}
+
+// saveConversation writes the conversation to dir. Saving over an existing
+// conversation keeps its creation time.
+func saveConversation(dir string, c savedConversation, now time.Time) (string, error) {
+	if err := ensureDir(dir); err != nil {
+		return "", err
+	}
+	c.Created, c.Updated = now, now
+	if prev, err := loadConversation(dir, c.Name); err == nil {
+		c.Created = prev.Created
+	}
+	raw, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := conversationPath(dir, c.Name)
+	if err := os.WriteFile(path, raw, 0600); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+func loadConversation(dir, name string) (*savedConversation, error) {
+	return readConversation(conversationPath(dir, name))
+}
+
+func readConversation(path string) (*savedConversation, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c savedConversation
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, fmt.Errorf("invalid conversation file: %w", err)
+	}
+
+	return &c, nil
+}
+
+// listConversations returns the conversations saved in dir, most recently
+// saved first. Unreadable files are skipped.
+func listConversations(dir string) []savedConversation {
+	ee, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var cc []savedConversation
+	for _, e := range ee {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), conversationExt) {
+			continue
+		}
+		c, err := readConversation(filepath.Join(dir, e.Name()))
+		if err != nil {
+			slog.Warn("Skipping saved AI conversation", slogs.FileName, e.Name(), slogs.Error, err)
+			continue
+		}
+		cc = append(cc, *c)
+	}
+	slices.SortFunc(cc, func(a, b savedConversation) int { return b.Updated.Compare(a.Updated) })
+
+	return cc
+}
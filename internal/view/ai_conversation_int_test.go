@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/derailed/k9s/internal/ai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveLoadConversation(t *testing.T) {
+	dir := t.TempDir()
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	mm := []chatMessage{
+		{role: "user", content: "why is api OOMKilled?"},
+		{role: "system", content: "🔧 get_pod_diagnostics", activity: true, repeats: 2},
+		{role: "assistant", content: "The limit is too low [1].", sources: []ai.Source{{ID: 1, Tool: "get_pod_diagnostics"}}, truncated: true},
+	}
+	c := savedConversation{
+		Name:     "payment-svc-oom-investigation",
+		Model:    "gpt-5",
+		Scope:    savedScope{Kind: "Deployment", Name: "api", Namespace: "prod"},
+		Messages: newSavedMessages(mm),
+	}
+
+	path, err := saveConversation(dir, c, created)
+	require.NoError(t, err)
+	assert.Equal(t, conversationPath(dir, c.Name), path)
+
+	// Saving again keeps the creation time.
+	_, err = saveConversation(dir, c, created.Add(24*time.Hour))
+	require.NoError(t, err)
+	_, err = saveConversation(dir, savedConversation{Name: "older"}, created.Add(time.Hour))
+	require.NoError(t, err)
+
+	got, err := loadConversation(dir, c.Name)
+	require.NoError(t, err)
+	assert.Equal(t, created, got.Created.UTC())
+	assert.Equal(t, created.Add(24*time.Hour), got.Updated.UTC())
+	assert.Equal(t, "gpt-5", got.Model)
+	assert.Equal(t, "Deployment prod/api", got.Scope.String())
+	assert.Equal(t, mm, got.chatMessages())
+
+	cc := listConversations(dir)
+	require.Len(t, cc, 2)
+	assert.Equal(t, c.Name, cc[0].Name)
+	assert.Equal(t, "older", cc[1].Name)
+	assert.Equal(t, "cluster", cc[1].Scope.String())
+
+	_, err = loadConversation(dir, "nope")
+	assert.Error(t, err)
+}
+
+func TestContextNotice(t *testing.T) {
+	uu := map[string]struct {
+		saved, active string
+		e             string
+	}{
+		"same": {
+			saved:  "prod",
+			active: "prod",
+		},
+		"unrecorded": {
+			active: "prod",
+		},
+		"mismatch": {
+			saved:  "prod",
+			active: "staging",
+			e:      "⚠ This conversation was saved on context prod, you are now on staging: the resources it mentions may differ or not exist here",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, contextNotice(u.saved, u.active))
+		})
+	}
+}
+
+func TestConversationRecap(t *testing.T) {
+	assert.Empty(t, conversationRecap([]chatMessage{{role: "system", content: "hi", activity: true}}, maxRecapBytes))
+
+	recap := conversationRecap([]chatMessage{
+		{role: "user", content: "why is api down?"},
+		{role: "system", content: "🔧 get_events", activity: true},
+		{role: "assistant", content: "It is OOMKilled."},
+	}, maxRecapBytes)
+	assert.True(t, strings.HasPrefix(recap, recapHeader+"\n"))
+	assert.Contains(t, recap, "User: why is api down?\n\nAssistant: It is OOMKilled.")
+	assert.NotContains(t, recap, "get_events")
+
+	long := strings.Repeat("x", maxRecapBytes)
+	recap = conversationRecap([]chatMessage{
+		{role: "user", content: long},
+		{role: "assistant", content: "latest"},
+	}, maxRecapBytes)
+	assert.Contains(t, recap, trimmedMarker)
+	assert.NotContains(t, recap, long)
+}
@@ -0,0 +1,307 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/derailed/k9s/internal/ai"
+	"github.com/derailed/k9s/internal/config"
+	"github.com/derailed/k9s/internal/model"
+	"github.com/derailed/k9s/internal/slogs"
+	"github.com/derailed/k9s/internal/ui"
+	"github.com/derailed/k9s/internal/ui/dialog"
+	"github.com/derailed/k9s/internal/view/cmd"
+	"github.com/derailed/tcell/v2"
+	"github.com/derailed/tview"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	aiDashboardTitle = "AI Status"
+	allSkillsOption  = "all (no skill)"
+)
+
+// AIDashboardView shows the state of the AI integration and offers quick
+// actions to switch model/skill, reset the session or open the chat.
+type AIDashboardView struct {
+	*tview.Flex
+
+	app     *App
+	table   *tview.Table
+	actions *ui.KeyActions
+	cancel  context.CancelFunc
+}
+
+var _ model.Component = (*AIDashboardView)(nil)
+
+// NewAIDashboardView returns a new AI status view.
+func NewAIDashboardView() *AIDashboardView {
+	return &AIDashboardView{
+		Flex:    tview.NewFlex().SetDirection(tview.FlexRow),
+		table:   tview.NewTable(),
+		actions: ui.NewKeyActions(),
+	}
+}
+
+func (*AIDashboardView) SetCommand(*cmd.Interpreter)            {}
+func (*AIDashboardView) SetFilter(string, bool)                 {}
+func (*AIDashboardView) SetLabelSelector(labels.Selector, bool) {}
+
+// Init initializes the dashboard view.
+func (v *AIDashboardView) Init(ctx context.Context) error {
+	var err error
+	if v.app, err = extractApp(ctx); err != nil {
+		return err
+	}
+
+	v.SetBorder(true)
+	v.SetBorderPadding(1, 1, 2, 2)
+	v.table.SetSelectable(false, false)
+	v.AddItem(v.table, 0, 1, true)
+
+	v.bindKeys()
+	v.SetInputCapture(v.keyboard)
+	v.StylesChanged(v.app.Styles)
+	v.updateTitle()
+
+	return nil
+}
+
+// StylesChanged applies current skin styles.
+func (v *AIDashboardView) StylesChanged(s *config.Styles) {
+	views := s.Views()
+	v.SetBackgroundColor(views.Table.BgColor.Color())
+	v.table.SetBackgroundColor(views.Table.BgColor.Color())
+}
+
+func (v *AIDashboardView) updateTitle() {
+	styles := v.app.Styles.Frame()
+	v.SetTitle(ui.SkinTitle(" AI Status ", &styles))
+}
+
+// InCmdMode checks if prompt is active.
+func (*AIDashboardView) InCmdMode() bool { return false }
+
+// Name returns the component name.
+func (*AIDashboardView) Name() string { return aiDashboardTitle }
+
+// Start starts the dashboard and refreshes its state. Start is also called
+// when returning from the models or chat view so changes show up right away.
+func (v *AIDashboardView) Start() {
+	v.app.Styles.AddListener(v)
+	v.app.SetFocus(v.table)
+	v.refresh()
+}
+
+// Stop stops the dashboard view.
+func (v *AIDashboardView) Stop() {
+	v.app.Styles.RemoveListener(v)
+	if v.cancel != nil {
+		v.cancel()
+		v.cancel = nil
+	}
+}
+
+// Hints returns menu hints.
+func (v *AIDashboardView) Hints() model.MenuHints {
+	return v.actions.Hints()
+}
+
+// ExtraHints returns additional hints.
+func (*AIDashboardView) ExtraHints() map[string]string { return nil }
+
+// Actions returns menu actions.
+func (v *AIDashboardView) Actions() *ui.KeyActions {
+	return v.actions
+}
+
+func (v *AIDashboardView) bindKeys() {
+	v.actions.Bulk(ui.KeyMap{
+		tcell.KeyEscape: ui.NewKeyAction("Back", v.backCmd, false),
+		tcell.KeyEnter:  ui.NewKeyAction("Chat", v.chatCmd, true),
+		ui.KeyM:         ui.NewKeyAction("Models", v.modelsCmd, true),
+		ui.KeyS:         ui.NewKeyAction("Skill", v.skillCmd, true),
+		ui.KeyX:         ui.NewKeyAction("Reset Session", v.resetCmd, true),
+		tcell.KeyCtrlR:  ui.NewKeyAction("Refresh", v.refreshCmd, false),
+	})
+}
+
+func (v *AIDashboardView) keyboard(evt *tcell.EventKey) *tcell.EventKey {
+	if a, ok := v.actions.Get(ui.AsKey(evt)); ok {
+		return a.Action(evt)
+	}
+	return evt
+}
+
+func (v *AIDashboardView) backCmd(*tcell.EventKey) *tcell.EventKey {
+	v.app.Content.Pop()
+	return nil
+}
+
+func (v *AIDashboardView) chatCmd(*tcell.EventKey) *tcell.EventKey {
+	if err := v.app.openAIChat("", "", ""); err != nil {
+		v.app.Flash().Err(err)
+	}
+	return nil
+}
+
+func (v *AIDashboardView) modelsCmd(*tcell.EventKey) *tcell.EventKey {
+	if v.app.Config.K9s.ActiveAI().IsBYOK() {
+		v.app.Flash().Errf("Model listing is not available with BYOK providers. Set your model in the config file (ai.model).")
+		return nil
+	}
+	if err := v.app.inject(NewAIModelsView(), false); err != nil {
+		v.app.Flash().Err(err)
+	}
+	return nil
+}
+
+func (v *AIDashboardView) skillCmd(*tcell.EventKey) *tcell.EventKey {
+	if ai.Client == nil {
+		v.app.Flash().Errf("AI client not available")
+		return nil
+	}
+	names := ai.Client.Skills().List()
+	options := append([]string{allSkillsOption}, names...)
+
+	d := v.app.Styles.Dialog()
+	dialog.ShowSelection(&d, v.app.Content.Pages, "Skill", options, func(index int) {
+		if index < 0 {
+			return
+		}
+		skill := ""
+		if index > 0 {
+			skill = names[index-1]
+		}
+		ai.Client.SetSkill(skill)
+		slog.Info("AI skill changed", slogs.Subsys, "ai", "skill", skill)
+		v.app.Flash().Infof("AI skill set to: %s", options[index])
+		v.refresh()
+	})
+
+	return nil
+}
+
+func (v *AIDashboardView) resetCmd(*tcell.EventKey) *tcell.EventKey {
+	if ai.Client == nil {
+		v.app.Flash().Errf("AI client not available")
+		return nil
+	}
+	ai.Client.ResetSession()
+	v.app.Flash().Info("AI session reset")
+	v.refresh()
+
+	return nil
+}
+
+func (v *AIDashboardView) refreshCmd(*tcell.EventKey) *tcell.EventKey {
+	v.refresh()
+	return nil
+}
+
+// refresh renders the local state right away, then fills in the CLI version
+// and auth details once the CLI server answers.
+func (v *AIDashboardView) refresh() {
+	if ai.Client == nil {
+		v.render(ai.Status{Enabled: v.app.Config.K9s.ActiveAI().IsEnabled()}, false)
+		return
+	}
+	if v.cancel != nil {
+		v.cancel()
+	}
+	var ctx context.Context
+	ctx, v.cancel = context.WithCancel(context.Background())
+
+	v.render(ai.Client.Snapshot(), true)
+	go func() {
+		st := ai.Client.Status(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		v.app.QueueUpdateDraw(func() {
+			v.render(st, false)
+		})
+	}()
+}
+
+func (v *AIDashboardView) render(st ai.Status, pending bool) {
+	v.table.Clear()
+
+	skill := st.Skill
+	if skill == "" {
+		skill = allSkillsOption
+	}
+	mdl := st.Model
+	if mdl == "" {
+		mdl = "(default)"
+	}
+	cliPath := st.CLIPath
+	if cliPath == "" {
+		cliPath = "n/a"
+	}
+	version, auth := "n/a", "n/a"
+	switch {
+	case pending && st.Ready:
+		version, auth = "checking...", "checking..."
+	case st.CLIVersion != "":
+		version = st.CLIVersion
+	}
+	if !pending && st.Ready {
+		auth = authStatus(st)
+	}
+	usage := fmt.Sprintf("%d in / %d out (%d total, %d requests)",
+		st.Usage.Input, st.Usage.Output, st.Usage.Total(), st.Usage.Requests)
+
+	rows := [][2]string{
+		{"Status", readyStatus(st)},
+		{"Model", mdl},
+		{"Skill", skill},
+		{"Provider", st.Provider},
+		{"Auth", auth},
+		{"CLI Path", cliPath},
+		{"CLI Version", version},
+		{"Session Tokens", usage},
+	}
+	if st.Err != nil && !pending {
+		rows = append(rows, [2]string{"Error", fmt.Sprintf("[red::]%s[-::]", st.Err)})
+	}
+	for i, r := range rows {
+		v.table.SetCell(i, 0, tview.NewTableCell(r[0]+":").
+			SetAttributes(tcell.AttrBold).
+			SetAlign(tview.AlignRight))
+		v.table.SetCell(i, 1, tview.NewTableCell(" "+r[1]).SetExpansion(1))
+	}
+}
+
+func readyStatus(st ai.Status) string {
+	switch {
+	case !st.Enabled:
+		return "[gray::b]● Disabled[-::-]"
+	case st.Ready:
+		return "[green::b]● Ready[-::-]"
+	default:
+		return "[yellow::b]● Not started[-::-] (starts on first use)"
+	}
+}
+
+func authStatus(st ai.Status) string {
+	if st.Provider != "copilot" {
+		return "BYOK (API key)"
+	}
+	if !st.Authenticated {
+		msg := "[red::b]not authenticated[-::-]"
+		if st.AuthMessage != "" {
+			msg += " — " + st.AuthMessage
+		}
+		return msg
+	}
+	if st.Login != "" {
+		return "[green::]authenticated[-::] as " + st.Login
+	}
+
+	return "[green::]authenticated[-::]"
+}
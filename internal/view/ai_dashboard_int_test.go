@@ -0,0 +1,158 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/derailed/k9s/internal/ai"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadyStatus(t *testing.T) {
+	uu := map[string]struct {
+		st ai.Status
+		e  string
+	}{
+		"disabled": {
+			st: ai.Status{Ready: true},
+			e:  "[gray::b]● Disabled[-::-]",
+		},
+		"ready": {
+			st: ai.Status{Enabled: true, Ready: true},
+			e:  "[green::b]● Ready[-::-]",
+		},
+		"not-started": {
+			st: ai.Status{Enabled: true},
+			e:  "[yellow::b]● Not started[-::-] (starts on first use)",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, readyStatus(u.st))
+		})
+	}
+}
+
+func TestAuthStatus(t *testing.T) {
+	uu := map[string]struct {
+		st ai.Status
+		e  string
+	}{
+		"byok": {
+			st: ai.Status{Provider: "openai"},
+			e:  "BYOK (API key)",
+		},
+		"anonymous": {
+			st: ai.Status{Provider: "copilot"},
+			e:  "[red::b]not authenticated[-::-]",
+		},
+		"anonymous-message": {
+			st: ai.Status{Provider: "copilot", AuthMessage: "run gh auth login"},
+			e:  "[red::b]not authenticated[-::-] — run gh auth login",
+		},
+		"authenticated": {
+			st: ai.Status{Provider: "copilot", Authenticated: true},
+			e:  "[green::]authenticated[-::]",
+		},
+		"login": {
+			st: ai.Status{Provider: "copilot", Authenticated: true, Login: "fred"},
+			e:  "[green::]authenticated[-::] as fred",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, authStatus(u.st))
+		})
+	}
+}
+
+func TestAIDashboardRender(t *testing.T) {
+	ready := ai.Status{
+		Enabled:       true,
+		Ready:         true,
+		Model:         "gpt-5",
+		Provider:      "copilot",
+		CLIPath:       "/usr/bin/copilot",
+		CLIVersion:    "1.2.3",
+		Authenticated: true,
+		Usage:         ai.TokenUsage{Input: 100, Output: 20, Requests: 2},
+	}
+	failed := ready
+	failed.Err = errors.New("boom")
+
+	uu := map[string]struct {
+		st      ai.Status
+		pending bool
+		count   int
+		e       map[string]string
+	}{
+		"not-started": {
+			st:    ai.Status{Enabled: true, Provider: "copilot"},
+			count: 8,
+			e: map[string]string{
+				"Model:":          " (default)",
+				"Skill:":          " " + allSkillsOption,
+				"Auth:":           " n/a",
+				"CLI Path:":       " n/a",
+				"CLI Version:":    " n/a",
+				"Session Tokens:": " 0 in / 0 out (0 total, 0 requests)",
+			},
+		},
+		"pending": {
+			st:      ready,
+			pending: true,
+			count:   8,
+			e: map[string]string{
+				"Model:":       " gpt-5",
+				"Auth:":        " checking...",
+				"CLI Version:": " checking...",
+			},
+		},
+		"ready": {
+			st:    ready,
+			count: 8,
+			e: map[string]string{
+				"Auth:":           " [green::]authenticated[-::]",
+				"CLI Path:":       " /usr/bin/copilot",
+				"CLI Version:":    " 1.2.3",
+				"Session Tokens:": " 100 in / 20 out (120 total, 2 requests)",
+			},
+		},
+		"error": {
+			st:    failed,
+			count: 9,
+			e: map[string]string{
+				"Error:": " [red::]boom[-::]",
+			},
+		},
+		"error-pending": {
+			st:      failed,
+			pending: true,
+			count:   8,
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			v := NewAIDashboardView()
+			v.render(u.st, u.pending)
+			assert.Equal(t, u.count, v.table.GetRowCount())
+
+			rows := make(map[string]string, v.table.GetRowCount())
+			for r := range v.table.GetRowCount() {
+				rows[v.table.GetCell(r, 0).Text] = v.table.GetCell(r, 1).Text
+			}
+			for label, e := range u.e {
+				assert.Equal(t, e, rows[label], label)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/derailed/k9s/internal/ai"
+)
+
+const aiDoctorTitle = "AI Doctor"
+
+// runAIDoctor shows a details view and fills it with the AI health report
+// once all checks have run.
+func (a *App) runAIDoctor() error {
+	if ai.Client == nil {
+		return fmt.Errorf("AI client not available")
+	}
+	details := NewDetails(a, aiDoctorTitle, "checks", contentTXT, true).
+		Update("Running AI checks, this may take a few seconds...")
+	if err := a.inject(details, false); err != nil {
+		return err
+	}
+
+	var tf *ai.ToolFactory
+	if a.Conn() != nil && a.factory != nil {
+		tf = ai.NewToolFactory(a.factory, a.Conn(), a.Config.K9s.ActiveAI(), slog.Default())
+	}
+	go func() {
+		report := doctorReport(ai.Client.Doctor(context.Background(), tf))
+		a.QueueUpdateDraw(func() {
+			details.Update(report)
+		})
+	}()
+
+	return nil
+}
+
+func doctorReport(rr []ai.CheckResult) string {
+	var (
+		b      strings.Builder
+		failed int
+	)
+	for _, r := range rr {
+		var mark string
+		switch r.State {
+		case ai.CheckPass:
+			mark = "[green::b]✔ PASS[-::-]"
+		case ai.CheckFail:
+			mark = "[red::b]✘ FAIL[-::-]"
+			failed++
+		default:
+			mark = "[gray::b]- SKIP[-::-]"
+		}
+		fmt.Fprintf(&b, "%s  %-11s %s\n", mark, r.Name, r.Detail)
+		if r.Hint != "" {
+			fmt.Fprintf(&b, "                   [yellow::]↳ %s[-::]\n", r.Hint)
+		}
+	}
+	b.WriteString("\n")
+	if failed == 0 {
+		b.WriteString("[green::b]All checks passed.[-::-]\n")
+	} else {
+		fmt.Fprintf(&b, "[red::b]%d check(s) failed.[-::-]\n", failed)
+	}
+
+	return b.String()
+}
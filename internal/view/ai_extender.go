@@ -4,27 +4,56 @@
 package view
 
 import (
+	"github.com/derailed/k9s/internal/ai"
 	"github.com/derailed/k9s/internal/client"
+	"github.com/derailed/k9s/internal/model1"
 	"github.com/derailed/k9s/internal/ui"
 	"github.com/derailed/tcell/v2"
 )
 
 // AIExtender adds AI-powered actions to resource viewers.
-// It wraps workload-oriented views with an AI Chat keybinding.
+// It wraps workload-oriented views with an AI Chat keybinding and, when
+// enabled, health badges.
 type AIExtender struct {
 	ResourceViewer
+
+	health *healthScorer
 }
 
 // NewAIExtender returns a new AI extender wrapping the given viewer.
 func NewAIExtender(v ResourceViewer) ResourceViewer {
 	e := AIExtender{
 		ResourceViewer: v,
+		health:         newHealthScorer(v.GVR().R()),
 	}
 	e.AddBindKeysFn(e.bindKeys)
+	decorate := e.GetTable().DecorateFn()
+	e.GetTable().SetDecorateFn(func(data *model1.TableData) {
+		if decorate != nil {
+			decorate(data)
+		}
+		e.healthBadges(data)
+	})
 
 	return &e
 }
 
+// healthBadges adds the health badge column when ai.healthBadges is enabled.
+func (e *AIExtender) healthBadges(data *model1.TableData) {
+	if e.App() == nil || e.App().Config == nil {
+		return
+	}
+	cfg := e.App().Config.K9s.ActiveAI()
+	if !cfg.IsEnabled() || !cfg.HealthBadgesEnabled() {
+		return
+	}
+	hc := healthConfig{refresh: cfg.HealthRefresh()}
+	if c := ai.Client; c != nil && cfg.HealthBadges.ModelAssist && !consentPending(cfg) {
+		hc.grade = c.GradeHealth
+	}
+	e.health.decorate(data, hc)
+}
+
 func (e *AIExtender) bindKeys(aa *ui.KeyActions) {
 	aa.Bulk(ui.KeyMap{
 		ui.KeyShiftA: ui.NewKeyAction("AI Chat", e.aiChatCmd, true),
@@ -40,11 +69,8 @@ func (e *AIExtender) aiChatCmd(*tcell.EventKey) *tcell.EventKey {
 	ns, name := client.Namespaced(path)
 	kind := e.GVR().R()
 
-	chat := NewAIChatView()
-	chat.SetResourceContext(kind, name, ns)
-	if err := e.App().inject(chat, false); err != nil {
+	if err := e.App().openAIChat(kind, name, ns); err != nil {
 		e.App().Flash().Err(err)
-		return nil
 	}
 
 	return nil
@@ -0,0 +1,258 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/derailed/k9s/internal/ai"
+	"github.com/derailed/k9s/internal/model1"
+	"github.com/derailed/k9s/internal/slogs"
+)
+
+const (
+	// healthCol is the table column showing health badges.
+	healthCol = "AI"
+
+	// healthRestarts is the restart count past which a pod is degraded.
+	healthRestarts = 5
+
+	// healthGradeTimeout caps a model grading call.
+	healthGradeTimeout = 30 * time.Second
+)
+
+var (
+	// healthBadges render the grades told by the local heuristics.
+	healthBadges = map[string]string{
+		ai.HealthHealthy:  "[green::b]●",
+		ai.HealthDegraded: "[orange::b]●",
+		ai.HealthCritical: "[red::b]●",
+	}
+
+	// modelHealthBadges render the grades told by the model.
+	modelHealthBadges = map[string]string{
+		ai.HealthHealthy:  "[green::b]◆",
+		ai.HealthDegraded: "[orange::b]◆",
+		ai.HealthCritical: "[red::b]◆",
+	}
+
+	// Status markers, checked from worst to best since node statuses carry
+	// several, e.g. Ready,SchedulingDisabled.
+	criticalStatuses = []string{
+		"CrashLoopBackOff", "Error", "Failed", "ImagePullBackOff", "ErrImagePull", "InvalidImageName",
+		"CreateContainerConfigError", "OOMKilled", "Evicted", "NotReady", "Unknown", "Lost",
+	}
+	degradedStatuses = []string{
+		"Pending", "ContainerCreating", "PodInitializing", "Init:", "Terminating", "SchedulingDisabled", "Unschedulable",
+	}
+	healthyStatuses = []string{"Running", "Completed", "Succeeded", "Complete", "Ready", "Active", "Bound"}
+)
+
+// healthScorer grades the rows of a table. Grades are cached and rescored
+// once per refresh so the badges stay cheap, and the model is asked at most
+// once per refresh about the rows the heuristics can't tell.
+type healthScorer struct {
+	kind string
+
+	mx       sync.Mutex
+	local    map[string]string
+	model    map[string]string
+	scoredAt time.Time
+	askedAt  time.Time
+	asking   bool
+}
+
+func newHealthScorer(kind string) *healthScorer {
+	return &healthScorer{
+		kind:  kind,
+		local: make(map[string]string),
+		model: make(map[string]string),
+	}
+}
+
+// decorate adds the badge column to the table data.
+func (s *healthScorer) decorate(data *model1.TableData, cfg healthConfig) {
+	if _, ok := data.IndexOfHeader(healthCol); ok {
+		return
+	}
+	h := data.Header()
+	now := time.Now()
+
+	s.mx.Lock()
+	rescore := now.Sub(s.scoredAt) >= cfg.refresh
+	if rescore {
+		s.scoredAt = now
+		s.local = make(map[string]string, data.RowCount())
+	}
+	var unknown []ai.HealthRow
+	data.RowsRange(func(_ int, re model1.RowEvent) bool {
+		g, ok := s.local[re.Row.ID]
+		if !ok {
+			g = localHealth(h, re.Row.Fields)
+			s.local[re.Row.ID] = g
+		}
+		if g == "" {
+			unknown = append(unknown, ai.HealthRow{ID: re.Row.ID, Fields: re.Row.Fields})
+		}
+		return true
+	})
+	if rescore {
+		// Drop the model grades of rows now told by the heuristics or gone.
+		for id := range s.model {
+			if g, ok := s.local[id]; !ok || g != "" {
+				delete(s.model, id)
+			}
+		}
+	}
+	ask := cfg.grade != nil && len(unknown) > 0 && !s.asking && now.Sub(s.askedAt) >= cfg.refresh
+	if ask {
+		s.asking, s.askedAt = true, now
+	}
+	s.mx.Unlock()
+
+	if ask {
+		go s.askModel(cfg.grade, h.ColumnNames(true), unknown)
+	}
+
+	data.SetHeader(data.GetNamespace(), append(h.Clone(), model1.HeaderColumn{Name: healthCol}))
+	data.RowsRange(func(i int, re model1.RowEvent) bool {
+		re.Row.Fields = append(re.Row.Fields, s.badge(re.Row.ID))
+		data.SetRow(i, re)
+		return true
+	})
+}
+
+func (s *healthScorer) badge(id string) string {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if g := s.local[id]; g != "" {
+		return healthBadges[g]
+	}
+
+	return modelHealthBadges[s.model[id]]
+}
+
+// gradeFunc asks the model to grade table rows.
+type gradeFunc func(ctx context.Context, kind string, header []string, rows []ai.HealthRow) (map[string]string, error)
+
+func (s *healthScorer) askModel(grade gradeFunc, header []string, rows []ai.HealthRow) {
+	ctx, cancel := context.WithTimeout(context.Background(), healthGradeTimeout)
+	defer cancel()
+	grades, err := grade(ctx, s.kind, header, rows)
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	s.asking = false
+	if err != nil {
+		slog.Debug("AI health grading failed", slogs.Subsys, "ai", slogs.Error, err)
+		return
+	}
+	for id, g := range grades {
+		s.model[id] = g
+	}
+}
+
+// healthConfig tunes a scoring pass.
+type healthConfig struct {
+	refresh time.Duration
+	// grade is nil unless the model may be asked.
+	grade gradeFunc
+}
+
+// localHealth grades a row from its status, readiness, restarts and
+// validation columns. It returns an empty grade when none tells.
+func localHealth(h model1.Header, ff model1.Fields) string {
+	field := func(col string) (string, bool) {
+		idx, ok := h.IndexOf(col, true)
+		if !ok || idx >= len(ff) {
+			return "", false
+		}
+		return strings.TrimSpace(ff[idx]), true
+	}
+
+	var gg []string
+	status, _ := field("STATUS")
+	sg := statusHealth(status)
+	gg = append(gg, sg)
+	// Readiness tells nothing more when the status already explains it, or for
+	// completed pods which are never ready.
+	done := status == "Completed" || status == "Succeeded" || status == "Complete"
+	if ready, ok := field("READY"); ok && (sg == "" || sg == ai.HealthHealthy) && !done {
+		desired, _ := field("DESIRED")
+		gg = append(gg, readyHealth(ready, desired, true))
+	}
+	if completions, ok := field("COMPLETIONS"); ok {
+		gg = append(gg, readyHealth(completions, "", false))
+	}
+	// Restarts may carry the time of the last one, e.g. 7 (2m ago).
+	if restarts, ok := field("RESTARTS"); ok {
+		count, _, _ := strings.Cut(restarts, " ")
+		if n, err := strconv.Atoi(count); err == nil && n >= healthRestarts {
+			gg = append(gg, ai.HealthDegraded)
+		}
+	}
+	if valid, ok := field("VALID"); ok && valid != "" {
+		gg = append(gg, ai.HealthDegraded)
+	}
+
+	return worstHealth(gg)
+}
+
+// statusHealth grades a status column.
+func statusHealth(status string) string {
+	has := func(markers []string) bool {
+		return slices.ContainsFunc(markers, func(m string) bool { return strings.Contains(status, m) })
+	}
+	switch {
+	case has(criticalStatuses):
+		return ai.HealthCritical
+	case has(degradedStatuses):
+		return ai.HealthDegraded
+	case has(healthyStatuses):
+		return ai.HealthHealthy
+	default:
+		return ""
+	}
+}
+
+// readyHealth grades a ready/total column, e.g. 1/3, or a ready count
+// against a desired count. Nothing ready is critical when critical is set.
+func readyHealth(ready, desired string, critical bool) string {
+	cur, total, ok := strings.Cut(ready, "/")
+	if !ok {
+		cur, total = ready, desired
+	}
+	c, err1 := strconv.Atoi(strings.TrimSpace(cur))
+	t, err2 := strconv.Atoi(strings.TrimSpace(total))
+	switch {
+	case err1 != nil || err2 != nil:
+		return ""
+	case c >= t:
+		return ai.HealthHealthy
+	case c == 0 && critical:
+		return ai.HealthCritical
+	default:
+		return ai.HealthDegraded
+	}
+}
+
+// worstHealth returns the worst of the grades, ignoring blank ones.
+func worstHealth(gg []string) string {
+	rank := map[string]int{ai.HealthHealthy: 1, ai.HealthDegraded: 2, ai.HealthCritical: 3}
+	var worst string
+	for _, g := range gg {
+		if rank[g] > rank[worst] {
+			worst = g
+		}
+	}
+
+	return worst
+}
@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/derailed/k9s/internal/ai"
+	"github.com/derailed/k9s/internal/client"
+	"github.com/derailed/k9s/internal/model1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalHealth(t *testing.T) {
+	pods := model1.Header{
+		{Name: "NAME"}, {Name: "READY"}, {Name: "STATUS"}, {Name: "RESTARTS"},
+		{Name: "VALID", Attrs: model1.Attrs{Wide: true}},
+	}
+	uu := map[string]struct {
+		h  model1.Header
+		ff model1.Fields
+		e  string
+	}{
+		"running":   {h: pods, ff: model1.Fields{"p1", "1/1", "Running", "0", ""}, e: ai.HealthHealthy},
+		"crashloop": {h: pods, ff: model1.Fields{"p1", "0/1", "CrashLoopBackOff", "12", "container not ready"}, e: ai.HealthCritical},
+		"restarts":  {h: pods, ff: model1.Fields{"p1", "1/1", "Running", "7", ""}, e: ai.HealthDegraded},
+		"not-ready": {h: pods, ff: model1.Fields{"p1", "1/2", "Running", "0", ""}, e: ai.HealthDegraded},
+		"completed": {h: pods, ff: model1.Fields{"p1", "0/1", "Completed", "0", ""}, e: ai.HealthHealthy},
+		"pending":   {h: pods, ff: model1.Fields{"p1", "0/1", "Pending", "0", ""}, e: ai.HealthDegraded},
+		"unready":   {h: pods, ff: model1.Fields{"p1", "0/1", "Running", "0", ""}, e: ai.HealthCritical},
+		"invalid":   {h: pods, ff: model1.Fields{"p1", "1/1", "Running", "0", "probe missing"}, e: ai.HealthDegraded},
+		"node-cordoned": {
+			h:  model1.Header{{Name: "NAME"}, {Name: "STATUS"}},
+			ff: model1.Fields{"n1", "Ready,SchedulingDisabled"},
+			e:  ai.HealthDegraded,
+		},
+		"node-down": {
+			h:  model1.Header{{Name: "NAME"}, {Name: "STATUS"}},
+			ff: model1.Fields{"n1", "NotReady"},
+			e:  ai.HealthCritical,
+		},
+		"ds": {
+			h:  model1.Header{{Name: "NAME"}, {Name: "DESIRED"}, {Name: "READY"}},
+			ff: model1.Fields{"ds1", "3", "2"},
+			e:  ai.HealthDegraded,
+		},
+		"job-running": {
+			h:  model1.Header{{Name: "NAME"}, {Name: "COMPLETIONS"}},
+			ff: model1.Fields{"j1", "0/1"},
+			e:  ai.HealthDegraded,
+		},
+		"unknown": {
+			h:  model1.Header{{Name: "NAME"}, {Name: "TYPE"}},
+			ff: model1.Fields{"svc1", "ClusterIP"},
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, localHealth(u.h, u.ff))
+		})
+	}
+}
+
+func TestHealthScorerDecorate(t *testing.T) {
+	h := model1.Header{{Name: "NAME"}, {Name: "STATUS"}}
+	newData := func() *model1.TableData {
+		return model1.NewTableDataWithRows(client.NewGVR("v1/pods"), h, model1.NewRowEventsWithEvts(
+			model1.NewRowEvent(model1.EventAdd, model1.Row{ID: "ns/p1", Fields: model1.Fields{"p1", "Running"}}),
+			model1.NewRowEvent(model1.EventAdd, model1.Row{ID: "ns/p2", Fields: model1.Fields{"p2", "Weird"}}),
+		))
+	}
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	grade := func(_ context.Context, kind string, _ []string, rows []ai.HealthRow) (map[string]string, error) {
+		calls.Add(1)
+		assert.Equal(t, "pods", kind)
+		assert.Len(t, rows, 1)
+		<-release
+		return map[string]string{"ns/p2": ai.HealthCritical}, nil
+	}
+
+	s := newHealthScorer("pods")
+	cfg := healthConfig{refresh: time.Hour, grade: grade}
+	data := newData()
+	s.decorate(data, cfg)
+
+	idx, ok := data.IndexOfHeader(healthCol)
+	require.True(t, ok)
+	re, _ := data.FindRow("ns/p1")
+	assert.Equal(t, healthBadges[ai.HealthHealthy], re.Row.Fields[idx])
+	re, _ = data.FindRow("ns/p2")
+	assert.Empty(t, re.Row.Fields[idx])
+	close(release)
+
+	// The model grade shows once it lands, without asking again.
+	require.Eventually(t, func() bool {
+		data = newData()
+		s.decorate(data, cfg)
+		re, _ = data.FindRow("ns/p2")
+		return re.Row.Fields[idx] == modelHealthBadges[ai.HealthCritical]
+	}, time.Second, 10*time.Millisecond)
+	s.decorate(data, cfg)
+	assert.Len(t, data.Header(), 3)
+	assert.Equal(t, int32(1), calls.Load())
+}
@@ -0,0 +1,243 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/derailed/k9s/internal/config"
+	"github.com/derailed/tview"
+)
+
+// codePalette maps code tokens to skin colors.
+type codePalette struct {
+	text, key, str, num, keyword, comment, punct config.Color
+}
+
+func newCodePalette(s *config.Styles) codePalette {
+	f, y := s.Frame(), s.Views().Yaml
+
+	return codePalette{
+		text:    f.Title.HighlightColor,
+		key:     y.KeyColor,
+		str:     f.Status.ModifyColor,
+		num:     f.Status.PendingColor,
+		keyword: f.Status.KillColor,
+		comment: f.Status.CompletedColor,
+		punct:   y.ColonColor,
+	}
+}
+
+// codeLang describes how to tokenize a fenced code block language.
+type codeLang struct {
+	comment  string
+	quotes   string
+	punct    string
+	keywords map[string]struct{}
+}
+
+func newKeywords(kk ...string) map[string]struct{} {
+	m := make(map[string]struct{}, len(kk))
+	for _, k := range kk {
+		m[k] = struct{}{}
+	}
+
+	return m
+}
+
+var (
+	yamlLang = &codeLang{
+		comment:  "#",
+		quotes:   `"'`,
+		punct:    "{}[],",
+		keywords: newKeywords("true", "false", "null", "yes", "no", "on", "off"),
+	}
+	jsonLang = &codeLang{
+		quotes:   `"`,
+		punct:    "{}[],:",
+		keywords: newKeywords("true", "false", "null"),
+	}
+	bashLang = &codeLang{
+		comment: "#",
+		quotes:  `"'`,
+		punct:   "|&;<>(){}",
+		keywords: newKeywords("if", "then", "else", "elif", "fi", "for", "while", "until", "do", "done",
+			"case", "esac", "in", "function", "return", "export", "local", "set", "unset", "echo", "exit"),
+	}
+	goLang = &codeLang{
+		comment: "//",
+		quotes:  "\"'`",
+		punct:   "{}[](),;:=",
+		keywords: newKeywords("break", "case", "chan", "const", "continue", "default", "defer", "else",
+			"fallthrough", "for", "func", "go", "goto", "if", "import", "interface", "map", "package",
+			"range", "return", "select", "struct", "switch", "type", "var", "nil", "true", "false"),
+	}
+
+	codeLangs = map[string]*codeLang{
+		"yaml": yamlLang, "yml": yamlLang,
+		"json": jsonLang, "jsonc": jsonLang,
+		"bash": bashLang, "sh": bashLang, "shell": bashLang, "zsh": bashLang, "console": bashLang,
+		"go": goLang, "golang": goLang,
+	}
+
+	yamlKeyRX = regexp.MustCompile(`\A(\s*(?:- +)?)("[^"]*"|'[^']*'|[^\s#'"{\[][^:#]*?)(:)(\s|\z)`)
+)
+
+// highlightCode returns a tview-tagged rendition of a single code line. Lines in
+// unknown languages use the plain code color.
+func highlightCode(lang, line string, p codePalette) string {
+	l, ok := codeLangs[strings.ToLower(lang)]
+	if !ok {
+		return "[" + p.text.String() + "::-]" + tview.Escape(line) + "[-::-]"
+	}
+
+	h := codeHighlighter{lang: l, p: p}
+	if l == yamlLang {
+		if m := yamlKeyRX.FindStringSubmatch(line); m != nil {
+			h.emit(p.punct, m[1])
+			h.emit(p.key, m[2])
+			h.emit(p.punct, m[3])
+			line = line[len(m[1])+len(m[2])+len(m[3]):]
+		} else if t := strings.TrimLeft(line, " "); strings.HasPrefix(t, "- ") {
+			h.emit(p.punct, line[:len(line)-len(t)+2])
+			line = t[2:]
+		}
+	}
+	h.scan(line)
+
+	return h.String()
+}
+
+type codeHighlighter struct {
+	strings.Builder
+
+	lang  *codeLang
+	p     codePalette
+	plain strings.Builder
+}
+
+// emit writes a colored token, flushing any pending plain text first.
+func (h *codeHighlighter) emit(c config.Color, s string) {
+	h.write(c, "-", s)
+}
+
+func (h *codeHighlighter) write(c config.Color, attrs, s string) {
+	h.flush()
+	if s == "" {
+		return
+	}
+	h.WriteString("[" + c.String() + "::" + attrs + "]" + tview.Escape(s) + "[-::-]")
+}
+
+func (h *codeHighlighter) flush() {
+	if h.plain.Len() == 0 {
+		return
+	}
+	s := h.plain.String()
+	h.plain.Reset()
+	h.emit(h.p.text, s)
+}
+
+func (h *codeHighlighter) scan(s string) {
+	l := h.lang
+	for i := 0; i < len(s); {
+		c := s[i]
+		atBoundary := i == 0 || !isWordByte(s[i-1])
+
+		switch {
+		case l.comment != "" && strings.HasPrefix(s[i:], l.comment) &&
+			(l.comment != "#" || i == 0 || s[i-1] == ' ' || s[i-1] == '\t'):
+			h.write(h.p.comment, "d", s[i:])
+			return
+
+		case strings.IndexByte(l.quotes, c) >= 0:
+			j := closingQuote(s, i)
+			color := h.p.str
+			if l == jsonLang && strings.HasPrefix(strings.TrimLeft(s[j:], " \t"), ":") {
+				color = h.p.key
+			}
+			h.emit(color, s[i:j])
+			i = j
+
+		case l == bashLang && c == '$' && i+1 < len(s):
+			j := i + 1
+			if s[j] == '{' {
+				if k := strings.IndexByte(s[j:], '}'); k >= 0 {
+					j += k + 1
+				} else {
+					j = len(s)
+				}
+			} else {
+				for j < len(s) && (isWordByte(s[j]) || strings.IndexByte("?#@!*", s[j]) >= 0 && j == i+1) {
+					j++
+				}
+			}
+			h.emit(h.p.key, s[i:j])
+			i = j
+
+		case l == bashLang && c == '-' && (i == 0 || s[i-1] == ' ') && i+1 < len(s) && (isWordByte(s[i+1]) || s[i+1] == '-'):
+			j := i + 1
+			for j < len(s) && s[j] != ' ' && s[j] != '=' {
+				j++
+			}
+			h.emit(h.p.key, s[i:j])
+			i = j
+
+		case isDigit(c) && atBoundary:
+			j := i + 1
+			for j < len(s) && (isWordByte(s[j]) || s[j] == '.') {
+				j++
+			}
+			h.emit(h.p.num, s[i:j])
+			i = j
+
+		case isWordByte(c) && atBoundary:
+			j := i + 1
+			for j < len(s) && isWordByte(s[j]) {
+				j++
+			}
+			w := s[i:j]
+			if _, ok := l.keywords[w]; ok && (j == len(s) || s[j] != '-') {
+				h.emit(h.p.keyword, w)
+			} else {
+				h.plain.WriteString(w)
+			}
+			i = j
+
+		case strings.IndexByte(l.punct, c) >= 0:
+			h.emit(h.p.punct, s[i:i+1])
+			i++
+
+		default:
+			h.plain.WriteByte(c)
+			i++
+		}
+	}
+	h.flush()
+}
+
+// closingQuote returns the index just past the quote closing the string
+// starting at i, or the end of the line for unterminated strings.
+func closingQuote(s string, i int) int {
+	q := s[i]
+	for j := i + 1; j < len(s); j++ {
+		switch {
+		case s[j] == '\\' && q != '`' && q != '\'':
+			j++
+		case s[j] == q:
+			return j + 1
+		}
+	}
+
+	return len(s)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || isDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/derailed/k9s/internal/config"
+	"github.com/derailed/k9s/internal/slogs"
+)
+
+var (
+	// historyLoad loads the persisted chat histories once per run.
+	historyLoad sync.Once
+	// historyMu serializes writes to the chat history file.
+	historyMu sync.Mutex
+)
+
+// loadPersistedHistory seeds the chat histories from disk the first time a
+// chat view starts, when ai.persistHistory is set.
+func (v *AIChatView) loadPersistedHistory() {
+	if !v.app.Config.K9s.ActiveAI().PersistHistory {
+		return
+	}
+	historyLoad.Do(func() {
+		if err := loadChatHistories(config.AppChatHistoryFile); err != nil {
+			slog.Warn("Unable to load AI chat history", slogs.FileName, config.AppChatHistoryFile, slogs.Error, err)
+		}
+	})
+}
+
+// persistHistory saves the chat histories to disk when ai.persistHistory is
+// set.
+func (v *AIChatView) persistHistory() {
+	if !v.app.Config.K9s.ActiveAI().PersistHistory {
+		return
+	}
+	if err := saveChatHistories(config.AppChatHistoryFile); err != nil {
+		slog.Warn("Unable to save AI chat history", slogs.FileName, config.AppChatHistoryFile, slogs.Error, err)
+	}
+}
+
+// loadChatHistories adds the histories saved in path to the chat histories.
+// Scopes already chatted in during this run are kept as is. A missing file
+// is not an error.
+func loadChatHistories(path string) error {
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var ss map[string][]savedMessage
+	if err := json.Unmarshal(raw, &ss); err != nil {
+		return fmt.Errorf("invalid chat history file: %w", err)
+	}
+
+	globalChatMu.Lock()
+	defer globalChatMu.Unlock()
+	for scope, mm := range ss {
+		if _, ok := globalChatHistories[scope]; ok || len(mm) == 0 {
+			continue
+		}
+		c := savedConversation{Messages: mm}
+		globalChatHistories[scope] = c.chatMessages()
+	}
+
+	return nil
+}
+
+// saveChatHistories writes the chat histories to path, keyed by scope.
+func saveChatHistories(path string) error {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	globalChatMu.Lock()
+	ss := make(map[string][]savedMessage, len(globalChatHistories))
+	for scope, mm := range globalChatHistories {
+		if len(mm) > 0 {
+			ss[scope] = newSavedMessages(mm)
+		}
+	}
+	globalChatMu.Unlock()
+
+	raw, err := json.Marshal(ss)
+	if err != nil {
+		return err
+	}
+	if err := ensureDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, raw, 0600)
+}
@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveLoadChatHistories(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ai", "chat-history.json")
+	saved := []chatMessage{
+		{role: "user", content: "why is api OOMKilled?"},
+		{role: "assistant", content: "The limit is too low.", truncated: true},
+	}
+	live := []chatMessage{{role: "user", content: "still here"}}
+
+	globalChatMu.Lock()
+	prev := globalChatHistories
+	globalChatHistories = map[string][]chatMessage{
+		"Deployment/prod/api": saved,
+		"Pod/prod/p1":         live,
+		"_global_":            nil,
+	}
+	globalChatMu.Unlock()
+	t.Cleanup(func() {
+		globalChatMu.Lock()
+		globalChatHistories = prev
+		globalChatMu.Unlock()
+	})
+
+	require.NoError(t, saveChatHistories(path))
+	fi, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+
+	// Scopes chatted in since the start are left alone.
+	globalChatMu.Lock()
+	globalChatHistories = map[string][]chatMessage{"Pod/prod/p1": {{role: "user", content: "newer"}}}
+	globalChatMu.Unlock()
+	require.NoError(t, loadChatHistories(path))
+
+	globalChatMu.Lock()
+	defer globalChatMu.Unlock()
+	assert.Equal(t, map[string][]chatMessage{
+		"Deployment/prod/api": saved,
+		"Pod/prod/p1":         {{role: "user", content: "newer"}},
+	}, globalChatHistories)
+}
+
+func TestLoadChatHistoriesMissing(t *testing.T) {
+	require.NoError(t, loadChatHistories(filepath.Join(t.TempDir(), "nope.json")))
+
+	path := filepath.Join(t.TempDir(), "bad.json")
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0600))
+	assert.ErrorContains(t, loadChatHistories(path), "invalid chat history file")
+}
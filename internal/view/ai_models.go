@@ -7,6 +7,8 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/derailed/k9s/internal/ai"
@@ -33,6 +35,7 @@ type AIModelsView struct {
 	table   *tview.Table
 	actions *ui.KeyActions
 	models  []ai.ModelInfo
+	cancel  context.CancelFunc
 	mu      sync.Mutex
 }
 
@@ -75,9 +78,6 @@ func (v *AIModelsView) Init(ctx context.Context) error {
 	v.StylesChanged(v.app.Styles)
 	v.updateTitle()
 
-	// Load models asynchronously.
-	go v.loadModels(ctx)
-
 	return nil
 }
 
@@ -104,11 +104,36 @@ func (*AIModelsView) Name() string { return aiModelsTitle }
 func (v *AIModelsView) Start() {
 	v.app.Styles.AddListener(v)
 	v.app.SetFocus(v.table)
+
+	v.mu.Lock()
+	loaded := v.models != nil
+	v.mu.Unlock()
+	if loaded {
+		return
+	}
+	v.load(false)
+}
+
+// load fetches the models in the background, bypassing the cache when force
+// is set. The load is cancelled when the view stops.
+func (v *AIModelsView) load(force bool) {
+	v.cancelLoad()
+	ctx, cancel := context.WithCancel(context.Background())
+	v.cancel = cancel
+	go v.loadModels(ctx, force)
 }
 
 // Stop stops the models view.
 func (v *AIModelsView) Stop() {
 	v.app.Styles.RemoveListener(v)
+	v.cancelLoad()
+}
+
+func (v *AIModelsView) cancelLoad() {
+	if v.cancel != nil {
+		v.cancel()
+		v.cancel = nil
+	}
 }
 
 // Hints returns menu hints.
@@ -128,6 +153,7 @@ func (v *AIModelsView) bindKeys() {
 	v.actions.Bulk(ui.KeyMap{
 		tcell.KeyEscape: ui.NewKeyAction("Back", v.backCmd, false),
 		tcell.KeyEnter:  ui.NewKeyAction("Select", v.selectModelKey, false),
+		tcell.KeyCtrlR:  ui.NewKeyAction("Refresh", v.refreshCmd, false),
 	})
 }
 
@@ -139,10 +165,16 @@ func (v *AIModelsView) keyboard(evt *tcell.EventKey) *tcell.EventKey {
 }
 
 func (v *AIModelsView) backCmd(evt *tcell.EventKey) *tcell.EventKey {
+	v.cancelLoad()
 	v.app.Content.Pop()
 	return nil
 }
 
+func (v *AIModelsView) refreshCmd(*tcell.EventKey) *tcell.EventKey {
+	v.load(true)
+	return nil
+}
+
 func (v *AIModelsView) selectModelKey(evt *tcell.EventKey) *tcell.EventKey {
 	row, _ := v.table.GetSelection()
 	v.selectModel(row, 0)
@@ -170,40 +202,58 @@ func (v *AIModelsView) selectModel(row, _ int) {
 	v.app.Content.Pop()
 }
 
-func (v *AIModelsView) loadModels(ctx context.Context) {
+// queueUpdateDraw schedules f on the UI goroutine unless the load was
+// cancelled by the time it runs.
+func (v *AIModelsView) queueUpdateDraw(ctx context.Context, f func()) {
+	v.app.QueueUpdateDraw(func() {
+		if ctx.Err() != nil {
+			return
+		}
+		f()
+	})
+}
+
+func (v *AIModelsView) loadModels(ctx context.Context, force bool) {
 	if ai.Client == nil {
-		v.app.QueueUpdateDraw(func() {
+		v.queueUpdateDraw(ctx, func() {
 			v.showError("AI client not initialized")
 		})
 		return
 	}
 
-	v.app.QueueUpdateDraw(func() {
+	v.queueUpdateDraw(ctx, func() {
 		v.table.Clear()
 		v.table.SetCell(0, 0, tview.NewTableCell("Loading models...").
 			SetSelectable(false))
 	})
 
-	models, err := ai.Client.ListModels(ctx)
+	list := ai.Client.ListModels
+	if force {
+		list = ai.Client.RefreshModels
+	}
+	models, err := list(ctx)
+	if ctx.Err() != nil {
+		slog.Debug("AI model listing cancelled", slogs.Subsys, "ai")
+		return
+	}
 	if err != nil {
 		slog.Error("Failed to list AI models", slogs.Error, err)
-		v.app.QueueUpdateDraw(func() {
+		v.queueUpdateDraw(ctx, func() {
 			v.showError(fmt.Sprintf("Failed to load models: %v", err))
 		})
 		return
 	}
 
-	v.mu.Lock()
-	v.models = models
-	v.mu.Unlock()
-
 	activeModel := ai.Client.ActiveModel()
 
-	v.app.QueueUpdateDraw(func() {
+	v.queueUpdateDraw(ctx, func() {
+		v.mu.Lock()
+		v.models = models
+		v.mu.Unlock()
 		v.table.Clear()
 
 		// Header row.
-		headers := []string{"", "MODEL ID", "NAME"}
+		headers := []string{"", "MODEL ID", "NAME", "CONTEXT", "COST", "NOTES"}
 		for col, h := range headers {
 			cell := tview.NewTableCell(h).
 				SetSelectable(false).
@@ -221,9 +271,22 @@ func (v *AIModelsView) loadModels(ctx context.Context) {
 				indicator = "✓"
 			}
 
-			v.table.SetCell(row, 0, tview.NewTableCell(indicator).SetExpansion(0))
-			v.table.SetCell(row, 1, tview.NewTableCell(m.ID).SetExpansion(1))
-			v.table.SetCell(row, 2, tview.NewTableCell(m.Name).SetExpansion(1))
+			// Preview, deprecated and disabled models stand out from the rest.
+			color := tcell.ColorDefault
+			switch {
+			case m.Disabled, m.Deprecated:
+				color = tcell.ColorGray
+			case m.Preview:
+				color = tcell.ColorOrange
+			}
+			cells := []string{indicator, m.ID, m.Name, modelContext(m), modelCost(m), modelNotes(m)}
+			for col, c := range cells {
+				cell := tview.NewTableCell(c).SetExpansion(1).SetTextColor(color)
+				if col == 0 {
+					cell.SetExpansion(0)
+				}
+				v.table.SetCell(row, col, cell)
+			}
 		}
 
 		if len(models) == 0 {
@@ -236,6 +299,46 @@ func (v *AIModelsView) loadModels(ctx context.Context) {
 	})
 }
 
+// modelContext renders a model's context window, e.g. 128k.
+func modelContext(m ai.ModelInfo) string {
+	if m.ContextWindow <= 0 {
+		return ""
+	}
+
+	return strconv.Itoa(m.ContextWindow/1000) + "k"
+}
+
+// modelCost renders a model's premium request multiplier, e.g. 0.33x.
+func modelCost(m ai.ModelInfo) string {
+	if m.Multiplier <= 0 {
+		return ""
+	}
+
+	return strconv.FormatFloat(m.Multiplier, 'f', -1, 64) + "x"
+}
+
+// modelNotes lists a model's status and capabilities.
+func modelNotes(m ai.ModelInfo) string {
+	var nn []string
+	if m.Disabled {
+		nn = append(nn, "disabled")
+	}
+	if m.Deprecated {
+		nn = append(nn, "deprecated")
+	}
+	if m.Preview {
+		nn = append(nn, "preview")
+	}
+	if m.Vision {
+		nn = append(nn, "vision")
+	}
+	if len(m.ReasoningEfforts) > 0 {
+		nn = append(nn, "reasoning")
+	}
+
+	return strings.Join(nn, ", ")
+}
+
 func (v *AIModelsView) showError(msg string) {
 	v.table.Clear()
 	v.table.SetCell(0, 0, tview.NewTableCell(fmt.Sprintf("[red::b]%s[-::-]", msg)).
@@ -0,0 +1,140 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/derailed/k9s/internal/ai"
+	"github.com/derailed/k9s/internal/config"
+	"github.com/derailed/k9s/internal/slogs"
+)
+
+// panelCmd asks several models the same question and shows their answers
+// one after the other, for a second opinion.
+// Usage: /panel [-m model1,model2] question. Models default to ai.panelModels.
+func (v *AIChatView) panelCmd(args []string) {
+	models, question := parsePanelArgs(args, v.app.Config.K9s.ActiveAI().PanelModels)
+	if question == "" {
+		v.app.Flash().Errf("Missing question. Use /panel [-m model1,model2] <question>")
+		return
+	}
+	if len(models) < 2 {
+		v.app.Flash().Errf("A panel takes at least 2 models. Set ai.panelModels or use /panel -m model1,model2 <question>")
+		return
+	}
+	if len(models) > config.MaxAIPanelModels {
+		v.app.Flash().Warnf("A panel asks at most %d models, ignoring %s", config.MaxAIPanelModels, strings.Join(models[config.MaxAIPanelModels:], ", "))
+		models = models[:config.MaxAIPanelModels]
+	}
+	v.mu.Lock()
+	busy := v.streaming
+	v.mu.Unlock()
+	if busy {
+		v.app.Flash().Warn("AI chat is busy, wait for the current answer first")
+		return
+	}
+
+	v.follow = true
+	v.appendMessage("user", question)
+	msg := fmt.Sprintf("⚖ Asking a panel of %d models: %s — each answers on a session of its own, costing about %d times the tokens of a single answer",
+		len(models), strings.Join(models, ", "), len(models))
+	v.recordMessage(chatMessage{role: "system", content: msg, activity: true})
+	v.queueDraw(func() {
+		v.renderMessage("system", msg)
+	})
+	v.showThinkingIndicator()
+	go v.askPanel(models, question)
+}
+
+// parsePanelArgs splits /panel arguments into the models to ask and the
+// question.
+func parsePanelArgs(args, defaults []string) ([]string, string) {
+	models := defaults
+	if len(args) >= 2 && (args[0] == "-m" || args[0] == "--models") {
+		models, args = strings.Split(args[1], ","), args[2:]
+	}
+	var mm []string
+	for _, m := range models {
+		if m = strings.TrimSpace(m); m != "" && !slices.Contains(mm, m) {
+			mm = append(mm, m)
+		}
+	}
+
+	return mm, strings.Join(args, " ")
+}
+
+// askPanel sends the question to the panel and records the answers once all
+// models are done.
+func (v *AIChatView) askPanel(models []string, question string) {
+	v.mu.Lock()
+	if v.streaming {
+		v.mu.Unlock()
+		return
+	}
+	v.streaming, v.paneling = true, true
+	v.mu.Unlock()
+
+	v.queueDraw(func() {
+		v.input.SetPlaceholder("Waiting on the panel answers...")
+		v.setStatusThinking()
+	})
+	defer func() {
+		v.mu.Lock()
+		v.streaming, v.paneling = false, false
+		v.mu.Unlock()
+		v.queueDraw(func() {
+			v.restorePlaceholder()
+			v.setStatusReady()
+			v.app.SetFocus(v.input)
+		})
+	}()
+
+	if ai.Client == nil {
+		v.queueDraw(v.clearThinkingIndicator)
+		v.appendError("AI client not available. Check logs for initialization errors.")
+		return
+	}
+	ctx, done := v.trackRequest()
+	defer done()
+	aa, err := ai.Client.AskPanel(ctx, models, v.buildContextualPrompt(question))
+	if errors.Is(context.Cause(ctx), errChatClosed) {
+		return
+	}
+	v.queueDraw(v.clearThinkingIndicator)
+	if err != nil {
+		slog.Error("AI panel request failed", slogs.Error, err)
+		v.appendError(err.Error())
+		return
+	}
+	for _, a := range aa {
+		v.appendMessage("assistant", panelAnswer(a))
+	}
+}
+
+// panelAnswer labels the answer of a panel member with its model and token
+// cost. Citation markers are dropped since the tool results they point to
+// belong to the member's own session.
+func panelAnswer(a ai.PanelAnswer) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "### %s\n\n", a.Model)
+	switch {
+	case a.Err != nil:
+		fmt.Fprintf(&b, "*No answer: %s*", a.Err)
+	case strings.TrimSpace(a.Content) == "":
+		b.WriteString(emptyResponseNotice)
+	default:
+		b.WriteString(ai.CitationRX.ReplaceAllString(a.Content, ""))
+	}
+	if t := a.Usage.Total(); t > 0 {
+		fmt.Fprintf(&b, "\n\n*%d tokens*", t)
+	}
+
+	return b.String()
+}
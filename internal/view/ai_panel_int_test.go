@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/derailed/k9s/internal/ai"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePanelArgs(t *testing.T) {
+	defaults := []string{"gpt-4.1", "claude-sonnet-4"}
+
+	uu := map[string]struct {
+		args     []string
+		models   []string
+		question string
+	}{
+		"defaults": {
+			args:     []string{"should", "I", "drain", "node-1?"},
+			models:   defaults,
+			question: "should I drain node-1?",
+		},
+		"flag": {
+			args:     []string{"-m", "gpt-5,o3,gpt-5,", "why?"},
+			models:   []string{"gpt-5", "o3"},
+			question: "why?",
+		},
+		"long-flag": {
+			args:     []string{"--models", "o3", "why?"},
+			models:   []string{"o3"},
+			question: "why?",
+		},
+		"no-question": {
+			args:   []string{"-m", "o3,gpt-5"},
+			models: []string{"o3", "gpt-5"},
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			models, question := parsePanelArgs(u.args, defaults)
+			assert.Equal(t, u.models, models)
+			assert.Equal(t, u.question, question)
+		})
+	}
+}
+
+func TestPanelAnswer(t *testing.T) {
+	uu := map[string]struct {
+		answer ai.PanelAnswer
+		e      string
+	}{
+		"answer": {
+			answer: ai.PanelAnswer{Model: "o3", Content: "Roll back [1] the deployment.", Usage: ai.TokenUsage{Input: 100, Output: 20}},
+			e:      "### o3\n\nRoll back the deployment.\n\n*120 tokens*",
+		},
+		"failed": {
+			answer: ai.PanelAnswer{Model: "o3", Err: errors.New("model not available")},
+			e:      "### o3\n\n*No answer: model not available*",
+		},
+		"empty": {
+			answer: ai.PanelAnswer{Model: "o3"},
+			e:      "### o3\n\n" + emptyResponseNotice,
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, panelAnswer(u.answer))
+		})
+	}
+}
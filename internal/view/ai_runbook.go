@@ -0,0 +1,239 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/derailed/k9s/internal/ai"
+	"github.com/derailed/k9s/internal/config/data"
+	"github.com/derailed/k9s/internal/slogs"
+)
+
+const (
+	genericSymptom   = "general"
+	runbookTimestamp = "20060102-150405"
+)
+
+// knownSymptoms lists the failure signatures used to tag runbooks, most
+// specific first.
+var knownSymptoms = []string{
+	"OOMKilled",
+	"CrashLoopBackOff",
+	"ImagePullBackOff",
+	"ErrImagePull",
+	"CreateContainerConfigError",
+	"CreateContainerError",
+	"FailedScheduling",
+	"FailedMount",
+	"Evicted",
+	"Unhealthy",
+	"Pending",
+}
+
+var codeFenceRX = regexp.MustCompile("(?s)```([\\w-]*)\\n(.*?)```")
+
+// runbookMeta describes what a runbook is about.
+type runbookMeta struct {
+	kind, name, namespace string
+	symptom               string
+	context               string
+	created               time.Time
+}
+
+// runbookCmd saves the current conversation as a markdown runbook.
+// Usage: /runbook [symptom]. The symptom is detected when omitted.
+func (v *AIChatView) runbookCmd(symptom string) {
+	mm := v.messages()
+	if !slices.ContainsFunc(mm, func(m chatMessage) bool { return m.role == "assistant" }) {
+		v.app.Flash().Errf("Nothing to save yet. Ask a question first")
+		return
+	}
+	if symptom == "" {
+		symptom = detectSymptom(mm)
+	}
+	meta := runbookMeta{
+		kind:      v.resKind,
+		name:      v.resName,
+		namespace: v.resNamespace,
+		symptom:   symptom,
+		context:   v.app.Config.ActiveContextName(),
+		created:   time.Now(),
+	}
+
+	path, err := saveRunbook(v.app.Config.K9s.ActiveAI().RunbooksPath(), meta, buildRunbook(meta, mm))
+	if err != nil {
+		slog.Error("Unable to save runbook", slogs.Error, err)
+		v.app.Flash().Err(err)
+		return
+	}
+	v.app.Flash().Infof("Runbook saved to %s", path)
+}
+
+// suggestRunbooks flashes saved runbooks matching the symptom in a question.
+func (v *AIChatView) suggestRunbooks(question string) {
+	symptom := detectSymptom([]chatMessage{{role: "user", content: question}})
+	if symptom == genericSymptom {
+		return
+	}
+	rr := findRunbooks(v.app.Config.K9s.ActiveAI().RunbooksPath(), v.resKind, symptom)
+	if len(rr) == 0 {
+		return
+	}
+	v.app.Flash().Infof("%d saved runbook(s) for %s %s, latest: %s", len(rr), runbookKind(v.resKind), symptom, rr[len(rr)-1])
+}
+
+// detectSymptom returns the most specific known symptom mentioned in the chat.
+func detectSymptom(mm []chatMessage) string {
+	var text strings.Builder
+	for _, m := range mm {
+		if m.role == "user" || m.role == "assistant" {
+			text.WriteString(m.content)
+			text.WriteString("\n")
+		}
+	}
+	lower := strings.ToLower(text.String())
+	for _, s := range knownSymptoms {
+		if strings.Contains(lower, strings.ToLower(s)) {
+			return s
+		}
+	}
+
+	return genericSymptom
+}
+
+func runbookKind(kind string) string {
+	if kind == "" {
+		return "cluster"
+	}
+
+	return kind
+}
+
+// buildRunbook renders the conversation as a structured markdown runbook.
+func buildRunbook(meta runbookMeta, mm []chatMessage) string {
+	var (
+		users, answers, actions, blocks []string
+	)
+	for _, m := range mm {
+		switch {
+		case m.role == "user":
+			users = append(users, m.content)
+		case m.role == "assistant":
+			answers = append(answers, strings.TrimSpace(m.content))
+			for _, b := range codeFenceRX.FindAllStringSubmatch(m.content, -1) {
+				blocks = append(blocks, "```"+b[1]+"\n"+strings.TrimRight(b[2], "\n")+"\n```")
+			}
+		case m.mutation:
+			actions = append(actions, m.content)
+		}
+	}
+
+	kind := runbookKind(meta.kind)
+	var b strings.Builder
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "kind: %s\n", kind)
+	if meta.name != "" {
+		fmt.Fprintf(&b, "resource: %s\n", strings.TrimPrefix(meta.namespace+"/"+meta.name, "/"))
+	}
+	fmt.Fprintf(&b, "symptom: %s\n", meta.symptom)
+	if meta.context != "" {
+		fmt.Fprintf(&b, "context: %s\n", meta.context)
+	}
+	fmt.Fprintf(&b, "created: %s\n", meta.created.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "tags: [%s, %s]\n", strings.ToLower(kind), strings.ToLower(meta.symptom))
+	b.WriteString("---\n\n")
+	fmt.Fprintf(&b, "# %s: %s\n\n", kind, meta.symptom)
+
+	b.WriteString("## Problem\n\n")
+	if len(users) > 0 {
+		b.WriteString(users[0] + "\n\n")
+	}
+	if len(users) > 1 {
+		b.WriteString("Follow-ups:\n\n")
+		for _, u := range users[1:] {
+			b.WriteString("- " + u + "\n")
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("## Diagnosis\n\n")
+	b.WriteString(answers[0] + "\n\n")
+	if len(answers) > 1 {
+		b.WriteString("## Resolution\n\n")
+		b.WriteString(answers[len(answers)-1] + "\n\n")
+	}
+
+	b.WriteString("## Actions Applied\n\n")
+	if len(actions) == 0 {
+		b.WriteString("_No cluster changes were applied from the chat._\n")
+	}
+	for _, a := range actions {
+		b.WriteString("- " + a + "\n")
+	}
+
+	if len(blocks) > 0 {
+		b.WriteString("\n## Commands & Patches\n\n")
+		b.WriteString(strings.Join(blocks, "\n\n") + "\n")
+	}
+
+	srcs := sourceIndex(mm)
+	var cited []string
+	for _, a := range answers {
+		for _, id := range ai.CitedIDs(a) {
+			if s, ok := srcs[id]; ok && !slices.Contains(cited, s.String()) {
+				cited = append(cited, s.String())
+			}
+		}
+	}
+	if len(cited) > 0 {
+		b.WriteString("\n## Sources\n\n")
+		for _, c := range cited {
+			b.WriteString("- " + c + "\n")
+		}
+	}
+
+	return b.String()
+}
+
+func runbookPrefix(kind, symptom string) string {
+	return data.SanitizeFileName(strings.ToLower(runbookKind(kind) + "_" + symptom + "_"))
+}
+
+func saveRunbook(dir string, meta runbookMeta, content string) (string, error) {
+	if err := ensureDir(dir); err != nil {
+		return "", err
+	}
+	name := runbookPrefix(meta.kind, meta.symptom) + meta.created.Format(runbookTimestamp) + ".md"
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+// findRunbooks returns the saved runbooks for a kind and symptom, oldest first.
+func findRunbooks(dir, kind, symptom string) []string {
+	ee, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	prefix := runbookPrefix(kind, symptom)
+	var rr []string
+	for _, e := range ee {
+		if !e.IsDir() && strings.HasPrefix(e.Name(), prefix) && strings.HasSuffix(e.Name(), ".md") {
+			rr = append(rr, filepath.Join(dir, e.Name()))
+		}
+	}
+
+	return rr
+}
@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/derailed/k9s/internal/ai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectSymptom(t *testing.T) {
+	uu := map[string]struct {
+		mm []chatMessage
+		e  string
+	}{
+		"none": {
+			mm: []chatMessage{{role: "user", content: "why is it slow?"}},
+			e:  genericSymptom,
+		},
+		"user": {
+			mm: []chatMessage{{role: "user", content: "pod is in crashloopbackoff"}},
+			e:  "CrashLoopBackOff",
+		},
+		"most-specific": {
+			mm: []chatMessage{
+				{role: "user", content: "pod keeps restarting with CrashLoopBackOff"},
+				{role: "assistant", content: "The container was OOMKilled."},
+			},
+			e: "OOMKilled",
+		},
+		"skip-activity": {
+			mm: []chatMessage{{role: "activity", content: "ImagePullBackOff"}},
+			e:  genericSymptom,
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, detectSymptom(u.mm))
+		})
+	}
+}
+
+func TestBuildRunbook(t *testing.T) {
+	meta := runbookMeta{
+		kind:      "Deployment",
+		name:      "api",
+		namespace: "prod",
+		symptom:   "OOMKilled",
+		context:   "ctx1",
+		created:   time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	mm := []chatMessage{
+		{role: "user", content: "Why is api restarting?"},
+		{role: "assistant", content: "The container is OOMKilled [1].", sources: []ai.Source{{ID: 1, Tool: "get_pod_diagnostics", Description: `Running diagnostics on pod "api"`}}},
+		{role: "activity", content: "get_pod_diagnostics prod/api"},
+		{role: "activity", content: "patch_resource prod/api", mutation: true},
+		{role: "assistant", content: "Raised the limit:\n```yaml\nmemory: 512Mi\n```\nDone."},
+	}
+
+	assert.Equal(t, "---\n"+
+		"kind: Deployment\n"+
+		"resource: prod/api\n"+
+		"symptom: OOMKilled\n"+
+		"context: ctx1\n"+
+		"created: 2026-01-02T03:04:05Z\n"+
+		"tags: [deployment, oomkilled]\n"+
+		"---\n\n"+
+		"# Deployment: OOMKilled\n\n"+
+		"## Problem\n\nWhy is api restarting?\n\n"+
+		"## Diagnosis\n\nThe container is OOMKilled [1].\n\n"+
+		"## Resolution\n\nRaised the limit:\n```yaml\nmemory: 512Mi\n```\nDone.\n\n"+
+		"## Actions Applied\n\n- patch_resource prod/api\n\n"+
+		"## Commands & Patches\n\n```yaml\nmemory: 512Mi\n```\n"+
+		"\n## Sources\n\n- [1] get_pod_diagnostics: Running diagnostics on pod \"api\"\n",
+		buildRunbook(meta, mm))
+}
+
+func TestSaveFindRunbooks(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "runbooks")
+	meta := runbookMeta{kind: "Pod", symptom: "CrashLoopBackOff", created: time.Now()}
+
+	assert.Empty(t, findRunbooks(dir, "Pod", "CrashLoopBackOff"))
+	path, err := saveRunbook(dir, meta, "# Pod: CrashLoopBackOff\n")
+	require.NoError(t, err)
+	assert.Equal(t, "pod_crashloopbackoff_", filepath.Base(path)[:len("pod_crashloopbackoff_")])
+
+	bb, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "# Pod: CrashLoopBackOff\n", string(bb))
+
+	assert.Equal(t, []string{path}, findRunbooks(dir, "Pod", "CrashLoopBackOff"))
+	assert.Empty(t, findRunbooks(dir, "Pod", "OOMKilled"))
+	assert.Empty(t, findRunbooks(dir, "", "CrashLoopBackOff"))
+}
@@ -0,0 +1,222 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/derailed/k9s/internal/ai"
+	"github.com/derailed/k9s/internal/config"
+	"github.com/derailed/k9s/internal/model"
+	"github.com/derailed/k9s/internal/slogs"
+	"github.com/derailed/k9s/internal/ui"
+	"github.com/derailed/k9s/internal/view/cmd"
+	"github.com/derailed/tcell/v2"
+	"github.com/derailed/tview"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	aiSkillsTitle    = "AI Skills"
+	aiSkillsTitleFmt = " AI Skills [hilite:bg:b](%d available)[fg:bg:-] "
+)
+
+// AISkillsView displays the AI skills for selection. The first row clears
+// the skill so the assistant gets all tools.
+type AISkillsView struct {
+	*tview.Flex
+
+	app     *App
+	table   *tview.Table
+	actions *ui.KeyActions
+	skills  []*ai.Skill
+}
+
+var _ model.Component = (*AISkillsView)(nil)
+
+// NewAISkillsView returns a new skill picker view.
+func NewAISkillsView() *AISkillsView {
+	return &AISkillsView{
+		Flex:    tview.NewFlex().SetDirection(tview.FlexRow),
+		table:   tview.NewTable(),
+		actions: ui.NewKeyActions(),
+	}
+}
+
+func (*AISkillsView) SetCommand(*cmd.Interpreter)            {}
+func (*AISkillsView) SetFilter(string, bool)                 {}
+func (*AISkillsView) SetLabelSelector(labels.Selector, bool) {}
+
+// Init initializes the skills view.
+func (v *AISkillsView) Init(ctx context.Context) error {
+	var err error
+	if v.app, err = extractApp(ctx); err != nil {
+		return err
+	}
+
+	v.SetBorder(true)
+	v.SetBorderPadding(0, 0, 1, 1)
+
+	v.table.SetSelectable(true, false)
+	v.table.SetSelectedStyle(tcell.StyleDefault.
+		Foreground(tcell.ColorBlack).
+		Background(tcell.ColorAqua))
+	v.table.SetSelectedFunc(v.selectSkill)
+
+	v.AddItem(v.table, 0, 1, true)
+
+	v.bindKeys()
+	v.SetInputCapture(v.keyboard)
+	v.StylesChanged(v.app.Styles)
+	v.load()
+
+	return nil
+}
+
+// StylesChanged applies current skin styles.
+func (v *AISkillsView) StylesChanged(s *config.Styles) {
+	views := s.Views()
+	v.SetBackgroundColor(views.Table.BgColor.Color())
+	v.table.SetBackgroundColor(views.Table.BgColor.Color())
+}
+
+func (v *AISkillsView) updateTitle() {
+	styles := v.app.Styles.Frame()
+	title := ui.SkinTitle(fmt.Sprintf(aiSkillsTitleFmt, len(v.skills)), &styles)
+	v.SetTitle(title)
+}
+
+// InCmdMode checks if prompt is active.
+func (*AISkillsView) InCmdMode() bool { return false }
+
+// Name returns the component name.
+func (*AISkillsView) Name() string { return aiSkillsTitle }
+
+// Start starts the skills view.
+func (v *AISkillsView) Start() {
+	v.app.Styles.AddListener(v)
+	v.app.SetFocus(v.table)
+}
+
+// Stop stops the skills view.
+func (v *AISkillsView) Stop() {
+	v.app.Styles.RemoveListener(v)
+}
+
+// Hints returns menu hints.
+func (v *AISkillsView) Hints() model.MenuHints {
+	return v.actions.Hints()
+}
+
+// ExtraHints returns additional hints.
+func (*AISkillsView) ExtraHints() map[string]string { return nil }
+
+// Actions returns menu actions.
+func (v *AISkillsView) Actions() *ui.KeyActions {
+	return v.actions
+}
+
+func (v *AISkillsView) bindKeys() {
+	v.actions.Bulk(ui.KeyMap{
+		tcell.KeyEscape: ui.NewKeyAction("Back", v.backCmd, false),
+		tcell.KeyEnter:  ui.NewKeyAction("Select", v.selectSkillKey, false),
+	})
+}
+
+func (v *AISkillsView) keyboard(evt *tcell.EventKey) *tcell.EventKey {
+	if a, ok := v.actions.Get(ui.AsKey(evt)); ok {
+		return a.Action(evt)
+	}
+	return evt
+}
+
+func (v *AISkillsView) backCmd(*tcell.EventKey) *tcell.EventKey {
+	v.app.Content.Pop()
+	return nil
+}
+
+func (v *AISkillsView) selectSkillKey(*tcell.EventKey) *tcell.EventKey {
+	row, _ := v.table.GetSelection()
+	v.selectSkill(row, 0)
+	return nil
+}
+
+// selectSkill activates the skill on the given row. Row 1 clears the skill.
+// The chat title picks up the new skill once the chat view starts again.
+func (v *AISkillsView) selectSkill(row, _ int) {
+	if row < 1 || row > len(v.skills)+1 {
+		return
+	}
+	if ai.Client == nil {
+		v.app.Flash().Errf("AI client not available")
+		return
+	}
+
+	skill, label := "", allSkillsOption
+	if row > 1 {
+		skill = v.skills[row-2].Name
+		label = skill
+	}
+	ai.Client.SetSkill(skill)
+	v.app.Flash().Infof("AI skill set to: %s", label)
+	slog.Info("AI skill changed", slogs.Subsys, "ai", "skill", skill)
+
+	v.app.Content.Pop()
+}
+
+// load lists the registered skills sorted by name.
+func (v *AISkillsView) load() {
+	v.table.Clear()
+	if ai.Client == nil {
+		v.showError("AI client not initialized")
+		return
+	}
+
+	all := ai.Client.Skills().All()
+	v.skills = make([]*ai.Skill, 0, len(all))
+	for _, n := range slices.Sorted(maps.Keys(all)) {
+		v.skills = append(v.skills, all[n])
+	}
+	active := ai.Client.ActiveSkill()
+
+	headers := []string{"", "NAME", "DESCRIPTION", "TOOLS"}
+	for col, h := range headers {
+		v.table.SetCell(0, col, tview.NewTableCell(h).
+			SetSelectable(false).
+			SetExpansion(1).
+			SetAttributes(tcell.AttrBold))
+	}
+	v.setRow(1, active == "", allSkillsOption, "Every tool, no specialized instructions", "")
+	for i, s := range v.skills {
+		v.setRow(i+2, s.Name == active, s.Name, s.Description, strconv.Itoa(len(s.ToolNames)))
+	}
+
+	v.table.Select(1, 0)
+	v.updateTitle()
+}
+
+func (v *AISkillsView) setRow(row int, active bool, cells ...string) {
+	indicator := " "
+	if active {
+		indicator = "✓"
+	}
+	for col, c := range append([]string{indicator}, cells...) {
+		cell := tview.NewTableCell(c).SetExpansion(1)
+		if col == 0 {
+			cell.SetExpansion(0)
+		}
+		v.table.SetCell(row, col, cell)
+	}
+}
+
+func (v *AISkillsView) showError(msg string) {
+	v.table.Clear()
+	v.table.SetCell(0, 0, tview.NewTableCell(fmt.Sprintf("[red::b]%s[-::-]", msg)).
+		SetSelectable(false))
+}
@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/derailed/k9s/internal/ai"
+	"github.com/derailed/k9s/internal/config/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAISkillsViewSelect(t *testing.T) {
+	a := NewApp(mock.NewMockConfig(t))
+	old := ai.Client
+	ai.Client = ai.NewAIClient(a.Config.K9s.AI, slog.Default())
+	t.Cleanup(func() { ai.Client = old })
+
+	require.NoError(t, a.openAIChat("", "", ""))
+	chat, ok := a.Content.Top().(*AIChatView)
+	require.True(t, ok)
+	t.Cleanup(chat.clearHistory)
+	chat.skillsCmd(nil)
+	v, ok := a.Content.Top().(*AISkillsView)
+	require.True(t, ok)
+
+	names := ai.Client.Skills().List()
+	slices.Sort(names)
+	require.Equal(t, len(names)+2, v.table.GetRowCount())
+	assert.Equal(t, "✓", v.table.GetCell(1, 0).Text)
+	assert.Equal(t, allSkillsOption, v.table.GetCell(1, 1).Text)
+
+	row := slices.Index(names, "diagnostics") + 2
+	assert.Equal(t, "diagnostics", v.table.GetCell(row, 1).Text)
+	s, _ := ai.Client.Skills().Get("diagnostics")
+	assert.Equal(t, s.Description, v.table.GetCell(row, 2).Text)
+
+	v.selectSkill(row, 0)
+	assert.Equal(t, "diagnostics", ai.Client.ActiveSkill())
+	assert.Same(t, chat, a.Content.Top())
+
+	// The page stack starts the chat again once the picker is popped.
+	chat.Start()
+	t.Cleanup(chat.Stop)
+	assert.Contains(t, chat.GetTitle(), "skill:diagnostics")
+}
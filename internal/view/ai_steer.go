@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/derailed/k9s/internal/ai"
+	"github.com/derailed/k9s/internal/slogs"
+	"github.com/derailed/tcell/v2"
+	"github.com/derailed/tview"
+)
+
+// interruptCmd stops the answer in flight and sends whatever is typed in the
+// input as a correction, keeping the conversation so far.
+func (v *AIChatView) interruptCmd(*tcell.EventKey) *tcell.EventKey {
+	v.mu.Lock()
+	busy := v.streaming
+	v.mu.Unlock()
+	if !busy {
+		v.app.Flash().Info("No answer to interrupt")
+		return nil
+	}
+
+	correction := strings.TrimSpace(v.input.GetText())
+	v.input.SetText("")
+	v.interrupt(correction)
+
+	return nil
+}
+
+// nudge steers the answer in flight with guidance typed while it streams.
+// When the CLI refuses mid-turn input, the answer is interrupted and the
+// guidance is sent as a correction instead.
+func (v *AIChatView) nudge(guidance string) {
+	go func() {
+		if err := ai.Client.Steer(context.Background(), guidance); err != nil {
+			slog.Warn("AI steering unavailable, interrupting instead", slogs.Error, err)
+			v.interrupt(guidance)
+			return
+		}
+		msg := "↪ Nudge: " + tview.Escape(guidance)
+		v.recordMessage(chatMessage{role: "system", content: msg, activity: true})
+		v.queueDraw(func() {
+			v.renderMessage("system", msg)
+			v.scrollToEnd()
+		})
+	}()
+}
+
+// interrupt cuts the answer in flight short. The correction is sent once the
+// answer has stopped.
+func (v *AIChatView) interrupt(correction string) {
+	v.mu.Lock()
+	v.correction = &correction
+	v.mu.Unlock()
+
+	if ai.Client == nil || !ai.Client.Interrupt() {
+		// The answer completed meanwhile, send the correction right away
+		// unless the send already picked it up.
+		if c, ok := v.takeCorrection(); ok {
+			go v.correct(c)
+		}
+	}
+}
+
+// takeCorrection returns the pending correction, if any, and clears it.
+func (v *AIChatView) takeCorrection() (string, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	c := v.correction
+	v.correction = nil
+	if c == nil {
+		return "", false
+	}
+
+	return *c, true
+}
+
+// correct resumes an interrupted conversation with the user's correction.
+func (v *AIChatView) correct(correction string) {
+	if correction != "" {
+		v.recordMessage(chatMessage{role: "user", content: correction})
+	}
+	v.queueDraw(func() {
+		if correction != "" {
+			v.renderMessage("user", correction)
+		}
+		v.follow = true
+		v.showThinkingIndicator()
+	})
+	v.send(ai.CorrectionPrompt(correction), false)
+}
+
+// recordInterrupted keeps the partial answer of an interrupted turn.
+func (v *AIChatView) recordInterrupted(partial string, resume bool) {
+	if partial != "" {
+		if resume {
+			v.extendAnswer(partial, false, ai.Client.TurnSources())
+		} else {
+			v.recordMessage(chatMessage{role: "assistant", content: partial, sources: ai.Client.TurnSources()})
+		}
+	}
+	v.recordMessage(chatMessage{role: "system", content: "⏸ Interrupted", activity: true})
+	v.queueDraw(func() {
+		v.reRenderChat()
+	})
+}
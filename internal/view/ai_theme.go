@@ -0,0 +1,32 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"cmp"
+
+	"github.com/derailed/k9s/internal/config"
+)
+
+// chatTheme holds the colors and glyphs of the chat roles. Unless the skin
+// sets views.ai, they derive from the frame styles.
+type chatTheme struct {
+	userColor, assistantColor, activityColor, errorColor config.Color
+	userGlyph, assistantGlyph, activityGlyph, errorGlyph string
+}
+
+func newChatTheme(s *config.Styles) chatTheme {
+	f, ai := s.Frame(), s.Views().AI
+
+	return chatTheme{
+		userColor:      cmp.Or(ai.UserColor, f.Title.HighlightColor),
+		assistantColor: cmp.Or(ai.AssistantColor, f.Status.AddColor),
+		activityColor:  cmp.Or(ai.ActivityColor, f.Menu.FgColor),
+		errorColor:     cmp.Or(ai.ErrorColor, "red"),
+		userGlyph:      cmp.Or(ai.UserGlyph, "▶"),
+		assistantGlyph: cmp.Or(ai.AssistantGlyph, "✦"),
+		activityGlyph:  cmp.Or(ai.ActivityGlyph, "⚡"),
+		errorGlyph:     cmp.Or(ai.ErrorGlyph, "✖"),
+	}
+}
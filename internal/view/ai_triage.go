@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/derailed/k9s/internal/client"
+)
+
+// triageToolBudget caps the tool calls a namespace triage should make, so a
+// large namespace doesn't turn into a pod-by-pod crawl.
+const triageToolBudget = 10
+
+// triagePrompt asks the assistant to survey a namespace and rank its problems.
+func triagePrompt(ns string) string {
+	return fmt.Sprintf(`Triage the namespace %q: survey its workloads and rank the problems you find by severity.
+
+Stay within namespace %q and use at most %d tool calls:
+1. Call get_cluster_health with namespace %q and groupByNamespace true for the pod status summary.
+2. Call list_resources in namespace %q for apps/v1/deployments, apps/v1/statefulsets, apps/v1/daemonsets and batch/v1/jobs to spot workloads that are not fully ready.
+3. Call get_events in namespace %q with eventType Warning.
+4. Drill into at most 3 of the worst workloads, e.g. with get_workload_summary or get_pod_diagnostics. Do not inspect pods one by one.
+
+Answer with a list ranked from most to least severe (critical, warning, info). For each problem name the affected workload, the evidence and a suggested next step.
+If nothing is wrong, say so in one sentence.`, ns, ns, triageToolBudget, ns, ns, ns)
+}
+
+// triageNamespace opens a chat scoped to the namespace and asks for its
+// triage, so follow-up questions stay on that namespace.
+func (a *App) triageNamespace(ns string) error {
+	if err := a.openAIChat(client.NsGVR.R(), ns, ns); err != nil {
+		return err
+	}
+	chat, ok := a.Content.Top().(*AIChatView)
+	if !ok {
+		return errors.New("AI chat is not available")
+	}
+	chat.ask(fmt.Sprintf("Triage namespace %s", ns), triagePrompt(ns))
+
+	return nil
+}
@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/derailed/k9s/internal/ai"
+)
+
+// trimmedMarker stands in for the turns dropped from the AI session once the
+// history token budget is spent.
+const trimmedMarker = "[earlier conversation trimmed]"
+
+// estimateTokens roughly counts the tokens of a text, at about 4 bytes per
+// token.
+func estimateTokens(s string) int {
+	return (len(s) + 3) / 4
+}
+
+// addSessionTokens adds to the tokens spent in the current AI session.
+func (v *AIChatView) addSessionTokens(n int) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.sessionTokens += n
+}
+
+// spentTokens returns the size of the AI session context. It's the usage
+// reported by the model, which counts tool outputs, falling back to the
+// estimate of the prompts and answers when the provider reports none.
+func (v *AIChatView) spentTokens(u ai.TokenUsage) int {
+	if u.Context > 0 {
+		return int(u.Context)
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return v.sessionTokens
+}
+
+// resetSessionTokens starts counting the tokens of a new AI session.
+func (v *AIChatView) resetSessionTokens() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.sessionTokens = 0
+}
+
+// trimContext starts a new AI session when sending the prompt would exceed
+// ai.historyTokenBudget. The new session only gets a recap of the latest
+// turns, sized to half the budget, along with the prompt.
+func (v *AIChatView) trimContext(prompt string) {
+	budget := v.app.Config.K9s.ActiveAI().HistoryTokenBudget
+	if budget <= 0 || ai.Client == nil {
+		return
+	}
+	spent := v.spentTokens(ai.Client.Usage())
+	if spent == 0 || spent+estimateTokens(prompt) <= budget {
+		return
+	}
+
+	mm := v.messages()
+	// The question being sent is already in the history.
+	if n := len(mm); n > 0 && mm[n-1].role == "user" {
+		mm = mm[:n-1]
+	}
+	ai.Client.ResetSession()
+	v.resetSessionTokens()
+	v.swapRecap(conversationRecap(mm, budget*2))
+	slog.Debug("AI history trimmed", slog.Int("tokens", spent), slog.Int("budget", budget))
+
+	msg := fmt.Sprintf("✂ %s — about %d tokens over the %d token budget, the assistant keeps the latest turns",
+		trimmedMarker, spent+estimateTokens(prompt)-budget, budget)
+	v.recordMessage(chatMessage{role: "system", content: msg, activity: true})
+	v.queueDraw(func() {
+		v.renderMessage("system", msg)
+		v.scrollToEnd()
+	})
+}
@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/derailed/k9s/internal/ai"
+	"github.com/derailed/k9s/internal/config/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	assert.Equal(t, 0, estimateTokens(""))
+	assert.Equal(t, 1, estimateTokens("abc"))
+	assert.Equal(t, 2, estimateTokens("abcde"))
+}
+
+func TestTrimContext(t *testing.T) {
+	v := NewAIChatView()
+	v.app = NewApp(mock.NewMockConfig(t))
+	v.closed.Store(true)
+	old := ai.Client
+	ai.Client = ai.NewAIClient(v.app.Config.K9s.AI, slog.Default())
+	t.Cleanup(func() { ai.Client = old })
+
+	v.history = []chatMessage{
+		{role: "user", content: strings.Repeat("a", 400)},
+		{role: "assistant", content: strings.Repeat("b", 400)},
+		{role: "user", content: "why is api down?"},
+		{role: "assistant", content: "It is OOMKilled."},
+		{role: "user", content: "how do I fix it?"},
+	}
+	v.addSessionTokens(300)
+
+	v.trimContext("how do I fix it?")
+	assert.Empty(t, v.swapRecap(""), "no budget")
+
+	v.app.Config.K9s.AI.HistoryTokenBudget = 200
+	v.trimContext("how do I fix it?")
+	recap := v.swapRecap("")
+	assert.Contains(t, recap, trimmedMarker)
+	assert.Contains(t, recap, "User: why is api down?\n\nAssistant: It is OOMKilled.")
+	assert.NotContains(t, recap, "aaaa")
+	assert.NotContains(t, recap, "how do I fix it?")
+	assert.Zero(t, v.sessionTokens)
+	last := v.messages()[len(v.history)-1]
+	assert.Equal(t, "system", last.role)
+	assert.Contains(t, last.content, trimmedMarker)
+
+	v.addSessionTokens(100)
+	v.trimContext("short")
+	assert.Empty(t, v.swapRecap(""), "within budget")
+}
+
+func TestSpentTokens(t *testing.T) {
+	uu := map[string]struct {
+		estimate int
+		usage    ai.TokenUsage
+		e        int
+	}{
+		"estimate": {
+			estimate: 300,
+			e:        300,
+		},
+		"reported": {
+			estimate: 300,
+			usage:    ai.TokenUsage{Input: 5200, Output: 400, Requests: 3, Context: 2400},
+			e:        2400,
+		},
+		"none": {},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			v := NewAIChatView()
+			v.addSessionTokens(u.estimate)
+			assert.Equal(t, u.e, v.spentTokens(u.usage))
+		})
+	}
+}
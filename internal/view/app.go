@@ -132,15 +132,8 @@ func (a *App) Init(version string, _ int) error {
 	if a.Config.K9s.ImageScans.Enable {
 		a.initImgScanner(version)
 	}
-	if a.Config.K9s.AI.IsEnabled() {
-		hints := model.MenuHints{
-			{Mnemonic: ":ai", Description: "AI Chat", Visible: true},
-			{Mnemonic: ":byok", Description: "BYOK Setup", Visible: true},
-		}
-		if !a.Config.K9s.AI.IsBYOK() {
-			hints = append(hints, model.MenuHint{Mnemonic: ":ai models", Description: "AI Models", Visible: true})
-		}
-		a.Menu().SetPersistentHints(hints)
+	if a.Config.K9s.ActiveAI().IsEnabled() {
+		a.setAIHints()
 		a.initAI()
 	}
 	a.ReloadStyles()
@@ -148,13 +141,65 @@ func (a *App) Init(version string, _ int) error {
 	return nil
 }
 
+// setAIHints shows the AI commands in the menu, none when AI is disabled.
+func (a *App) setAIHints() {
+	cfg := a.Config.K9s.ActiveAI()
+	if !cfg.IsEnabled() {
+		a.Menu().SetPersistentHints(nil)
+		return
+	}
+	hints := model.MenuHints{
+		{Mnemonic: ":ai", Description: "AI Chat", Visible: true},
+		{Mnemonic: ":byok", Description: "BYOK Setup", Visible: true},
+	}
+	if !cfg.IsBYOK() {
+		hints = append(hints, model.MenuHint{Mnemonic: ":ai models", Description: "AI Models", Visible: true})
+	}
+	a.Menu().SetPersistentHints(hints)
+}
+
+// reloadAI restarts the AI client when the active context overrides the AI
+// settings differently than prev, the settings the client runs with.
+// Otherwise a client kept across a context switch is pointed at the new
+// cluster and starts a new session.
+func (a *App) reloadAI(prev config.AI, switched bool) {
+	next := a.Config.K9s.ActiveAI()
+	if !prev.ClientChanged(next) {
+		if switched && ai.Client != nil {
+			a.wireAITools(ai.Client)
+			ai.Client.ResetSession()
+		}
+		return
+	}
+	slog.Info("AI settings changed with the context, restarting the AI client",
+		slogs.Context, a.Config.ActiveContextName(),
+		"enabled", next.IsEnabled(),
+		"model", next.Model,
+		"byok", next.IsBYOK(),
+	)
+	a.stopAI()
+	a.setAIHints()
+	if next.IsEnabled() {
+		a.initAI()
+	}
+}
+
 func (a *App) initAI() {
 	defer func(t time.Time) {
 		slog.Debug("AI client init time", slogs.Elapsed, time.Since(t))
 	}(time.Now())
 
-	aiClient := ai.NewAIClient(a.Config.K9s.AI, slog.Default())
+	if err := a.Config.K9s.ActiveAI().ProviderError(); err != nil {
+		slog.Error("Invalid AI provider config", slogs.Error, err)
+		a.Flash().Errf("Invalid AI provider config: %s", err)
+	}
+
+	aiClient := ai.NewAIClient(a.Config.K9s.ActiveAI(), slog.Default())
 	ai.Client = aiClient
+	if a.Config.K9s.ActiveAI().Prewarm {
+		go a.prewarmAI(aiClient)
+		return
+	}
 
 	if err := aiClient.Init(context.Background()); err != nil {
 		slog.Error("AI client init failed", slogs.Error, err)
@@ -162,15 +207,44 @@ func (a *App) initAI() {
 		return
 	}
 
-	// Wire tools if we have a connection
-	if a.Conn() != nil && a.Conn().ConnectionOK() && a.factory != nil {
-		tf := ai.NewToolFactory(a.factory, a.Conn(), slog.Default())
-		aiClient.SetTools(tf.BuildTools())
-	}
+	a.wireAITools(aiClient)
 
 	slog.Info("🤖 AI/Copilot integration initialized")
 }
 
+// prewarmAI initializes the AI client and creates its chat session in the
+// background so startup doesn't wait on the Copilot CLI and the first
+// question is answered right away.
+func (a *App) prewarmAI(aiClient *ai.AIClient) {
+	defer func(t time.Time) {
+		slog.Debug("AI client prewarm time", slogs.Elapsed, time.Since(t))
+	}(time.Now())
+
+	// Tools go first so a question asked meanwhile doesn't get a session
+	// without them.
+	a.wireAITools(aiClient)
+	if err := aiClient.Prewarm(context.Background()); err != nil {
+		slog.Error("AI client prewarm failed", slogs.Error, err)
+		a.Flash().Warn("AI prewarm failed (will retry on use): " + err.Error())
+		return
+	}
+
+	slog.Info("🤖 AI/Copilot integration prewarmed")
+	a.Flash().Info("AI assistant ready")
+}
+
+// wireAITools points the AI tools at the current cluster connection, if any.
+func (a *App) wireAITools(aiClient *ai.AIClient) {
+	if a.Conn() == nil || !a.Conn().ConnectionOK() || a.factory == nil {
+		return
+	}
+	tf := ai.NewToolFactory(a.factory, a.Conn(), a.Config.K9s.ActiveAI(), slog.Default())
+	tf.SetReadOnlyFunc(a.Config.IsReadOnly)
+	aiClient.SetTools(tf.BuildTools())
+	aiClient.SetFingerprinter(tf.Fingerprint)
+	aiClient.SetPreflight(tf.Preflight)
+}
+
 func (*App) stopImgScanner() {
 	if vul.ImgScanner != nil {
 		vul.ImgScanner.Stop()
@@ -529,6 +603,7 @@ func (a *App) switchContext(ci *cmd.Interpreter, force bool) error {
 	a.Halt()
 	defer a.Resume()
 	{
+		aiCfg, prevContext := a.Config.K9s.ActiveAI(), a.Config.ActiveContextName()
 		a.Config.Reset()
 		ct, err := a.Config.ActivateContext(contextName)
 		if err != nil {
@@ -583,6 +658,7 @@ func (a *App) switchContext(ci *cmd.Interpreter, force bool) error {
 		if a.clusterModel != nil {
 			a.clusterModel.Reset(a.factory)
 		}
+		a.reloadAI(aiCfg, prevContext != contextName)
 	}
 
 	return nil
@@ -224,6 +224,24 @@ func (c *Interpreter) IsAIModelsCmd() bool {
 	return ok && topic == "models"
 }
 
+// IsAIStatusCmd returns true if `:ai status` is detected.
+func (c *Interpreter) IsAIStatusCmd() bool {
+	if !c.IsAICmd() {
+		return false
+	}
+	topic, ok := c.args[topicKey]
+	return ok && topic == "status"
+}
+
+// IsAIDoctorCmd returns true if `:ai doctor` is detected.
+func (c *Interpreter) IsAIDoctorCmd() bool {
+	if !c.IsAICmd() {
+		return false
+	}
+	topic, ok := c.args[topicKey]
+	return ok && topic == "doctor"
+}
+
 // IsAISkillCmd returns true if `:ai skill <name>` is detected.
 func (c *Interpreter) IsAISkillCmd() bool {
 	if !c.IsAICmd() {
@@ -312,7 +312,7 @@ func (c *Command) specialCmd(p *cmd.Interpreter, pushCmd bool) bool {
 			c.app.Flash().Err(err)
 		}
 	case p.IsAIModelsCmd():
-		if c.app.Config.K9s.AI.IsBYOK() {
+		if c.app.Config.K9s.ActiveAI().IsBYOK() {
 			c.app.Flash().Errf("Model listing is not available with BYOK providers. Set your model in the config file (ai.model).")
 		} else {
 			modelsView := NewAIModelsView()
@@ -320,6 +320,14 @@ func (c *Command) specialCmd(p *cmd.Interpreter, pushCmd bool) bool {
 				c.app.Flash().Err(err)
 			}
 		}
+	case p.IsAIStatusCmd():
+		if err := c.app.inject(NewAIDashboardView(), false); err != nil {
+			c.app.Flash().Err(err)
+		}
+	case p.IsAIDoctorCmd():
+		if err := c.app.runAIDoctor(); err != nil {
+			c.app.Flash().Err(err)
+		}
 	case p.IsAISkillCmd():
 		if name, ok := p.AISkillArg(); !ok {
 			c.app.Flash().Errf("Invalid command. Use `ai skill <name>` (diagnostics, security, optimization)")
@@ -327,8 +335,7 @@ func (c *Command) specialCmd(p *cmd.Interpreter, pushCmd bool) bool {
 			c.aiSkillCmd(name)
 		}
 	case p.IsAICmd():
-		chat := NewAIChatView()
-		if err := c.app.inject(chat, false); err != nil {
+		if err := c.app.openAIChat("", "", ""); err != nil {
 			c.app.Flash().Err(err)
 		}
 	default:
@@ -352,8 +359,7 @@ func (c *Command) aiSkillCmd(name string) {
 	c.app.Flash().Infof("AI skill set to: %s", name)
 
 	// Open chat view with the new skill active.
-	chat := NewAIChatView()
-	if err := c.app.inject(chat, false); err != nil {
+	if err := c.app.openAIChat("", "", ""); err != nil {
 		c.app.Flash().Err(err)
 	}
 }
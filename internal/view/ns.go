@@ -35,7 +35,8 @@ func NewNamespace(gvr *client.GVR) ResourceViewer {
 
 func (n *Namespace) bindKeys(aa *ui.KeyActions) {
 	aa.Bulk(ui.KeyMap{
-		ui.KeyU: ui.NewKeyAction("Use", n.useNsCmd, true),
+		ui.KeyU:      ui.NewKeyAction("Use", n.useNsCmd, true),
+		ui.KeyShiftT: ui.NewKeyAction("AI Triage", n.aiTriageCmd, true),
 	})
 }
 
@@ -55,6 +56,23 @@ func (n *Namespace) useNsCmd(*tcell.EventKey) *tcell.EventKey {
 	return nil
 }
 
+func (n *Namespace) aiTriageCmd(*tcell.EventKey) *tcell.EventKey {
+	path := n.GetTable().GetSelectedItem()
+	if path == "" {
+		return nil
+	}
+	_, ns := client.Namespaced(path)
+	if client.IsAllNamespace(ns) {
+		n.App().Flash().Warn("Select a namespace to triage")
+		return nil
+	}
+	if err := n.App().triageNamespace(ns); err != nil {
+		n.App().Flash().Err(err)
+	}
+
+	return nil
+}
+
 func (n *Namespace) useNamespace(fqn string) {
 	_, ns := client.Namespaced(fqn)
 	if client.CleanseNamespace(n.App().Config.ActiveNamespace()) == ns {
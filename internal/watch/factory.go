@@ -108,6 +108,25 @@ func (f *Factory) HasSynced(gvr *client.GVR, ns string) (bool, error) {
 	return inf.Informer().HasSynced(), nil
 }
 
+// IsWatching checks if a synced informer already watches a resource in a
+// namespace. Unlike the other accessors, it never starts an informer.
+func (f *Factory) IsWatching(gvr *client.GVR, ns string) bool {
+	if client.IsClusterWide(ns) {
+		ns = client.BlankNamespace
+	}
+	f.mx.RLock()
+	defer f.mx.RUnlock()
+	fac, ok := f.factories[ns]
+	if !ok {
+		return false
+	}
+	// A closed channel reports the started informers without waiting.
+	stop := make(chan struct{})
+	close(stop)
+
+	return fac.WaitForCacheSync(stop)[gvr.GVR()]
+}
+
 // Get retrieves a given resource.
 func (f *Factory) Get(gvr *client.GVR, fqn string, wait bool, _ labels.Selector) (runtime.Object, error) {
 	ns, n := namespaced(fqn)